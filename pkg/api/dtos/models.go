@@ -79,6 +79,10 @@ type MetricRequest struct {
 	Queries []*simplejson.Json `json:"queries"`
 	// required: false
 	Debug bool `json:"debug"`
+	// Explain adds the execution profile of server-side expressions to the response, as a table under the refId "__explain__".
+	// Applies only to requests with expressions.
+	// required: false
+	Explain bool `json:"explain"`
 }
 
 func (mr *MetricRequest) GetUniqueDatasourceTypes() []string {
@@ -106,6 +110,7 @@ func (mr *MetricRequest) CloneWithQueries(queries []*simplejson.Json) MetricRequ
 		To:      mr.To,
 		Queries: queries,
 		Debug:   mr.Debug,
+		Explain: mr.Explain,
 	}
 }
 
@@ -0,0 +1,255 @@
+package expr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+var errResultCacheAborted = errors.New("query of the shared result was aborted")
+
+// minResultCacheAlignment is the minimal step the time range of a query is aligned to when computing the cache key.
+const minResultCacheAlignment = time.Second
+
+// ResultCache is a cache of data source query responses that is shared by pipelines executed at the same time,
+// e.g. the alert rules evaluated in the same tick of the scheduler. Queries are identified by the data source,
+// the query model, and the time range aligned to the query interval, so that pipelines that differ only in
+// their expressions send the query to the data source once. A query that is already in flight is not sent
+// again; the other pipelines wait for its response instead.
+//
+// The cache does not expire entries and therefore must not outlive the execution it is created for.
+type ResultCache struct {
+	mtx     sync.Mutex
+	entries map[string]*resultCacheEntry
+}
+
+type resultCacheEntry struct {
+	done chan struct{}
+	// response is the response to the query. Only valid after done is closed and if err is nil.
+	response backend.DataResponse
+	// ok is false if the data source did not respond to the query.
+	ok  bool
+	err error
+}
+
+// NewResultCache creates an empty ResultCache.
+func NewResultCache() *ResultCache {
+	return &ResultCache{
+		entries: map[string]*resultCacheEntry{},
+	}
+}
+
+type resultCacheKey struct{}
+
+// WithResultCache returns a copy of the context that carries the cache. Data source queries executed by the Service
+// with the returned context are served from the cache. A nil cache returns the context as is.
+func WithResultCache(ctx context.Context, cache *ResultCache) context.Context {
+	if cache == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, resultCacheKey{}, cache)
+}
+
+func resultCacheFromContext(ctx context.Context) *ResultCache {
+	cache, _ := ctx.Value(resultCacheKey{}).(*ResultCache)
+	return cache
+}
+
+// acquire returns the entry for the key. If the entry did not exist it is created and the caller must complete it.
+func (c *ResultCache) acquire(key string) (*resultCacheEntry, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if e, ok := c.entries[key]; ok {
+		return e, false
+	}
+	e := &resultCacheEntry{done: make(chan struct{})}
+	c.entries[key] = e
+	return e, true
+}
+
+// complete sets the response of the entry and releases the callers that wait for it. If the query failed,
+// the entry is removed so that the query is retried by the next caller.
+func (c *ResultCache) complete(key string, e *resultCacheEntry, response backend.DataResponse, ok bool, err error) {
+	e.response, e.ok, e.err = response, ok, err
+	if err != nil {
+		c.mtx.Lock()
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+		c.mtx.Unlock()
+	}
+	close(e.done)
+}
+
+func (e *resultCacheEntry) wait(ctx context.Context) (backend.DataResponse, bool, error) {
+	select {
+	case <-e.done:
+		return e.response, e.ok, e.err
+	case <-ctx.Done():
+		return backend.DataResponse{}, false, ctx.Err()
+	}
+}
+
+// queryData sends the request to the data source. If the context carries a ResultCache, responses to the queries
+// are taken from the cache, and only the queries that are not cached are sent to the data source.
+func (s *Service) queryData(ctx context.Context, orgID int64, ds *datasources.DataSource, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	cache := resultCacheFromContext(ctx)
+	if cache == nil {
+		return s.dataService.QueryData(ctx, req)
+	}
+
+	type cachedQuery struct {
+		key   string
+		entry *resultCacheEntry
+	}
+	owned := make(map[string]cachedQuery, len(req.Queries))
+	shared := make(map[string]cachedQuery, len(req.Queries))
+	missing := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		key, err := resultCacheQueryKey(orgID, ds.UID, q)
+		if err != nil {
+			return nil, err
+		}
+		e, created := cache.acquire(key)
+		if created {
+			owned[q.RefID] = cachedQuery{key: key, entry: e}
+			missing = append(missing, q)
+			s.metrics.dsCacheRequests.WithLabelValues("miss", ds.Type).Inc()
+			continue
+		}
+		shared[q.RefID] = cachedQuery{key: key, entry: e}
+		s.metrics.dsCacheRequests.WithLabelValues("hit", ds.Type).Inc()
+	}
+
+	resp := backend.NewQueryDataResponse()
+	if len(missing) > 0 {
+		err := func() (err error) {
+			// Entries must be completed even if the data source panics, otherwise the other callers would wait for them forever.
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				for _, q := range owned {
+					cache.complete(q.key, q.entry, backend.DataResponse{}, false, errResultCacheAborted)
+				}
+			}()
+
+			missingReq := *req
+			missingReq.Queries = missing
+			missingResp, err := s.dataService.QueryData(ctx, &missingReq)
+			var responses backend.Responses
+			if missingResp != nil {
+				responses = missingResp.Responses
+			}
+			completed = true
+			for refID, q := range owned {
+				if err != nil {
+					cache.complete(q.key, q.entry, backend.DataResponse{}, false, err)
+					continue
+				}
+				r, ok := responses[refID]
+				cache.complete(q.key, q.entry, r, ok, nil)
+				if ok {
+					// the cached response is shared, so even the caller that queried it gets a copy.
+					resp.Responses[refID] = copyDataResponse(r, refID)
+				}
+			}
+			return err
+		}()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for refID, q := range shared {
+		r, ok, err := q.entry.wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			resp.Responses[refID] = copyDataResponse(r, refID)
+		}
+	}
+	return resp, nil
+}
+
+// resultCacheQueryKey returns the key the response to the query is cached by. The refID of the query is not part
+// of the key, and the time range is aligned to the interval of the query.
+func resultCacheQueryKey(orgID int64, dsUID string, q backend.DataQuery) (string, error) {
+	var model map[string]any
+	if err := json.Unmarshal(q.JSON, &model); err != nil {
+		return "", fmt.Errorf("failed to read query %s: %w", q.RefID, err)
+	}
+	delete(model, "refId")
+	// json.Marshal sorts the keys of maps, so equal models produce equal keys.
+	normalized, err := json.Marshal(model)
+	if err != nil {
+		return "", fmt.Errorf("failed to read query %s: %w", q.RefID, err)
+	}
+
+	alignment := q.Interval
+	if alignment < minResultCacheAlignment {
+		alignment = minResultCacheAlignment
+	}
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%s\x00%d\x00%d\x00%d\x00%d\x00",
+		orgID,
+		dsUID,
+		q.QueryType,
+		q.Interval,
+		q.MaxDataPoints,
+		q.TimeRange.From.Truncate(alignment).UnixMilli(),
+		q.TimeRange.To.Truncate(alignment).UnixMilli(),
+	)
+	_, _ = h.Write(normalized)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyDataResponse returns a deep copy of the frames of the response, so that the pipeline can modify them
+// without affecting other pipelines that share the response.
+func copyDataResponse(r backend.DataResponse, refID string) backend.DataResponse {
+	if r.Frames == nil {
+		return r
+	}
+	frames := make(data.Frames, 0, len(r.Frames))
+	for _, f := range r.Frames {
+		frames = append(frames, copyFrame(f, refID))
+	}
+	r.Frames = frames
+	return r
+}
+
+func copyFrame(f *data.Frame, refID string) *data.Frame {
+	c := f.EmptyCopy()
+	if f.RefID != "" {
+		c.RefID = refID
+	}
+	if f.Meta != nil {
+		meta := *f.Meta
+		meta.Notices = append([]data.Notice(nil), f.Meta.Notices...)
+		c.Meta = &meta
+	}
+	for i, field := range f.Fields {
+		c.Fields[i].Labels = field.Labels.Copy()
+		if field.Config != nil {
+			config := *field.Config
+			c.Fields[i].Config = &config
+		}
+		c.Fields[i].Extend(field.Len())
+		for j := 0; j < field.Len(); j++ {
+			c.Fields[i].Set(j, field.CopyAt(j))
+		}
+	}
+	return c
+}
@@ -0,0 +1,163 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+type countingQueryDataHandler struct {
+	mtx     sync.Mutex
+	queries []backend.DataQuery
+	err     error
+}
+
+func (h *countingQueryDataHandler) QueryData(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.queries = append(h.queries, req.Queries...)
+	if h.err != nil {
+		return nil, h.err
+	}
+	resp := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		frame := data.NewFrame("",
+			data.NewField("Time", nil, []time.Time{q.TimeRange.To}),
+			data.NewField("Value", data.Labels{"job": "test"}, []*float64{fp(1)}),
+		)
+		frame.RefID = q.RefID
+		resp.Responses[q.RefID] = backend.DataResponse{Frames: data.Frames{frame}}
+	}
+	return resp, nil
+}
+
+func TestResultCache(t *testing.T) {
+	ds := &datasources.DataSource{UID: "prom", Type: datasources.DS_PROMETHEUS}
+	now := time.Unix(1700000000, 0)
+
+	newRequest := func(refID string, expr string, to time.Time) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					RefID:         refID,
+					JSON:          []byte(`{"refId":"` + refID + `","expr":"` + expr + `"}`),
+					Interval:      time.Minute,
+					MaxDataPoints: 100,
+					TimeRange:     backend.TimeRange{From: to.Add(-time.Hour), To: to},
+				},
+			},
+		}
+	}
+
+	newService := func(h *countingQueryDataHandler) *Service {
+		return &Service{
+			dataService: h,
+			metrics:     newMetrics(nil),
+		}
+	}
+
+	t.Run("should share responses of identical queries", func(t *testing.T) {
+		h := &countingQueryDataHandler{}
+		s := newService(h)
+		ctx := WithResultCache(context.Background(), NewResultCache())
+
+		respA, err := s.queryData(ctx, 1, ds, newRequest("A", "up", now))
+		require.NoError(t, err)
+		respB, err := s.queryData(ctx, 1, ds, newRequest("B", "up", now.Add(time.Second)))
+		require.NoError(t, err)
+
+		require.Len(t, h.queries, 1)
+		require.Contains(t, respA.Responses, "A")
+		require.Contains(t, respB.Responses, "B")
+		require.Equal(t, "B", respB.Responses["B"].Frames[0].RefID)
+		require.Equal(t, respA.Responses["A"].Frames[0].Fields[1].At(0), respB.Responses["B"].Frames[0].Fields[1].At(0))
+	})
+
+	t.Run("should return independent copies", func(t *testing.T) {
+		h := &countingQueryDataHandler{}
+		s := newService(h)
+		ctx := WithResultCache(context.Background(), NewResultCache())
+
+		respA, err := s.queryData(ctx, 1, ds, newRequest("A", "up", now))
+		require.NoError(t, err)
+		respA.Responses["A"].Frames[0].Fields[1].Labels["job"] = "changed"
+		respA.Responses["A"].Frames[0].Fields[1].Set(0, fp(2))
+
+		respB, err := s.queryData(ctx, 1, ds, newRequest("B", "up", now))
+		require.NoError(t, err)
+		require.Equal(t, data.Labels{"job": "test"}, respB.Responses["B"].Frames[0].Fields[1].Labels)
+		require.Equal(t, fp(1), respB.Responses["B"].Frames[0].Fields[1].At(0))
+	})
+
+	t.Run("should not share responses of different queries", func(t *testing.T) {
+		h := &countingQueryDataHandler{}
+		s := newService(h)
+		ctx := WithResultCache(context.Background(), NewResultCache())
+
+		_, err := s.queryData(ctx, 1, ds, newRequest("A", "up", now))
+		require.NoError(t, err)
+		_, err = s.queryData(ctx, 1, ds, newRequest("A", "down", now))
+		require.NoError(t, err)
+		_, err = s.queryData(ctx, 1, ds, newRequest("A", "up", now.Add(time.Minute)))
+		require.NoError(t, err)
+		_, err = s.queryData(ctx, 2, ds, newRequest("A", "up", now))
+		require.NoError(t, err)
+
+		require.Len(t, h.queries, 4)
+	})
+
+	t.Run("should not share responses without cache", func(t *testing.T) {
+		h := &countingQueryDataHandler{}
+		s := newService(h)
+
+		_, err := s.queryData(context.Background(), 1, ds, newRequest("A", "up", now))
+		require.NoError(t, err)
+		_, err = s.queryData(context.Background(), 1, ds, newRequest("B", "up", now))
+		require.NoError(t, err)
+
+		require.Len(t, h.queries, 2)
+	})
+
+	t.Run("should retry failed queries", func(t *testing.T) {
+		h := &countingQueryDataHandler{err: errors.New("boom")}
+		s := newService(h)
+		ctx := WithResultCache(context.Background(), NewResultCache())
+
+		_, err := s.queryData(ctx, 1, ds, newRequest("A", "up", now))
+		require.ErrorContains(t, err, "boom")
+
+		h.err = nil
+		resp, err := s.queryData(ctx, 1, ds, newRequest("A", "up", now))
+		require.NoError(t, err)
+		require.Contains(t, resp.Responses, "A")
+		require.Len(t, h.queries, 2)
+	})
+
+	t.Run("should query once when queries run concurrently", func(t *testing.T) {
+		h := &countingQueryDataHandler{}
+		s := newService(h)
+		ctx := WithResultCache(context.Background(), NewResultCache())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := s.queryData(ctx, 1, ds, newRequest("A", "up", now))
+				require.NoError(t, err)
+				require.Contains(t, resp.Responses, "A")
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, h.queries, 1)
+	})
+}
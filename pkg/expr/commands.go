@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 )
 
 // Command is an interface for all expression commands.
@@ -27,11 +28,13 @@ type MathCommand struct {
 	RawExpression string
 	Expression    *mathexp.Expr
 	refID         string
+	// units is true if the units of the values are propagated and converted by the expression.
+	units bool
 }
 
 // NewMathCommand creates a new MathCommand. It will return an error
 // if there is an error parsing expr.
-func NewMathCommand(refID, expr string) (*MathCommand, error) {
+func NewMathCommand(refID, expr string, features featuremgmt.FeatureToggles) (*MathCommand, error) {
 	parsedExpr, err := mathexp.New(expr)
 	if err != nil {
 		return nil, err
@@ -40,11 +43,12 @@ func NewMathCommand(refID, expr string) (*MathCommand, error) {
 		RawExpression: expr,
 		Expression:    parsedExpr,
 		refID:         refID,
+		units:         features.IsEnabledGlobally(featuremgmt.FlagSseUnitConversion),
 	}, nil
 }
 
 // UnmarshalMathCommand creates a MathCommand from Grafana's frontend query.
-func UnmarshalMathCommand(rn *rawNode) (*MathCommand, error) {
+func UnmarshalMathCommand(rn *rawNode, features featuremgmt.FeatureToggles) (*MathCommand, error) {
 	rawExpr, ok := rn.Query["expression"]
 	if !ok {
 		return nil, errors.New("command is missing an expression")
@@ -54,7 +58,7 @@ func UnmarshalMathCommand(rn *rawNode) (*MathCommand, error) {
 		return nil, fmt.Errorf("math expression is expected to be a string, got %T", rawExpr)
 	}
 
-	gm, err := NewMathCommand(rn.RefID, exprString)
+	gm, err := NewMathCommand(rn.RefID, exprString, features)
 	if err != nil {
 		return nil, fmt.Errorf("invalid math command type: %w", err)
 	}
@@ -73,7 +77,15 @@ func (gm *MathCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Va
 	_, span := tracer.Start(ctx, "SSE.ExecuteMath")
 	span.SetAttributes(attribute.String("expression", gm.RawExpression))
 	defer span.End()
-	return gm.Expression.Execute(gm.refID, vars, tracer)
+	execute := gm.Expression.Execute
+	if gm.units {
+		execute = gm.Expression.ExecuteWithUnits
+	}
+	res, err := execute(gm.refID, vars, tracer)
+	if err != nil {
+		return res, makeUnitError(gm.refID, err)
+	}
+	return res, nil
 }
 
 func (gm *MathCommand) Type() string {
@@ -86,11 +98,13 @@ type ReduceCommand struct {
 	VarToReduce  string
 	refID        string
 	seriesMapper mathexp.ReduceMapper
+	// units is true if the units of the values are propagated and converted by the reducer.
+	units bool
 }
 
 // NewReduceCommand creates a new ReduceCMD.
-func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce string, mapper mathexp.ReduceMapper, features featuremgmt.FeatureToggles) (*ReduceCommand, error) {
+	_, err := mathexp.GetTimeReduceFunc(reducer)
 	if err != nil {
 		return nil, err
 	}
@@ -100,11 +114,12 @@ func NewReduceCommand(refID string, reducer mathexp.ReducerID, varToReduce strin
 		VarToReduce:  varToReduce,
 		refID:        refID,
 		seriesMapper: mapper,
+		units:        features.IsEnabledGlobally(featuremgmt.FlagSseUnitConversion),
 	}, nil
 }
 
 // UnmarshalReduceCommand creates a MathCMD from Grafana's frontend query.
-func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
+func UnmarshalReduceCommand(rn *rawNode, features featuremgmt.FeatureToggles) (*ReduceCommand, error) {
 	rawVar, ok := rn.Query["expression"]
 	if !ok {
 		return nil, errors.New("no expression ID is specified to reduce. Must be a reference to an existing query or expression")
@@ -154,7 +169,7 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", s, rn.RefID)
 		}
 	}
-	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper, features)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -179,6 +194,9 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 			if err != nil {
 				return newRes, err
 			}
+			if gr.units {
+				mathexp.SetReducedUnit(num, gr.Reducer, mathexp.GetUnit(v))
+			}
 			newRes.Values = append(newRes.Values, num)
 		case mathexp.Number: // if incoming vars is just a number, any reduce op is just a noop, add it as it is
 			value := v.GetFloat64Value()
@@ -190,6 +208,9 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 			}
 			copyV := mathexp.NewNumber(gr.refID, v.GetLabels())
 			copyV.SetValue(value)
+			if gr.units {
+				mathexp.SetUnit(copyV, mathexp.GetUnit(v))
+			}
 			if gr.seriesMapper == nil && i == 0 { // Add notice to only the first result to not multiple them in presentation
 				copyV.AddNotice(data.Notice{
 					Severity: data.NoticeSeverityWarning,
@@ -222,11 +243,13 @@ type ResampleCommand struct {
 
 // NewResampleCommand creates a new ResampleCMD.
 func NewResampleCommand(refID, rawWindow, varToResample string, downsampler mathexp.ReducerID, upsampler mathexp.Upsampler, tr TimeRange) (*ResampleCommand, error) {
-	// TODO: validate reducer here, before execution
 	window, err := gtime.ParseDuration(rawWindow)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse resample "window" duration field %q: %w`, window, err)
 	}
+	if _, err := mathexp.GetTimeReduceFunc(downsampler); err != nil {
+		return nil, fmt.Errorf("invalid resample downsampler: %w", err)
+	}
 	return &ResampleCommand{
 		Window:        window,
 		VarToResample: varToResample,
@@ -282,7 +305,7 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 
 	return NewResampleCommand(rn.RefID, window,
 		varToResample,
-		mathexp.ReducerID(downsampler),
+		mathexp.ReducerID(strings.ToLower(downsampler)),
 		mathexp.Upsampler(upsampler),
 		rn.TimeRange)
 }
@@ -343,6 +366,10 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeJoin is the CMDType for joining results on time or on a label or field
+	TypeJoin
+	// TypeMerge is the CMDType for merging results
+	TypeMerge
 )
 
 func (gt CommandType) String() string {
@@ -359,6 +386,10 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeJoin:
+		return "join"
+	case TypeMerge:
+		return "merge"
 	default:
 		return "unknown"
 	}
@@ -379,6 +410,10 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "join":
+		return TypeJoin, nil
+	case "merge":
+		return TypeMerge, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -85,7 +86,7 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 				QueryType:  "",
 				TimeRange:  RelativeTimeRange{},
 				DataSource: nil,
-			})
+			}, featuremgmt.WithFeatures())
 
 			if test.isError {
 				require.Error(t, err)
@@ -103,7 +104,7 @@ func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 
 	t.Run("when mapper is nil", func(t *testing.T) {
-		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil)
+		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil, featuremgmt.WithFeatures())
 		require.NoError(t, err)
 
 		t.Run("should noop if Number", func(t *testing.T) {
@@ -161,7 +162,7 @@ func TestReduceExecute(t *testing.T) {
 		}
 
 		t.Run("drop all non numbers if mapper is DropNonNumber", func(t *testing.T) {
-			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, &mathexp.DropNonNumber{})
+			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, &mathexp.DropNonNumber{}, featuremgmt.WithFeatures())
 			require.NoError(t, err)
 			execute, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
 			require.NoError(t, err)
@@ -169,7 +170,7 @@ func TestReduceExecute(t *testing.T) {
 		})
 
 		t.Run("replace all non numbers if mapper is ReplaceNonNumberWithValue", func(t *testing.T) {
-			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, &mathexp.ReplaceNonNumberWithValue{Value: 1})
+			cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, &mathexp.ReplaceNonNumberWithValue{Value: 1}, featuremgmt.WithFeatures())
 			require.NoError(t, err)
 			execute, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
 			require.NoError(t, err)
@@ -192,7 +193,7 @@ func TestReduceExecute(t *testing.T) {
 				Values: noData,
 			},
 		}
-		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil)
+		cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil, featuremgmt.WithFeatures())
 		require.NoError(t, err)
 		results, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
 		require.NoError(t, err)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
 var ErrSeriesMustBeWide = errors.New("input data must be a wide series")
@@ -93,3 +94,27 @@ func makeUnexpectedNodeTypeError(refID, nodeType string) error {
 
 	return UnexpectedNodeTypeError.Build(data)
 }
+
+var IncompatibleUnitsError = errutil.BadRequest("sse.incompatibleUnits").MustTemplate(
+	"[{{ .Public.refId }}] {{ .Error }}",
+	errutil.WithPublic(
+		"expression {{ .Public.refId }} combines values with incompatible units: {{ .Public.error }}",
+	),
+)
+
+// makeUnitError returns IncompatibleUnitsError if err is caused by values with units that cannot be converted
+// into each other, e.g. an addition of bytes and seconds, and err otherwise.
+func makeUnitError(refID string, err error) error {
+	var unitErr mathexp.UnitMismatchError
+	if !errors.As(err, &unitErr) {
+		return err
+	}
+	data := errutil.TemplateData{
+		Public: map[string]any{
+			"refId": refID,
+			"error": unitErr.Error(),
+		},
+		Error: err,
+	}
+	return IncompatibleUnitsError.Build(data)
+}
@@ -1,14 +1,20 @@
 package expr_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func TestQueryErrorType(t *testing.T) {
@@ -19,3 +25,24 @@ func TestQueryErrorType(t *testing.T) {
 	require.True(t, errors.Is(qe, qet))
 	require.True(t, errors.As(qe, &utilError))
 }
+
+func TestIncompatibleUnitsErrorType(t *testing.T) {
+	a := mathexp.NewNumber("A", nil)
+	a.SetValue(util.Pointer(float64(1)))
+	mathexp.SetUnit(a, "bytes")
+	b := mathexp.NewNumber("B", nil)
+	b.SetValue(util.Pointer(float64(1)))
+	mathexp.SetUnit(b, "s")
+
+	cmd, err := expr.NewMathCommand("C", "$A + $B", featuremgmt.WithFeatures(featuremgmt.FlagSseUnitConversion))
+	require.NoError(t, err)
+	_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{a}},
+		"B": mathexp.Results{Values: mathexp.Values{b}},
+	}, tracing.InitializeTracerForTest())
+
+	utilError := errutil.Error{}
+	require.True(t, errors.Is(err, expr.IncompatibleUnitsError))
+	require.True(t, errors.As(err, &utilError))
+	require.Contains(t, utilError.PublicMessage, "cannot apply '+' to values in 'bytes' and 's'")
+}
@@ -0,0 +1,295 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// ExplainRefID is the refID of the response that contains the execution profile of the pipeline,
+// which is returned in addition to the results of the queries if the request has Explain set.
+const ExplainRefID = "__explain__"
+
+// nodeProfile describes the execution of a single node of the pipeline.
+type nodeProfile struct {
+	refID    string
+	nodeType NodeType
+	// kind is the type of the data source for data source queries, and the type of the command otherwise.
+	kind   string
+	inputs []string
+
+	executed bool
+	duration time.Duration
+	// For data source queries, the input is the frames returned by the data source: series are frames and points are rows.
+	inputSeries   int
+	inputPoints   int
+	outputSeries  int
+	outputPoints  int
+	droppedSeries int
+	// conversion is the response type determined by the ResultConverter for data source queries,
+	// and the change of the value types by the command otherwise, e.g. "seriesSet -> numberSet".
+	conversion string
+	err        error
+}
+
+// executionProfile collects the profile of a pipeline execution. All methods are safe to call on a nil profile.
+type executionProfile struct {
+	mtx     sync.Mutex
+	start   time.Time
+	nodes   []*nodeProfile
+	byRefID map[string]*nodeProfile
+}
+
+func newExecutionProfile(pipeline DataPipeline) *executionProfile {
+	p := &executionProfile{
+		start:   time.Now(),
+		nodes:   make([]*nodeProfile, 0, len(pipeline)),
+		byRefID: make(map[string]*nodeProfile, len(pipeline)),
+	}
+	for _, node := range pipeline {
+		np := &nodeProfile{
+			refID:    node.RefID(),
+			nodeType: node.NodeType(),
+			kind:     nodeKind(node),
+			inputs:   node.NeedsVars(),
+		}
+		p.nodes = append(p.nodes, np)
+		p.byRefID[np.refID] = np
+	}
+	return p
+}
+
+type executionProfileKey struct{}
+
+func withExecutionProfile(ctx context.Context, p *executionProfile) context.Context {
+	if p == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, executionProfileKey{}, p)
+}
+
+func executionProfileFromContext(ctx context.Context) *executionProfile {
+	p, _ := ctx.Value(executionProfileKey{}).(*executionProfile)
+	return p
+}
+
+// recordNode records the result of the node and the time it took to produce it.
+func (p *executionProfile) recordNode(node Node, vars mathexp.Vars, res mathexp.Results, duration time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	np, ok := p.byRefID[node.RefID()]
+	if !ok {
+		return
+	}
+	np.executed = true
+	np.duration = duration
+	np.err = res.Error
+	np.outputSeries, np.outputPoints = countValues(res.Values)
+
+	if node.NodeType() == TypeDatasourceNode {
+		return
+	}
+	var inputs mathexp.Values
+	for _, refID := range node.NeedsVars() {
+		inputs = append(inputs, vars[refID].Values...)
+	}
+	np.inputSeries, np.inputPoints = countValues(inputs)
+	np.droppedSeries = countDroppedSeries(inputs, res.Values)
+	if in, out := valueTypes(inputs), valueTypes(res.Values); in != out && len(inputs) > 0 && len(res.Values) > 0 {
+		np.conversion = fmt.Sprintf("%s -> %s", in, out)
+	}
+}
+
+// recordQuery records the frames returned by the data source for the query, and how the ResultConverter read them.
+func (p *executionProfile) recordQuery(refID string, frames data.Frames, responseType string) {
+	if p == nil {
+		return
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	np, ok := p.byRefID[refID]
+	if !ok {
+		return
+	}
+	np.inputSeries = len(frames)
+	np.inputPoints = 0
+	for _, frame := range frames {
+		if frame != nil {
+			np.inputPoints += frame.Rows()
+		}
+	}
+	np.conversion = responseType
+}
+
+// frame returns the profile as a table with a row per node, in the order the nodes were executed.
+func (p *executionProfile) frame() *data.Frame {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	size := len(p.nodes)
+	refIDs := make([]string, 0, size)
+	nodeTypes := make([]string, 0, size)
+	kinds := make([]string, 0, size)
+	inputs := make([]string, 0, size)
+	durations := make([]*float64, 0, size)
+	inputSeries := make([]int64, 0, size)
+	inputPoints := make([]int64, 0, size)
+	outputSeries := make([]int64, 0, size)
+	outputPoints := make([]int64, 0, size)
+	droppedSeries := make([]int64, 0, size)
+	conversions := make([]string, 0, size)
+	errs := make([]string, 0, size)
+	for _, np := range p.nodes {
+		refIDs = append(refIDs, np.refID)
+		nodeTypes = append(nodeTypes, np.nodeType.String())
+		kinds = append(kinds, np.kind)
+		inputs = append(inputs, strings.Join(np.inputs, ","))
+		var duration *float64
+		if np.executed {
+			ms := float64(np.duration.Microseconds()) / 1000
+			duration = &ms
+		}
+		durations = append(durations, duration)
+		inputSeries = append(inputSeries, int64(np.inputSeries))
+		inputPoints = append(inputPoints, int64(np.inputPoints))
+		outputSeries = append(outputSeries, int64(np.outputSeries))
+		outputPoints = append(outputPoints, int64(np.outputPoints))
+		droppedSeries = append(droppedSeries, int64(np.droppedSeries))
+		conversions = append(conversions, np.conversion)
+		errMsg := ""
+		if np.err != nil {
+			errMsg = np.err.Error()
+		}
+		errs = append(errs, errMsg)
+	}
+
+	durationField := data.NewField("duration", nil, durations)
+	durationField.Config = &data.FieldConfig{Unit: "ms"}
+	frame := data.NewFrame("explain",
+		data.NewField("refId", nil, refIDs),
+		data.NewField("type", nil, nodeTypes),
+		data.NewField("kind", nil, kinds),
+		data.NewField("inputs", nil, inputs),
+		durationField,
+		data.NewField("inputSeries", nil, inputSeries),
+		data.NewField("inputPoints", nil, inputPoints),
+		data.NewField("outputSeries", nil, outputSeries),
+		data.NewField("outputPoints", nil, outputPoints),
+		data.NewField("droppedSeries", nil, droppedSeries),
+		data.NewField("conversion", nil, conversions),
+		data.NewField("error", nil, errs),
+	)
+	frame.RefID = ExplainRefID
+	frame.SetMeta(&data.FrameMeta{
+		PreferredVisualization: data.VisTypeTable,
+		Stats: []data.QueryStat{
+			{
+				FieldConfig: data.FieldConfig{DisplayName: "Total duration", Unit: "ms"},
+				Value:       float64(time.Since(p.start).Microseconds()) / 1000,
+			},
+		},
+	})
+	return frame
+}
+
+func nodeKind(node Node) string {
+	switch t := node.(type) {
+	case *DSNode:
+		if t.datasource != nil {
+			return t.datasource.Type
+		}
+	case *CMDNode:
+		if t.Command != nil {
+			return t.Command.Type()
+		}
+	case *MLNode:
+		return fmt.Sprintf("ml_%s", t.commandType())
+	}
+	return ""
+}
+
+// countValues returns the number of series and the number of points in the values. NoData is not counted.
+func countValues(values mathexp.Values) (int, int) {
+	series, points := 0, 0
+	for _, v := range values {
+		switch t := v.(type) {
+		case mathexp.NoData:
+			continue
+		case mathexp.Series:
+			points += t.Len()
+		case mathexp.TableData:
+			if t.Frame != nil {
+				points += t.Frame.Rows()
+			}
+		default:
+			points++
+		}
+		series++
+	}
+	return series, points
+}
+
+// countDroppedSeries returns the number of input series that have no related series in the output.
+// Series are related if the labels of one are a subset of the labels of the other, which covers commands
+// that keep the labels, e.g. reduce, as well as joins and aggregations.
+func countDroppedSeries(inputs, outputs mathexp.Values) int {
+	outputLabels := make([]data.Labels, 0, len(outputs))
+	outputFingerprints := make(map[data.Fingerprint]struct{}, len(outputs))
+	for _, v := range outputs {
+		if _, ok := v.(mathexp.NoData); ok {
+			continue
+		}
+		outputLabels = append(outputLabels, v.GetLabels())
+		outputFingerprints[v.GetLabels().Fingerprint()] = struct{}{}
+	}
+
+	dropped := 0
+	for _, v := range inputs {
+		if _, ok := v.(mathexp.NoData); ok {
+			continue
+		}
+		labels := v.GetLabels()
+		if _, ok := outputFingerprints[labels.Fingerprint()]; ok {
+			continue
+		}
+		related := slices.ContainsFunc(outputLabels, func(l data.Labels) bool {
+			return isLabelsSubset(labels, l) || isLabelsSubset(l, labels)
+		})
+		if !related {
+			dropped++
+		}
+	}
+	return dropped
+}
+
+func isLabelsSubset(subset, labels data.Labels) bool {
+	for k, v := range subset {
+		if lv, ok := labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// valueTypes returns the sorted unique types of the values, e.g. "numberSet" or "numberSet,seriesSet".
+func valueTypes(values mathexp.Values) string {
+	types := make([]string, 0, 2)
+	for _, v := range values {
+		t := v.Type().String()
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	slices.Sort(types)
+	return strings.Join(types, ",")
+}
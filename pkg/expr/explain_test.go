@@ -0,0 +1,139 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginconfig"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestExplain(t *testing.T) {
+	times := []time.Time{time.Unix(1, 0), time.Unix(2, 0)}
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{data.NewFrame("",
+				data.NewField("time", nil, times),
+				data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(1), fp(2)}),
+				data.NewField("value", data.Labels{"host": "b"}, []*float64{fp(3), fp(4)}),
+			)}},
+			"B": {Frames: data.Frames{data.NewFrame("",
+				data.NewField("time", nil, times),
+				data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(5), fp(6)}),
+			)}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeCacheService{}, &datafakes.FakeDataSourceService{}, nil, pluginconfig.NewFakePluginRequestConfigProvider())
+
+	cfg := setting.NewCfg()
+	cfg.ExpressionsEnabled = true
+	features := featuremgmt.WithFeatures()
+	s := Service{
+		cfg:          cfg,
+		dataService:  me,
+		pCtxProvider: pCtxProvider,
+		features:     features,
+		tracer:       tracing.InitializeTracerForTest(),
+		metrics:      newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracing.InitializeTracerForTest(),
+		},
+	}
+
+	ds := &datasources.DataSource{OrgID: 1, UID: "test", Type: "test"}
+	queries := []Query{
+		{
+			RefID:      "A",
+			DataSource: ds,
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange:  AbsoluteTimeRange{},
+		},
+		{
+			RefID:      "B",
+			DataSource: ds,
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange:  AbsoluteTimeRange{},
+		},
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "reduce", "reducer": "last", "expression": "A" }`),
+		},
+		{
+			RefID:      "D",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A + $B" }`),
+		},
+	}
+
+	t.Run("should not return profile if not requested", func(t *testing.T) {
+		res, err := s.TransformData(context.Background(), time.Now(), &Request{Queries: queries, User: &user.SignedInUser{}})
+		require.NoError(t, err)
+		require.NotContains(t, res.Responses, ExplainRefID)
+	})
+
+	t.Run("should return profile of each node", func(t *testing.T) {
+		res, err := s.TransformData(context.Background(), time.Now(), &Request{Queries: queries, User: &user.SignedInUser{}, Explain: true})
+		require.NoError(t, err)
+		require.Contains(t, res.Responses, "D")
+		require.Contains(t, res.Responses, ExplainRefID)
+
+		frames := res.Responses[ExplainRefID].Frames
+		require.Len(t, frames, 1)
+		frame := frames[0]
+		require.Equal(t, 4, frame.Rows())
+
+		type row struct {
+			kind, inputs, conversion                             string
+			inputSeries, inputPoints, outputSeries, outputPoints int64
+			droppedSeries                                        int64
+		}
+		rows := make(map[string]row, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			field := func(name string) any {
+				f, _ := frame.FieldByName(name)
+				require.NotNil(t, f, name)
+				return f.At(i)
+			}
+			require.NotNil(t, field("duration"))
+			require.Empty(t, field("error"))
+			rows[field("refId").(string)] = row{
+				kind:          field("kind").(string),
+				inputs:        field("inputs").(string),
+				conversion:    field("conversion").(string),
+				inputSeries:   field("inputSeries").(int64),
+				inputPoints:   field("inputPoints").(int64),
+				outputSeries:  field("outputSeries").(int64),
+				outputPoints:  field("outputPoints").(int64),
+				droppedSeries: field("droppedSeries").(int64),
+			}
+		}
+
+		require.Equal(t, map[string]row{
+			"A": {kind: "test", conversion: "single frame series", inputSeries: 1, inputPoints: 2, outputSeries: 2, outputPoints: 4},
+			"B": {kind: "test", conversion: "single frame series", inputSeries: 1, inputPoints: 2, outputSeries: 1, outputPoints: 2},
+			"C": {kind: "reduce", inputs: "A", conversion: "seriesSet -> numberSet", inputSeries: 2, inputPoints: 4, outputSeries: 2, outputPoints: 2},
+			"D": {kind: "math", inputs: "A,B", inputSeries: 3, inputPoints: 6, outputSeries: 1, outputPoints: 2, droppedSeries: 1},
+		}, rows)
+	})
+}
@@ -3,6 +3,7 @@ package expr
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"gonum.org/v1/gonum/graph/topo"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/ml"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 )
 
@@ -75,9 +77,15 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 		}
 
 		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		for _, node := range dsNodes {
+			if err := vars[node.RefID()].Error; errors.Is(err, ErrSeriesLimitExceeded) {
+				return vars, err
+			}
+		}
 	}
 
 	s.allowLongFrames = hasSqlExpression(*dp)
+	profile := executionProfileFromContext(c)
 
 	for _, node := range *dp {
 		if groupByDSFlag && node.NodeType() == TypeDatasourceNode {
@@ -99,6 +107,7 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			}
 		}
 		if hasDepError {
+			profile.recordNode(node, vars, vars[node.RefID()], 0)
 			continue
 		}
 
@@ -115,12 +124,18 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
 		if err != nil {
 			res.Error = err
 		}
 
 		vars[node.RefID()] = res
+		profile.recordNode(node, vars, res, time.Since(start))
+		if errors.Is(res.Error, ErrSeriesLimitExceeded) {
+			// Stop the pipeline, there is no point in executing the expressions on a partial set of series.
+			return vars, res.Error
+		}
 	}
 	return vars, nil
 }
@@ -139,7 +154,7 @@ func (dp *DataPipeline) GetDatasourceTypes() []string {
 				name = t.datasource.Type
 			}
 		case *MLNode:
-			name = fmt.Sprintf("ml_%s", t.command.Type())
+			name = fmt.Sprintf("ml_%s", t.commandType())
 		}
 		if name == "" {
 			continue
@@ -281,7 +296,8 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 		case TypeCMDNode:
 			node, err = buildCMDNode(rn, s.features)
 		case TypeMLNode:
-			if s.features.IsEnabledGlobally(featuremgmt.FlagMlExpressions) {
+			// Local commands are executed by Grafana itself, only the commands proxied to the ML plugin are behind the feature flag.
+			if ml.IsLocalCommand(rn.QueryRaw) || s.features.IsEnabledGlobally(featuremgmt.FlagMlExpressions) {
 				node, err = s.buildMLNode(dp, rn, req)
 				if err != nil {
 					err = fmt.Errorf("fail to parse expression with refID %v: %w", rn.RefID, err)
@@ -309,6 +325,23 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 	for nodeIt.Next() {
 		node := nodeIt.Node().(Node)
 
+		if mlNode, ok := node.(*MLNode); ok {
+			for _, neededVar := range mlNode.NeedsVars() {
+				neededNode, ok := registry[neededVar]
+				if !ok {
+					return fmt.Errorf("unable to find dependent node '%v'", neededVar)
+				}
+				if neededNode.ID() == mlNode.ID() {
+					return fmt.Errorf("expression '%v' cannot reference itself. Must be query or another expression", neededVar)
+				}
+				if neededNode.NodeType() == TypeCMDNode && neededNode.(*CMDNode).CMDType == TypeClassicConditions {
+					return fmt.Errorf("classic conditions may not be the input for other expressions, but %v is the input for %v", neededVar, mlNode.RefID())
+				}
+				dp.SetEdge(dp.NewEdge(neededNode, mlNode))
+			}
+			continue
+		}
+
 		if node.NodeType() != TypeCMDNode {
 			// datasource node, nothing to do for now. Although if we want expression results to be
 			// used as datasource query params some day this will need change
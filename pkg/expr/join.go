@@ -0,0 +1,380 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// JoinCommand is an expression command that joins the results of several queries or expressions into one result.
+// Time series are joined on their timestamps into a wide frame. Numbers and tables are joined on a label or field
+// into a table, with a column per number result or table field. The joined frame is then read with the same rules
+// the ResultConverter applies to data source responses: a wide frame becomes a set of time series, a table with
+// a single numeric column becomes a set of numbers, and any other table is kept as a table.
+type JoinCommand struct {
+	VarsToJoin []string
+	Mode       JoinMode
+	On         JoinOn
+	Field      string
+	refID      string
+}
+
+// NewJoinCommand creates a new JoinCommand.
+func NewJoinCommand(refID string, varsToJoin []string, mode JoinMode, on JoinOn, field string) (*JoinCommand, error) {
+	if len(varsToJoin) < 2 {
+		return nil, fmt.Errorf("join requires at least two expressions, got %d", len(varsToJoin))
+	}
+	if mode == "" {
+		mode = JoinModeOuter
+	}
+	if mode != JoinModeOuter && mode != JoinModeInner {
+		return nil, fmt.Errorf("unsupported join mode '%s', expected '%s' or '%s'", mode, JoinModeOuter, JoinModeInner)
+	}
+	if on == "" {
+		on = JoinOnTime
+	}
+	switch on {
+	case JoinOnTime:
+	case JoinOnField:
+		if field == "" {
+			return nil, fmt.Errorf("join on %s requires the name of the label or field to join on", on)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported join key '%s', expected '%s' or '%s'", on, JoinOnTime, JoinOnField)
+	}
+	vars := make([]string, 0, len(varsToJoin))
+	for _, v := range varsToJoin {
+		v = strings.TrimPrefix(v, "$")
+		if v == "" {
+			return nil, fmt.Errorf("no variable specified to reference for refId %v", refID)
+		}
+		vars = append(vars, v)
+	}
+	return &JoinCommand{
+		VarsToJoin: vars,
+		Mode:       mode,
+		On:         on,
+		Field:      field,
+		refID:      refID,
+	}, nil
+}
+
+// UnmarshalJoinCommand creates a JoinCommand from Grafana's frontend query.
+func UnmarshalJoinCommand(rn *rawNode) (*JoinCommand, error) {
+	q := JoinQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the join command: %w", err)
+	}
+	return NewJoinCommand(rn.RefID, q.Expressions, q.Mode, q.On, q.Field)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gj *JoinCommand) NeedsVars() []string {
+	return gj.VarsToJoin
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gj *JoinCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteJoin")
+	span.SetAttributes(attribute.String("mode", string(gj.Mode)), attribute.String("on", string(gj.On)))
+	defer span.End()
+
+	var frame *data.Frame
+	var err error
+	switch gj.On {
+	case JoinOnField:
+		frame, err = gj.joinOnField(vars)
+	default:
+		frame, err = gj.joinOnTime(vars)
+	}
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	if frame == nil || frame.Rows() == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
+	}
+	return readJoinedFrame(frame)
+}
+
+func (gj *JoinCommand) Type() string {
+	return TypeJoin.String()
+}
+
+// joinOnTime joins time series into a wide frame with a value field per series. The outer join contains the timestamps
+// of all series, the inner join only the timestamps that all series have.
+func (gj *JoinCommand) joinOnTime(vars mathexp.Vars) (*data.Frame, error) {
+	var series []mathexp.Series
+	var seriesVars []string
+	for _, refID := range gj.VarsToJoin {
+		found := false
+		for _, val := range vars[refID].Values {
+			switch v := val.(type) {
+			case mathexp.Series:
+				series = append(series, v)
+				seriesVars = append(seriesVars, refID)
+				found = true
+			case mathexp.NoData:
+			default:
+				return nil, fmt.Errorf("join on %s requires time series, but got %s from %s", JoinOnTime, val.Type(), refID)
+			}
+		}
+		if !found && gj.Mode == JoinModeInner {
+			return nil, nil
+		}
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	counts := map[time.Time]int{}
+	for _, s := range series {
+		seen := make(map[time.Time]struct{}, s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t := s.GetTime(i)
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			counts[t]++
+		}
+	}
+	times := make([]time.Time, 0, len(counts))
+	for t, c := range counts {
+		if gj.Mode == JoinModeInner && c < len(series) {
+			continue
+		}
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	index := make(map[time.Time]int, len(times))
+	for i, t := range times {
+		index[t] = i
+	}
+
+	fields := make([]*data.Field, 0, len(series)+1)
+	fields = append(fields, data.NewField("Time", nil, times))
+	for i, s := range series {
+		values := make([]*float64, len(times))
+		for j := 0; j < s.Len(); j++ {
+			if idx, ok := index[s.GetTime(j)]; ok {
+				values[idx] = s.GetValue(j)
+			}
+		}
+		var labels data.Labels
+		if s.GetLabels() != nil {
+			labels = s.GetLabels().Copy()
+		}
+		f := data.NewField(seriesVars[i], labels, values)
+		if config := s.GetConfig(); config != nil {
+			copied := *config
+			f.Config = &copied
+		}
+		fields = append(fields, f)
+	}
+	frame := data.NewFrame(gj.refID, fields...)
+	frame.RefID = gj.refID
+	return frame, nil
+}
+
+// joinRow is a row of the table joined on a field. Columns are indexed by the position of the output field.
+type joinRow struct {
+	key    string
+	values map[int]any
+	inputs int
+}
+
+// joinOnField joins numbers and tables into a table with the key as the first field. Numbers provide the key from
+// their labels and a column named after the refID of their result. Tables provide the key from the field with the
+// same name and a column for each of their other fields.
+func (gj *JoinCommand) joinOnField(vars mathexp.Vars) (*data.Frame, error) {
+	var fields []*data.Field
+	fieldNames := map[string]struct{}{gj.Field: {}}
+	addField := func(refID, name string, fieldType data.FieldType, labels data.Labels, config *data.FieldConfig) int {
+		if _, ok := fieldNames[name]; ok {
+			name = fmt.Sprintf("%s %s", refID, name)
+		}
+		fieldNames[name] = struct{}{}
+		f := data.NewFieldFromFieldType(fieldType.NullableType(), 0)
+		f.Name = name
+		f.Labels = labels
+		f.Config = config
+		fields = append(fields, f)
+		return len(fields) - 1
+	}
+
+	var rows []*joinRow
+	byKey := map[string]*joinRow{}
+	getRow := func(key string) *joinRow {
+		if r, ok := byKey[key]; ok {
+			return r
+		}
+		r := &joinRow{key: key, values: map[int]any{}}
+		byKey[key] = r
+		rows = append(rows, r)
+		return r
+	}
+
+	for _, refID := range gj.VarsToJoin {
+		seen := map[string]struct{}{}
+		addRow := func(key string) (*joinRow, error) {
+			if _, ok := seen[key]; ok {
+				return nil, fmt.Errorf("join on %s requires unique keys, but %s has more than one row with %s '%s'", JoinOnField, refID, gj.Field, key)
+			}
+			seen[key] = struct{}{}
+			r := getRow(key)
+			r.inputs++
+			return r, nil
+		}
+
+		numberColumn := -1
+		for _, val := range vars[refID].Values {
+			switch v := val.(type) {
+			case mathexp.Number:
+				key, ok := v.GetLabels()[gj.Field]
+				if !ok {
+					continue
+				}
+				if numberColumn < 0 {
+					numberColumn = addField(refID, refID, data.FieldTypeNullableFloat64, nil, v.Frame.Fields[0].Config)
+				}
+				r, err := addRow(key)
+				if err != nil {
+					return nil, err
+				}
+				if value := v.GetFloat64Value(); value != nil {
+					r.values[numberColumn] = *value
+				}
+			case mathexp.TableData:
+				if err := gj.joinTable(v.Frame, refID, addField, addRow); err != nil {
+					return nil, err
+				}
+			case mathexp.NoData:
+			default:
+				return nil, fmt.Errorf("join on %s requires numbers or tables, but got %s from %s", JoinOnField, val.Type(), refID)
+			}
+		}
+	}
+
+	if gj.Mode == JoinModeInner {
+		rows = slices.DeleteFunc(rows, func(r *joinRow) bool {
+			return r.inputs < len(gj.VarsToJoin)
+		})
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(rows))
+	for _, r := range rows {
+		keys = append(keys, r.key)
+		for i, f := range fields {
+			f.Extend(1)
+			if v, ok := r.values[i]; ok {
+				f.SetConcrete(f.Len()-1, v)
+			}
+		}
+	}
+	frame := data.NewFrame(gj.refID, append([]*data.Field{data.NewField(gj.Field, nil, keys)}, fields...)...)
+	frame.RefID = gj.refID
+	return frame, nil
+}
+
+func (gj *JoinCommand) joinTable(
+	frame *data.Frame,
+	refID string,
+	addField func(refID, name string, fieldType data.FieldType, labels data.Labels, config *data.FieldConfig) int,
+	addRow func(key string) (*joinRow, error),
+) error {
+	if frame == nil {
+		return nil
+	}
+	keyIdx := -1
+	for i, f := range frame.Fields {
+		if f.Name == gj.Field {
+			keyIdx = i
+			break
+		}
+	}
+	if keyIdx < 0 {
+		return fmt.Errorf("join on %s requires field '%s', but %s does not have it", JoinOnField, gj.Field, refID)
+	}
+	columns := make(map[int]int, len(frame.Fields)-1)
+	for i, f := range frame.Fields {
+		if i == keyIdx {
+			continue
+		}
+		columns[i] = addField(refID, f.Name, f.Type(), f.Labels, f.Config)
+	}
+	for rowIdx := 0; rowIdx < frame.Rows(); rowIdx++ {
+		key, ok := frame.Fields[keyIdx].ConcreteAt(rowIdx)
+		if !ok {
+			continue
+		}
+		r, err := addRow(fmt.Sprintf("%v", key))
+		if err != nil {
+			return err
+		}
+		for i, column := range columns {
+			if v, ok := frame.Fields[i].ConcreteAt(rowIdx); ok {
+				r.values[column] = v
+			}
+		}
+	}
+	return nil
+}
+
+// readJoinedFrame converts the joined frame to values with the rules the ResultConverter applies to a single frame.
+func readJoinedFrame(frame *data.Frame) (mathexp.Results, error) {
+	if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeWide {
+		series, err := WideToMany(frame, nil)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		vals := make([]mathexp.Value, 0, len(series))
+		for _, s := range series {
+			vals = append(vals, s)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+	if isNumberTable(frame) && !hasNullStrings(frame) {
+		numberSet, err := extractNumberSet(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		vals := make([]mathexp.Value, 0, len(numberSet))
+		for _, n := range numberSet {
+			vals = append(vals, n)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+// hasNullStrings returns true if a string field of the frame has a null value, which happens when an outer join
+// has no row for a key in one of the tables. Such a frame cannot be read as numbers because every string field
+// becomes a label.
+func hasNullStrings(frame *data.Frame) bool {
+	for _, f := range frame.Fields {
+		if f.Type() != data.FieldTypeNullableString {
+			continue
+		}
+		for i := 0; i < f.Len(); i++ {
+			if _, ok := f.ConcreteAt(i); !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestNewJoinCommand(t *testing.T) {
+	t.Run("should set defaults", func(t *testing.T) {
+		cmd, err := NewJoinCommand("C", []string{"$A", "B"}, "", "", "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+		require.Equal(t, JoinModeOuter, cmd.Mode)
+		require.Equal(t, JoinOnTime, cmd.On)
+		require.Equal(t, "join", cmd.Type())
+	})
+
+	testCases := []struct {
+		name          string
+		vars          []string
+		mode          JoinMode
+		on            JoinOn
+		field         string
+		expectedError string
+	}{
+		{
+			name:          "fail with a single expression",
+			vars:          []string{"A"},
+			expectedError: "at least two expressions",
+		},
+		{
+			name:          "fail with empty expression",
+			vars:          []string{"A", "$"},
+			expectedError: "no variable specified",
+		},
+		{
+			name:          "fail with unknown mode",
+			vars:          []string{"A", "B"},
+			mode:          "left",
+			expectedError: "unsupported join mode",
+		},
+		{
+			name:          "fail with unknown key",
+			vars:          []string{"A", "B"},
+			on:            "labels",
+			expectedError: "unsupported join key",
+		},
+		{
+			name:          "fail to join on field without field",
+			vars:          []string{"A", "B"},
+			on:            JoinOnField,
+			expectedError: "requires the name of the label or field",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewJoinCommand("C", tc.vars, tc.mode, tc.on, tc.field)
+			require.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestUnmarshalJoinCommand(t *testing.T) {
+	query := `{"type": "join", "expressions": ["$A", "$B"], "mode": "inner", "on": "field", "field": "host"}`
+	var qmap = make(map[string]any)
+	require.NoError(t, json.Unmarshal([]byte(query), &qmap))
+
+	cmd, err := UnmarshalJoinCommand(&rawNode{RefID: "C", Query: qmap, QueryRaw: []byte(query)})
+	require.NoError(t, err)
+	require.Equal(t, &JoinCommand{
+		VarsToJoin: []string{"A", "B"},
+		Mode:       JoinModeInner,
+		On:         JoinOnField,
+		Field:      "host",
+		refID:      "C",
+	}, cmd)
+}
+
+func TestJoinCommandExecute(t *testing.T) {
+	tracer := tracing.InitializeTracerForTest()
+	series := func(refID string, labels data.Labels, points map[int64]float64) mathexp.Series {
+		s := mathexp.NewSeries(refID, labels, 0)
+		for _, ts := range []int64{1, 2, 3} {
+			if v, ok := points[ts]; ok {
+				s.AppendPoint(time.Unix(ts, 0), &v)
+			}
+		}
+		return s
+	}
+	number := func(refID string, labels data.Labels, value float64) mathexp.Number {
+		n := mathexp.NewNumber(refID, labels)
+		n.SetValue(&value)
+		return n
+	}
+	points := func(s mathexp.Series) map[int64]*float64 {
+		result := make(map[int64]*float64, s.Len())
+		for i := 0; i < s.Len(); i++ {
+			ts, v := s.GetPoint(i)
+			result[ts.Unix()] = v
+		}
+		return result
+	}
+
+	t.Run("should join series on time", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{series("A", data.Labels{"host": "a"}, map[int64]float64{1: 1, 2: 2})}},
+			"B": mathexp.Results{Values: mathexp.Values{series("B", data.Labels{"host": "b"}, map[int64]float64{2: 20, 3: 30})}},
+		}
+
+		outer, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeOuter, JoinOnTime, "")
+		require.NoError(t, err)
+		res, err := outer.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		a, b := res.Values[0].(mathexp.Series), res.Values[1].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a"}, a.GetLabels())
+		require.Equal(t, map[int64]*float64{1: fp(1), 2: fp(2), 3: nil}, points(a))
+		require.Equal(t, data.Labels{"host": "b"}, b.GetLabels())
+		require.Equal(t, map[int64]*float64{1: nil, 2: fp(20), 3: fp(30)}, points(b))
+
+		inner, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeInner, JoinOnTime, "")
+		require.NoError(t, err)
+		res, err = inner.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, map[int64]*float64{2: fp(2)}, points(res.Values[0].(mathexp.Series)))
+		require.Equal(t, map[int64]*float64{2: fp(20)}, points(res.Values[1].(mathexp.Series)))
+	})
+
+	t.Run("should keep the config of the value field of series", func(t *testing.T) {
+		// the value field comes before the time field and is moved by SeriesFromFrame
+		frame := data.NewFrame("",
+			data.NewField("value", data.Labels{"host": "a"}, []*float64{fp(1)}).SetConfig(&data.FieldConfig{Unit: "percent"}),
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+		)
+		a, err := mathexp.SeriesFromFrame(frame)
+		require.NoError(t, err)
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{a}},
+			"B": mathexp.Results{Values: mathexp.Values{series("B", data.Labels{"host": "b"}, map[int64]float64{1: 10})}},
+		}
+
+		cmd, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeOuter, JoinOnTime, "")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, &data.FieldConfig{Unit: "percent"}, res.Values[0].(mathexp.Series).GetConfig())
+		require.Nil(t, res.Values[1].(mathexp.Series).GetConfig())
+	})
+
+	t.Run("should return NoData if inner join on time has no common timestamps", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{series("A", nil, map[int64]float64{1: 1})}},
+			"B": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}},
+		}
+		cmd, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeInner, JoinOnTime, "")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{mathexp.NewNoData()}, res.Values)
+	})
+
+	t.Run("should join numbers on label into a table", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				number("A", data.Labels{"host": "a"}, 1),
+				number("A", data.Labels{"host": "b"}, 2),
+			}},
+			"B": mathexp.Results{Values: mathexp.Values{
+				number("B", data.Labels{"host": "a"}, 10),
+				number("B", data.Labels{"host": "c"}, 30),
+				number("B", data.Labels{"region": "eu"}, 40),
+			}},
+		}
+		cmd, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeOuter, JoinOnField, "host")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		table, ok := res.Values[0].(mathexp.TableData)
+		require.True(t, ok)
+
+		expected := data.NewFrame("C",
+			data.NewField("host", nil, []string{"a", "b", "c"}),
+			data.NewField("A", nil, []*float64{fp(1), fp(2), nil}),
+			data.NewField("B", nil, []*float64{fp(10), nil, fp(30)}),
+		)
+		expected.RefID = "C"
+		require.Equal(t, expected, table.Frame)
+	})
+
+	t.Run("should enrich numbers with the fields of a table", func(t *testing.T) {
+		region := data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "c"}),
+			data.NewField("region", nil, []string{"eu", "us"}),
+		)
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				number("A", data.Labels{"host": "a"}, 1),
+				number("A", data.Labels{"host": "b"}, 2),
+			}},
+			"B": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: region}}},
+		}
+		cmd, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeInner, JoinOnField, "host")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		n, ok := res.Values[0].(mathexp.Number)
+		require.True(t, ok)
+		require.Equal(t, data.Labels{"host": "a", "region": "eu"}, n.GetLabels())
+		require.Equal(t, fp(1), n.GetFloat64Value())
+	})
+
+	t.Run("should fail on duplicate keys", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{
+				number("A", data.Labels{"host": "a", "job": "x"}, 1),
+				number("A", data.Labels{"host": "a", "job": "y"}, 2),
+			}},
+			"B": mathexp.Results{Values: mathexp.Values{number("B", data.Labels{"host": "a"}, 10)}},
+		}
+		cmd, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeOuter, JoinOnField, "host")
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.ErrorContains(t, err, "requires unique keys")
+	})
+
+	t.Run("should fail on unsupported input", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{number("A", data.Labels{"host": "a"}, 1)}},
+			"B": mathexp.Results{Values: mathexp.Values{series("B", data.Labels{"host": "a"}, map[int64]float64{1: 1})}},
+		}
+		onTime, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeOuter, JoinOnTime, "")
+		require.NoError(t, err)
+		_, err = onTime.Execute(context.Background(), time.Now(), vars, tracer)
+		require.ErrorContains(t, err, "requires time series")
+
+		onField, err := NewJoinCommand("C", []string{"A", "B"}, JoinModeOuter, JoinOnField, "host")
+		require.NoError(t, err)
+		_, err = onField.Execute(context.Background(), time.Now(), vars, tracer)
+		require.ErrorContains(t, err, "requires numbers or tables")
+	})
+}
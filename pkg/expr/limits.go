@@ -0,0 +1,34 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// ErrSeriesLimitExceeded is returned by ExecutePipeline when a data source query returns more series than
+// the limit set with WithSeriesLimit. The pipeline stops at the first query that exceeds the limit.
+var ErrSeriesLimitExceeded = errors.New("series limit exceeded")
+
+type seriesLimitKey struct{}
+
+// WithSeriesLimit returns a copy of the context that limits the number of series each data source query of
+// a pipeline executed with it can return. A limit that is not positive returns the context as is.
+func WithSeriesLimit(ctx context.Context, limit int) context.Context {
+	if limit <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, seriesLimitKey{}, limit)
+}
+
+// checkSeriesLimit returns an error if the converted response of the query refID has more series than
+// the limit carried by the context.
+func checkSeriesLimit(ctx context.Context, refID string, result mathexp.Results) error {
+	limit, ok := ctx.Value(seriesLimitKey{}).(int)
+	if !ok || len(result.Values) <= limit {
+		return nil
+	}
+	return fmt.Errorf("%w: query %s returned %d series (limit: %d)", ErrSeriesLimitExceeded, refID, len(result.Values), limit)
+}
@@ -0,0 +1,190 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// aggregateGroup holds the items of a set that share the same grouping labels.
+type aggregateGroup struct {
+	labels data.Labels
+	values []Value
+}
+
+// walkAggregate aggregates the items of a NumberSet or SeriesSet into one item per
+// group, e.g. sum by (instance) ($A). Numbers are aggregated into a number, series
+// are aggregated point by point into a series with a point for each time of any of
+// the series. Null values are ignored.
+func (e *State) walkAggregate(node *parse.AggregateNode) (Results, error) {
+	res, err := e.walk(node.Arg)
+	if err != nil {
+		return Results{}, err
+	}
+
+	order := make([]string, 0, len(res.Values))
+	groups := map[string]*aggregateGroup{}
+	hasNoData := false
+	for _, val := range res.Values {
+		switch val.(type) {
+		case Number, Series:
+		case NoData:
+			hasNoData = true
+			continue
+		default:
+			return Results{}, fmt.Errorf("can not perform aggregation %s on type %v", node.Op, val.Type())
+		}
+		labels := groupingLabels(val.GetLabels(), node)
+		key := labels.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &aggregateGroup{labels: labels}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.values = append(g.values, val)
+	}
+
+	newRes := Results{}
+	if len(order) == 0 && hasNoData {
+		newRes.Values = append(newRes.Values, NewNoData())
+		return newRes, nil
+	}
+	for _, key := range order {
+		g := groups[key]
+		var unit string
+		if e.units {
+			unit, g.values, err = commonUnit(fmt.Sprintf("aggregate with %s", node.Op), g.values)
+			if err != nil {
+				return newRes, err
+			}
+		}
+		var value Value
+		switch g.values[0].(type) {
+		case Number:
+			value, err = e.aggregateNumbers(node.Op, g)
+		case Series:
+			value, err = e.aggregateSeries(node.Op, g)
+		}
+		if err != nil {
+			return newRes, err
+		}
+		if e.units && node.Op != "count" {
+			SetUnit(value, unit)
+		}
+		newRes.Values = append(newRes.Values, value)
+	}
+	return newRes, nil
+}
+
+func (e *State) aggregateNumbers(op string, g *aggregateGroup) (Number, error) {
+	n := NewNumber(e.RefID, g.labels)
+	values := make([]float64, 0, len(g.values))
+	for _, v := range g.values {
+		number, ok := v.(Number)
+		if !ok {
+			return n, fmt.Errorf("can not perform aggregation %s on numbers and series with the same labels %s", op, g.labels)
+		}
+		if f := number.GetFloat64Value(); f != nil {
+			values = append(values, *f)
+		}
+	}
+	f, err := aggregateFloats(op, values)
+	if err != nil {
+		return n, err
+	}
+	n.SetValue(f)
+	return n, nil
+}
+
+func (e *State) aggregateSeries(op string, g *aggregateGroup) (Series, error) {
+	times := map[int64]time.Time{}
+	points := map[int64][]float64{}
+	for _, v := range g.values {
+		s, ok := v.(Series)
+		if !ok {
+			return Series{}, fmt.Errorf("can not perform aggregation %s on numbers and series with the same labels %s", op, g.labels)
+		}
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			key := t.UnixNano()
+			if _, ok := times[key]; !ok {
+				times[key] = t
+				points[key] = nil
+			}
+			if f != nil {
+				points[key] = append(points[key], *f)
+			}
+		}
+	}
+
+	keys := make([]int64, 0, len(times))
+	for key := range times {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	newSeries := NewSeries(e.RefID, g.labels, len(keys))
+	for i, key := range keys {
+		f, err := aggregateFloats(op, points[key])
+		if err != nil {
+			return newSeries, err
+		}
+		newSeries.SetPoint(i, times[key], f)
+	}
+	return newSeries, nil
+}
+
+// aggregateFloats aggregates the values with the aggregation operator op. The
+// result is null if there are no values, except for count.
+func aggregateFloats(op string, values []float64) (*float64, error) {
+	var f float64
+	switch op {
+	case "count":
+		f = float64(len(values))
+		return &f, nil
+	case "sum", "avg", "min", "max":
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %s", op)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	switch op {
+	case "sum", "avg":
+		for _, v := range values {
+			f += v
+		}
+		if op == "avg" {
+			f /= float64(len(values))
+		}
+	case "min":
+		f = values[0]
+		for _, v := range values[1:] {
+			f = math.Min(f, v)
+		}
+	case "max":
+		f = values[0]
+		for _, v := range values[1:] {
+			f = math.Max(f, v)
+		}
+	}
+	return &f, nil
+}
+
+// groupingLabels returns the labels an item is grouped by in the aggregation.
+// Without grouping all items are aggregated into one item without labels.
+func groupingLabels(labels data.Labels, node *parse.AggregateNode) data.Labels {
+	if !node.Without && len(node.Grouping) == 0 {
+		return nil
+	}
+	grouped := matchingLabels(labels, node.Grouping, !node.Without)
+	if len(grouped) == 0 {
+		return nil
+	}
+	return grouped
+}
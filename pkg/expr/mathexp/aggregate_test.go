@@ -0,0 +1,131 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	numbers := Vars{
+		"A": resultValuesNoErr(
+			makeNumber("", data.Labels{"instance": "a", "cpu": "0"}, float64Pointer(1)),
+			makeNumber("", data.Labels{"instance": "a", "cpu": "1"}, float64Pointer(3)),
+			makeNumber("", data.Labels{"instance": "b", "cpu": "0"}, float64Pointer(8)),
+			makeNumber("", data.Labels{"instance": "b", "cpu": "1"}, nil),
+		),
+	}
+	series := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", data.Labels{"instance": "a", "cpu": "0"},
+				tp{time.Unix(5, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(2)}),
+			makeSeries("", data.Labels{"instance": "a", "cpu": "1"},
+				tp{time.Unix(10, 0), float64Pointer(4)},
+				tp{time.Unix(15, 0), nil}),
+		),
+	}
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "sum without grouping",
+			expr:      "sum($A)",
+			vars:      numbers,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(12))),
+		},
+		{
+			name:      "sum by label",
+			expr:      "sum by (instance) ($A)",
+			vars:      numbers,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a"}, float64Pointer(4)),
+				makeNumber("", data.Labels{"instance": "b"}, float64Pointer(8)),
+			),
+		},
+		{
+			name:      "avg without label with trailing grouping",
+			expr:      "avg($A) without (instance)",
+			vars:      numbers,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"cpu": "0"}, float64Pointer(4.5)),
+				makeNumber("", data.Labels{"cpu": "1"}, float64Pointer(3)),
+			),
+		},
+		{
+			name:      "count ignores null values",
+			expr:      "count by (instance) ($A)",
+			vars:      numbers,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a"}, float64Pointer(2)),
+				makeNumber("", data.Labels{"instance": "b"}, float64Pointer(1)),
+			),
+		},
+		{
+			name:      "max of series point by point",
+			expr:      "max by (instance) ($A)",
+			vars:      series,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"instance": "a"},
+					tp{time.Unix(5, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), float64Pointer(4)},
+					tp{time.Unix(15, 0), nil}),
+			),
+		},
+		{
+			name:      "aggregation in a binary operation",
+			expr:      "$A / on(instance) group_left sum by (instance) ($A)",
+			vars:      Vars{"A": resultValuesNoErr(numbers["A"].Values[:3]...)},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a", "cpu": "0"}, float64Pointer(0.25)),
+				makeNumber("", data.Labels{"instance": "a", "cpu": "1"}, float64Pointer(0.75)),
+				makeNumber("", data.Labels{"instance": "b", "cpu": "0"}, float64Pointer(1)),
+			),
+		},
+		{
+			name:     "aggregation of a scalar should error",
+			expr:     "sum(1)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "aggregation with unterminated grouping should error",
+			expr:     "sum by (instance ($A)",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if err != nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tt.results, res)
+		})
+	}
+}
@@ -29,6 +29,8 @@ type State struct {
 	RefID     string
 	Drops     map[string]map[string][]data.Labels // binary node text -> LH/RH -> Drop Labels
 	DropCount int64
+	// units is true if the units of the values are propagated and converted, see ExecuteWithUnits.
+	units bool
 
 	tracer tracing.Tracer
 }
@@ -61,6 +63,20 @@ func (e *Expr) Execute(refID string, vars Vars, tracer tracing.Tracer) (r Result
 	return e.executeState(s)
 }
 
+// ExecuteWithUnits is like Execute, but it also propagates the units of the values through the expression, and
+// converts the values of operations on units of the same kind. It fails if the units of an operation are incompatible.
+func (e *Expr) ExecuteWithUnits(refID string, vars Vars, tracer tracing.Tracer) (r Results, err error) {
+	s := &State{
+		Expr:  e,
+		Vars:  vars,
+		RefID: refID,
+		units: true,
+
+		tracer: tracer,
+	}
+	return e.executeState(s)
+}
+
 func (e *Expr) executeState(s *State) (r Results, err error) {
 	defer errRecover(&err, s)
 	r, err = s.walk(e.Tree.Root)
@@ -98,6 +114,8 @@ func (e *State) walk(node parse.Node) (res Results, err error) {
 		res, err = e.walkUnary(node)
 	case *parse.FuncNode:
 		res, err = e.walkFunc(node)
+	case *parse.AggregateNode:
+		res, err = e.walkAggregate(node)
 	default:
 		return res, fmt.Errorf("expr: can not walk node type: %s", node.Type())
 	}
@@ -135,6 +153,9 @@ func (e *State) walkUnary(node *parse.UnaryNode) (Results, error) {
 		if err != nil {
 			return newResults, err
 		}
+		if e.units && node.OpStr == "-" {
+			SetUnit(newVal, GetUnit(val))
+		}
 		newResults.Values = append(newResults.Values, newVal)
 	}
 	return newResults, nil
@@ -208,34 +229,10 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 		unions = append(unions, u)
 	}
 
-	aVar := biNode.Args[0].String()
-	bVar := biNode.Args[1].String()
-
 	aMatched := make([]bool, len(aResults.Values))
 	bMatched := make([]bool, len(bResults.Values))
 	collectDrops := func() {
-		check := func(v string, matchArray []bool, r *Results) {
-			for i, b := range matchArray {
-				if b {
-					continue
-				}
-				if e.Drops == nil {
-					e.Drops = make(map[string]map[string][]data.Labels)
-				}
-				if e.Drops[biNode.String()] == nil {
-					e.Drops[biNode.String()] = make(map[string][]data.Labels)
-				}
-
-				if r.Values[i].Type() == parse.TypeNoData {
-					continue
-				}
-
-				e.DropCount++
-				e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
-			}
-		}
-		check(aVar, aMatched, &aResults)
-		check(bVar, bMatched, &bResults)
+		e.collectDrops(biNode, aResults, bResults, aMatched, bMatched)
 	}
 
 	aValueLen := len(aResults.Values)
@@ -306,6 +303,33 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 	return unions
 }
 
+// collectDrops records the items on either side of the binary node that were
+// not matched with an item on the other side.
+func (e *State) collectDrops(biNode *parse.BinaryNode, aResults, bResults Results, aMatched, bMatched []bool) {
+	check := func(v string, matchArray []bool, r *Results) {
+		for i, b := range matchArray {
+			if b {
+				continue
+			}
+			if e.Drops == nil {
+				e.Drops = make(map[string]map[string][]data.Labels)
+			}
+			if e.Drops[biNode.String()] == nil {
+				e.Drops[biNode.String()] = make(map[string][]data.Labels)
+			}
+
+			if r.Values[i].Type() == parse.TypeNoData {
+				continue
+			}
+
+			e.DropCount++
+			e.Drops[biNode.String()][v] = append(e.Drops[biNode.String()][v], r.Values[i].GetLabels())
+		}
+	}
+	check(biNode.Args[0].String(), aMatched, &aResults)
+	check(biNode.Args[1].String(), bMatched, &bResults)
+}
+
 func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	res := Results{Values: Values{}}
 	ar, err := e.walk(node.Args[0])
@@ -316,8 +340,24 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
-	unions := e.union(ar, br, node)
+	var unions []*Union
+	if node.Matching != nil {
+		unions, err = e.matchingUnion(ar, br, node)
+		if err != nil {
+			return res, err
+		}
+	} else {
+		unions = e.union(ar, br, node)
+	}
 	for _, uni := range unions {
+		var units binaryUnits
+		if e.units {
+			units, err = binaryOpUnits(node.OpStr, GetUnit(uni.A), GetUnit(uni.B))
+			if err != nil {
+				return res, err
+			}
+			uni.A, uni.B = scaleValue(uni.A, units.aFactor), scaleValue(uni.B, units.bFactor)
+		}
 		var value Value
 		switch at := uni.A.(type) {
 		case Scalar:
@@ -391,6 +431,9 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 		if err != nil {
 			return res, err
 		}
+		if e.units {
+			SetUnit(value, units.unit)
+		}
 		res.Values = append(res.Values, value)
 	}
 	return res, nil
@@ -569,12 +612,16 @@ func (e *State) walkFunc(node *parse.FuncNode) (Results, error) {
 			v = e.Vars[t.Name]
 		case *parse.ScalarNode:
 			v = NewScalarResults(e.RefID, &t.Float64)
+		case *parse.DurationNode:
+			v = t.Duration
 		case *parse.FuncNode:
 			v, err = e.walkFunc(t)
 		case *parse.UnaryNode:
 			v, err = e.walkUnary(t)
 		case *parse.BinaryNode:
 			v, err = e.walkBinary(t)
+		case *parse.AggregateNode:
+			v, err = e.walkAggregate(t)
 		default:
 			return res, fmt.Errorf("expr: unknown func arg type: %T", t)
 		}
@@ -596,6 +643,11 @@ func (e *State) walkFunc(node *parse.FuncNode) (Results, error) {
 			return res, err
 		}
 	}
+	if e.units && len(in) > 0 {
+		if arg, ok := in[0].Interface().(Results); ok {
+			res = setFuncUnits(node.Name, arg, res)
+		}
+	}
 	return res, nil
 }
 
@@ -0,0 +1,246 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// shift moves each point of each series in the SeriesSet forward in time by d, so that
+// for example shift($A, 1w) can be compared to $A to see the change since last week.
+func shift(e *State, varSet Results, d time.Duration) (Results, error) {
+	return perSeries(e, "shift", varSet, func(s Series) (Series, error) {
+		newSeries := sortedCopy(e.RefID, s)
+		for i := 0; i < newSeries.Len(); i++ {
+			t, f := newSeries.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries, nil
+	})
+}
+
+// movingAvg returns for each point of each series in the SeriesSet the mean of the points
+// within the preceding window, including the point itself. Null values are ignored; a point
+// is null if there are no values within its window.
+func movingAvg(e *State, varSet Results, window time.Duration) (Results, error) {
+	if window <= 0 {
+		return Results{}, fmt.Errorf("moving_avg: window must be greater than zero, got %s", window)
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) (Series, error) {
+		sorted := sortedCopy(e.RefID, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), sorted.Len())
+		start := 0
+		for i := 0; i < sorted.Len(); i++ {
+			t := sorted.GetTime(i)
+			for !sorted.GetTime(start).After(t.Add(-window)) {
+				start++
+			}
+			var sum, count float64
+			for j := start; j <= i; j++ {
+				if f := sorted.GetValue(j); f != nil {
+					sum += *f
+					count++
+				}
+			}
+			var value *float64
+			if count > 0 {
+				avg := sum / count
+				value = &avg
+			}
+			newSeries.SetPoint(i, t, value)
+		}
+		return newSeries, nil
+	})
+}
+
+// delta returns for each point of each series in the SeriesSet the difference to the previous
+// point. The first point, and any point where either value is null, is null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) (Series, error) {
+		return perPointPair(e.RefID, s, func(_, _ time.Time, prev, cur float64) *float64 {
+			f := cur - prev
+			return &f
+		}), nil
+	})
+}
+
+// rate returns for each point of each series in the SeriesSet the per-second rate of increase
+// since the previous point. A value lower than the previous one is treated as a counter reset.
+// The first point, and any point where either value is null, is null.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) (Series, error) {
+		return perPointPair(e.RefID, s, func(prevTime, curTime time.Time, prev, cur float64) *float64 {
+			seconds := curTime.Sub(prevTime).Seconds()
+			if seconds <= 0 {
+				return nil
+			}
+			increase := cur - prev
+			if cur < prev {
+				increase = cur
+			}
+			f := increase / seconds
+			return &f
+		}), nil
+	})
+}
+
+// cumsum returns for each point of each series in the SeriesSet the sum of all values up to and
+// including the point. Null values are not added to the sum and stay null.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) (Series, error) {
+		newSeries := sortedCopy(e.RefID, s)
+		var sum float64
+		for i := 0; i < newSeries.Len(); i++ {
+			t, f := newSeries.GetPoint(i)
+			if f == nil {
+				continue
+			}
+			sum += *f
+			nF := sum
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries, nil
+	})
+}
+
+// clamp limits the value for each result in NumberSet, SeriesSet, or Scalar to the range [lo, hi].
+func clamp(e *State, varSet Results, loRes Results, hiRes Results) (Results, error) {
+	lo, err := scalarArg("clamp", loRes)
+	if err != nil {
+		return Results{}, err
+	}
+	hi, err := scalarArg("clamp", hiRes)
+	if err != nil {
+		return Results{}, err
+	}
+	if lo > hi {
+		return Results{}, fmt.Errorf("clamp: lower bound %v is greater than upper bound %v", lo, hi)
+	}
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			return math.Max(lo, math.Min(hi, f))
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// holtWinters applies double exponential smoothing to each series in the SeriesSet, with the
+// smoothing factor sf and the trend factor tf, both between 0 and 1. Each point is replaced by
+// the smoothed value at that point. Null values are skipped and stay null.
+func holtWinters(e *State, varSet Results, sfRes Results, tfRes Results) (Results, error) {
+	sf, err := scalarArg("holt_winters", sfRes)
+	if err != nil {
+		return Results{}, err
+	}
+	tf, err := scalarArg("holt_winters", tfRes)
+	if err != nil {
+		return Results{}, err
+	}
+	if sf <= 0 || sf >= 1 {
+		return Results{}, fmt.Errorf("holt_winters: smoothing factor must be between 0 and 1, got %v", sf)
+	}
+	if tf <= 0 || tf >= 1 {
+		return Results{}, fmt.Errorf("holt_winters: trend factor must be between 0 and 1, got %v", tf)
+	}
+	return perSeries(e, "holt_winters", varSet, func(s Series) (Series, error) {
+		newSeries := sortedCopy(e.RefID, s)
+		var level, trend float64
+		seen := 0
+		for i := 0; i < newSeries.Len(); i++ {
+			t, f := newSeries.GetPoint(i)
+			if f == nil {
+				continue
+			}
+			switch seen {
+			case 0:
+				level = *f
+			case 1:
+				trend = *f - level
+				fallthrough
+			default:
+				prevLevel := level
+				level = sf*(*f) + (1-sf)*(level+trend)
+				trend = tf*(level-prevLevel) + (1-tf)*trend
+			}
+			seen++
+			nF := level
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries, nil
+	})
+}
+
+// perSeries passes each Series in varSet to seriesF. NoData is passed through and any other
+// type is an error, since the function name requires time series.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) (Series, error)) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newSeries, err := seriesF(v)
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, newSeries)
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a time series, got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// perPointPair returns a copy of the series where each point is the result of pairF applied
+// to the point and the previous one. The first point, and any point where either value is
+// null, is null.
+func perPointPair(refID string, s Series, pairF func(prevTime, curTime time.Time, prev, cur float64) *float64) Series {
+	sorted := sortedCopy(refID, s)
+	newSeries := NewSeries(refID, s.GetLabels(), sorted.Len())
+	for i := 0; i < sorted.Len(); i++ {
+		t, cur := sorted.GetPoint(i)
+		var value *float64
+		if i > 0 {
+			prevTime, prev := sorted.GetPoint(i - 1)
+			if prev != nil && cur != nil {
+				value = pairF(prevTime, t, *prev, *cur)
+			}
+		}
+		newSeries.SetPoint(i, t, value)
+	}
+	return newSeries
+}
+
+// sortedCopy returns a copy of the series sorted by time from oldest to newest.
+func sortedCopy(refID string, s Series) Series {
+	newSeries := NewSeries(refID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		if f != nil {
+			nF := *f
+			f = &nF
+		}
+		newSeries.SetPoint(i, t, f)
+	}
+	newSeries.SortByTime(false)
+	return newSeries
+}
+
+// scalarArg returns the value of a scalar function argument.
+func scalarArg(name string, res Results) (float64, error) {
+	if len(res.Values) != 1 || res.Values[0].Type() != parse.TypeScalar {
+		return 0, fmt.Errorf("%s: expected a scalar argument", name)
+	}
+	f := res.Values[0].(Scalar).GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("%s: scalar argument must not be null", name)
+	}
+	return *f, nil
+}
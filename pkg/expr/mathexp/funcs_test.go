@@ -140,3 +140,198 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	counter := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(3)},
+				tp{time.Unix(120, 0), float64Pointer(6)},
+				tp{time.Unix(180, 0), float64Pointer(2)}),
+		),
+	}
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "shift series by a duration",
+			expr:      "shift($A, 1h)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(3600, 0), float64Pointer(1)},
+					tp{time.Unix(3660, 0), float64Pointer(3)},
+					tp{time.Unix(3720, 0), float64Pointer(6)},
+					tp{time.Unix(3780, 0), float64Pointer(2)}),
+			),
+		},
+		{
+			name:      "moving average over a window",
+			expr:      "moving_avg($A, 2m)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(60, 0), float64Pointer(2)},
+					tp{time.Unix(120, 0), float64Pointer(4.5)},
+					tp{time.Unix(180, 0), float64Pointer(4)}),
+			),
+		},
+		{
+			name:      "delta between points",
+			expr:      "delta($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(60, 0), float64Pointer(2)},
+					tp{time.Unix(120, 0), float64Pointer(3)},
+					tp{time.Unix(180, 0), float64Pointer(-4)}),
+			),
+		},
+		{
+			name:      "rate with a counter reset",
+			expr:      "rate($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(60, 0), float64Pointer(2.0 / 60)},
+					tp{time.Unix(120, 0), float64Pointer(3.0 / 60)},
+					tp{time.Unix(180, 0), float64Pointer(2.0 / 60)}),
+			),
+		},
+		{
+			name:      "cumulative sum",
+			expr:      "cumsum($A)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(60, 0), float64Pointer(4)},
+					tp{time.Unix(120, 0), float64Pointer(10)},
+					tp{time.Unix(180, 0), float64Pointer(12)}),
+			),
+		},
+		{
+			name:      "clamp series",
+			expr:      "clamp($A, 2, 5)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(2)},
+					tp{time.Unix(60, 0), float64Pointer(3)},
+					tp{time.Unix(120, 0), float64Pointer(5)},
+					tp{time.Unix(180, 0), float64Pointer(2)}),
+			),
+		},
+		{
+			name: "clamp number",
+			expr: "clamp($A, -1, 1)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(7))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:      "holt winters smoothing",
+			expr:      "holt_winters($A, 0.5, 0.5)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(60, 0), float64Pointer(3)},
+					tp{time.Unix(120, 0), float64Pointer(5.5)},
+					tp{time.Unix(180, 0), float64Pointer(4.875)}),
+			),
+		},
+		{
+			name:      "compare series with its shifted self",
+			expr:      "$A - shift($A, 1m)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", nil,
+					tp{time.Unix(60, 0), float64Pointer(2)},
+					tp{time.Unix(120, 0), float64Pointer(3)},
+					tp{time.Unix(180, 0), float64Pointer(-4)}),
+			),
+		},
+		{
+			name:      "clamp with inverted bounds should error",
+			expr:      "clamp($A, 5, 2)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "holt winters with invalid smoothing factor should error",
+			expr:      "holt_winters($A, 1, 0.5)",
+			vars:      counter,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name: "rate on number should error",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(7))),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "shift by a number should error",
+			expr:     "shift($A, 60)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "duration outside a function should error",
+			expr:     "$A + 1h",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "invalid duration unit should error",
+			expr:     "shift($A, 1x)",
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if err != nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tt.results, res)
+		})
+	}
+}
@@ -56,8 +56,9 @@ const (
 	itemRightParen
 	itemString
 	itemFunc
-	itemVar // e.g. $A
-	itemPow // '**'
+	itemVar      // e.g. $A
+	itemPow      // '**'
+	itemDuration // e.g. 5m or 1h30m
 )
 
 const eof = -1
@@ -210,6 +211,15 @@ func lexNumber(l *lexer) stateFn {
 	if !l.scanNumber() {
 		return l.errorf("bad number syntax: %q", l.input[l.start:l.pos])
 	}
+	// A number directly followed by a unit, e.g. 5m or 1h30m, is a duration.
+	// The parser validates the units.
+	if r := l.peek(); unicode.IsLetter(r) {
+		for r := l.next(); isVarchar(r); r = l.next() {
+		}
+		l.backup()
+		l.emit(itemDuration)
+		return lexItem
+	}
 	l.emit(itemNumber)
 	return lexItem
 }
@@ -276,7 +286,7 @@ func lexSymbol(l *lexer) stateFn {
 func lexFunc(l *lexer) stateFn {
 	for {
 		switch r := l.next(); {
-		case unicode.IsLetter(r) || r == '_':
+		case unicode.IsLetter(r) || r == '_' || unicode.IsDigit(r):
 			// absorb
 		default:
 			l.backup()
@@ -8,7 +8,11 @@ package parse
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -62,6 +66,10 @@ const (
 	NodeNumber
 	// NodeVar is variable: $A
 	NodeVar
+	// NodeDuration is a duration constant: 5m
+	NodeDuration
+	// NodeAggregate is an aggregation over a set: sum by (instance) ($A)
+	NodeAggregate
 )
 
 // String returns the string representation of the NodeType
@@ -79,6 +87,10 @@ func (t NodeType) String() string {
 		return "NodeNumber"
 	case NodeVar:
 		return "NodeVar"
+	case NodeDuration:
+		return "NodeDuration"
+	case NodeAggregate:
+		return "NodeAggregate"
 	default:
 		return "NodeUnknown"
 	}
@@ -293,6 +305,149 @@ func (s *StringNode) Return() ReturnType {
 	return TypeString
 }
 
+// DurationNode holds a duration constant such as 5m or 1h30m.
+type DurationNode struct {
+	NodeType
+	Pos
+	Duration time.Duration // The parsed duration.
+	Text     string        // The original textual representation from the input.
+}
+
+func newDuration(pos Pos, text string) (*DurationNode, error) {
+	d, err := parseDuration(text)
+	if err != nil {
+		return nil, err
+	}
+	return &DurationNode{NodeType: NodeDuration, Pos: pos, Duration: d, Text: text}, nil
+}
+
+// durationUnits are the units a duration literal may use. Days, weeks and
+// years have a fixed length and do not account for daylight saving time or leap years.
+var durationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+// parseDuration parses a duration literal made of one or more integers each
+// followed by a unit, e.g. 90s, 1h30m or 1w.
+func parseDuration(text string) (time.Duration, error) {
+	var d time.Duration
+	s := text
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return 0, fmt.Errorf("illegal duration syntax: %q", text)
+		}
+		n, err := strconv.ParseInt(s[:i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("illegal duration syntax: %q", text)
+		}
+		s = s[i:]
+		j := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' })
+		if j < 0 {
+			j = len(s)
+		}
+		unit, ok := durationUnits[s[:j]]
+		if !ok {
+			return 0, fmt.Errorf("illegal duration syntax: %q: unknown unit %q", text, s[:j])
+		}
+		s = s[j:]
+		if n > int64(math.MaxInt64/unit) {
+			return 0, fmt.Errorf("illegal duration syntax: %q: duration out of range", text)
+		}
+		d += time.Duration(n) * unit
+		if d < 0 {
+			return 0, fmt.Errorf("illegal duration syntax: %q: duration out of range", text)
+		}
+	}
+	return d, nil
+}
+
+// String returns the string representation of the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) String() string {
+	return d.Text
+}
+
+// StringAST returns the string representation of abstract syntax tree of the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) StringAST() string {
+	return d.String()
+}
+
+// Check performs parse time checking on the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) Check(*Tree) error {
+	return nil
+}
+
+// Return returns the result type of the DurationNode so it fulfills the Node interface.
+func (d *DurationNode) Return() ReturnType {
+	return TypeDuration
+}
+
+// VectorMatchCardinality describes how many items on each side of a binary
+// operation may match each other.
+type VectorMatchCardinality int
+
+const (
+	// CardOneToOne matches each item with at most one item on the other side.
+	CardOneToOne VectorMatchCardinality = iota
+	// CardManyToOne matches many items on the left with one item on the right (group_left).
+	CardManyToOne
+	// CardOneToMany matches one item on the left with many items on the right (group_right).
+	CardOneToMany
+)
+
+// VectorMatching describes how the items of both sides of a binary operation
+// are matched by their labels, e.g. $A / on(instance) group_left(job) $B.
+type VectorMatching struct {
+	Card VectorMatchCardinality
+	// On is true if the items are matched on MatchingLabels only (on), and false
+	// if they are matched on all labels but MatchingLabels (ignoring).
+	On             bool
+	MatchingLabels []string
+	// Include are labels copied from the "one" side to the result of a
+	// many-to-one or one-to-many match.
+	Include []string
+}
+
+// String returns the string representation of the VectorMatching.
+func (m *VectorMatching) String() string {
+	s := "ignoring"
+	if m.On {
+		s = "on"
+	}
+	s += "(" + joinLabels(m.MatchingLabels) + ")"
+	switch m.Card {
+	case CardManyToOne:
+		s += " group_left"
+	case CardOneToMany:
+		s += " group_right"
+	default:
+		return s
+	}
+	if len(m.Include) > 0 {
+		s += "(" + joinLabels(m.Include) + ")"
+	}
+	return s
+}
+
+// joinLabels returns the label names as a comma separated list, quoting the
+// names that could not be parsed unquoted.
+func joinLabels(labels []string) string {
+	quoted := make([]string, len(labels))
+	for i, l := range labels {
+		quoted[i] = l
+		if l == "" || strings.IndexFunc(l, func(r rune) bool { return !isVarchar(r) }) >= 0 || !unicode.IsLetter([]rune(l)[0]) {
+			quoted[i] = strconv.Quote(l)
+		}
+	}
+	return strings.Join(quoted, ", ")
+}
+
 // BinaryNode holds two arguments and an operator.
 type BinaryNode struct {
 	NodeType
@@ -300,6 +455,7 @@ type BinaryNode struct {
 	Args     [2]Node
 	Operator item
 	OpStr    string
+	Matching *VectorMatching // Optional, nil if the items are matched on all labels.
 }
 
 func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
@@ -308,16 +464,39 @@ func newBinary(operator item, arg1, arg2 Node) *BinaryNode {
 
 // String returns the string representation of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) String() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s %s %s", b.Args[0], b.Operator.val, b.Matching, b.Args[1])
+	}
 	return fmt.Sprintf("%s %s %s", b.Args[0], b.Operator.val, b.Args[1])
 }
 
 // StringAST returns the string representation of abstract syntax tree of the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) StringAST() string {
+	if b.Matching != nil {
+		return fmt.Sprintf("%s %s(%s, %s)", b.Operator.val, b.Matching, b.Args[0], b.Args[1])
+	}
 	return fmt.Sprintf("%s(%s, %s)", b.Operator.val, b.Args[0], b.Args[1])
 }
 
 // Check performs parse time checking on the BinaryNode so it fulfills the Node interface.
 func (b *BinaryNode) Check(t *Tree) error {
+	for _, arg := range b.Args {
+		if arg.Return() == TypeDuration {
+			return fmt.Errorf("parse: type error in %s, durations can only be used as function arguments", b)
+		}
+		if b.Matching != nil && arg.Return() == TypeScalar {
+			return fmt.Errorf("parse: vector matching in %s is only allowed between series or numbers", b)
+		}
+	}
+	if b.Matching != nil && b.Matching.On {
+		for _, l := range b.Matching.Include {
+			for _, m := range b.Matching.MatchingLabels {
+				if l == m {
+					return fmt.Errorf("parse: label %q must not occur in on and group clause at once in %s", l, b)
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -369,6 +548,56 @@ func (u *UnaryNode) Return() ReturnType {
 	return u.Arg.Return()
 }
 
+// AggregateNode holds an aggregation over the items of a set, e.g. sum by (instance) ($A).
+type AggregateNode struct {
+	NodeType
+	Pos
+	Op       string   // The aggregation operator, e.g. sum.
+	Grouping []string // The labels to group by, or to drop if Without is set.
+	Without  bool
+	Arg      Node
+}
+
+func newAggregate(pos Pos, op string) *AggregateNode {
+	return &AggregateNode{NodeType: NodeAggregate, Pos: pos, Op: op}
+}
+
+// String returns the string representation of the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) String() string {
+	return fmt.Sprintf("%s%s(%s)", a.Op, a.grouping(), a.Arg)
+}
+
+// StringAST returns the string representation of abstract syntax tree of the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) StringAST() string {
+	return fmt.Sprintf("%s%s(%s)", a.Op, a.grouping(), a.Arg.StringAST())
+}
+
+func (a *AggregateNode) grouping() string {
+	switch {
+	case a.Without:
+		return " without (" + joinLabels(a.Grouping) + ") "
+	case len(a.Grouping) > 0:
+		return " by (" + joinLabels(a.Grouping) + ") "
+	default:
+		return ""
+	}
+}
+
+// Check performs parse time checking on the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) Check(t *Tree) error {
+	switch rt := a.Arg.Return(); rt {
+	case TypeNumberSet, TypeSeriesSet:
+		return a.Arg.Check(t)
+	default:
+		return fmt.Errorf(`parse: type error in %s, expected "numberSet" or "seriesSet", got %s`, a, rt)
+	}
+}
+
+// Return returns the result type of the AggregateNode so it fulfills the Node interface.
+func (a *AggregateNode) Return() ReturnType {
+	return a.Arg.Return()
+}
+
 // Walk invokes f on n and sub-nodes of n.
 func Walk(n Node, f func(Node)) {
 	f(n)
@@ -380,10 +609,12 @@ func Walk(n Node, f func(Node)) {
 		for _, a := range n.Args {
 			Walk(a, f)
 		}
-	case *ScalarNode, *StringNode:
+	case *ScalarNode, *StringNode, *DurationNode:
 		// Ignore since these node types have no sub nodes.
 	case *UnaryNode:
 		Walk(n.Arg, f)
+	case *AggregateNode:
+		Walk(n.Arg, f)
 	default:
 		panic(fmt.Errorf("other type: %T", n))
 	}
@@ -407,6 +638,8 @@ const (
 	TypeNoData
 	// TypeTableData is a tabular data response.
 	TypeTableData
+	// TypeDuration is a duration constant, only valid as a function argument.
+	TypeDuration
 )
 
 // String returns a string representation of the ReturnType.
@@ -426,6 +659,8 @@ func (f ReturnType) String() string {
 		return "noData"
 	case TypeTableData:
 		return "tableData"
+	case TypeDuration:
+		return "duration"
 	default:
 		return "unknown"
 	}
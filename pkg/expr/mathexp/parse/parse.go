@@ -159,22 +159,29 @@ func (t *Tree) Parse(text string, funcs ...map[string]Func) (err error) {
 func (t *Tree) parse() {
 	t.Root = t.O()
 	t.expect(itemEOF, "root input")
+	if _, ok := t.Root.(*DurationNode); ok {
+		t.errorf("unexpected duration %s, durations can only be used as function arguments", t.Root)
+	}
 	if err := t.Root.Check(t); err != nil {
 		t.error(err)
 	}
 }
 
 /* Grammar:
-O -> A {"||" A}
-A -> C {"&&" C}
-C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") P}
-P -> M {( "+" | "-" ) M}
-M -> E {( "*" | "/" ) F}
-E -> F {( "**" ) F}
+O -> A {"||" [matching] A}
+A -> C {"&&" [matching] C}
+C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=") [matching] P}
+P -> M {( "+" | "-" ) [matching] M}
+M -> E {( "*" | "/" ) [matching] F}
+E -> F {( "**" ) [matching] F}
 F -> v | "(" O ")" | "!" O | "-" O
-v -> number | func(..) | queryVar
+v -> number | duration | func(..) | aggregate | queryVar
 Func -> name "(" param {"," param} ")"
-param -> number | "string" | queryVar
+param -> number | duration | "string" | queryVar
+aggregate -> op [grouping] "(" O ")" [grouping]
+grouping -> ( "by" | "without" ) labels
+matching -> ( "on" | "ignoring" ) labels [( "group_left" | "group_right" ) [labels]]
+labels -> "(" [label {"," label}] ")"
 */
 
 // expr:
@@ -185,7 +192,7 @@ func (t *Tree) O() Node {
 	for {
 		switch t.peek().typ {
 		case itemOr:
-			n = newBinary(t.next(), n, t.A())
+			n = t.binary(n, t.A)
 		default:
 			return n
 		}
@@ -198,7 +205,7 @@ func (t *Tree) A() Node {
 	for {
 		switch t.peek().typ {
 		case itemAnd:
-			n = newBinary(t.next(), n, t.C())
+			n = t.binary(n, t.C)
 		default:
 			return n
 		}
@@ -211,7 +218,7 @@ func (t *Tree) C() Node {
 	for {
 		switch t.peek().typ {
 		case itemEq, itemNotEq, itemGreater, itemGreaterEq, itemLess, itemLessEq:
-			n = newBinary(t.next(), n, t.P())
+			n = t.binary(n, t.P)
 		default:
 			return n
 		}
@@ -224,7 +231,7 @@ func (t *Tree) P() Node {
 	for {
 		switch t.peek().typ {
 		case itemPlus, itemMinus:
-			n = newBinary(t.next(), n, t.M())
+			n = t.binary(n, t.M)
 		default:
 			return n
 		}
@@ -237,7 +244,7 @@ func (t *Tree) M() Node {
 	for {
 		switch t.peek().typ {
 		case itemMult, itemDiv, itemMod:
-			n = newBinary(t.next(), n, t.E())
+			n = t.binary(n, t.E)
 		default:
 			return n
 		}
@@ -250,7 +257,7 @@ func (t *Tree) E() Node {
 	for {
 		switch t.peek().typ {
 		case itemPow:
-			n = newBinary(t.next(), n, t.F())
+			n = t.binary(n, t.F)
 		default:
 			return n
 		}
@@ -260,7 +267,7 @@ func (t *Tree) E() Node {
 // F is v | "(" O ")" | "!" O | "-" O in the grammar.
 func (t *Tree) F() Node {
 	switch token := t.peek(); token.typ {
-	case itemNumber, itemFunc, itemVar:
+	case itemNumber, itemDuration, itemFunc, itemVar:
 		return t.v()
 	case itemNot, itemMinus:
 		return newUnary(t.next(), t.F())
@@ -275,7 +282,7 @@ func (t *Tree) F() Node {
 	return nil
 }
 
-// V is number | func(..) | queryVar in the grammar.
+// V is number | duration | func(..) | queryVar in the grammar.
 func (t *Tree) v() Node {
 	switch token := t.next(); token.typ {
 	case itemNumber:
@@ -284,8 +291,17 @@ func (t *Tree) v() Node {
 			t.error(err)
 		}
 		return n
+	case itemDuration:
+		d, err := newDuration(token.pos, token.val)
+		if err != nil {
+			t.error(err)
+		}
+		return d
 	case itemFunc:
 		t.backup()
+		if _, ok := t.GetFunction(token.val); !ok && aggregateOps[token.val] {
+			return t.Aggregate()
+		}
 		return t.Func()
 	case itemVar:
 		t.backup()
@@ -296,6 +312,123 @@ func (t *Tree) v() Node {
 	return nil
 }
 
+// binary parses the operator and the optional vector matching of a binary
+// operation with the left operand n. operand parses the right operand.
+func (t *Tree) binary(n Node, operand func() Node) Node {
+	operator := t.next()
+	matching := t.vectorMatching()
+	b := newBinary(operator, n, operand())
+	b.Matching = matching
+	return b
+}
+
+// vectorMatching parses the optional matching in the grammar. It returns nil if
+// there is none.
+func (t *Tree) vectorMatching() *VectorMatching {
+	token := t.peek()
+	if token.typ != itemFunc {
+		return nil
+	}
+	switch token.val {
+	case "on", "ignoring":
+	case "group_left", "group_right":
+		t.errorf("%s must be preceded by on or ignoring", token.val)
+	default:
+		return nil
+	}
+	t.next()
+	m := &VectorMatching{
+		Card:           CardOneToOne,
+		On:             token.val == "on",
+		MatchingLabels: t.labels(token.val),
+	}
+	token = t.peek()
+	if token.typ != itemFunc {
+		return m
+	}
+	switch token.val {
+	case "group_left":
+		m.Card = CardManyToOne
+	case "group_right":
+		m.Card = CardOneToMany
+	default:
+		return m
+	}
+	t.next()
+	if t.peek().typ == itemLeftParen {
+		m.Include = t.labels(token.val)
+	}
+	return m
+}
+
+// aggregateOps are the operators that can be used in an aggregation.
+var aggregateOps = map[string]bool{
+	"sum":   true,
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+	"count": true,
+}
+
+// Aggregate parses an AggregateNode.
+func (t *Tree) Aggregate() *AggregateNode {
+	token := t.next()
+	a := newAggregate(token.pos, token.val)
+	grouped := t.grouping(a)
+	t.expect(itemLeftParen, "aggregation")
+	a.Arg = t.O()
+	t.expect(itemRightParen, "aggregation")
+	if !grouped {
+		t.grouping(a)
+	}
+	return a
+}
+
+// grouping parses the optional grouping of an aggregation and reports whether
+// there was one.
+func (t *Tree) grouping(a *AggregateNode) bool {
+	token := t.peek()
+	if token.typ != itemFunc || (token.val != "by" && token.val != "without") {
+		return false
+	}
+	t.next()
+	a.Without = token.val == "without"
+	a.Grouping = t.labels(token.val)
+	return true
+}
+
+// labels parses a parenthesized, comma separated list of label names. A label
+// name can be quoted if it contains characters other than letters, digits and
+// underscores.
+func (t *Tree) labels(context string) []string {
+	labels := []string{}
+	t.expect(itemLeftParen, context)
+	for {
+		switch token := t.next(); token.typ {
+		case itemFunc:
+			labels = append(labels, token.val)
+		case itemString:
+			l, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			labels = append(labels, l)
+		case itemRightParen:
+			return labels
+		default:
+			t.unexpected(token, context)
+		}
+		switch token := t.next(); token.typ {
+		case itemComma:
+			// continue with the next label
+		case itemRightParen:
+			return labels
+		default:
+			t.unexpected(token, context)
+		}
+	}
+}
+
 // Var is queryVar in the grammar.
 func (t *Tree) Var() (v *VarNode) {
 	token := t.next()
@@ -332,6 +465,14 @@ func (t *Tree) Func() (f *FuncNode) {
 		case itemRightParen:
 			return
 		}
+		switch token = t.next(); token.typ {
+		case itemComma:
+			// continue with the next argument
+		case itemRightParen:
+			return
+		default:
+			t.unexpected(token, "func")
+		}
 	}
 }
 
@@ -4,29 +4,53 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
 type ReducerFunc = func(fv *Float64Field) *float64
 
+// TimeReducerFunc is a reducer that needs the time of each value in addition to the value itself.
+type TimeReducerFunc = func(times []time.Time, fv *Float64Field) *float64
+
 // The reducer function
 // +enum
 type ReducerID string
 
 const (
-	ReducerSum    ReducerID = "sum"
-	ReducerMean   ReducerID = "mean"
-	ReducerMin    ReducerID = "min"
-	ReducerMax    ReducerID = "max"
-	ReducerCount  ReducerID = "count"
-	ReducerLast   ReducerID = "last"
-	ReducerMedian ReducerID = "median"
+	ReducerSum          ReducerID = "sum"
+	ReducerMean         ReducerID = "mean"
+	ReducerMin          ReducerID = "min"
+	ReducerMax          ReducerID = "max"
+	ReducerCount        ReducerID = "count"
+	ReducerLast         ReducerID = "last"
+	ReducerMedian       ReducerID = "median"
+	ReducerFirst        ReducerID = "first"
+	ReducerStdDev       ReducerID = "stddev"
+	ReducerVariance     ReducerID = "variance"
+	ReducerRange        ReducerID = "range"
+	ReducerDiff         ReducerID = "diff"
+	ReducerIncrease     ReducerID = "increase"
+	ReducerRate         ReducerID = "rate"
+	ReducerCountNonNull ReducerID = "count_non_null"
+	ReducerP50          ReducerID = "p50"
+	ReducerP75          ReducerID = "p75"
+	ReducerP90          ReducerID = "p90"
+	ReducerP95          ReducerID = "p95"
+	ReducerP99          ReducerID = "p99"
 )
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSupportedReduceFuncs returns collection of supported function names.
+// Besides the listed percentiles, any percentile in the form pNN (for example p99.9) is supported.
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{
+		ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerFirst, ReducerStdDev, ReducerVariance, ReducerRange, ReducerDiff, ReducerIncrease, ReducerRate,
+		ReducerCountNonNull, ReducerP50, ReducerP75, ReducerP90, ReducerP95, ReducerP99,
+	}
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -126,6 +150,164 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// numbers returns the values of the field, or false if any of them is null or NaN.
+func numbers(fv *Float64Field) ([]float64, bool) {
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return nil, false
+		}
+		values = append(values, *v)
+	}
+	return values, true
+}
+
+// Variance returns the population variance of the values.
+func Variance(fv *Float64Field) *float64 {
+	values, ok := numbers(fv)
+	if !ok || len(values) == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	var mean, m2 float64
+	for i, v := range values {
+		delta := v - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (v - mean)
+	}
+	f := m2 / float64(len(values))
+	return &f
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	f := math.Sqrt(*Variance(fv))
+	return &f
+}
+
+// Range returns the difference between the largest and the smallest value.
+func Range(fv *Float64Field) *float64 {
+	f := *Max(fv) - *Min(fv)
+	return &f
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	first, last := First(fv), Last(fv)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Increase returns the increase of a counter over the values. A value lower
+// than its predecessor is treated as a counter reset, so the counter
+// restarted from zero. At least two values are required.
+func Increase(fv *Float64Field) *float64 {
+	values, ok := numbers(fv)
+	if !ok || len(values) < 2 {
+		nan := math.NaN()
+		return &nan
+	}
+	var f float64
+	for i := 1; i < len(values); i++ {
+		if values[i] < values[i-1] {
+			f += values[i]
+			continue
+		}
+		f += values[i] - values[i-1]
+	}
+	return &f
+}
+
+// Rate returns the per-second rate of increase of a counter between the
+// first and the last value, handling counter resets as Increase does. The
+// result is not extrapolated to the boundaries of the time range.
+func Rate(times []time.Time, fv *Float64Field) *float64 {
+	increase := Increase(fv)
+	if math.IsNaN(*increase) || len(times) < 2 {
+		return increase
+	}
+	seconds := times[len(times)-1].Sub(times[0]).Seconds()
+	if seconds <= 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *increase / seconds
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		if v := fv.GetValue(i); v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// Percentile returns a reducer for the p-th percentile (0 <= p <= 100), linearly
+// interpolating between the closest ranks. The 50th percentile is the median.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values, ok := numbers(fv)
+		if !ok || len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+		sort.Float64s(values)
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// parsePercentile parses reducer IDs in the form pNN, for example p95 or p99.9.
+func parsePercentile(rFunc ReducerID) (float64, bool) {
+	s := string(rFunc)
+	if !strings.HasPrefix(s, "p") {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(s[1:], 64)
+	if err != nil || p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, false
+	}
+	return p, true
+}
+
+// GetTimeReduceFunc returns the reducer for rFunc. Unlike GetReduceFunc it
+// supports reducers that depend on the time of the values, such as rate.
+func GetTimeReduceFunc(rFunc ReducerID) (TimeReducerFunc, error) {
+	if rFunc == ReducerRate {
+		return Rate, nil
+	}
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return nil, err
+	}
+	return func(_ []time.Time, fv *Float64Field) *float64 {
+		return reduceFunc(fv)
+	}, nil
+}
+
+// GetReduceFunc returns the reducer for rFunc. Reducers that depend on the
+// time of the values, such as rate, are only available from GetTimeReduceFunc.
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,7 +324,26 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerVariance:
+		return Variance, nil
+	case ReducerRange:
+		return Range, nil
+	case ReducerDiff:
+		return Diff, nil
+	case ReducerIncrease:
+		return Increase, nil
+	case ReducerCountNonNull:
+		return CountNonNull, nil
+	case ReducerRate:
+		return nil, fmt.Errorf("reduction %v requires the time of the values", rFunc)
 	default:
+		if p, ok := parsePercentile(rFunc); ok {
+			return Percentile(p), nil
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
@@ -163,11 +364,15 @@ func (s Series) Reduce(refID string, rFunc ReducerID, mapper ReduceMapper) (Numb
 	}
 	fVec := series.Frame.Fields[seriesTypeValIdx]
 	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetTimeReduceFunc(rFunc)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	times := make([]time.Time, series.Len())
+	for i := range times {
+		times[i] = series.GetTime(i)
+	}
+	f = reduceFunc(times, &floatField)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
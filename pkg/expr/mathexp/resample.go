@@ -28,6 +28,10 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
 	}
+	reduceFunc, err := GetTimeReduceFunc(downsampler)
+	if err != nil {
+		return s, fmt.Errorf("downsampling %v not implemented", downsampler)
+	}
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
@@ -35,6 +39,7 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
 		vals := make([]*float64, 0)
+		times := make([]time.Time, 0)
 		sIdx := bookmark
 		for {
 			if sIdx == s.Len() {
@@ -48,6 +53,7 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			sIdx++
 			lastSeen = v
 			vals = append(vals, v)
+			times = append(times, st)
 		}
 		var value *float64
 		if len(vals) == 0 { // upsampling
@@ -69,27 +75,12 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
-		} else if len(vals) == 1 {
+		} else if len(vals) == 1 && preservesSingleValue(downsampler) {
 			value = vals[0]
 		} else { // downsampling
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case ReducerSum:
-				tmp = Sum(&ff)
-			case ReducerMean:
-				tmp = Avg(&ff)
-			case ReducerMin:
-				tmp = Min(&ff)
-			case ReducerMax:
-				tmp = Max(&ff)
-			case ReducerLast:
-				tmp = Last(&ff)
-			default:
-				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
-			}
-			value = tmp
+			value = reduceFunc(times, &ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
@@ -97,3 +88,13 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	}
 	return resampled, nil
 }
+
+// preservesSingleValue reports whether the reducer returns the value itself when it reduces a single value.
+func preservesSingleValue(rFunc ReducerID) bool {
+	switch rFunc {
+	case ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerLast, ReducerFirst, ReducerMedian:
+		return true
+	}
+	_, isPercentile := parsePercentile(rFunc)
+	return isPercentile
+}
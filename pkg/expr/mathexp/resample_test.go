@@ -105,6 +105,79 @@ func TestResampleSeries(t *testing.T) {
 				time.Unix(15, 0), nil,
 			}),
 		},
+		{
+			name:        "resample series: downsampling (rate / fillna)",
+			interval:    time.Second * 5,
+			downsampler: "rate",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(16, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(8),
+			}, tp{
+				time.Unix(6, 0), float64Pointer(10),
+			}, tp{
+				time.Unix(9, 0), float64Pointer(3),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(15, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: downsampling (p75 / fillna)",
+			interval:    time.Second * 5,
+			downsampler: "p75",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(16, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(1, 0), float64Pointer(4),
+			}, tp{
+				time.Unix(2, 0), float64Pointer(1),
+			}, tp{
+				time.Unix(3, 0), float64Pointer(3),
+			}, tp{
+				time.Unix(4, 0), float64Pointer(2),
+			}, tp{
+				time.Unix(5, 0), float64Pointer(5),
+			}, tp{
+				time.Unix(9, 0), float64Pointer(7),
+			}),
+			series: makeSeries("", nil, tp{
+				time.Unix(0, 0), nil,
+			}, tp{
+				time.Unix(5, 0), float64Pointer(4),
+			}, tp{
+				time.Unix(10, 0), float64Pointer(7),
+			}, tp{
+				time.Unix(15, 0), nil,
+			}),
+		},
+		{
+			name:        "resample series: invalid downsampler",
+			interval:    time.Second * 5,
+			downsampler: "p200",
+			upsampler:   "fillna",
+			timeRange: backend.TimeRange{
+				From: time.Unix(0, 0),
+				To:   time.Unix(16, 0),
+			},
+			seriesToResample: makeSeries("", nil, tp{
+				time.Unix(2, 0), float64Pointer(2),
+			}),
+		},
 		{
 			name:        "resample series: downsampling (min / fillna)",
 			interval:    time.Second * 5,
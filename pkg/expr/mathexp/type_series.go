@@ -189,6 +189,9 @@ func (s Series) SetLabels(ls data.Labels) { s.Frame.Fields[seriesTypeValIdx].Lab
 
 func (s Series) GetName() string { return s.Frame.Fields[seriesTypeValIdx].Name }
 
+// GetConfig returns the config of the value field of the series.
+func (s Series) GetConfig() *data.FieldConfig { return s.Frame.Fields[seriesTypeValIdx].Config }
+
 func (s Series) GetMeta() any {
 	return s.Frame.Meta.Custom
 }
@@ -0,0 +1,424 @@
+package mathexp
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// unitKind is the physical quantity a unit measures. Values of units of the same kind can be converted into each other.
+type unitKind string
+
+const (
+	unitKindData     unitKind = "data"
+	unitKindTime     unitKind = "time"
+	unitKindRatio    unitKind = "ratio"
+	unitKindDataRate unitKind = "data rate"
+)
+
+// unitDef describes a known unit: its kind and the factor to convert a value in the unit to the base unit of the kind.
+type unitDef struct {
+	kind  unitKind
+	scale float64
+}
+
+// baseUnits are the units of the results that are derived from values of different units, e.g. the rate of bytes.
+var baseUnits = map[unitKind]string{
+	unitKindData:     "bytes",
+	unitKindTime:     "s",
+	unitKindRatio:    "percentunit",
+	unitKindDataRate: "binBps",
+}
+
+// knownUnits are the units, by the id used in data.FieldConfig.Unit, that values can be converted from and to.
+// Values with any other unit are never converted, and only combined with values of the same unit or without unit.
+var knownUnits = map[string]unitDef{
+	// data, base unit is the byte
+	"bits":      {unitKindData, 1.0 / 8},
+	"decbits":   {unitKindData, 1.0 / 8},
+	"bytes":     {unitKindData, 1},
+	"decbytes":  {unitKindData, 1},
+	"kbytes":    {unitKindData, 1 << 10},
+	"deckbytes": {unitKindData, 1e3},
+	"mbytes":    {unitKindData, 1 << 20},
+	"decmbytes": {unitKindData, 1e6},
+	"gbytes":    {unitKindData, 1 << 30},
+	"decgbytes": {unitKindData, 1e9},
+	"tbytes":    {unitKindData, 1 << 40},
+	"dectbytes": {unitKindData, 1e12},
+	"pbytes":    {unitKindData, 1 << 50},
+	"decpbytes": {unitKindData, 1e15},
+
+	// data rate, base unit is the byte per second
+	"binBps": {unitKindDataRate, 1},
+	"Bps":    {unitKindDataRate, 1},
+	"binbps": {unitKindDataRate, 1.0 / 8},
+	"bps":    {unitKindDataRate, 1.0 / 8},
+	"KiBs":   {unitKindDataRate, 1 << 10},
+	"KBs":    {unitKindDataRate, 1e3},
+	"Kibits": {unitKindDataRate, (1 << 10) / 8.0},
+	"Kbits":  {unitKindDataRate, 1e3 / 8},
+	"MiBs":   {unitKindDataRate, 1 << 20},
+	"MBs":    {unitKindDataRate, 1e6},
+	"Mibits": {unitKindDataRate, (1 << 20) / 8.0},
+	"Mbits":  {unitKindDataRate, 1e6 / 8},
+	"GiBs":   {unitKindDataRate, 1 << 30},
+	"GBs":    {unitKindDataRate, 1e9},
+	"Gibits": {unitKindDataRate, (1 << 30) / 8.0},
+	"Gbits":  {unitKindDataRate, 1e9 / 8},
+	"TiBs":   {unitKindDataRate, 1 << 40},
+	"TBs":    {unitKindDataRate, 1e12},
+	"Tibits": {unitKindDataRate, (1 << 40) / 8.0},
+	"Tbits":  {unitKindDataRate, 1e12 / 8},
+	"PiBs":   {unitKindDataRate, 1 << 50},
+	"PBs":    {unitKindDataRate, 1e15},
+	"Pibits": {unitKindDataRate, (1 << 50) / 8.0},
+	"Pbits":  {unitKindDataRate, 1e15 / 8},
+
+	// time, base unit is the second
+	"ns":           {unitKindTime, 1e-9},
+	"µs":           {unitKindTime, 1e-6},
+	"ms":           {unitKindTime, 1e-3},
+	"s":            {unitKindTime, 1},
+	"m":            {unitKindTime, 60},
+	"h":            {unitKindTime, 3600},
+	"d":            {unitKindTime, 86400},
+	"dtdurationms": {unitKindTime, 1e-3},
+	"dtdurations":  {unitKindTime, 1},
+	"clockms":      {unitKindTime, 1e-3},
+	"clocks":       {unitKindTime, 1},
+	"timeticks":    {unitKindTime, 1e-2},
+
+	// ratio, base unit is the fraction between 0.0 and 1.0
+	"percent":     {unitKindRatio, 1e-2},
+	"percentunit": {unitKindRatio, 1},
+}
+
+// unitProducts and unitQuotients are the kinds of the results of multiplying and dividing values of two kinds.
+var (
+	unitProducts = map[[2]unitKind]unitKind{
+		{unitKindDataRate, unitKindTime}: unitKindData,
+		{unitKindTime, unitKindDataRate}: unitKindData,
+	}
+	unitQuotients = map[[2]unitKind]unitKind{
+		{unitKindData, unitKindTime}: unitKindDataRate,
+	}
+)
+
+// UnitMismatchError is returned when an operation requires values of the same kind, e.g. an addition, but the values
+// have units of different kinds that cannot be converted into each other, such as bytes and seconds.
+type UnitMismatchError struct {
+	// Op is the operation, e.g. "+", or the name of the function or aggregation.
+	Op string
+	A  string
+	B  string
+}
+
+func (e UnitMismatchError) Error() string {
+	switch e.Op {
+	case "convert":
+		return fmt.Sprintf("cannot convert values in '%s' to '%s': the units are incompatible", e.A, e.B)
+	case "+", "-", "%", "==", "!=", ">", "<", ">=", "<=":
+		return fmt.Sprintf("cannot apply '%s' to values in '%s' and '%s': the units are incompatible", e.Op, e.A, e.B)
+	default:
+		return fmt.Sprintf("cannot %s values in '%s' and '%s': the units are incompatible", e.Op, e.A, e.B)
+	}
+}
+
+// normalizeUnit returns the unit, or an empty string if the unit does not describe a quantity.
+func normalizeUnit(unit string) string {
+	switch unit {
+	case "none", "short":
+		return ""
+	}
+	return unit
+}
+
+// GetUnit returns the unit of the value from the config of its value field, or an empty string if it has no unit.
+func GetUnit(v Value) string {
+	var f *data.Field
+	switch t := v.(type) {
+	case Series:
+		f = t.Frame.Fields[seriesTypeValIdx]
+	case Number:
+		f = t.Frame.Fields[0]
+	case Scalar:
+		f = t.Frame.Fields[0]
+	default:
+		return ""
+	}
+	if f.Config == nil {
+		return ""
+	}
+	return normalizeUnit(f.Config.Unit)
+}
+
+// SetUnit sets the unit in the config of the value field of the value. It does nothing for NoData and tables.
+func SetUnit(v Value, unit string) {
+	var f *data.Field
+	switch t := v.(type) {
+	case Series:
+		f = t.Frame.Fields[seriesTypeValIdx]
+	case Number:
+		f = t.Frame.Fields[0]
+	case Scalar:
+		f = t.Frame.Fields[0]
+	default:
+		return
+	}
+	if f.Config == nil {
+		if unit == "" {
+			return
+		}
+		f.Config = &data.FieldConfig{}
+	} else {
+		// the config can be shared with the input of the command, so it must be copied before it is changed
+		c := *f.Config
+		f.Config = &c
+	}
+	f.Config.Unit = unit
+}
+
+// UnitFactor returns the factor to multiply a value in unit from with, to convert it to unit to. Values without unit,
+// and values of units that are not known, are not converted. It returns UnitMismatchError if the units are known but
+// of different kinds.
+func UnitFactor(from, to string) (float64, error) {
+	from, to = normalizeUnit(from), normalizeUnit(to)
+	if from == to || from == "" || to == "" {
+		return 1, nil
+	}
+	f, okFrom := knownUnits[from]
+	t, okTo := knownUnits[to]
+	if !okFrom || !okTo {
+		return 1, nil
+	}
+	if f.kind != t.kind {
+		return 1, UnitMismatchError{Op: "convert", A: from, B: to}
+	}
+	return f.scale / t.scale, nil
+}
+
+// binaryUnits describes how the units of the operands of a binary operation are handled: the operands are multiplied
+// by the factors before the operation, and the result has the unit.
+type binaryUnits struct {
+	aFactor float64
+	bFactor float64
+	unit    string
+}
+
+// binaryOpUnits returns how a binary operation handles the units a and b of its operands:
+//   - additions, subtractions, modulo and comparisons convert b to the unit of a, and fail if the units are of different kinds.
+//     Comparisons return a value without unit.
+//   - multiplications and divisions by a value without unit keep the unit, and by a ratio, such as percent, convert the ratio.
+//   - divisions of values of the same kind return a ratio, and of data by time a data rate. Multiplications of a data rate
+//     by time return data.
+//
+// Any other operation, or operation on units that are not known, returns a value without unit.
+func binaryOpUnits(op, a, b string) (binaryUnits, error) {
+	res := binaryUnits{aFactor: 1, bFactor: 1}
+	ua, okA := knownUnits[a]
+	ub, okB := knownUnits[b]
+	switch op {
+	case "+", "-", "%", "==", "!=", ">", "<", ">=", "<=":
+		switch {
+		case a == "" || a == b:
+			res.unit = b
+		case b == "":
+			res.unit = a
+		case okA && okB && ua.kind == ub.kind:
+			res.bFactor = ub.scale / ua.scale
+			res.unit = a
+		case okA && okB:
+			return res, UnitMismatchError{Op: op, A: a, B: b}
+		}
+		if op != "+" && op != "-" && op != "%" {
+			res.unit = ""
+		}
+	case "*":
+		switch {
+		case a == "":
+			res.unit = b
+		case b == "":
+			res.unit = a
+		case okA && okB && ub.kind == unitKindRatio:
+			res.bFactor = ub.scale
+			res.unit = a
+		case okA && okB && ua.kind == unitKindRatio:
+			res.aFactor = ua.scale
+			res.unit = b
+		case okA && okB:
+			if kind, ok := unitProducts[[2]unitKind{ua.kind, ub.kind}]; ok {
+				res.aFactor, res.bFactor = ua.scale, ub.scale
+				res.unit = baseUnits[kind]
+			}
+		}
+	case "/":
+		switch {
+		case b == "":
+			res.unit = a
+		case a == "":
+		case a == b:
+			res.unit = baseUnits[unitKindRatio]
+		case okA && okB && ua.kind == ub.kind:
+			res.bFactor = ub.scale / ua.scale
+			res.unit = baseUnits[unitKindRatio]
+		case okA && okB && ub.kind == unitKindRatio:
+			res.bFactor = ub.scale
+			res.unit = a
+		case okA && okB:
+			if kind, ok := unitQuotients[[2]unitKind{ua.kind, ub.kind}]; ok {
+				res.aFactor, res.bFactor = ua.scale, ub.scale
+				res.unit = baseUnits[kind]
+			}
+		}
+	}
+	return res, nil
+}
+
+// perSecondUnit returns the unit of the per-second rate of values in unit, and the factor to multiply the rate with.
+// Only the rate of data is known, which is a data rate.
+func perSecondUnit(unit string) (string, float64) {
+	if u, ok := knownUnits[unit]; ok && u.kind == unitKindData {
+		return baseUnits[unitKindDataRate], u.scale
+	}
+	return "", 1
+}
+
+// reducerUnit returns the unit of the result of the reducer on values in unit, and the factor to multiply the result with.
+// Counts and the variance have no unit.
+func reducerUnit(rFunc ReducerID, unit string) (string, float64) {
+	switch rFunc {
+	case ReducerCount, ReducerCountNonNull, ReducerVariance:
+		return "", 1
+	case ReducerRate:
+		return perSecondUnit(unit)
+	default:
+		return unit, 1
+	}
+}
+
+// SetReducedUnit sets the unit of the number that is the result of the reducer on values in unit, and converts its value
+// to it if needed, e.g. the rate of bytes is in bytes per second.
+func SetReducedUnit(number Number, rFunc ReducerID, unit string) {
+	unit, factor := reducerUnit(rFunc, unit)
+	if f := number.GetFloat64Value(); f != nil && factor != 1 {
+		scaled := *f * factor
+		number.SetValue(&scaled)
+	}
+	SetUnit(number, unit)
+}
+
+// commonUnit converts the values to the unit of the first value that has a unit. Op is used in the error if the
+// units are of different kinds.
+func commonUnit(op string, values []Value) (string, []Value, error) {
+	unit := ""
+	for _, v := range values {
+		if unit = GetUnit(v); unit != "" {
+			break
+		}
+	}
+	if unit == "" {
+		return "", values, nil
+	}
+	converted := make([]Value, 0, len(values))
+	for _, v := range values {
+		factor, err := UnitFactor(GetUnit(v), unit)
+		if err != nil {
+			return "", nil, UnitMismatchError{Op: op, A: unit, B: GetUnit(v)}
+		}
+		converted = append(converted, scaleValue(v, factor))
+	}
+	return unit, converted, nil
+}
+
+// scaleValue returns a copy of the value with all values multiplied by factor, or the value itself if factor is 1.
+func scaleValue(v Value, factor float64) Value {
+	if factor == 1 {
+		return v
+	}
+	scale := func(f *float64) *float64 {
+		if f == nil {
+			return nil
+		}
+		r := *f * factor
+		return &r
+	}
+	switch t := v.(type) {
+	case Scalar:
+		return NewScalar(t.Frame.Fields[0].Name, scale(t.GetFloat64Value()))
+	case Number:
+		n := NewNumber(t.Frame.Fields[0].Name, t.GetLabels())
+		n.SetValue(scale(t.GetFloat64Value()))
+		return n
+	case Series:
+		s := NewSeries(t.Frame.Fields[seriesTypeValIdx].Name, t.GetLabels(), t.Len())
+		for i := 0; i < t.Len(); i++ {
+			ts, f := t.GetPoint(i)
+			s.SetPoint(i, ts, scale(f))
+		}
+		return s
+	}
+	return v
+}
+
+// funcUnits are the units of the results of functions that return values in a unit related to the unit of their first
+// argument. Functions that are not listed return values without unit.
+var funcUnits = map[string]func(unit string) (string, float64){
+	"abs":          sameUnit,
+	"round":        sameUnit,
+	"ceil":         sameUnit,
+	"floor":        sameUnit,
+	"clamp":        sameUnit,
+	"shift":        sameUnit,
+	"moving_avg":   sameUnit,
+	"delta":        sameUnit,
+	"cumsum":       sameUnit,
+	"holt_winters": sameUnit,
+	"rate":         perSecondUnit,
+}
+
+func sameUnit(unit string) (string, float64) {
+	return unit, 1
+}
+
+// setFuncUnits sets the units of the results of the function from the units of its first argument. The results are
+// in the same order as the values of the argument.
+func setFuncUnits(name string, arg Results, res Results) Results {
+	unitF, ok := funcUnits[name]
+	if !ok || len(arg.Values) != len(res.Values) {
+		return res
+	}
+	for i, v := range res.Values {
+		unit, factor := unitF(GetUnit(arg.Values[i]))
+		v = scaleValue(v, factor)
+		SetUnit(v, unit)
+		res.Values[i] = v
+	}
+	return res
+}
+
+// toUnit converts each value in the NumberSet, SeriesSet or Scalar to the unit. Values without unit, or with a unit
+// that cannot be converted, are only assigned the unit.
+func toUnit(e *State, varSet Results, unit string) (Results, error) {
+	unit = normalizeUnit(unit)
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		factor, err := UnitFactor(GetUnit(res), unit)
+		if err != nil {
+			return newRes, err
+		}
+		newVal, err := perNullableFloat(e, res, func(f *float64) *float64 {
+			if f == nil {
+				return nil
+			}
+			r := *f * factor
+			return &r
+		})
+		if err != nil {
+			return newRes, err
+		}
+		SetUnit(newVal, unit)
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
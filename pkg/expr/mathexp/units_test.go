@@ -0,0 +1,157 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/stretchr/testify/require"
+)
+
+func withUnit[T Value](v T, unit string) T {
+	SetUnit(v, unit)
+	return v
+}
+
+func TestUnitFactor(t *testing.T) {
+	var tests = []struct {
+		name   string
+		from   string
+		to     string
+		factor float64
+		errIs  require.ErrorAssertionFunc
+	}{
+		{name: "same unit", from: "bytes", to: "bytes", factor: 1, errIs: require.NoError},
+		{name: "bytes to mebibytes", from: "bytes", to: "mbytes", factor: 1.0 / (1 << 20), errIs: require.NoError},
+		{name: "megabytes to bytes", from: "decmbytes", to: "decbytes", factor: 1e6, errIs: require.NoError},
+		{name: "bits to bytes", from: "bits", to: "bytes", factor: 1.0 / 8, errIs: require.NoError},
+		{name: "milliseconds to seconds", from: "ms", to: "s", factor: 1e-3, errIs: require.NoError},
+		{name: "percent to percent unit", from: "percent", to: "percentunit", factor: 1e-2, errIs: require.NoError},
+		{name: "no unit", from: "", to: "bytes", factor: 1, errIs: require.NoError},
+		{name: "none is no unit", from: "none", to: "s", factor: 1, errIs: require.NoError},
+		{name: "unknown units", from: "reqps", to: "ops", factor: 1, errIs: require.NoError},
+		{name: "units of different kinds", from: "bytes", to: "s", factor: 1, errIs: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factor, err := UnitFactor(tt.from, tt.to)
+			tt.errIs(t, err)
+			require.InDelta(t, tt.factor, factor, 1e-15)
+		})
+	}
+}
+
+func TestUnitsInMath(t *testing.T) {
+	vars := Vars{
+		"A": resultValuesNoErr(withUnit(makeNumber("", nil, float64Pointer(1<<20)), "bytes")),
+		"B": resultValuesNoErr(withUnit(makeNumber("", nil, float64Pointer(1)), "mbytes")),
+		"S": resultValuesNoErr(withUnit(makeNumber("", nil, float64Pointer(2)), "s")),
+		"P": resultValuesNoErr(withUnit(makeNumber("", nil, float64Pointer(50)), "percent")),
+		"N": resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		"M": resultValuesNoErr(
+			withUnit(makeNumber("", data.Labels{"host": "a"}, float64Pointer(1<<20)), "bytes"),
+			withUnit(makeNumber("", data.Labels{"host": "b"}, float64Pointer(1)), "mbytes"),
+		),
+	}
+	var tests = []struct {
+		name      string
+		expr      string
+		execErrIs require.ErrorAssertionFunc
+		value     float64
+		unit      string
+	}{
+		{name: "addition converts to the unit of the left operand", expr: "$A + $B", execErrIs: require.NoError, value: 2 << 20, unit: "bytes"},
+		{name: "subtraction converts to the unit of the left operand", expr: "$B - $A", execErrIs: require.NoError, value: 0, unit: "mbytes"},
+		{name: "addition of a value without unit keeps the unit", expr: "$A + 1", execErrIs: require.NoError, value: 1<<20 + 1, unit: "bytes"},
+		{name: "addition of units of different kinds fails", expr: "$A + $S", execErrIs: require.Error},
+		{name: "comparison converts units and has no unit", expr: "$A >= $B", execErrIs: require.NoError, value: 1, unit: ""},
+		{name: "comparison of units of different kinds fails", expr: "$A > $S", execErrIs: require.Error},
+		{name: "multiplication by a value without unit keeps the unit", expr: "$A * $N", execErrIs: require.NoError, value: 2 << 20, unit: "bytes"},
+		{name: "multiplication by percent converts the percent", expr: "$P * $A", execErrIs: require.NoError, value: 1 << 19, unit: "bytes"},
+		{name: "division of the same kind is a ratio", expr: "$A / $B", execErrIs: require.NoError, value: 1, unit: "percentunit"},
+		{name: "division of data by time is a data rate", expr: "$B / $S", execErrIs: require.NoError, value: 1 << 19, unit: "binBps"},
+		{name: "division of time by data has no unit", expr: "$S / $B", execErrIs: require.NoError, value: 2, unit: ""},
+		{name: "negation keeps the unit", expr: "-$A", execErrIs: require.NoError, value: -(1 << 20), unit: "bytes"},
+		{name: "abs keeps the unit", expr: "abs($A)", execErrIs: require.NoError, value: 1 << 20, unit: "bytes"},
+		{name: "log has no unit", expr: "log($N)", execErrIs: require.NoError, value: 0.6931471805599453, unit: ""},
+		{name: "to_unit converts the value", expr: `to_unit($A, "mbytes")`, execErrIs: require.NoError, value: 1, unit: "mbytes"},
+		{name: "to_unit sets the unit of a value without unit", expr: `$A > to_unit(0.5, "mbytes")`, execErrIs: require.NoError, value: 1, unit: ""},
+		{name: "to_unit fails for units of different kinds", expr: `to_unit($A, "s")`, execErrIs: require.Error},
+		{name: "aggregation converts to the unit of the first item", expr: "sum($M)", execErrIs: require.NoError, value: 2 << 20, unit: "bytes"},
+		{name: "count has no unit", expr: "count($M)", execErrIs: require.NoError, value: 2, unit: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.ExecuteWithUnits("", vars, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				require.ErrorAs(t, err, &UnitMismatchError{})
+				return
+			}
+			require.Len(t, res.Values, 1)
+			require.Equal(t, tt.unit, GetUnit(res.Values[0]))
+			var value *float64
+			switch v := res.Values[0].(type) {
+			case Number:
+				value = v.GetFloat64Value()
+			case Scalar:
+				value = v.GetFloat64Value()
+			}
+			require.NotNil(t, value)
+			require.InDelta(t, tt.value, *value, 1e-9)
+		})
+	}
+
+	t.Run("units are ignored without ExecuteWithUnits", func(t *testing.T) {
+		for expr, expected := range map[string]float64{
+			"$A + $B":   1<<20 + 1,
+			"$P * $A":   50 << 20,
+			"$A + $S":   1<<20 + 2,
+			"sum($M)":   1<<20 + 1,
+			"$A >= $B":  1,
+			"-$A":       -(1 << 20),
+			"abs($A)":   1 << 20,
+			"$B / $S":   0.5,
+			"$A / $B":   1 << 20,
+			"count($M)": 2,
+		} {
+			e, err := New(expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", vars, tracing.InitializeTracerForTest())
+			require.NoError(t, err, expr)
+			require.Len(t, res.Values, 1)
+			require.Empty(t, GetUnit(res.Values[0]), expr)
+			require.InDelta(t, expected, *res.Values[0].(Number).GetFloat64Value(), 1e-9, expr)
+		}
+	})
+}
+
+func TestUnitsInReduce(t *testing.T) {
+	series := withUnit(makeSeries("", nil,
+		tp{time.Unix(0, 0), float64Pointer(0)},
+		tp{time.Unix(10, 0), float64Pointer(10)},
+	), "kbytes")
+
+	var tests = []struct {
+		reducer ReducerID
+		value   float64
+		unit    string
+	}{
+		{reducer: ReducerMax, value: 10, unit: "kbytes"},
+		{reducer: ReducerMean, value: 5, unit: "kbytes"},
+		{reducer: ReducerCount, value: 2, unit: ""},
+		{reducer: ReducerRate, value: 1024, unit: "binBps"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.reducer), func(t *testing.T) {
+			n, err := series.Reduce("B", tt.reducer, nil)
+			require.NoError(t, err)
+			SetReducedUnit(n, tt.reducer, GetUnit(series))
+			require.Equal(t, tt.unit, GetUnit(n))
+			require.InDelta(t, tt.value, *n.GetFloat64Value(), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package mathexp
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// matchingUnion creates Union objects like union does, but matches the items of
+// both sides on the labels selected by the vector matching of the binary node
+// instead of on all labels, e.g. $A / on(instance) $B or $A / ignoring(job) group_left $B.
+//
+// For one-to-one matching each item must match at most one item on the other side.
+// For many-to-one (group_left) and one-to-many (group_right) matching only the items
+// on the "one" side must be unique.
+func (e *State) matchingUnion(aResults, bResults Results, biNode *parse.BinaryNode) ([]*Union, error) {
+	unions := []*Union{}
+	m := biNode.Matching
+
+	if len(aResults.Values) == 0 || len(bResults.Values) == 0 {
+		return unions, nil
+	}
+
+	aMatched := make([]bool, len(aResults.Values))
+	bMatched := make([]bool, len(bResults.Values))
+
+	aNoData := len(aResults.Values) == 1 && aResults.Values[0].Type() == parse.TypeNoData
+	bNoData := len(bResults.Values) == 1 && bResults.Values[0].Type() == parse.TypeNoData
+	if aNoData || bNoData {
+		unions = append(unions, &Union{
+			A: aResults.Values[0],
+			B: bResults.Values[0],
+		})
+		e.collectDrops(biNode, aResults, bResults, aMatched, bMatched)
+		return unions, nil
+	}
+
+	// For group_right the right side is the "many" side.
+	many, one := aResults, bResults
+	manyMatched, oneMatched := aMatched, bMatched
+	manySide, oneSide := "left", "right"
+	if m.Card == parse.CardOneToMany {
+		many, one = bResults, aResults
+		manyMatched, oneMatched = bMatched, aMatched
+		manySide, oneSide = "right", "left"
+	}
+
+	oneBySignature := make(map[string]int, len(one.Values))
+	for i, v := range one.Values {
+		if v.Type() == parse.TypeNoData {
+			continue
+		}
+		sig := matchingSignature(v.GetLabels(), m)
+		if _, ok := oneBySignature[sig]; ok {
+			return nil, fmt.Errorf("found duplicate items for the match group %s on the %s hand-side of %s; many-to-many matching is not allowed", sig, oneSide, biNode)
+		}
+		oneBySignature[sig] = i
+	}
+
+	seenSignatures := map[string]bool{}
+	seenLabels := map[string]bool{}
+	for i, v := range many.Values {
+		if v.Type() == parse.TypeNoData {
+			continue
+		}
+		sig := matchingSignature(v.GetLabels(), m)
+		j, ok := oneBySignature[sig]
+		if !ok {
+			continue
+		}
+		if m.Card == parse.CardOneToOne {
+			if seenSignatures[sig] {
+				return nil, fmt.Errorf("found duplicate items for the match group %s on the %s hand-side of %s; use group_left or group_right for many-to-one matching", sig, manySide, biNode)
+			}
+			seenSignatures[sig] = true
+		}
+
+		labels := matchingResultLabels(v.GetLabels(), one.Values[j].GetLabels(), m)
+		key := labels.String()
+		if seenLabels[key] {
+			return nil, fmt.Errorf("multiple matches for labels %s in %s; grouping labels must ensure unique matches", key, biNode)
+		}
+		seenLabels[key] = true
+
+		u := &Union{Labels: labels, A: v, B: one.Values[j]}
+		if m.Card == parse.CardOneToMany {
+			u.A, u.B = u.B, u.A
+		}
+		unions = append(unions, u)
+		manyMatched[i] = true
+		oneMatched[j] = true
+	}
+
+	e.collectDrops(biNode, aResults, bResults, aMatched, bMatched)
+	return unions, nil
+}
+
+// matchingSignature returns the labels that items are matched on as a string.
+func matchingSignature(labels data.Labels, m *parse.VectorMatching) string {
+	return matchingLabels(labels, m.MatchingLabels, m.On).String()
+}
+
+// matchingLabels returns the labels with the names in names if on is true, or
+// the labels without the names in names if on is false.
+func matchingLabels(labels data.Labels, names []string, on bool) data.Labels {
+	result := data.Labels{}
+	if on {
+		for _, name := range names {
+			if v, ok := labels[name]; ok {
+				result[name] = v
+			}
+		}
+		return result
+	}
+	for k, v := range labels {
+		result[k] = v
+	}
+	for _, name := range names {
+		delete(result, name)
+	}
+	return result
+}
+
+// matchingResultLabels returns the labels of the result of a matched pair of
+// items. For one-to-one matching these are the labels that were matched on. For
+// many-to-one and one-to-many matching these are the labels of the item on the
+// "many" side, with the included labels taken from the item on the "one" side.
+func matchingResultLabels(manyLabels, oneLabels data.Labels, m *parse.VectorMatching) data.Labels {
+	if m.Card == parse.CardOneToOne {
+		return matchingLabels(manyLabels, m.MatchingLabels, m.On)
+	}
+	labels := manyLabels.Copy()
+	if labels == nil {
+		labels = data.Labels{}
+	}
+	for _, name := range m.Include {
+		if v, ok := oneLabels[name]; ok {
+			labels[name] = v
+		} else {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
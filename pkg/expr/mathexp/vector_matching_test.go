@@ -0,0 +1,119 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorMatching(t *testing.T) {
+	requests := Vars{
+		"A": resultValuesNoErr(
+			makeNumber("", data.Labels{"instance": "a", "job": "api", "code": "500"}, float64Pointer(2)),
+			makeNumber("", data.Labels{"instance": "b", "job": "api", "code": "500"}, float64Pointer(6)),
+		),
+		"B": resultValuesNoErr(
+			makeNumber("", data.Labels{"instance": "a", "dc": "eu"}, float64Pointer(10)),
+			makeNumber("", data.Labels{"instance": "b", "dc": "us"}, float64Pointer(20)),
+		),
+	}
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "on matches on the listed labels and keeps them",
+			expr:      "$A / on(instance) $B",
+			vars:      requests,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a"}, float64Pointer(0.2)),
+				makeNumber("", data.Labels{"instance": "b"}, float64Pointer(0.3)),
+			),
+		},
+		{
+			name:      "ignoring matches on all but the listed labels",
+			expr:      "$A / ignoring(job, code, dc) $B",
+			vars:      requests,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a"}, float64Pointer(0.2)),
+				makeNumber("", data.Labels{"instance": "b"}, float64Pointer(0.3)),
+			),
+		},
+		{
+			name:      "group_left keeps the labels of the left side and includes labels from the right side",
+			expr:      "$A / on(instance) group_left(dc) $B",
+			vars:      requests,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a", "job": "api", "code": "500", "dc": "eu"}, float64Pointer(0.2)),
+				makeNumber("", data.Labels{"instance": "b", "job": "api", "code": "500", "dc": "us"}, float64Pointer(0.3)),
+			),
+		},
+		{
+			name:      "group_right keeps the labels of the right side",
+			expr:      "$B * on(instance) group_right $A",
+			vars:      requests,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"instance": "a", "job": "api", "code": "500"}, float64Pointer(20)),
+				makeNumber("", data.Labels{"instance": "b", "job": "api", "code": "500"}, float64Pointer(120)),
+			),
+		},
+		{
+			name: "many-to-one matching of series",
+			expr: "$A - on(host) group_left $B",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", data.Labels{"host": "a", "cpu": "0"}, tp{time.Unix(5, 0), float64Pointer(3)}),
+					makeSeries("", data.Labels{"host": "a", "cpu": "1"}, tp{time.Unix(5, 0), float64Pointer(4)}),
+				),
+				"B": resultValuesNoErr(
+					makeSeries("", data.Labels{"host": "a"}, tp{time.Unix(5, 0), float64Pointer(1)}),
+				),
+			},
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a", "cpu": "0"}, tp{time.Unix(5, 0), float64Pointer(2)}),
+				makeSeries("", data.Labels{"host": "a", "cpu": "1"}, tp{time.Unix(5, 0), float64Pointer(3)}),
+			),
+		},
+		{
+			name:      "one-to-one matching with duplicates on a side should error",
+			expr:      "$A / on(job) $B",
+			vars:      requests,
+			execErrIs: require.Error,
+		},
+		{
+			name: "many-to-one matching with duplicates on the one side should error",
+			expr: "$A / on(dc) group_left $B",
+			vars: Vars{
+				"A": requests["A"],
+				"B": resultValuesNoErr(
+					makeNumber("", data.Labels{"instance": "a"}, float64Pointer(10)),
+					makeNumber("", data.Labels{"instance": "b"}, float64Pointer(20)),
+				),
+			},
+			execErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			require.NoError(t, err)
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tt.results, res)
+		})
+	}
+}
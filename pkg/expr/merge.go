@@ -0,0 +1,172 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// MergeCommand is an expression command that merges the results of several queries or expressions into one result.
+// Time series, numbers and scalars are merged in the order of the expressions, keeping the first value for each set
+// of labels, so later expressions only provide the dimensions that earlier ones do not have. Tables are concatenated
+// and must have the same fields.
+type MergeCommand struct {
+	VarsToMerge []string
+	refID       string
+}
+
+// NewMergeCommand creates a new MergeCommand.
+func NewMergeCommand(refID string, varsToMerge []string) (*MergeCommand, error) {
+	if len(varsToMerge) < 2 {
+		return nil, fmt.Errorf("merge requires at least two expressions, got %d", len(varsToMerge))
+	}
+	vars := make([]string, 0, len(varsToMerge))
+	for _, v := range varsToMerge {
+		v = strings.TrimPrefix(v, "$")
+		if v == "" {
+			return nil, fmt.Errorf("no variable specified to reference for refId %v", refID)
+		}
+		vars = append(vars, v)
+	}
+	return &MergeCommand{
+		VarsToMerge: vars,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalMergeCommand creates a MergeCommand from Grafana's frontend query.
+func UnmarshalMergeCommand(rn *rawNode) (*MergeCommand, error) {
+	q := MergeQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the merge command: %w", err)
+	}
+	return NewMergeCommand(rn.RefID, q.Expressions)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gm *MergeCommand) NeedsVars() []string {
+	return gm.VarsToMerge
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gm *MergeCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteMerge")
+	defer span.End()
+
+	var table *data.Frame
+	var tableRefID string
+	newRes := mathexp.Results{Values: mathexp.Values{}}
+	seen := map[data.Fingerprint]struct{}{}
+	var firstType, firstRefID string
+	for _, refID := range gm.VarsToMerge {
+		for _, val := range vars[refID].Values {
+			if _, ok := val.(mathexp.NoData); ok {
+				continue
+			}
+			if firstType == "" {
+				firstType, firstRefID = val.Type().String(), refID
+			} else if val.Type().String() != firstType {
+				return mathexp.Results{}, fmt.Errorf("merge requires results of the same type, but got %s from %s and %s from %s", firstType, firstRefID, val.Type(), refID)
+			}
+
+			switch v := val.(type) {
+			case mathexp.TableData:
+				if v.Frame == nil {
+					continue
+				}
+				if table == nil {
+					table, tableRefID = v.Frame.EmptyCopy(), refID
+					table.Name = gm.refID
+					table.RefID = gm.refID
+				} else if err := checkSameFields(table, v.Frame); err != nil {
+					return mathexp.Results{}, fmt.Errorf("merge requires tables with the same fields, but %s and %s differ: %w", tableRefID, refID, err)
+				}
+				for i := 0; i < v.Frame.Rows(); i++ {
+					table.AppendRow(v.Frame.RowCopy(i)...)
+				}
+			default:
+				fp := val.GetLabels().Fingerprint()
+				if _, ok := seen[fp]; ok {
+					continue
+				}
+				seen[fp] = struct{}{}
+				copied, err := copyValue(gm.refID, val)
+				if err != nil {
+					return mathexp.Results{}, err
+				}
+				newRes.Values = append(newRes.Values, copied)
+			}
+		}
+	}
+
+	if table != nil {
+		newRes.Values = append(newRes.Values, mathexp.TableData{Frame: table})
+	}
+	if len(newRes.Values) == 0 {
+		newRes.Values = append(newRes.Values, mathexp.NewNoData())
+	}
+	return newRes, nil
+}
+
+func (gm *MergeCommand) Type() string {
+	return TypeMerge.String()
+}
+
+// copyValue returns a copy of the value, so that the merged result does not share frames with its inputs.
+func copyValue(refID string, val mathexp.Value) (mathexp.Value, error) {
+	var labels data.Labels
+	if val.GetLabels() != nil {
+		labels = val.GetLabels().Copy()
+	}
+	switch v := val.(type) {
+	case mathexp.Series:
+		s := mathexp.NewSeries(refID, labels, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			t, f := v.GetPoint(i)
+			if f != nil {
+				value := *f
+				f = &value
+			}
+			s.SetPoint(i, t, f)
+		}
+		return s, nil
+	case mathexp.Number:
+		n := mathexp.NewNumber(refID, labels)
+		if f := v.GetFloat64Value(); f != nil {
+			value := *f
+			n.SetValue(&value)
+		}
+		return n, nil
+	case mathexp.Scalar:
+		f := v.GetFloat64Value()
+		if f != nil {
+			value := *f
+			f = &value
+		}
+		return mathexp.NewScalar(refID, f), nil
+	default:
+		return nil, fmt.Errorf("merge does not support %s", val.Type())
+	}
+}
+
+func checkSameFields(a, b *data.Frame) error {
+	if len(a.Fields) != len(b.Fields) {
+		return fmt.Errorf("expected %d fields but got %d", len(a.Fields), len(b.Fields))
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return fmt.Errorf("expected field %d to be '%s' of type %s but got '%s' of type %s",
+				i, a.Fields[i].Name, a.Fields[i].Type(), b.Fields[i].Name, b.Fields[i].Type())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestNewMergeCommand(t *testing.T) {
+	cmd, err := NewMergeCommand("C", []string{"$A", "B"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+	require.Equal(t, "merge", cmd.Type())
+
+	_, err = NewMergeCommand("C", []string{"A"})
+	require.ErrorContains(t, err, "at least two expressions")
+
+	_, err = NewMergeCommand("C", []string{"A", ""})
+	require.ErrorContains(t, err, "no variable specified")
+}
+
+func TestMergeCommandExecute(t *testing.T) {
+	tracer := tracing.InitializeTracerForTest()
+	number := func(refID string, labels data.Labels, value float64) mathexp.Number {
+		n := mathexp.NewNumber(refID, labels)
+		n.SetValue(&value)
+		return n
+	}
+	table := func(hosts []string, values []float64) mathexp.TableData {
+		return mathexp.TableData{Frame: data.NewFrame("",
+			data.NewField("host", nil, hosts),
+			data.NewField("value", nil, values),
+		)}
+	}
+
+	t.Run("should keep the first value for each set of labels", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{number("A", data.Labels{"host": "a"}, 1)}},
+			"B": mathexp.Results{Values: mathexp.Values{
+				number("B", data.Labels{"host": "a"}, 10),
+				number("B", data.Labels{"host": "b"}, 20),
+			}},
+		}
+		cmd, err := NewMergeCommand("C", []string{"A", "B"})
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{
+			number("C", data.Labels{"host": "a"}, 1),
+			number("C", data.Labels{"host": "b"}, 20),
+		}, res.Values)
+	})
+
+	t.Run("should concatenate tables", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{table([]string{"a"}, []float64{1})}},
+			"B": mathexp.Results{Values: mathexp.Values{table([]string{"b", "c"}, []float64{2, 3})}},
+		}
+		cmd, err := NewMergeCommand("C", []string{"A", "B"})
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+
+		expected := data.NewFrame("C",
+			data.NewField("host", nil, []string{"a", "b", "c"}),
+			data.NewField("value", nil, []float64{1, 2, 3}),
+		)
+		expected.RefID = "C"
+		require.Equal(t, expected, res.Values[0].(mathexp.TableData).Frame)
+	})
+
+	t.Run("should ignore NoData", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}},
+			"B": mathexp.Results{Values: mathexp.Values{number("B", data.Labels{"host": "b"}, 20)}},
+			"D": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}},
+		}
+		cmd, err := NewMergeCommand("C", []string{"A", "B"})
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{number("C", data.Labels{"host": "b"}, 20)}, res.Values)
+
+		cmd, err = NewMergeCommand("C", []string{"A", "D"})
+		require.NoError(t, err)
+		res, err = cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{mathexp.NewNoData()}, res.Values)
+	})
+
+	t.Run("should fail if results have different types", func(t *testing.T) {
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{number("A", data.Labels{"host": "a"}, 1)}},
+			"B": mathexp.Results{Values: mathexp.Values{table([]string{"b"}, []float64{2})}},
+		}
+		cmd, err := NewMergeCommand("C", []string{"A", "B"})
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.ErrorContains(t, err, "requires results of the same type")
+	})
+
+	t.Run("should fail if tables have different fields", func(t *testing.T) {
+		other := data.NewFrame("",
+			data.NewField("host", nil, []string{"b"}),
+			data.NewField("value", nil, []string{"2"}),
+		)
+		vars := mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{table([]string{"a"}, []float64{1})}},
+			"B": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: other}}},
+		}
+		cmd, err := NewMergeCommand("C", []string{"A", "B"})
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracer)
+		require.ErrorContains(t, err, "requires tables with the same fields")
+	})
+}
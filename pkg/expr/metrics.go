@@ -12,6 +12,9 @@ const (
 type metrics struct {
 	dsRequests *prometheus.CounterVec
 
+	// dsCacheRequests counts lookups of datasource queries in the ResultCache
+	dsCacheRequests *prometheus.CounterVec
+
 	// older metric
 	expressionsQuerySummary *prometheus.SummaryVec
 }
@@ -25,6 +28,13 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			Help:      "Number of datasource queries made via server side expression requests",
 		}, []string{"error", "dataplane", "datasource_type"}),
 
+		dsCacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "ds_query_cache_requests_total",
+			Help:      "Number of datasource queries looked up in the result cache shared by evaluations, by result (hit or miss)",
+		}, []string{"result", "datasource_type"}),
+
 		// older (No Namespace or Subsystem)
 		expressionsQuerySummary: prometheus.NewSummaryVec(
 			prometheus.SummaryOpts{
@@ -39,6 +49,7 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 	if reg != nil {
 		reg.MustRegister(
 			m.dsRequests,
+			m.dsCacheRequests,
 			m.expressionsQuerySummary,
 		)
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	jsoniter "github.com/json-iterator/go"
 	"gonum.org/v1/gonum/graph/simple"
 
@@ -36,12 +37,14 @@ const (
 )
 
 // MLNode is a node of expression tree that evaluates the expression by sending the payload to Machine Learning back-end.
-// See ml.UnmarshalCommand for supported commands.
+// See ml.UnmarshalCommand for supported commands. Commands that do not require the Machine Learning back-end,
+// see ml.UnmarshalLocalCommand, are executed locally against the results of other nodes.
 type MLNode struct {
 	baseNode
-	command   ml.Command
-	TimeRange TimeRange
-	request   *Request
+	command      ml.Command
+	localCommand ml.LocalCommand
+	TimeRange    TimeRange
+	request      *Request
 }
 
 // NodeType returns the data pipeline node type.
@@ -51,12 +54,26 @@ func (m *MLNode) NodeType() NodeType {
 
 // NodeType returns the data pipeline node type.
 func (m *MLNode) NeedsVars() []string {
+	if m.localCommand != nil {
+		return m.localCommand.NeedsVars()
+	}
 	return []string{}
 }
 
+// commandType returns the type of the command of the node.
+func (m *MLNode) commandType() string {
+	if m.localCommand != nil {
+		return m.localCommand.Type()
+	}
+	return m.command.Type()
+}
+
 // Execute initializes plugin API client,  executes a ml.Command and then converts the result of the execution.
 // Returns non-empty mathexp.Results if evaluation was successful. Returns QueryError if command execution failed
-func (m *MLNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s *Service) (r mathexp.Results, e error) {
+func (m *MLNode) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, s *Service) (r mathexp.Results, e error) {
+	if m.localCommand != nil {
+		return m.executeLocal(ctx, now, vars, s)
+	}
 	logger := logger.FromContext(ctx).New("datasourceType", mlPluginID, "queryRefId", m.refID)
 	var result mathexp.Results
 	timeRange := m.TimeRange.AbsoluteTime(now)
@@ -134,23 +151,51 @@ func (m *MLNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s *
 	return result, err
 }
 
-func (s *Service) buildMLNode(dp *simple.DirectedGraph, rn *rawNode, req *Request) (Node, error) {
-	if rn.TimeRange == nil {
-		return nil, errors.New("time range must be specified")
+// executeLocal executes a ml.LocalCommand against the results of the nodes it depends on and then converts the result
+// of the execution the same way as the response of the Machine Learning back-end.
+func (m *MLNode) executeLocal(ctx context.Context, now time.Time, vars mathexp.Vars, s *Service) (mathexp.Results, error) {
+	timeRange := m.TimeRange.AbsoluteTime(now)
+
+	input := make(map[string]data.Frames, len(m.localCommand.NeedsVars()))
+	for _, refID := range m.localCommand.NeedsVars() {
+		res, ok := vars[refID]
+		if !ok {
+			return mathexp.Results{}, MakeQueryError(m.refID, "ml", fmt.Errorf("input %s is not available", refID))
+		}
+		input[refID] = res.Values.AsDataFrames(refID)
 	}
 
-	cmd, err := ml.UnmarshalCommand(rn.QueryRaw, s.cfg.AppURL)
+	frames, err := m.localCommand.Execute(timeRange.From, timeRange.To, input)
 	if err != nil {
-		return nil, err
+		return mathexp.Results{}, MakeQueryError(m.refID, "ml", err)
 	}
 
-	return &MLNode{
+	_, result, err := s.converter.Convert(ctx, mlPluginID, frames, s.allowLongFrames)
+	return result, err
+}
+
+func (s *Service) buildMLNode(dp *simple.DirectedGraph, rn *rawNode, req *Request) (Node, error) {
+	if rn.TimeRange == nil {
+		return nil, errors.New("time range must be specified")
+	}
+
+	node := &MLNode{
 		baseNode: baseNode{
 			id:    rn.idx,
 			refID: rn.RefID,
 		},
 		TimeRange: rn.TimeRange,
-		command:   cmd,
 		request:   req,
-	}, nil
+	}
+
+	var err error
+	if ml.IsLocalCommand(rn.QueryRaw) {
+		node.localCommand, err = ml.UnmarshalLocalCommand(rn.QueryRaw)
+	} else {
+		node.command, err = ml.UnmarshalCommand(rn.QueryRaw, s.cfg.AppURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
 }
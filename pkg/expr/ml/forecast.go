@@ -0,0 +1,426 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// ForecastLabel is the label added to the series produced by the forecast command.
+	// Its value is one of ForecastPredicted, ForecastUpper or ForecastLower.
+	ForecastLabel = "forecast"
+
+	ForecastPredicted = "predicted"
+	ForecastUpper     = "upper"
+	ForecastLower     = "lower"
+
+	defaultForecastAlpha       = 0.5
+	defaultForecastBeta        = 0.1
+	defaultForecastGamma       = 0.1
+	defaultForecastSensitivity = 3
+)
+
+type ForecastCommandConfiguration struct {
+	// Input is the refID of the query or expression whose series are forecast.
+	Input string `json:"input"`
+	// Season is the length of the seasonal cycle of the series, e.g. "1d". If empty,
+	// only the level and the trend of the series are modeled.
+	Season string `json:"season,omitempty"`
+	// Horizon is how far the forecast extends beyond the last point of the series, e.g. "1h".
+	Horizon string `json:"horizon,omitempty"`
+	// Alpha, Beta and Gamma are the smoothing factors of the level, the trend and the season.
+	Alpha *float64 `json:"alpha,omitempty"`
+	Beta  *float64 `json:"beta,omitempty"`
+	Gamma *float64 `json:"gamma,omitempty"`
+	// Sensitivity is the distance of the upper and lower bands from the predicted
+	// values, in standard deviations of the one-step-ahead forecast errors.
+	Sensitivity *float64 `json:"sensitivity,omitempty"`
+}
+
+// ForecastCommand implements LocalCommand. It forecasts each series of its input with
+// the Holt-Winters method and produces the predicted values together with an upper and
+// a lower band, so that an alert can fire when a value leaves the band.
+type ForecastCommand struct {
+	input       string
+	interval    time.Duration
+	season      time.Duration
+	horizon     time.Duration
+	alpha       float64
+	beta        float64
+	gamma       float64
+	sensitivity float64
+}
+
+var _ LocalCommand = &ForecastCommand{}
+
+func (c *ForecastCommand) Type() string {
+	return string(Forecast)
+}
+
+func (c *ForecastCommand) NeedsVars() []string {
+	return []string{c.input}
+}
+
+// Execute forecasts every series in the input frames. The series are first resampled
+// to the interval of the command, then the model is fitted and the one-step-ahead
+// predictions are returned for each point of the series and for every interval of the
+// horizon after the last point. Each input series produces three frames labeled with
+// ForecastLabel: the predicted values and the upper and lower bands.
+func (c *ForecastCommand) Execute(_, _ time.Time, input map[string]data.Frames) (data.Frames, error) {
+	var result data.Frames
+	for _, frame := range input[c.input] {
+		series, err := seriesFromFrame(frame)
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %w", c.input, err)
+		}
+		for _, s := range series {
+			frames, err := c.forecast(s)
+			if err != nil {
+				return nil, fmt.Errorf("input %s: %w", c.input, err)
+			}
+			result = append(result, frames...)
+		}
+	}
+	return result, nil
+}
+
+func (c *ForecastCommand) forecast(s inputSeries) (data.Frames, error) {
+	if len(s.times) == 0 {
+		return nil, nil
+	}
+	start := s.times[0]
+	values := resample(s, start, c.interval)
+
+	period := 0
+	if c.season > 0 {
+		period = int(c.season / c.interval)
+		if period < 2 {
+			return nil, fmt.Errorf("season %s must be at least two intervals of %s", c.season, c.interval)
+		}
+		if len(values) < 2*period {
+			return nil, fmt.Errorf("not enough data for a seasonal forecast of series %s: need at least two seasons of %s", s.labels, c.season)
+		}
+	}
+
+	model := newHoltWinters(c.alpha, c.beta, c.gamma, period)
+	predicted := model.fit(values)
+	horizon := int(c.horizon / c.interval)
+	for h := 1; h <= horizon; h++ {
+		p := model.predict(h)
+		predicted = append(predicted, &p)
+	}
+	band := c.sensitivity * model.stdDev()
+
+	times := make([]time.Time, len(predicted))
+	upper := make([]*float64, len(predicted))
+	lower := make([]*float64, len(predicted))
+	for i, p := range predicted {
+		times[i] = start.Add(time.Duration(i) * c.interval)
+		if p == nil {
+			continue
+		}
+		u, l := *p+band, *p-band
+		upper[i], lower[i] = &u, &l
+	}
+
+	return data.Frames{
+		forecastFrame(s, ForecastPredicted, times, predicted),
+		forecastFrame(s, ForecastUpper, times, upper),
+		forecastFrame(s, ForecastLower, times, lower),
+	}, nil
+}
+
+func forecastFrame(s inputSeries, kind string, times []time.Time, values []*float64) *data.Frame {
+	labels := s.labels.Copy()
+	if labels == nil {
+		labels = data.Labels{}
+	}
+	labels[ForecastLabel] = kind
+	frame := data.NewFrame(s.name,
+		data.NewField("Time", nil, times),
+		data.NewField(s.name, labels, values),
+	)
+	frame.Meta = &data.FrameMeta{
+		Type:        data.FrameTypeTimeSeriesMulti,
+		TypeVersion: data.FrameTypeVersion{0, 1},
+	}
+	return frame
+}
+
+// holtWinters is the additive Holt-Winters model. With a period of zero it has no
+// seasonal component, i.e. it is double exponential smoothing.
+type holtWinters struct {
+	alpha, beta, gamma float64
+	period             int
+
+	level, trend float64
+	seasonal     []float64
+	// step is the index of the next point
+	step int
+	// squared one-step-ahead errors
+	sse   float64
+	count int
+}
+
+func newHoltWinters(alpha, beta, gamma float64, period int) *holtWinters {
+	return &holtWinters{alpha: alpha, beta: beta, gamma: gamma, period: period}
+}
+
+// fit initializes the model from the first season, or the first point if there is no
+// season, and updates it with the remaining points. It returns the one-step-ahead
+// prediction for each point, which is null for the points used for the initialization.
+// Null values do not update the model.
+func (m *holtWinters) fit(values []*float64) []*float64 {
+	predicted := make([]*float64, len(values))
+	first := m.init(values)
+	for i := first; i < len(values); i++ {
+		p := m.predict(1)
+		predicted[i] = &p
+		m.update(values[i], p)
+	}
+	return predicted
+}
+
+// init sets the initial level, trend and seasonal components and returns the index of
+// the first point that is not used for the initialization.
+func (m *holtWinters) init(values []*float64) int {
+	if m.period == 0 {
+		for i, v := range values {
+			if v != nil {
+				m.level = *v
+				m.step = i + 1
+				return i + 1
+			}
+		}
+		m.step = len(values)
+		return len(values)
+	}
+
+	first, firstOK := mean(values[:m.period])
+	second, secondOK := mean(values[m.period : 2*m.period])
+	m.level = first
+	if firstOK && secondOK {
+		m.trend = (second - first) / float64(m.period)
+	}
+	m.seasonal = make([]float64, m.period)
+	for i := 0; i < m.period; i++ {
+		if values[i] != nil {
+			m.seasonal[i] = *values[i] - first
+		}
+	}
+	m.step = m.period
+	return m.period
+}
+
+// predict returns the prediction h steps after the last point.
+func (m *holtWinters) predict(h int) float64 {
+	p := m.level + float64(h)*m.trend
+	if m.period > 0 {
+		p += m.seasonal[(m.step+h-1)%m.period]
+	}
+	return p
+}
+
+func (m *holtWinters) update(value *float64, predicted float64) {
+	defer func() { m.step++ }()
+	if value == nil || math.IsNaN(*value) {
+		m.level += m.trend
+		return
+	}
+	v := *value
+	m.sse += (v - predicted) * (v - predicted)
+	m.count++
+
+	var season float64
+	idx := 0
+	if m.period > 0 {
+		idx = m.step % m.period
+		season = m.seasonal[idx]
+	}
+	level := m.alpha*(v-season) + (1-m.alpha)*(m.level+m.trend)
+	m.trend = m.beta*(level-m.level) + (1-m.beta)*m.trend
+	m.level = level
+	if m.period > 0 {
+		m.seasonal[idx] = m.gamma*(v-level) + (1-m.gamma)*season
+	}
+}
+
+// stdDev returns the standard deviation of the one-step-ahead forecast errors.
+func (m *holtWinters) stdDev() float64 {
+	if m.count == 0 {
+		return 0
+	}
+	return math.Sqrt(m.sse / float64(m.count))
+}
+
+func mean(values []*float64) (float64, bool) {
+	var sum float64
+	count := 0
+	for _, v := range values {
+		if v != nil && !math.IsNaN(*v) {
+			sum += *v
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// inputSeries is a series of the input of a command, sorted by time, without null values.
+type inputSeries struct {
+	name   string
+	labels data.Labels
+	times  []time.Time
+	values []float64
+}
+
+// seriesFromFrame returns a series for each numeric field of a frame with a time field.
+// Frames without fields, e.g. no data responses, have no series.
+func seriesFromFrame(frame *data.Frame) ([]inputSeries, error) {
+	if frame == nil || len(frame.Fields) == 0 || frame.Rows() == 0 {
+		return nil, nil
+	}
+	timeIdx := -1
+	for i, f := range frame.Fields {
+		if f.Type().Time() {
+			timeIdx = i
+			break
+		}
+	}
+	if timeIdx < 0 {
+		return nil, fmt.Errorf("forecast requires time series, but frame %q has no time field", frame.Name)
+	}
+
+	var result []inputSeries
+	for i, f := range frame.Fields {
+		if i == timeIdx || !f.Type().Numeric() {
+			continue
+		}
+		s := inputSeries{name: f.Name, labels: f.Labels}
+		for row := 0; row < f.Len(); row++ {
+			t, ok := frame.Fields[timeIdx].ConcreteAt(row)
+			if !ok {
+				continue
+			}
+			v, err := f.NullableFloatAt(row)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil || math.IsNaN(*v) {
+				continue
+			}
+			s.times = append(s.times, t.(time.Time))
+			s.values = append(s.values, *v)
+		}
+		sort.Sort(byTime(s))
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+type byTime inputSeries
+
+func (s byTime) Len() int           { return len(s.times) }
+func (s byTime) Less(i, j int) bool { return s.times[i].Before(s.times[j]) }
+func (s byTime) Swap(i, j int) {
+	s.times[i], s.times[j] = s.times[j], s.times[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+// resample returns the values of the series in intervals starting at start. The value
+// of an interval is the mean of the values within it, or null if there are none.
+func resample(s inputSeries, start time.Time, interval time.Duration) []*float64 {
+	n := int(s.times[len(s.times)-1].Sub(start)/interval) + 1
+	sums := make([]float64, n)
+	counts := make([]int, n)
+	for i, t := range s.times {
+		idx := int(t.Sub(start) / interval)
+		sums[idx] += s.values[i]
+		counts[idx]++
+	}
+	values := make([]*float64, n)
+	for i := range values {
+		if counts[i] > 0 {
+			v := sums[i] / float64(counts[i])
+			values[i] = &v
+		}
+	}
+	return values
+}
+
+// unmarshalForecastCommand parses the CommandConfiguration.Config, validates data and produces ForecastCommand.
+func unmarshalForecastCommand(expr CommandConfiguration) (*ForecastCommand, error) {
+	var cfg ForecastCommandConfiguration
+	err := json.Unmarshal(expr.Config, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal forecast command: %w", err)
+	}
+	if len(cfg.Input) == 0 {
+		return nil, fmt.Errorf("required field `config.input` is not specified")
+	}
+
+	interval := defaultInterval
+	if expr.IntervalMs != nil {
+		i := time.Duration(*expr.IntervalMs) * time.Millisecond
+		if i > 0 {
+			interval = i
+		}
+	}
+
+	cmd := &ForecastCommand{
+		input:       strings.TrimPrefix(cfg.Input, "$"),
+		interval:    interval,
+		alpha:       defaultForecastAlpha,
+		beta:        defaultForecastBeta,
+		gamma:       defaultForecastGamma,
+		sensitivity: defaultForecastSensitivity,
+	}
+
+	if cfg.Season != "" {
+		cmd.season, err = gtime.ParseDuration(cfg.Season)
+		if err != nil || cmd.season <= 0 {
+			return nil, fmt.Errorf("field `config.season` must be a positive duration, got %q", cfg.Season)
+		}
+	}
+	if cfg.Horizon != "" {
+		cmd.horizon, err = gtime.ParseDuration(cfg.Horizon)
+		if err != nil || cmd.horizon < 0 {
+			return nil, fmt.Errorf("field `config.horizon` must be a positive duration, got %q", cfg.Horizon)
+		}
+	}
+
+	for _, f := range []struct {
+		name  string
+		value *float64
+		dest  *float64
+	}{
+		{"alpha", cfg.Alpha, &cmd.alpha},
+		{"beta", cfg.Beta, &cmd.beta},
+		{"gamma", cfg.Gamma, &cmd.gamma},
+	} {
+		if f.value == nil {
+			continue
+		}
+		if *f.value <= 0 || *f.value >= 1 {
+			return nil, fmt.Errorf("field `config.%s` must be between 0 and 1, got %v", f.name, *f.value)
+		}
+		*f.dest = *f.value
+	}
+
+	if cfg.Sensitivity != nil {
+		if *cfg.Sensitivity < 0 {
+			return nil, fmt.Errorf("field `config.sensitivity` must not be negative, got %v", *cfg.Sensitivity)
+		}
+		cmd.sensitivity = *cfg.Sensitivity
+	}
+
+	return cmd, nil
+}
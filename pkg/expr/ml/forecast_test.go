@@ -0,0 +1,191 @@
+package ml
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+const forecastQuery = `{
+	"type": "forecast",
+	"intervalMs": 60000,
+	"config": {
+		"input": "A",
+		"season": "10m",
+		"horizon": "5m",
+		"alpha": 0.3,
+		"sensitivity": 2
+	}
+}`
+
+func TestUnmarshalLocalCommand(t *testing.T) {
+	t.Run("should parse forecast command", func(t *testing.T) {
+		require.True(t, IsLocalCommand([]byte(forecastQuery)))
+		cmd, err := UnmarshalLocalCommand([]byte(forecastQuery))
+		require.NoError(t, err)
+		require.IsType(t, &ForecastCommand{}, cmd)
+		forecast := cmd.(*ForecastCommand)
+		require.Equal(t, &ForecastCommand{
+			input:       "A",
+			interval:    time.Minute,
+			season:      10 * time.Minute,
+			horizon:     5 * time.Minute,
+			alpha:       0.3,
+			beta:        defaultForecastBeta,
+			gamma:       defaultForecastGamma,
+			sensitivity: 2,
+		}, forecast)
+		require.Equal(t, []string{"A"}, cmd.NeedsVars())
+	})
+
+	t.Run("outlier is not a local command", func(t *testing.T) {
+		require.False(t, IsLocalCommand([]byte(outlierQuery)))
+	})
+
+	t.Run("fails when", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			query  string
+			errMsg string
+		}{
+			{
+				name:   "input is missing",
+				query:  `{"type": "forecast", "config": {"season": "1h"}}`,
+				errMsg: "failed to unmarshal Machine learning forecast command: required field `config.input` is not specified",
+			},
+			{
+				name:   "season is invalid",
+				query:  `{"type": "forecast", "config": {"input": "A", "season": "abc"}}`,
+				errMsg: "failed to unmarshal Machine learning forecast command: field `config.season` must be a positive duration, got \"abc\"",
+			},
+			{
+				name:   "smoothing factor is out of range",
+				query:  `{"type": "forecast", "config": {"input": "A", "gamma": 1.5}}`,
+				errMsg: "failed to unmarshal Machine learning forecast command: field `config.gamma` must be between 0 and 1, got 1.5",
+			},
+			{
+				name:   "sensitivity is negative",
+				query:  `{"type": "forecast", "config": {"input": "A", "sensitivity": -1}}`,
+				errMsg: "failed to unmarshal Machine learning forecast command: field `config.sensitivity` must not be negative, got -1",
+			},
+			{
+				name:   "config is missing",
+				query:  `{"type": "forecast"}`,
+				errMsg: "required field 'config' is not specified",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := UnmarshalLocalCommand([]byte(tc.query))
+				require.EqualError(t, err, tc.errMsg)
+			})
+		}
+	})
+}
+
+func TestForecastCommandExecute(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+
+	newInput := func(values []float64) data.Frames {
+		times := make([]time.Time, len(values))
+		for i := range values {
+			times[i] = start.Add(time.Duration(i) * time.Minute)
+		}
+		return data.Frames{data.NewFrame("",
+			data.NewField("Time", nil, times),
+			data.NewField("Value", data.Labels{"host": "a"}, values),
+		)}
+	}
+
+	t.Run("should forecast a linear series", func(t *testing.T) {
+		values := make([]float64, 20)
+		for i := range values {
+			values[i] = float64(10 + 2*i)
+		}
+		cmd := &ForecastCommand{
+			input:    "A",
+			interval: time.Minute,
+			horizon:  3 * time.Minute,
+			alpha:    0.5,
+			beta:     0.5,
+		}
+		frames, err := cmd.Execute(start, start, map[string]data.Frames{"A": newInput(values)})
+		require.NoError(t, err)
+		require.Len(t, frames, 3)
+
+		for i, kind := range []string{ForecastPredicted, ForecastUpper, ForecastLower} {
+			require.Equal(t, data.Labels{"host": "a", ForecastLabel: kind}, frames[i].Fields[1].Labels)
+			require.Equal(t, 23, frames[i].Rows())
+		}
+
+		predicted := frames[0].Fields[1]
+		require.Nil(t, predicted.At(0))
+		last, err := predicted.NullableFloatAt(22)
+		require.NoError(t, err)
+		require.InDelta(t, float64(10+2*22), *last, 1)
+		require.Equal(t, start.Add(22*time.Minute), frames[0].Fields[0].At(22))
+	})
+
+	t.Run("should forecast a seasonal series", func(t *testing.T) {
+		pattern := []float64{1, 5, 9, 5}
+		values := make([]float64, 0, 40)
+		for i := 0; i < 10; i++ {
+			values = append(values, pattern...)
+		}
+		cmd := &ForecastCommand{
+			input:       "A",
+			interval:    time.Minute,
+			season:      4 * time.Minute,
+			horizon:     4 * time.Minute,
+			alpha:       0.5,
+			beta:        0.1,
+			gamma:       0.1,
+			sensitivity: 3,
+		}
+		frames, err := cmd.Execute(start, start, map[string]data.Frames{"A": newInput(values)})
+		require.NoError(t, err)
+		require.Len(t, frames, 3)
+
+		for h, expected := range pattern {
+			idx := len(values) + h
+			p, err := frames[0].Fields[1].NullableFloatAt(idx)
+			require.NoError(t, err)
+			require.InDelta(t, expected, *p, 0.01)
+			upper, err := frames[1].Fields[1].NullableFloatAt(idx)
+			require.NoError(t, err)
+			lower, err := frames[2].Fields[1].NullableFloatAt(idx)
+			require.NoError(t, err)
+			require.LessOrEqual(t, *lower, *p)
+			require.GreaterOrEqual(t, *upper, *p)
+		}
+	})
+
+	t.Run("should fail if there is less than two seasons of data", func(t *testing.T) {
+		cmd := &ForecastCommand{
+			input:    "A",
+			interval: time.Minute,
+			season:   10 * time.Minute,
+			alpha:    0.5,
+		}
+		_, err := cmd.Execute(start, start, map[string]data.Frames{"A": newInput([]float64{1, 2, 3, 4, 5})})
+		require.ErrorContains(t, err, "not enough data for a seasonal forecast")
+	})
+
+	t.Run("should return nothing if input has no data", func(t *testing.T) {
+		cmd := &ForecastCommand{input: "A", interval: time.Minute}
+		frames, err := cmd.Execute(start, start, map[string]data.Frames{"A": {data.NewFrame("")}})
+		require.NoError(t, err)
+		require.Empty(t, frames)
+	})
+
+	t.Run("should fill gaps with nulls", func(t *testing.T) {
+		values := []float64{1, 2, math.NaN(), 4, 5}
+		cmd := &ForecastCommand{input: "A", interval: time.Minute, alpha: 0.5, beta: 0.1}
+		frames, err := cmd.Execute(start, start, map[string]data.Frames{"A": newInput(values)})
+		require.NoError(t, err)
+		require.Equal(t, 5, frames[0].Rows())
+	})
+}
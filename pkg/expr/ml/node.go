@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/grafana/grafana/pkg/api/response"
@@ -16,7 +17,8 @@ var json = jsoniter.ConfigCompatibleWithStandardLibrary
 type CommandType string
 
 const (
-	Outlier CommandType = "outlier"
+	Outlier  CommandType = "outlier"
+	Forecast CommandType = "forecast"
 
 	// format of the time used by outlier API
 	timeFormat = "2006-01-02T15:04:05.999999999"
@@ -35,6 +37,17 @@ type Command interface {
 	Type() string
 }
 
+// LocalCommand is an interface implemented by Machine Learning commands that are executed by Grafana itself
+// and do not require the ML API. Unlike Command, it takes the results of other queries and expressions as the input.
+type LocalCommand interface {
+	// NeedsVars returns the refIDs of the queries and expressions the command uses as the input.
+	NeedsVars() []string
+	// Execute runs the command against the input frames, keyed by refID.
+	Execute(from, to time.Time, input map[string]data.Frames) (data.Frames, error)
+
+	Type() string
+}
+
 // UnmarshalCommand parses a config parameters and creates a command. Requires key `type` to be specified.
 // Based on the value of `type` field it parses a Command
 func UnmarshalCommand(query []byte, appURL string) (Command, error) {
@@ -63,3 +76,36 @@ func UnmarshalCommand(query []byte, appURL string) (Command, error) {
 	}
 	return cmd, nil
 }
+
+// IsLocalCommand returns true if the config parameters describe a command that is executed locally. See UnmarshalLocalCommand.
+func IsLocalCommand(query []byte) bool {
+	return strings.ToLower(jsoniter.Get(query, "type").ToString()) == string(Forecast)
+}
+
+// UnmarshalLocalCommand parses a config parameters and creates a LocalCommand. Requires key `type` to be specified.
+func UnmarshalLocalCommand(query []byte) (LocalCommand, error) {
+	var expr CommandConfiguration
+	err := json.Unmarshal(query, &expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Machine learning command: %w", err)
+	}
+	if len(expr.Type) == 0 {
+		return nil, fmt.Errorf("required field 'type' is not specified or empty.  Should be one of [%s]", Forecast)
+	}
+
+	if len(expr.Config) == 0 {
+		return nil, fmt.Errorf("required field 'config' is not specified")
+	}
+
+	var cmd LocalCommand
+	switch mlType := strings.ToLower(expr.Type); mlType {
+	case string(Forecast):
+		cmd, err = unmarshalForecastCommand(expr)
+	default:
+		return nil, fmt.Errorf("unsupported command type. Should be one of [%s]", Forecast)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Machine learning %s command: %w", expr.Type, err)
+	}
+	return cmd, nil
+}
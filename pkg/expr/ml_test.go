@@ -14,6 +14,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/ml"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -217,3 +219,93 @@ func TestMLNodeExecute(t *testing.T) {
 		require.ErrorIs(t, err, cmd.Error)
 	})
 }
+
+func TestMLNodeLocalCommand(t *testing.T) {
+	s := &Service{
+		features: featuremgmt.WithFeatures(featuremgmt.FlagMlExpressions),
+	}
+	mlDatasource, err := DataSourceModelFromNodeType(TypeMLNode)
+	require.NoError(t, err)
+
+	t.Run("should depend on its input", func(t *testing.T) {
+		req := &Request{
+			Queries: []Query{
+				{
+					RefID:      "C",
+					DataSource: dataSourceModel(),
+					JSON: json.RawMessage(`{
+						"expression": "F",
+						"type": "threshold",
+						"conditions": [{"evaluator": {"type": "gt", "params": [0]}}]
+					}`),
+				},
+				{
+					RefID:      "F",
+					DataSource: mlDatasource,
+					TimeRange:  AbsoluteTimeRange{},
+					JSON: json.RawMessage(`{
+						"type": "forecast",
+						"config": {"input": "B", "horizon": "1h"}
+					}`),
+				},
+				{
+					RefID: "B",
+					DataSource: &datasources.DataSource{
+						UID: "Fake",
+					},
+					TimeRange: AbsoluteTimeRange{},
+				},
+			},
+		}
+		nodes, err := s.buildPipeline(req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"B", "F", "C"}, getRefIDOrder(nodes))
+		require.Equal(t, []string{"ml_forecast"}, nodes.GetDatasourceTypes())
+	})
+
+	t.Run("should fail if input does not exist", func(t *testing.T) {
+		req := &Request{
+			Queries: []Query{
+				{
+					RefID:      "F",
+					DataSource: mlDatasource,
+					TimeRange:  AbsoluteTimeRange{},
+					JSON: json.RawMessage(`{
+						"type": "forecast",
+						"config": {"input": "B"}
+					}`),
+				},
+			},
+		}
+		_, err := s.buildPipeline(req)
+		require.ErrorContains(t, err, "unable to find dependent node 'B'")
+	})
+	t.Run("should not require the feature flag", func(t *testing.T) {
+		s := &Service{
+			features: featuremgmt.WithFeatures(),
+		}
+		req := &Request{
+			Queries: []Query{
+				{
+					RefID:      "F",
+					DataSource: mlDatasource,
+					TimeRange:  AbsoluteTimeRange{},
+					JSON: json.RawMessage(`{
+						"type": "forecast",
+						"config": {"input": "B", "horizon": "1h"}
+					}`),
+				},
+				{
+					RefID: "B",
+					DataSource: &datasources.DataSource{
+						UID: "Fake",
+					},
+					TimeRange: AbsoluteTimeRange{},
+				},
+			},
+		}
+		nodes, err := s.buildPipeline(req)
+		require.NoError(t, err)
+		require.Equal(t, []string{"B", "F"}, getRefIDOrder(nodes))
+	})
+}
@@ -147,9 +147,9 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 
 	switch commandType {
 	case TypeMath:
-		node.Command, err = UnmarshalMathCommand(rn)
+		node.Command, err = UnmarshalMathCommand(rn, toggles)
 	case TypeReduce:
-		node.Command, err = UnmarshalReduceCommand(rn)
+		node.Command, err = UnmarshalReduceCommand(rn, toggles)
 	case TypeResample:
 		node.Command, err = UnmarshalResampleCommand(rn)
 	case TypeClassicConditions:
@@ -158,6 +158,10 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 		node.Command, err = UnmarshalThresholdCommand(rn, toggles)
 	case TypeSQL:
 		node.Command, err = UnmarshalSQLCommand(rn)
+	case TypeJoin:
+		node.Command, err = UnmarshalJoinCommand(rn)
+	case TypeMerge:
+		node.Command, err = UnmarshalMergeCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -261,10 +265,18 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 		byDS[k] = append(byDS[k], node)
 	}
 
+	profile := executionProfileFromContext(ctx)
 	for _, nodeGroup := range byDS {
 		func() {
 			ctx, span := s.tracer.Start(ctx, "SSE.ExecuteDatasourceQuery")
 			defer span.End()
+			// the nodes of the group are queried in a single request, so they share its duration.
+			start := time.Now()
+			defer func() {
+				for _, dn := range nodeGroup {
+					profile.recordNode(dn, vars, vars[dn.refID], time.Since(start))
+				}
+			}()
 			firstNode := nodeGroup[0]
 			pCtx, err := s.pCtxProvider.GetWithDataSource(ctx, firstNode.datasource.Type, firstNode.request.User, firstNode.datasource)
 			if err != nil {
@@ -315,7 +327,7 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 				s.metrics.dsRequests.WithLabelValues(respStatus, fmt.Sprintf("%t", useDataplane), firstNode.datasource.Type).Inc()
 			}
 
-			resp, err := s.dataService.QueryData(ctx, req)
+			resp, err := s.queryData(ctx, firstNode.orgID, firstNode.datasource, req)
 			if err != nil {
 				for _, dn := range nodeGroup {
 					vars[dn.refID] = mathexp.Results{Error: MakeQueryError(firstNode.refID, firstNode.datasource.UID, err)}
@@ -336,7 +348,10 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 				responseType, result, err := s.converter.Convert(ctx, dn.datasource.Type, dataFrames, s.allowLongFrames)
 				if err != nil {
 					result.Error = makeConversionError(dn.RefID(), err)
+				} else if err = checkSeriesLimit(ctx, dn.refID, result); err != nil {
+					result = mathexp.Results{Error: err}
 				}
+				profile.recordQuery(dn.refID, dataFrames, responseType)
 				instrument(err, responseType)
 				vars[dn.refID] = result
 			}
@@ -390,7 +405,7 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		s.metrics.dsRequests.WithLabelValues(respStatus, fmt.Sprintf("%t", useDataplane), dn.datasource.Type).Inc()
 	}()
 
-	resp, err := s.dataService.QueryData(ctx, req)
+	resp, err := s.queryData(ctx, dn.orgID, dn.datasource, req)
 	if err != nil {
 		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
 	}
@@ -402,8 +417,12 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 
 	var result mathexp.Results
 	responseType, result, err = s.converter.Convert(ctx, dn.datasource.Type, dataFrames, s.allowLongFrames)
+	executionProfileFromContext(ctx).recordQuery(dn.refID, dataFrames, responseType)
 	if err != nil {
-		err = makeConversionError(dn.refID, err)
+		return result, makeConversionError(dn.refID, err)
+	}
+	if err := checkSeriesLimit(ctx, dn.refID, result); err != nil {
+		return mathexp.Results{}, err
 	}
-	return result, err
+	return result, nil
 }
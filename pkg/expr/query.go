@@ -3,6 +3,8 @@ package expr
 import (
 	"embed"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
@@ -29,6 +31,12 @@ const (
 
 	// SQL query via DuckDB
 	QueryTypeSQL QueryType = "sql"
+
+	// Join query results on time or on a label or field
+	QueryTypeJoin QueryType = "join"
+
+	// Merge query results
+	QueryTypeMerge QueryType = "merge"
 )
 
 type MathQuery struct {
@@ -66,8 +74,17 @@ type ThresholdQuery struct {
 	// Reference to single query result
 	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
 
-	// Threshold Conditions
+	// Threshold Conditions. Several conditions define levels of increasing severity
 	Conditions []ThresholdConditionJSON `json:"conditions"`
+
+	// Requires the threshold to be breached in a number of the most recent evaluations
+	Window *ThresholdWindowJSON `json:"window,omitempty"`
+
+	// Results of the previous evaluations, supplied by alerting for thresholds with several conditions or a window
+	PreviousState *data.Frame `json:"previousState,omitempty"`
+
+	// Unit of the threshold values, e.g. mbytes. The values of the expression are converted to it before they are compared
+	Unit string `json:"unit,omitempty" jsonschema:"example=mbytes"`
 }
 
 type ClassicQuery struct {
@@ -79,6 +96,27 @@ type SQLExpression struct {
 	Expression string `json:"expression" jsonschema:"minLength=1,example=SELECT * FROM A LIMIT 1"`
 }
 
+// QueryType = join
+type JoinQuery struct {
+	// References to the query results to join
+	Expressions []string `json:"expressions" jsonschema:"minItems=2"`
+
+	// Which rows are kept
+	Mode JoinMode `json:"mode,omitempty"`
+
+	// What the results are joined on
+	On JoinOn `json:"on,omitempty"`
+
+	// The label or field to join on, when joining on a field
+	Field string `json:"field,omitempty" jsonschema:"example=host"`
+}
+
+// QueryType = merge
+type MergeQuery struct {
+	// References to the query results to merge, in order of precedence
+	Expressions []string `json:"expressions" jsonschema:"minItems=2"`
+}
+
 //-------------------------------
 // Non-query commands
 //-------------------------------
@@ -106,6 +144,30 @@ const (
 	ReduceModeReplace ReduceMode = "replaceNN"
 )
 
+// Join row behavior mode
+// +enum
+type JoinMode string
+
+const (
+	// Keep the rows of all results
+	JoinModeOuter JoinMode = "outer"
+
+	// Keep only the rows that all results have
+	JoinModeInner JoinMode = "inner"
+)
+
+// Join key
+// +enum
+type JoinOn string
+
+const (
+	// Join time series on their timestamps
+	JoinOnTime JoinOn = "time"
+
+	// Join numbers and tables on a label or field
+	JoinOnField JoinOn = "field"
+)
+
 //go:embed query.types.json
 var f embed.FS
 
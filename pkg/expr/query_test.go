@@ -27,6 +27,8 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
+				reflect.TypeOf(JoinModeOuter),
+				reflect.TypeOf(JoinOnTime),
 			},
 		})
 	require.NoError(t, err)
@@ -161,6 +163,41 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				},
 			},
 		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeJoin),
+			GoType:         reflect.TypeOf(&JoinQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "Join two queries on time",
+					SaveModel: data.AsUnstructured(JoinQuery{
+						Expressions: []string{"$A", "$B"},
+						Mode:        JoinModeOuter,
+						On:          JoinOnTime,
+					}),
+				},
+				{
+					Name: "Join numbers on a label",
+					SaveModel: data.AsUnstructured(JoinQuery{
+						Expressions: []string{"$A", "$B"},
+						Mode:        JoinModeInner,
+						On:          JoinOnField,
+						Field:       "host",
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeMerge),
+			GoType:         reflect.TypeOf(&MergeQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "Fall back to B for the dimensions missing in A",
+					SaveModel: data.AsUnstructured(MergeQuery{
+						Expressions: []string{"$A", "$B"},
+					}),
+				},
+			},
+		},
 	)
 
 	require.NoError(t, err)
@@ -60,7 +60,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 		q := &MathQuery{}
 		err = iter.ReadVal(q)
 		if err == nil {
-			eq.Command, err = NewMathCommand(common.RefID, q.Expression)
+			eq.Command, err = NewMathCommand(common.RefID, q.Expression, h.features)
 			eq.Properties = q
 		}
 
@@ -90,7 +90,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			eq.Properties = q
 			eq.Command, err = NewReduceCommand(common.RefID,
-				q.Reducer, referenceVar, mapper)
+				q.Reducer, referenceVar, mapper, h.features)
 		}
 
 	case QueryTypeResample:
@@ -144,37 +144,27 @@ func (h *ExpressionQueryReader) ReadQuery(
 			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
 		}
 		if err == nil {
-			// we only support one condition for now, we might want to turn this in to "OR" expressions later
-			if len(q.Conditions) != 1 {
-				return eq, fmt.Errorf("threshold expression requires exactly one condition")
-			}
-			firstCondition := q.Conditions[0]
-
-			threshold, err := NewThresholdCommand(common.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+			eq.Command, err = newThresholdFromConditions(common.RefID, referenceVar, q.Conditions, q.Window, q.PreviousState, q.Unit, h.features)
 			if err != nil {
-				return eq, fmt.Errorf("invalid condition: %w", err)
+				return eq, err
 			}
-			eq.Command = threshold
 			eq.Properties = q
+		}
 
-			if firstCondition.UnloadEvaluator != nil && h.features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold) {
-				unloading, err := NewThresholdCommand(common.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
-				unloading.Invert = true
-				if err != nil {
-					return eq, fmt.Errorf("invalid unloadCondition: %w", err)
-				}
-				var d Fingerprints
-				if firstCondition.LoadedDimensions != nil {
-					d, err = FingerprintsFromFrame(firstCondition.LoadedDimensions)
-					if err != nil {
-						return eq, fmt.Errorf("failed to parse loaded dimensions: %w", err)
-					}
-				}
-				eq.Command, err = NewHysteresisCommand(common.RefID, referenceVar, *threshold, *unloading, d)
-				if err != nil {
-					return eq, err
-				}
-			}
+	case QueryTypeJoin:
+		q := &JoinQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewJoinCommand(common.RefID, q.Expressions, q.Mode, q.On, q.Field)
+		}
+
+	case QueryTypeMerge:
+		q := &MergeQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewMergeCommand(common.RefID, q.Expressions)
 		}
 
 	default:
@@ -184,6 +184,81 @@ func TestDSQueryError(t *testing.T) {
 	require.Equal(t, fp(42), resp.Responses["C"].Frames[0].Fields[0].At(0))
 }
 
+func TestSeriesLimit(t *testing.T) {
+	series := func(value string) *data.Frame {
+		return data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", data.Labels{"test": value}, []*float64{fp(2)}))
+	}
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{series("1"), series("2"), series("3")}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeCacheService{}, &datafakes.FakeDataSourceService{}, nil, pluginconfig.NewFakePluginRequestConfigProvider())
+
+	features := featuremgmt.WithFeatures()
+	s := Service{
+		cfg:          setting.NewCfg(),
+		dataService:  me,
+		pCtxProvider: pCtxProvider,
+		features:     features,
+		tracer:       tracing.InitializeTracerForTest(),
+		metrics:      newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracing.InitializeTracerForTest(),
+		},
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{
+				From: time.Time{},
+				To:   time.Time{},
+			},
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{}})
+	require.NoError(t, err)
+
+	t.Run("stops the pipeline when a query returns more series than the limit", func(t *testing.T) {
+		_, err := s.ExecutePipeline(WithSeriesLimit(context.Background(), 2), time.Now(), pl)
+		require.ErrorIs(t, err, ErrSeriesLimitExceeded)
+		require.ErrorContains(t, err, "query A returned 3 series (limit: 2)")
+	})
+
+	t.Run("executes the pipeline when the series are within the limit", func(t *testing.T) {
+		resp, err := s.ExecutePipeline(WithSeriesLimit(context.Background(), 3), time.Now(), pl)
+		require.NoError(t, err)
+		require.Len(t, resp.Responses["B"].Frames, 3)
+	})
+
+	t.Run("does not limit series if the limit is not positive", func(t *testing.T) {
+		resp, err := s.ExecutePipeline(WithSeriesLimit(context.Background(), 0), time.Now(), pl)
+		require.NoError(t, err)
+		require.Len(t, resp.Responses["B"].Frames, 3)
+	})
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
@@ -0,0 +1,195 @@
+package sql
+
+import "regexp"
+
+// selectStmt is a parsed SELECT statement.
+type selectStmt struct {
+	with     []cte
+	distinct bool
+	columns  []selectItem
+	from     tableExpr
+	where    expr
+	groupBy  []expr
+	having   expr
+	orderBy  []orderItem
+	limit    int64 // -1 when there is no LIMIT clause
+	offset   int64
+}
+
+// cte is a common table expression declared in a WITH clause.
+type cte struct {
+	name  string
+	query *selectStmt
+}
+
+// selectItem is a single entry in the select list. When star is true the
+// item expands to all columns, or all columns of starTable if set.
+type selectItem struct {
+	expr      expr
+	alias     string
+	text      string
+	star      bool
+	starTable string
+}
+
+type orderItem struct {
+	expr expr
+	desc bool
+}
+
+// tableExpr is a source of rows in the FROM clause.
+type tableExpr interface {
+	isTableExpr()
+}
+
+type tableName struct {
+	name  string
+	alias string
+}
+
+type subquery struct {
+	query *selectStmt
+	alias string
+}
+
+type joinKind int
+
+const (
+	joinInner joinKind = iota
+	joinLeft
+	joinRight
+	joinFull
+	joinCross
+)
+
+type joinExpr struct {
+	kind  joinKind
+	left  tableExpr
+	right tableExpr
+	on    expr
+}
+
+func (*tableName) isTableExpr() {}
+func (*subquery) isTableExpr()  {}
+func (*joinExpr) isTableExpr()  {}
+
+// expr is a scalar expression. Expressions are bound against the schema of
+// the relation they are evaluated over before execution (see binder).
+type expr interface {
+	eval(c *rowCtx) (any, error)
+}
+
+type literal struct {
+	value any
+}
+
+type columnRef struct {
+	table string
+	name  string
+	idx   int
+}
+
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+type binaryExpr struct {
+	op    string
+	left  expr
+	right expr
+}
+
+type isNullExpr struct {
+	x   expr
+	not bool
+}
+
+type inExpr struct {
+	x    expr
+	list []expr
+	not  bool
+}
+
+type betweenExpr struct {
+	x   expr
+	lo  expr
+	hi  expr
+	not bool
+}
+
+type likeExpr struct {
+	x       expr
+	pattern expr
+	not     bool
+
+	// compiled pattern, cached across rows
+	re     *regexp.Regexp
+	source string
+}
+
+type whenClause struct {
+	cond   expr
+	result expr
+}
+
+type caseExpr struct {
+	operand expr
+	whens   []whenClause
+	els     expr
+}
+
+type castExpr struct {
+	x  expr
+	to valueType
+}
+
+type funcKind int
+
+const (
+	funcScalar funcKind = iota
+	funcAggregate
+	funcWindow
+)
+
+// funcCall is a scalar, aggregate or window function call. The kind is
+// resolved at bind time; slot indexes the precomputed aggregate or window
+// value in the row context.
+type funcCall struct {
+	name     string
+	args     []expr
+	star     bool
+	distinct bool
+	over     *windowSpec
+
+	kind funcKind
+	slot int
+}
+
+type windowSpec struct {
+	partitionBy []expr
+	orderBy     []orderItem
+	frame       *windowFrame
+}
+
+type boundKind int
+
+const (
+	boundUnboundedPreceding boundKind = iota
+	boundPreceding
+	boundCurrentRow
+	boundFollowing
+	boundUnboundedFollowing
+)
+
+type frameBound struct {
+	kind   boundKind
+	offset int64
+}
+
+// windowFrame is an explicit ROWS or RANGE frame clause.
+type windowFrame struct {
+	rows  bool
+	start frameBound
+	end   frameBound
+}
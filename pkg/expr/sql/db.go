@@ -1,24 +1,46 @@
 package sql
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
+// DB is an in-memory SQL engine that runs a query over data frames. Each
+// frame is loaded as a table named after its RefID.
 type DB struct {
 }
 
+// TablesList returns the tables referenced by the query.
 func (db *DB) TablesList(rawSQL string) ([]string, error) {
-	return nil, errors.New("not implemented")
-}
-
-func (db *DB) RunCommands(commands []string) (string, error) {
-	return "", errors.New("not implemented")
+	return TablesList(rawSQL)
 }
 
+// QueryFramesInto runs the query over the frames and writes the result into f.
+// The result frame is named after name.
 func (db *DB) QueryFramesInto(name string, query string, frames []*data.Frame, f *data.Frame) error {
-	return errors.New("not implemented")
+	stmt, err := parseSQL(query)
+	if err != nil {
+		return fmt.Errorf("error parsing sql: %w", err)
+	}
+
+	tables, err := framesToTables(frames)
+	if err != nil {
+		return err
+	}
+
+	ex := &executor{tables: tables}
+	rel, err := ex.run(stmt)
+	if err != nil {
+		return fmt.Errorf("error running sql: %w", err)
+	}
+
+	result, err := relationToFrame(name, rel)
+	if err != nil {
+		return err
+	}
+	*f = *result
+	return nil
 }
 
 func NewInMemoryDB() *DB {
@@ -0,0 +1,167 @@
+package sql
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func queryFrames(t *testing.T, query string, frames ...*data.Frame) *data.Frame {
+	t.Helper()
+	db := NewInMemoryDB()
+	result := &data.Frame{}
+	err := db.QueryFramesInto("C", query, frames, result)
+	require.NoError(t, err)
+	return result
+}
+
+func TestQueryFramesInto(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	metrics := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{start, start.Add(time.Minute), start, start.Add(time.Minute)}),
+		data.NewField("host", nil, []string{"a", "a", "b", "b"}),
+		data.NewField("value", nil, []*float64{fp(1), fp(3), fp(10), nil}),
+	)
+	metrics.RefID = "A"
+
+	hosts := data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "c"}),
+		data.NewField("dc", nil, []string{"eu", "us"}),
+		data.NewField("cores", nil, []int32{4, 8}),
+	)
+	hosts.RefID = "B"
+
+	t.Run("select all columns keeps types", func(t *testing.T) {
+		f := queryFrames(t, "SELECT * FROM A", metrics)
+		require.Equal(t, "C", f.Name)
+		require.Equal(t, 4, f.Rows())
+		require.Equal(t, data.FieldTypeTime, f.Fields[0].Type())
+		require.Equal(t, data.FieldTypeString, f.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, f.Fields[2].Type())
+	})
+
+	t.Run("group by with aggregates", func(t *testing.T) {
+		f := queryFrames(t, "SELECT host, sum(value) AS total, count(*) AS n FROM A GROUP BY host ORDER BY total DESC", metrics)
+		require.Equal(t, 2, f.Rows())
+		require.Equal(t, "total", f.Fields[1].Name)
+		host, _ := f.Fields[0].ConcreteAt(0)
+		total, _ := f.Fields[1].ConcreteAt(0)
+		n, _ := f.Fields[2].ConcreteAt(1)
+		require.Equal(t, "b", host)
+		require.Equal(t, 10.0, total)
+		require.Equal(t, int64(2), n)
+		require.Equal(t, data.FieldTypeInt64, f.Fields[2].Type())
+	})
+
+	t.Run("left join makes right side nullable", func(t *testing.T) {
+		f := queryFrames(t, "SELECT A.host, B.dc, B.cores FROM A LEFT JOIN B ON A.host = B.host ORDER BY A.host, A.time", metrics, hosts)
+		require.Equal(t, 4, f.Rows())
+		require.Equal(t, data.FieldTypeNullableString, f.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableInt64, f.Fields[2].Type())
+		dc, ok := f.Fields[1].ConcreteAt(0)
+		require.True(t, ok)
+		require.Equal(t, "eu", dc)
+		_, ok = f.Fields[1].ConcreteAt(3)
+		require.False(t, ok)
+	})
+
+	t.Run("window functions", func(t *testing.T) {
+		f := queryFrames(t, `SELECT host, time,
+			sum(value) OVER (PARTITION BY host ORDER BY time) AS running,
+			row_number() OVER (PARTITION BY host ORDER BY time DESC) AS rn,
+			lag(value) OVER (PARTITION BY host ORDER BY time) AS prev
+			FROM A ORDER BY host, time`, metrics)
+		require.Equal(t, 4, f.Rows())
+		running, _ := f.Fields[2].ConcreteAt(1)
+		rn, _ := f.Fields[3].ConcreteAt(0)
+		prev, _ := f.Fields[4].ConcreteAt(1)
+		require.Equal(t, 4.0, running)
+		require.Equal(t, int64(2), rn)
+		require.Equal(t, 1.0, prev)
+		_, ok := f.Fields[4].ConcreteAt(0)
+		require.False(t, ok)
+	})
+
+	t.Run("expressions without tables", func(t *testing.T) {
+		f := queryFrames(t, "SELECT 1 + 2 AS a, CAST('1.5' AS DOUBLE) AS b, upper('x') AS c, NULL AS d")
+		require.Equal(t, 1, f.Rows())
+		require.Equal(t, data.FieldTypeInt64, f.Fields[0].Type())
+		require.Equal(t, data.FieldTypeFloat64, f.Fields[1].Type())
+		require.Equal(t, data.FieldTypeString, f.Fields[2].Type())
+		require.Equal(t, data.FieldTypeNullableString, f.Fields[3].Type())
+	})
+
+	t.Run("integer arithmetic at the limits of BIGINT", func(t *testing.T) {
+		f := queryFrames(t, "SELECT 9223372036854775806 + 1 AS a, -9223372036854775807 - 1 AS b, -4611686018427387904 * 2 AS c")
+		a, _ := f.Fields[0].ConcreteAt(0)
+		b, _ := f.Fields[1].ConcreteAt(0)
+		c, _ := f.Fields[2].ConcreteAt(0)
+		require.Equal(t, int64(math.MaxInt64), a)
+		require.Equal(t, int64(math.MinInt64), b)
+		require.Equal(t, int64(math.MinInt64), c)
+	})
+
+	t.Run("round clamps the precision", func(t *testing.T) {
+		f := queryFrames(t, "SELECT round(1.25, 1e9) AS a, round(1.25, -1e9) AS b, round(1e300, 30) AS c, round(123, -1e9) AS d")
+		a, _ := f.Fields[0].ConcreteAt(0)
+		b, _ := f.Fields[1].ConcreteAt(0)
+		c, _ := f.Fields[2].ConcreteAt(0)
+		d, _ := f.Fields[3].ConcreteAt(0)
+		require.InDelta(t, 1.25, a, 1e-9)
+		require.Equal(t, 0.0, b)
+		require.Equal(t, 1e300, c)
+		require.Equal(t, int64(0), d)
+	})
+
+	t.Run("labeled series are loaded in long format", func(t *testing.T) {
+		seriesA := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{start, start.Add(time.Minute)}),
+			data.NewField("value", data.Labels{"host": "a"}, []float64{1, 2}),
+		)
+		seriesA.RefID = "S"
+		seriesB := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{start}),
+			data.NewField("value", data.Labels{"host": "b"}, []float64{5}),
+		)
+		seriesB.RefID = "S"
+
+		f := queryFrames(t, "SELECT host, max(value) AS m FROM S GROUP BY host ORDER BY host", seriesA, seriesB)
+		require.Equal(t, 2, f.Rows())
+		m, _ := f.Fields[1].ConcreteAt(1)
+		require.Equal(t, 5.0, m)
+	})
+}
+
+func TestQueryFramesIntoErrors(t *testing.T) {
+	frame := data.NewFrame("", data.NewField("value", nil, []float64{1}))
+	frame.RefID = "A"
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unknown table", query: "SELECT * FROM B"},
+		{name: "unknown column", query: "SELECT nope FROM A"},
+		{name: "aggregate in where", query: "SELECT value FROM A WHERE sum(value) > 1"},
+		{name: "incompatible operands", query: "SELECT value + 'x' FROM A"},
+		{name: "invalid syntax", query: "SELECT FROM"},
+		{name: "integer addition overflow", query: "SELECT 9223372036854775807 + 1"},
+		{name: "integer subtraction overflow", query: "SELECT -9223372036854775807 - 2"},
+		{name: "integer multiplication overflow", query: "SELECT 4611686018427387904 * 2"},
+		{name: "integer round overflow", query: "SELECT round(9223372036854775807, -19)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewInMemoryDB().QueryFramesInto("B", tt.query, []*data.Frame{frame}, &data.Frame{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func fp(f float64) *float64 {
+	return &f
+}
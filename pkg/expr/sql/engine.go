@@ -0,0 +1,866 @@
+package sql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// column describes a column of a relation. table is the qualifier used to
+// resolve table.column references.
+type column struct {
+	table    string
+	name     string
+	typ      valueType
+	nullable bool
+}
+
+// relation is an in-memory table: a schema and its rows.
+type relation struct {
+	columns []column
+	rows    [][]any
+}
+
+// binder resolves column references against a schema, assigns slots to
+// aggregate and window function calls and infers expression types.
+type binder struct {
+	columns []column
+
+	allowAggregates bool
+	allowWindows    bool
+	inAggregate     bool
+
+	aggregates []*funcCall
+	aggTypes   []typeInfo
+	windows    []*funcCall
+	winTypes   []typeInfo
+	bound      map[*funcCall]typeInfo
+}
+
+func newBinder(columns []column) *binder {
+	return &binder{columns: columns, bound: map[*funcCall]typeInfo{}}
+}
+
+// resolve returns the index of the column referenced by table and name.
+func (b *binder) resolve(table, name string) (int, error) {
+	idx := -1
+	for i, c := range b.columns {
+		if !strings.EqualFold(c.name, name) || (table != "" && !strings.EqualFold(c.table, table)) {
+			continue
+		}
+		if idx >= 0 {
+			return -1, fmt.Errorf("column reference %q is ambiguous", name)
+		}
+		idx = i
+	}
+	if idx < 0 {
+		if table != "" {
+			return -1, fmt.Errorf("column %q not found in table %q", name, table)
+		}
+		return -1, fmt.Errorf("column %q not found", name)
+	}
+	return idx, nil
+}
+
+func (b *binder) bindAll(list []expr) ([]typeInfo, error) {
+	types := make([]typeInfo, len(list))
+	for i, e := range list {
+		t, err := b.bind(e)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+func (b *binder) bind(e expr) (typeInfo, error) {
+	switch e := e.(type) {
+	case *literal:
+		return literalType(e.value), nil
+	case *columnRef:
+		idx, err := b.resolve(e.table, e.name)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		e.idx = idx
+		return typeInfo{typ: b.columns[idx].typ, nullable: b.columns[idx].nullable}, nil
+	case *unaryExpr:
+		t, err := b.bind(e.x)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		if e.op == "not" {
+			return typeInfo{typ: typeBool, nullable: t.nullable}, nil
+		}
+		if err := requireNumeric([]typeInfo{t}); err != nil {
+			return typeInfo{}, fmt.Errorf("operator -: %w", err)
+		}
+		if t.typ != typeFloat {
+			t.typ = typeInt
+		}
+		return t, nil
+	case *binaryExpr:
+		l, err := b.bind(e.left)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		r, err := b.bind(e.right)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		switch e.op {
+		case "and", "or", "=", "!=", "<", "<=", ">", ">=":
+			return typeInfo{typ: typeBool, nullable: l.nullable || r.nullable}, nil
+		case "||":
+			return typeInfo{typ: typeString, nullable: l.nullable || r.nullable}, nil
+		}
+		return arithmeticResult(e.op, l, r)
+	case *isNullExpr:
+		if _, err := b.bind(e.x); err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{typ: typeBool}, nil
+	case *inExpr:
+		types, err := b.bindAll(append([]expr{e.x}, e.list...))
+		if err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{typ: typeBool, nullable: anyNullable(types)}, nil
+	case *betweenExpr:
+		types, err := b.bindAll([]expr{e.x, e.lo, e.hi})
+		if err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{typ: typeBool, nullable: anyNullable(types)}, nil
+	case *likeExpr:
+		types, err := b.bindAll([]expr{e.x, e.pattern})
+		if err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{typ: typeBool, nullable: anyNullable(types)}, nil
+	case *caseExpr:
+		return b.bindCase(e)
+	case *castExpr:
+		t, err := b.bind(e.x)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		// a float NaN or infinity cast to an integer becomes NULL
+		nullable := t.nullable || (e.to == typeInt && t.typ == typeFloat)
+		return typeInfo{typ: e.to, nullable: nullable}, nil
+	case *funcCall:
+		if t, ok := b.bound[e]; ok {
+			return t, nil
+		}
+		t, err := b.bindFunc(e)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		b.bound[e] = t
+		return t, nil
+	}
+	return typeInfo{}, fmt.Errorf("unsupported expression %T", e)
+}
+
+func literalType(v any) typeInfo {
+	switch v.(type) {
+	case nil:
+		return typeInfo{typ: typeNull, nullable: true}
+	case bool:
+		return typeInfo{typ: typeBool}
+	case int64:
+		return typeInfo{typ: typeInt}
+	case float64:
+		return typeInfo{typ: typeFloat}
+	case string:
+		return typeInfo{typ: typeString}
+	}
+	return typeInfo{typ: typeTime}
+}
+
+func (b *binder) bindCase(e *caseExpr) (typeInfo, error) {
+	if e.operand != nil {
+		if _, err := b.bind(e.operand); err != nil {
+			return typeInfo{}, err
+		}
+	}
+	result := typeInfo{typ: typeNull}
+	for i, w := range e.whens {
+		if _, err := b.bind(w.cond); err != nil {
+			return typeInfo{}, err
+		}
+		t, err := b.bind(w.result)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		if i == 0 {
+			result = t
+			continue
+		}
+		if result, err = unifyTypes(result, t); err != nil {
+			return typeInfo{}, fmt.Errorf("CASE: %w", err)
+		}
+	}
+	if e.els == nil {
+		result.nullable = true
+		return result, nil
+	}
+	t, err := b.bind(e.els)
+	if err != nil {
+		return typeInfo{}, err
+	}
+	result, err = unifyTypes(result, t)
+	if err != nil {
+		return typeInfo{}, fmt.Errorf("CASE: %w", err)
+	}
+	return result, nil
+}
+
+func (b *binder) bindFunc(e *funcCall) (typeInfo, error) {
+	_, isAggregate := aggregateFuncs[e.name]
+
+	if e.over != nil {
+		if !b.allowWindows {
+			return typeInfo{}, fmt.Errorf("window function %s is not allowed here", e.name)
+		}
+		if !isAggregate && !windowFuncs[e.name] {
+			return typeInfo{}, fmt.Errorf("unknown window function %s", e.name)
+		}
+		if e.distinct {
+			return typeInfo{}, fmt.Errorf("DISTINCT is not supported in window function %s", e.name)
+		}
+		// The window itself is evaluated per output row, which may contain
+		// aggregates when the query is grouped.
+		b.allowWindows = false
+		defer func() { b.allowWindows = true }()
+		if _, err := b.bindAll(e.over.partitionBy); err != nil {
+			return typeInfo{}, err
+		}
+		for _, o := range e.over.orderBy {
+			if _, err := b.bind(o.expr); err != nil {
+				return typeInfo{}, err
+			}
+		}
+		args, err := b.bindAll(e.args)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		t, err := windowResultType(e, args)
+		if err != nil {
+			return typeInfo{}, err
+		}
+		argType := typeInfo{}
+		if len(args) > 0 {
+			argType = args[0]
+		}
+		e.kind = funcWindow
+		e.slot = len(b.windows)
+		b.windows = append(b.windows, e)
+		b.winTypes = append(b.winTypes, argType)
+		return t, nil
+	}
+
+	if windowFuncs[e.name] {
+		return typeInfo{}, fmt.Errorf("window function %s requires an OVER clause", e.name)
+	}
+
+	if isAggregate {
+		switch {
+		case !b.allowAggregates:
+			return typeInfo{}, fmt.Errorf("aggregate function %s is not allowed here", e.name)
+		case b.inAggregate:
+			return typeInfo{}, fmt.Errorf("aggregate function calls can not be nested")
+		case e.star && e.name != "count":
+			return typeInfo{}, fmt.Errorf("%s(*) is not supported", e.name)
+		case !e.star && len(e.args) != 1:
+			return typeInfo{}, fmt.Errorf("%s expects exactly one argument", e.name)
+		}
+		argType := typeInfo{}
+		if !e.star {
+			allowWindows := b.allowWindows
+			b.inAggregate, b.allowWindows = true, false
+			t, err := b.bind(e.args[0])
+			b.inAggregate, b.allowWindows = false, allowWindows
+			if err != nil {
+				return typeInfo{}, err
+			}
+			argType = t
+		}
+		t, err := aggregateFuncs[e.name].returnType(argType)
+		if err != nil {
+			return typeInfo{}, fmt.Errorf("%s: %w", e.name, err)
+		}
+		e.kind = funcAggregate
+		e.slot = len(b.aggregates)
+		b.aggregates = append(b.aggregates, e)
+		b.aggTypes = append(b.aggTypes, argType)
+		return t, nil
+	}
+
+	fn, ok := scalarFuncs[e.name]
+	if !ok {
+		return typeInfo{}, fmt.Errorf("unknown function %s", e.name)
+	}
+	if e.star || e.distinct {
+		return typeInfo{}, fmt.Errorf("invalid arguments to function %s", e.name)
+	}
+	if len(e.args) < fn.minArgs || (fn.maxArgs >= 0 && len(e.args) > fn.maxArgs) {
+		return typeInfo{}, fmt.Errorf("wrong number of arguments to function %s", e.name)
+	}
+	args, err := b.bindAll(e.args)
+	if err != nil {
+		return typeInfo{}, err
+	}
+	t, err := fn.returnType(args)
+	if err != nil {
+		return typeInfo{}, fmt.Errorf("%s: %w", e.name, err)
+	}
+	e.kind = funcScalar
+	return t, nil
+}
+
+// executor runs statements against a set of named tables.
+type executor struct {
+	tables map[string]*relation
+}
+
+func (ex *executor) lookup(name string) (*relation, bool) {
+	rel, ok := ex.tables[strings.ToLower(name)]
+	return rel, ok
+}
+
+// run executes a SELECT statement and returns its result.
+func (ex *executor) run(stmt *selectStmt) (*relation, error) {
+	if len(stmt.with) > 0 {
+		scoped := &executor{tables: make(map[string]*relation, len(ex.tables)+len(stmt.with))}
+		for k, v := range ex.tables {
+			scoped.tables[k] = v
+		}
+		for _, c := range stmt.with {
+			rel, err := scoped.run(c.query)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", c.name, err)
+			}
+			scoped.tables[strings.ToLower(c.name)] = rel
+		}
+		ex = scoped
+	}
+
+	source := &relation{rows: [][]any{{}}}
+	if stmt.from != nil {
+		var err error
+		if source, err = ex.from(stmt.from); err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.where != nil {
+		b := newBinder(source.columns)
+		if _, err := b.bind(stmt.where); err != nil {
+			return nil, fmt.Errorf("WHERE: %w", err)
+		}
+		rows := source.rows[:0:0]
+		for _, row := range source.rows {
+			v, err := stmt.where.eval(&rowCtx{row: row})
+			if err != nil {
+				return nil, err
+			}
+			if truthy(v) {
+				rows = append(rows, row)
+			}
+		}
+		source = &relation{columns: source.columns, rows: rows}
+	}
+
+	return ex.project(stmt, source)
+}
+
+// outputColumn is a bound entry of the expanded select list.
+type outputColumn struct {
+	name  string
+	alias string
+	expr  expr
+	typ   typeInfo
+}
+
+func (ex *executor) project(stmt *selectStmt, source *relation) (*relation, error) {
+	outputs, err := expandSelect(stmt.columns, source.columns)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBinder(source.columns)
+	b.allowAggregates = true
+	b.allowWindows = true
+	for i := range outputs {
+		if outputs[i].typ, err = b.bind(outputs[i].expr); err != nil {
+			return nil, err
+		}
+		if ref, ok := outputs[i].expr.(*columnRef); ok {
+			outputs[i].name = source.columns[ref.idx].name
+		}
+	}
+
+	// GROUP BY, HAVING and ORDER BY may refer to select list aliases and positions.
+	groupBy := make([]expr, len(stmt.groupBy))
+	for i, e := range stmt.groupBy {
+		if groupBy[i], err = resolveOutputRef(e, outputs, b, false); err != nil {
+			return nil, fmt.Errorf("GROUP BY: %w", err)
+		}
+	}
+	var having expr
+	if stmt.having != nil {
+		having = replaceAliases(stmt.having, outputs, b)
+		if _, err := b.bind(having); err != nil {
+			return nil, fmt.Errorf("HAVING: %w", err)
+		}
+	}
+	orderBy := make([]orderItem, len(stmt.orderBy))
+	for i, o := range stmt.orderBy {
+		e, err := resolveOutputRef(o.expr, outputs, b, true)
+		if err != nil {
+			return nil, fmt.Errorf("ORDER BY: %w", err)
+		}
+		if _, err := b.bind(e); err != nil {
+			return nil, fmt.Errorf("ORDER BY: %w", err)
+		}
+		orderBy[i] = orderItem{expr: e, desc: o.desc}
+	}
+
+	gb := newBinder(source.columns)
+	if _, err := gb.bindAll(groupBy); err != nil {
+		return nil, fmt.Errorf("GROUP BY: %w", err)
+	}
+
+	var rows []*rowCtx
+	if len(groupBy) > 0 || len(b.aggregates) > 0 || having != nil {
+		if rows, err = group(source, groupBy, b); err != nil {
+			return nil, err
+		}
+		if having != nil {
+			kept := rows[:0]
+			for _, r := range rows {
+				v, err := having.eval(r)
+				if err != nil {
+					return nil, err
+				}
+				if truthy(v) {
+					kept = append(kept, r)
+				}
+			}
+			rows = kept
+		}
+	} else {
+		rows = make([]*rowCtx, len(source.rows))
+		for i, row := range source.rows {
+			rows[i] = &rowCtx{row: row}
+		}
+	}
+
+	if len(b.windows) > 0 {
+		for _, r := range rows {
+			r.window = make([]any, len(b.windows))
+		}
+		for i, w := range b.windows {
+			// computeWindow reorders the rows it is given
+			if err := computeWindow(w, b.winTypes[i], append([]*rowCtx{}, rows...)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(orderBy) > 0 {
+		if _, err := sortRows(rows, orderBy); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &relation{columns: make([]column, len(outputs))}
+	for i, o := range outputs {
+		name := o.alias
+		if name == "" {
+			name = o.name
+		}
+		result.columns[i] = column{name: name, typ: o.typ.typ, nullable: o.typ.nullable}
+	}
+
+	seen := map[string]bool{}
+	skipped := int64(0)
+	for _, r := range rows {
+		if stmt.limit >= 0 && int64(len(result.rows)) >= stmt.limit {
+			break
+		}
+		out := make([]any, len(outputs))
+		for i, o := range outputs {
+			v, err := o.expr.eval(r)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		if stmt.distinct {
+			k := groupKey(out)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+		}
+		if skipped < stmt.offset {
+			skipped++
+			continue
+		}
+		result.rows = append(result.rows, out)
+	}
+	return result, nil
+}
+
+// expandSelect expands * and table.* entries of the select list.
+func expandSelect(items []selectItem, columns []column) ([]outputColumn, error) {
+	var outputs []outputColumn
+	for _, item := range items {
+		if !item.star {
+			outputs = append(outputs, outputColumn{name: item.text, alias: item.alias, expr: item.expr})
+			continue
+		}
+		found := false
+		for i, c := range columns {
+			if item.starTable != "" && !strings.EqualFold(c.table, item.starTable) {
+				continue
+			}
+			found = true
+			outputs = append(outputs, outputColumn{name: c.name, expr: &columnRef{table: c.table, name: c.name, idx: i}})
+		}
+		if !found && item.starTable != "" {
+			return nil, fmt.Errorf("table %q not found", item.starTable)
+		}
+	}
+	return outputs, nil
+}
+
+// resolveOutputRef resolves positional (GROUP BY 1) and alias references to
+// select list entries. For ORDER BY aliases take precedence over source
+// columns, for GROUP BY source columns do.
+func resolveOutputRef(e expr, outputs []outputColumn, b *binder, preferAlias bool) (expr, error) {
+	if lit, ok := e.(*literal); ok {
+		if pos, ok := lit.value.(int64); ok {
+			if pos < 1 || pos > int64(len(outputs)) {
+				return nil, fmt.Errorf("position %d is not in select list", pos)
+			}
+			return outputs[pos-1].expr, nil
+		}
+	}
+	ref, ok := e.(*columnRef)
+	if !ok {
+		return replaceAliases(e, outputs, b), nil
+	}
+	if ref.table == "" {
+		_, err := b.resolve("", ref.name)
+		sourceColumn := err == nil
+		for _, o := range outputs {
+			if o.alias != "" && strings.EqualFold(o.alias, ref.name) && (preferAlias || !sourceColumn) {
+				return o.expr, nil
+			}
+		}
+	}
+	return e, nil
+}
+
+// replaceAliases substitutes references to select list aliases that do not
+// name a source column with the aliased expression.
+func replaceAliases(e expr, outputs []outputColumn, b *binder) expr {
+	return rewrite(e, func(e expr) expr {
+		ref, ok := e.(*columnRef)
+		if !ok || ref.table != "" {
+			return e
+		}
+		if _, err := b.resolve("", ref.name); err == nil {
+			return e
+		}
+		for _, o := range outputs {
+			if o.alias != "" && strings.EqualFold(o.alias, ref.name) {
+				return o.expr
+			}
+		}
+		return e
+	})
+}
+
+// rewrite applies f bottom-up to every node of an expression tree.
+func rewrite(e expr, f func(expr) expr) expr {
+	switch x := e.(type) {
+	case *unaryExpr:
+		return f(&unaryExpr{op: x.op, x: rewrite(x.x, f)})
+	case *binaryExpr:
+		return f(&binaryExpr{op: x.op, left: rewrite(x.left, f), right: rewrite(x.right, f)})
+	case *isNullExpr:
+		return f(&isNullExpr{x: rewrite(x.x, f), not: x.not})
+	case *inExpr:
+		list := make([]expr, len(x.list))
+		for i, item := range x.list {
+			list[i] = rewrite(item, f)
+		}
+		return f(&inExpr{x: rewrite(x.x, f), list: list, not: x.not})
+	case *betweenExpr:
+		return f(&betweenExpr{x: rewrite(x.x, f), lo: rewrite(x.lo, f), hi: rewrite(x.hi, f), not: x.not})
+	case *likeExpr:
+		return f(&likeExpr{x: rewrite(x.x, f), pattern: rewrite(x.pattern, f), not: x.not})
+	case *caseExpr:
+		c := &caseExpr{whens: make([]whenClause, len(x.whens))}
+		if x.operand != nil {
+			c.operand = rewrite(x.operand, f)
+		}
+		for i, w := range x.whens {
+			c.whens[i] = whenClause{cond: rewrite(w.cond, f), result: rewrite(w.result, f)}
+		}
+		if x.els != nil {
+			c.els = rewrite(x.els, f)
+		}
+		return f(c)
+	case *castExpr:
+		return f(&castExpr{x: rewrite(x.x, f), to: x.to})
+	}
+	// Function calls are left untouched: aggregates and window functions are
+	// bound once and aliases can not be referenced inside them.
+	return f(e)
+}
+
+// group partitions the source rows by the GROUP BY keys and computes the
+// aggregates bound in b for every group.
+func group(source *relation, groupBy []expr, b *binder) ([]*rowCtx, error) {
+	groups := map[string][]*rowCtx{}
+	var order []string
+	for _, row := range source.rows {
+		r := &rowCtx{row: row}
+		values := make([]any, len(groupBy))
+		for i, e := range groupBy {
+			v, err := e.eval(r)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		k := groupKey(values)
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], r)
+	}
+	// Without GROUP BY an aggregate query always returns one row, even for
+	// empty input.
+	if len(groupBy) == 0 && len(order) == 0 {
+		order = append(order, "")
+		groups[""] = nil
+	}
+
+	result := make([]*rowCtx, 0, len(order))
+	for _, k := range order {
+		members := groups[k]
+		ctx := &rowCtx{aggs: make([]any, len(b.aggregates))}
+		if len(members) > 0 {
+			ctx.row = members[0].row
+		} else {
+			ctx.row = make([]any, len(source.columns))
+		}
+		for i, f := range b.aggregates {
+			v, err := aggregate(f, b.aggTypes[i], members)
+			if err != nil {
+				return nil, err
+			}
+			ctx.aggs[i] = v
+		}
+		result = append(result, ctx)
+	}
+	return result, nil
+}
+
+// from builds the relation described by a FROM clause.
+func (ex *executor) from(t tableExpr) (*relation, error) {
+	switch t := t.(type) {
+	case *tableName:
+		rel, ok := ex.lookup(t.name)
+		if !ok {
+			return nil, fmt.Errorf("table %q not found, available tables are %s", t.name, strings.Join(ex.sortedTableNames(), ", "))
+		}
+		return qualify(rel, t.name, t.alias), nil
+	case *subquery:
+		rel, err := ex.run(t.query)
+		if err != nil {
+			return nil, err
+		}
+		return qualify(rel, "", t.alias), nil
+	case *joinExpr:
+		return ex.join(t)
+	}
+	return nil, fmt.Errorf("unsupported table expression %T", t)
+}
+
+// qualify returns the relation with its columns qualified by alias, or name
+// if there is no alias.
+func qualify(rel *relation, name, alias string) *relation {
+	if alias != "" {
+		name = alias
+	}
+	columns := make([]column, len(rel.columns))
+	for i, c := range rel.columns {
+		c.table = name
+		columns[i] = c
+	}
+	return &relation{columns: columns, rows: rel.rows}
+}
+
+func (ex *executor) join(j *joinExpr) (*relation, error) {
+	left, err := ex.from(j.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := ex.from(j.right)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]column, 0, len(left.columns)+len(right.columns))
+	for _, c := range left.columns {
+		c.nullable = c.nullable || j.kind == joinRight || j.kind == joinFull
+		columns = append(columns, c)
+	}
+	for _, c := range right.columns {
+		c.nullable = c.nullable || j.kind == joinLeft || j.kind == joinFull
+		columns = append(columns, c)
+	}
+	result := &relation{columns: columns}
+
+	if j.on != nil {
+		if _, err := newBinder(columns).bind(j.on); err != nil {
+			return nil, fmt.Errorf("ON: %w", err)
+		}
+	}
+
+	leftKeys, rightKeys := equiJoinKeys(j.on, columns, len(left.columns))
+	var index map[string][]int
+	if len(leftKeys) > 0 {
+		index = map[string][]int{}
+		for i, row := range right.rows {
+			if k, ok := joinKey(row, rightKeys, len(left.columns)); ok {
+				index[k] = append(index[k], i)
+			}
+		}
+	}
+
+	combine := func(l, r []any) []any {
+		row := make([]any, len(columns))
+		if l != nil {
+			copy(row, l)
+		}
+		if r != nil {
+			copy(row[len(left.columns):], r)
+		}
+		return row
+	}
+
+	allRight := make([]int, len(right.rows))
+	for i := range allRight {
+		allRight[i] = i
+	}
+	rightMatched := make([]bool, len(right.rows))
+	for _, l := range left.rows {
+		candidates := allRight
+		if index != nil {
+			candidates = nil
+			if k, ok := joinKey(l, leftKeys, 0); ok {
+				candidates = index[k]
+			}
+		}
+		matched := false
+		for _, ri := range candidates {
+			row := combine(l, right.rows[ri])
+			if j.on != nil {
+				v, err := j.on.eval(&rowCtx{row: row})
+				if err != nil {
+					return nil, err
+				}
+				if !truthy(v) {
+					continue
+				}
+			}
+			matched = true
+			rightMatched[ri] = true
+			result.rows = append(result.rows, row)
+		}
+		if !matched && (j.kind == joinLeft || j.kind == joinFull) {
+			result.rows = append(result.rows, combine(l, nil))
+		}
+	}
+	if j.kind == joinRight || j.kind == joinFull {
+		for i, r := range right.rows {
+			if !rightMatched[i] {
+				result.rows = append(result.rows, combine(nil, r))
+			}
+		}
+	}
+	return result, nil
+}
+
+// equiJoinKeys extracts the column pairs of a join condition that is a
+// conjunction containing equalities between a left and a right column, so the
+// join can be done with a hash lookup instead of comparing every pair of rows.
+func equiJoinKeys(on expr, columns []column, leftWidth int) ([]int, []int) {
+	var leftKeys, rightKeys []int
+	var visit func(e expr)
+	visit = func(e expr) {
+		b, ok := e.(*binaryExpr)
+		if !ok {
+			return
+		}
+		switch b.op {
+		case "and":
+			visit(b.left)
+			visit(b.right)
+		case "=":
+			l, lok := b.left.(*columnRef)
+			r, rok := b.right.(*columnRef)
+			if !lok || !rok {
+				return
+			}
+			if l.idx >= leftWidth && r.idx < leftWidth {
+				l, r = r, l
+			}
+			// Hashing only matches values of the same kind, mixed types are
+			// left to the comparison in the join condition.
+			lt, rt := columns[l.idx].typ, columns[r.idx].typ
+			if lt != rt && !(lt.numeric() && rt.numeric()) {
+				return
+			}
+			if l.idx < leftWidth && r.idx >= leftWidth {
+				leftKeys = append(leftKeys, l.idx)
+				rightKeys = append(rightKeys, r.idx)
+			}
+		}
+	}
+	visit(on)
+	return leftKeys, rightKeys
+}
+
+// joinKey builds the hash key of a row for the given column indexes. Rows
+// with a NULL key never match.
+func joinKey(row []any, keys []int, offset int) (string, bool) {
+	values := make([]any, len(keys))
+	for i, k := range keys {
+		v := row[k-offset]
+		if v == nil {
+			return "", false
+		}
+		values[i] = v
+	}
+	return groupKey(values), true
+}
+
+// sortedTableNames returns the names of the loaded tables, used in error messages.
+func (ex *executor) sortedTableNames() []string {
+	names := make([]string, 0, len(ex.tables))
+	for k := range ex.tables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,383 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// rowCtx holds the values an expression is evaluated against: the current
+// row of the source relation and, for grouped or windowed queries, the
+// aggregate and window function results computed for that row.
+type rowCtx struct {
+	row    []any
+	aggs   []any
+	window []any
+}
+
+func (e *literal) eval(_ *rowCtx) (any, error) {
+	return e.value, nil
+}
+
+func (e *columnRef) eval(c *rowCtx) (any, error) {
+	if e.idx < 0 || e.idx >= len(c.row) {
+		return nil, fmt.Errorf("column %s is not bound", e.name)
+	}
+	return c.row[e.idx], nil
+}
+
+func (e *unaryExpr) eval(c *rowCtx) (any, error) {
+	v, err := e.x.eval(c)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch e.op {
+	case "not":
+		return !truthy(v), nil
+	case "-":
+		switch x := v.(type) {
+		case int64:
+			return -x, nil
+		case float64:
+			return -x, nil
+		case bool:
+			return -boolToInt(x), nil
+		}
+		return nil, fmt.Errorf("can not negate %T", v)
+	}
+	return nil, fmt.Errorf("unknown operator %s", e.op)
+}
+
+func (e *binaryExpr) eval(c *rowCtx) (any, error) {
+	switch e.op {
+	case "and", "or":
+		return e.evalLogical(c)
+	}
+
+	l, err := e.left.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.right.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+
+	switch e.op {
+	case "=", "!=", "<", "<=", ">", ">=":
+		cmp, err := compareValues(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch e.op {
+		case "=":
+			return cmp == 0, nil
+		case "!=":
+			return cmp != 0, nil
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		}
+		return cmp >= 0, nil
+	case "||":
+		return formatValue(l) + formatValue(r), nil
+	}
+	return arithmetic(e.op, l, r)
+}
+
+// evalLogical implements three-valued AND / OR.
+func (e *binaryExpr) evalLogical(c *rowCtx) (any, error) {
+	l, err := e.left.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	short := e.op == "or"
+	if l != nil && truthy(l) == short {
+		return short, nil
+	}
+	r, err := e.right.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	if r != nil && truthy(r) == short {
+		return short, nil
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	return !short, nil
+}
+
+// errIntegerOverflow is returned when the result of an integer operation does not fit in a BIGINT.
+var errIntegerOverflow = errors.New("BIGINT value is out of range")
+
+// arithmetic applies a numeric operator. Integer operands produce an integer
+// result except for division, and an error if the result overflows.
+// Division or modulo by zero yields NULL.
+func arithmetic(op string, l, r any) (any, error) {
+	if b, ok := l.(bool); ok {
+		l = boolToInt(b)
+	}
+	if b, ok := r.(bool); ok {
+		r = boolToInt(b)
+	}
+	li, lInt := l.(int64)
+	ri, rInt := r.(int64)
+	if lInt && rInt && op != "/" {
+		switch op {
+		case "+":
+			sum := li + ri
+			if (sum > li) != (ri > 0) {
+				return nil, fmt.Errorf("%w: %d + %d", errIntegerOverflow, li, ri)
+			}
+			return sum, nil
+		case "-":
+			diff := li - ri
+			if (diff < li) != (ri > 0) {
+				return nil, fmt.Errorf("%w: %d - %d", errIntegerOverflow, li, ri)
+			}
+			return diff, nil
+		case "*":
+			if li == 0 || ri == 0 {
+				return int64(0), nil
+			}
+			product := li * ri
+			if product/ri != li || (li == -1 && ri == math.MinInt64) || (ri == -1 && li == math.MinInt64) {
+				return nil, fmt.Errorf("%w: %d * %d", errIntegerOverflow, li, ri)
+			}
+			return product, nil
+		case "%":
+			if ri == 0 {
+				return nil, nil
+			}
+			return li % ri, nil
+		}
+	}
+
+	lf, err := toFloat(l)
+	if err != nil {
+		return nil, err
+	}
+	rf, err := toFloat(r)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, nil
+		}
+		return lf / rf, nil
+	case "%":
+		if rf == 0 {
+			return nil, nil
+		}
+		return math.Mod(lf, rf), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+func toFloat(v any) (float64, error) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), nil
+	case float64:
+		return x, nil
+	case bool:
+		return float64(boolToInt(x)), nil
+	}
+	return 0, fmt.Errorf("expected a number but got %T", v)
+}
+
+func (e *isNullExpr) eval(c *rowCtx) (any, error) {
+	v, err := e.x.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	return (v == nil) != e.not, nil
+}
+
+func (e *inExpr) eval(c *rowCtx) (any, error) {
+	v, err := e.x.eval(c)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	sawNull := false
+	for _, item := range e.list {
+		iv, err := item.eval(c)
+		if err != nil {
+			return nil, err
+		}
+		if iv == nil {
+			sawNull = true
+			continue
+		}
+		cmp, err := compareValues(v, iv)
+		if err != nil {
+			return nil, err
+		}
+		if cmp == 0 {
+			return !e.not, nil
+		}
+	}
+	if sawNull {
+		return nil, nil
+	}
+	return e.not, nil
+}
+
+func (e *betweenExpr) eval(c *rowCtx) (any, error) {
+	v, err := e.x.eval(c)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	lo, err := e.lo.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := e.hi.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	if lo == nil || hi == nil {
+		return nil, nil
+	}
+	cmpLo, err := compareValues(v, lo)
+	if err != nil {
+		return nil, err
+	}
+	cmpHi, err := compareValues(v, hi)
+	if err != nil {
+		return nil, err
+	}
+	return (cmpLo >= 0 && cmpHi <= 0) != e.not, nil
+}
+
+func (e *likeExpr) eval(c *rowCtx) (any, error) {
+	v, err := e.x.eval(c)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	p, err := e.pattern.eval(c)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	pattern := formatValue(p)
+	if e.re == nil || e.source != pattern {
+		if e.re, err = likeRegexp(pattern); err != nil {
+			return nil, err
+		}
+		e.source = pattern
+	}
+	return e.re.MatchString(formatValue(v)) != e.not, nil
+}
+
+// likeRegexp translates a LIKE pattern into a case-insensitive regular
+// expression where % matches any sequence and _ matches a single character.
+func likeRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			sb.WriteString(".*")
+		case r == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+func (e *caseExpr) eval(c *rowCtx) (any, error) {
+	var operand any
+	if e.operand != nil {
+		v, err := e.operand.eval(c)
+		if err != nil {
+			return nil, err
+		}
+		operand = v
+	}
+	for _, w := range e.whens {
+		cond, err := w.cond.eval(c)
+		if err != nil {
+			return nil, err
+		}
+		matched := false
+		if e.operand != nil {
+			if operand != nil && cond != nil {
+				cmp, err := compareValues(operand, cond)
+				if err != nil {
+					return nil, err
+				}
+				matched = cmp == 0
+			}
+		} else {
+			matched = truthy(cond)
+		}
+		if matched {
+			return w.result.eval(c)
+		}
+	}
+	if e.els != nil {
+		return e.els.eval(c)
+	}
+	return nil, nil
+}
+
+func (e *castExpr) eval(c *rowCtx) (any, error) {
+	v, err := e.x.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	return convertValue(v, e.to)
+}
+
+func (e *funcCall) eval(c *rowCtx) (any, error) {
+	switch e.kind {
+	case funcAggregate:
+		return c.aggs[e.slot], nil
+	case funcWindow:
+		return c.window[e.slot], nil
+	}
+
+	fn := scalarFuncs[e.name]
+	args := make([]any, len(e.args))
+	for i, a := range e.args {
+		v, err := a.eval(c)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil && !fn.handlesNull {
+			return nil, nil
+		}
+		args[i] = v
+	}
+	v, err := fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.name, err)
+	}
+	return v, nil
+}
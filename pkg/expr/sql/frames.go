@@ -0,0 +1,241 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// framesToTables loads frames as tables named after their RefID. Frames that
+// share a RefID, for example one frame per series, are combined into a single
+// table with the union of their columns. Labeled wide time series are
+// converted to the long format first so that labels become columns.
+func framesToTables(frames []*data.Frame) (map[string]*relation, error) {
+	tables := map[string]*relation{}
+	var order []string
+	grouped := map[string][]*data.Frame{}
+	for _, f := range frames {
+		if f == nil {
+			continue
+		}
+		name := f.RefID
+		if name == "" {
+			name = f.Name
+		}
+		key := strings.ToLower(name)
+		if _, ok := grouped[key]; !ok {
+			order = append(order, key)
+		}
+		grouped[key] = append(grouped[key], f)
+	}
+
+	for _, key := range order {
+		rel, err := framesToRelation(grouped[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load table %s: %w", grouped[key][0].RefID, err)
+		}
+		tables[key] = rel
+	}
+	return tables, nil
+}
+
+func framesToRelation(frames []*data.Frame) (*relation, error) {
+	rel := &relation{}
+	index := map[string]int{}
+
+	for _, f := range frames {
+		if hasLabels(f) && f.TimeSeriesSchema().Type == data.TimeSeriesTypeWide {
+			long, err := data.WideToLong(f)
+			if err != nil {
+				return nil, err
+			}
+			f = long
+		}
+
+		// map the frame fields to relation columns, adding new columns as needed
+		mapping := make([]int, len(f.Fields))
+		for i, field := range f.Fields {
+			typ, err := fieldValueType(field.Type())
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", field.Name, err)
+			}
+			name := field.Name
+			if name == "" {
+				name = fmt.Sprintf("field_%d", i)
+			}
+			idx, ok := index[name]
+			if !ok {
+				idx = len(rel.columns)
+				index[name] = idx
+				// rows loaded from earlier frames have no value for this column
+				rel.columns = append(rel.columns, column{name: name, typ: typ, nullable: field.Type().Nullable() || len(rel.rows) > 0})
+				for j := range rel.rows {
+					rel.rows[j] = append(rel.rows[j], nil)
+				}
+			} else {
+				c := &rel.columns[idx]
+				unified, err := unifyTypes(typeInfo{typ: c.typ, nullable: c.nullable}, typeInfo{typ: typ, nullable: field.Type().Nullable()})
+				if err != nil {
+					return nil, fmt.Errorf("column %q: %w", name, err)
+				}
+				c.typ, c.nullable = unified.typ, unified.nullable
+			}
+			mapping[i] = idx
+		}
+
+		seen := make([]bool, len(rel.columns))
+		for _, idx := range mapping {
+			seen[idx] = true
+		}
+		for i, c := range rel.columns {
+			if !seen[i] {
+				rel.columns[i].nullable = c.nullable || f.Rows() > 0
+			}
+		}
+
+		for r := 0; r < f.Rows(); r++ {
+			row := make([]any, len(rel.columns))
+			for i, field := range f.Fields {
+				v, ok := field.ConcreteAt(r)
+				if !ok {
+					continue
+				}
+				row[mapping[i]] = normalizeValue(v)
+			}
+			rel.rows = append(rel.rows, row)
+		}
+	}
+
+	// Columns that were widened from integer to float need their values converted.
+	for i, c := range rel.columns {
+		if c.typ != typeFloat {
+			continue
+		}
+		for _, row := range rel.rows {
+			if v, ok := row[i].(int64); ok {
+				row[i] = float64(v)
+			}
+		}
+	}
+	return rel, nil
+}
+
+func hasLabels(f *data.Frame) bool {
+	for _, field := range f.Fields {
+		if len(field.Labels) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValueType maps a frame field type to a SQL column type. Unsigned 64-bit
+// integers are loaded as floats since they may not fit into a BIGINT.
+func fieldValueType(t data.FieldType) (valueType, error) {
+	switch t {
+	case data.FieldTypeInt8, data.FieldTypeNullableInt8,
+		data.FieldTypeInt16, data.FieldTypeNullableInt16,
+		data.FieldTypeInt32, data.FieldTypeNullableInt32,
+		data.FieldTypeInt64, data.FieldTypeNullableInt64,
+		data.FieldTypeUint8, data.FieldTypeNullableUint8,
+		data.FieldTypeUint16, data.FieldTypeNullableUint16,
+		data.FieldTypeUint32, data.FieldTypeNullableUint32:
+		return typeInt, nil
+	case data.FieldTypeUint64, data.FieldTypeNullableUint64,
+		data.FieldTypeFloat32, data.FieldTypeNullableFloat32,
+		data.FieldTypeFloat64, data.FieldTypeNullableFloat64:
+		return typeFloat, nil
+	case data.FieldTypeString, data.FieldTypeNullableString,
+		data.FieldTypeJSON, data.FieldTypeNullableJSON:
+		return typeString, nil
+	case data.FieldTypeBool, data.FieldTypeNullableBool:
+		return typeBool, nil
+	case data.FieldTypeTime, data.FieldTypeNullableTime:
+		return typeTime, nil
+	}
+	return typeNull, fmt.Errorf("unsupported field type %s", t)
+}
+
+// normalizeValue converts a concrete field value to its runtime representation.
+func normalizeValue(v any) any {
+	switch x := v.(type) {
+	case int8:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case int64:
+		return x
+	case uint8:
+		return int64(x)
+	case uint16:
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint64:
+		return float64(x)
+	case float32:
+		return float64(x)
+	case json.RawMessage:
+		return string(x)
+	}
+	return v
+}
+
+// relationToFrame converts a query result to a frame. Field types follow the
+// static column types; a column is only nullable if it can contain NULL.
+func relationToFrame(name string, rel *relation) (*data.Frame, error) {
+	fields := make([]*data.Field, len(rel.columns))
+	for i, c := range rel.columns {
+		nullable := c.nullable
+		if !nullable {
+			// guard against values that turned out NULL at runtime
+			for _, row := range rel.rows {
+				if row[i] == nil {
+					nullable = true
+					break
+				}
+			}
+		}
+		field := data.NewFieldFromFieldType(frameFieldType(c.typ, nullable), len(rel.rows))
+		field.Name = c.name
+		for r, row := range rel.rows {
+			if row[i] == nil {
+				continue
+			}
+			v, err := convertValue(row[i], c.typ)
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %w", c.name, err)
+			}
+			if v == nil {
+				continue
+			}
+			field.SetConcrete(r, v)
+		}
+		fields[i] = field
+	}
+	return data.NewFrame(name, fields...), nil
+}
+
+func frameFieldType(t valueType, nullable bool) data.FieldType {
+	var ft data.FieldType
+	switch t {
+	case typeBool:
+		ft = data.FieldTypeBool
+	case typeInt:
+		ft = data.FieldTypeInt64
+	case typeFloat:
+		ft = data.FieldTypeFloat64
+	case typeTime:
+		ft = data.FieldTypeTime
+	default:
+		ft = data.FieldTypeString
+	}
+	if nullable {
+		return ft.NullableType()
+	}
+	return ft
+}
@@ -0,0 +1,1026 @@
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// scalarFunc is a function evaluated once per row.
+type scalarFunc struct {
+	minArgs int
+	maxArgs int // -1 for variadic functions
+	// handlesNull is set for functions that are called with NULL arguments,
+	// all other functions return NULL when any argument is NULL.
+	handlesNull bool
+	returnType  func(args []typeInfo) (typeInfo, error)
+	call        func(args []any) (any, error)
+}
+
+var scalarFuncs map[string]scalarFunc
+
+// maxRoundDigits is the maximum number of digits round rounds to, before or after the decimal point.
+const maxRoundDigits = 30
+
+func init() {
+	numeric := func(f func(float64) float64) scalarFunc {
+		return scalarFunc{minArgs: 1, maxArgs: 1, returnType: floatResult(true), call: func(args []any) (any, error) {
+			x, err := toFloat(args[0])
+			if err != nil {
+				return nil, err
+			}
+			v := f(x)
+			if math.IsNaN(v) && !math.IsNaN(x) {
+				// outside of the function domain, e.g. sqrt(-1)
+				return nil, nil
+			}
+			return v, nil
+		}}
+	}
+	sameNumeric := func(fi func(int64) int64, ff func(float64) float64) scalarFunc {
+		return scalarFunc{minArgs: 1, maxArgs: 1, returnType: sameNumericResult, call: func(args []any) (any, error) {
+			switch x := args[0].(type) {
+			case int64:
+				return fi(x), nil
+			case float64:
+				return ff(x), nil
+			case bool:
+				return fi(boolToInt(x)), nil
+			}
+			return nil, fmt.Errorf("expected a number but got %T", args[0])
+		}}
+	}
+	str := func(f func(string) any, ret valueType) scalarFunc {
+		return scalarFunc{minArgs: 1, maxArgs: 1, returnType: fixedResult(ret), call: func(args []any) (any, error) {
+			return f(formatValue(args[0])), nil
+		}}
+	}
+	identity := func(x int64) int64 { return x }
+
+	scalarFuncs = map[string]scalarFunc{
+		"abs": sameNumeric(func(x int64) int64 {
+			if x < 0 {
+				return -x
+			}
+			return x
+		}, math.Abs),
+		"ceil":    sameNumeric(identity, math.Ceil),
+		"ceiling": sameNumeric(identity, math.Ceil),
+		"floor":   sameNumeric(identity, math.Floor),
+		"sign": {minArgs: 1, maxArgs: 1, returnType: fixedResult(typeInt), call: func(args []any) (any, error) {
+			x, err := toFloat(args[0])
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case x > 0:
+				return int64(1), nil
+			case x < 0:
+				return int64(-1), nil
+			}
+			return int64(0), nil
+		}},
+		"round": {minArgs: 1, maxArgs: 2, returnType: sameNumericResult, call: func(args []any) (any, error) {
+			digits := int64(0)
+			if len(args) > 1 {
+				d, err := convertValue(args[1], typeInt)
+				if err != nil {
+					return nil, err
+				}
+				// Like MySQL, round to at most 30 digits on either side of the decimal point.
+				digits = min(max(d.(int64), -maxRoundDigits), maxRoundDigits)
+			}
+			switch x := args[0].(type) {
+			case int64:
+				if digits >= 0 {
+					return x, nil
+				}
+				p := math.Pow10(int(-digits))
+				rounded := math.Round(float64(x)/p) * p
+				if rounded >= math.MaxInt64 || rounded < math.MinInt64 {
+					return nil, fmt.Errorf("%w: round(%d, %d)", errIntegerOverflow, x, digits)
+				}
+				return int64(rounded), nil
+			case float64:
+				p := math.Pow10(int(digits))
+				scaled := x * p
+				if math.IsInf(scaled, 0) && !math.IsInf(x, 0) {
+					// The value is too large to have digits at that precision.
+					return x, nil
+				}
+				return math.Round(scaled) / p, nil
+			}
+			return nil, fmt.Errorf("expected a number but got %T", args[0])
+		}},
+		"sqrt":  numeric(math.Sqrt),
+		"exp":   numeric(math.Exp),
+		"ln":    numeric(logDomain(math.Log)),
+		"log10": numeric(logDomain(math.Log10)),
+		"log2":  numeric(logDomain(math.Log2)),
+		"log": {minArgs: 1, maxArgs: 2, returnType: floatResult(true), call: func(args []any) (any, error) {
+			x, err := toFloat(args[len(args)-1])
+			if err != nil {
+				return nil, err
+			}
+			if x <= 0 {
+				return nil, nil
+			}
+			if len(args) == 1 {
+				return math.Log(x), nil
+			}
+			base, err := toFloat(args[0])
+			if err != nil {
+				return nil, err
+			}
+			if base <= 0 || base == 1 {
+				return nil, nil
+			}
+			return math.Log(x) / math.Log(base), nil
+		}},
+		"power": {minArgs: 2, maxArgs: 2, returnType: floatResult(false), call: callPow},
+		"pow":   {minArgs: 2, maxArgs: 2, returnType: floatResult(false), call: callPow},
+		"mod": {minArgs: 2, maxArgs: 2, returnType: func(args []typeInfo) (typeInfo, error) {
+			return arithmeticResult("%", args[0], args[1])
+		}, call: func(args []any) (any, error) {
+			return arithmetic("%", args[0], args[1])
+		}},
+		"greatest": {minArgs: 1, maxArgs: -1, returnType: unifiedResult, call: func(args []any) (any, error) {
+			return extreme(args, 1)
+		}},
+		"least": {minArgs: 1, maxArgs: -1, returnType: unifiedResult, call: func(args []any) (any, error) {
+			return extreme(args, -1)
+		}},
+
+		"coalesce": {minArgs: 1, maxArgs: -1, handlesNull: true, returnType: coalesceResult, call: callCoalesce},
+		"ifnull":   {minArgs: 2, maxArgs: 2, handlesNull: true, returnType: coalesceResult, call: callCoalesce},
+		"nullif": {minArgs: 2, maxArgs: 2, handlesNull: true, returnType: func(args []typeInfo) (typeInfo, error) {
+			return typeInfo{typ: args[0].typ, nullable: true}, nil
+		}, call: func(args []any) (any, error) {
+			if args[0] == nil || args[1] == nil {
+				return args[0], nil
+			}
+			cmp, err := compareValues(args[0], args[1])
+			if err != nil || cmp == 0 {
+				return nil, err
+			}
+			return args[0], nil
+		}},
+		"if": {minArgs: 3, maxArgs: 3, handlesNull: true, returnType: func(args []typeInfo) (typeInfo, error) {
+			return unifyTypes(args[1], args[2])
+		}, call: func(args []any) (any, error) {
+			if truthy(args[0]) {
+				return args[1], nil
+			}
+			return args[2], nil
+		}},
+
+		"upper": str(func(s string) any { return strings.ToUpper(s) }, typeString),
+		"lower": str(func(s string) any { return strings.ToLower(s) }, typeString),
+		"trim":  str(func(s string) any { return strings.TrimSpace(s) }, typeString),
+		"ltrim": str(func(s string) any { return strings.TrimLeft(s, " \t\r\n") }, typeString),
+		"rtrim": str(func(s string) any { return strings.TrimRight(s, " \t\r\n") }, typeString),
+		"length": str(func(s string) any {
+			return int64(utf8.RuneCountInString(s))
+		}, typeInt),
+		"char_length": str(func(s string) any {
+			return int64(utf8.RuneCountInString(s))
+		}, typeInt),
+		"concat": {minArgs: 1, maxArgs: -1, returnType: fixedResult(typeString), call: func(args []any) (any, error) {
+			var sb strings.Builder
+			for _, a := range args {
+				sb.WriteString(formatValue(a))
+			}
+			return sb.String(), nil
+		}},
+		"substr":    {minArgs: 2, maxArgs: 3, returnType: fixedResult(typeString), call: callSubstr},
+		"substring": {minArgs: 2, maxArgs: 3, returnType: fixedResult(typeString), call: callSubstr},
+		"replace": {minArgs: 3, maxArgs: 3, returnType: fixedResult(typeString), call: func(args []any) (any, error) {
+			return strings.ReplaceAll(formatValue(args[0]), formatValue(args[1]), formatValue(args[2])), nil
+		}},
+
+		"unix_timestamp": {minArgs: 1, maxArgs: 1, returnType: fixedResult(typeInt), call: func(args []any) (any, error) {
+			t, err := convertValue(args[0], typeTime)
+			if err != nil {
+				return nil, err
+			}
+			return t.(time.Time).Unix(), nil
+		}},
+		"from_unixtime": {minArgs: 1, maxArgs: 1, returnType: fixedResult(typeTime), call: func(args []any) (any, error) {
+			return convertValue(args[0], typeTime)
+		}},
+	}
+}
+
+func logDomain(f func(float64) float64) func(float64) float64 {
+	return func(x float64) float64 {
+		if x <= 0 {
+			return math.NaN()
+		}
+		return f(x)
+	}
+}
+
+func callPow(args []any) (any, error) {
+	x, err := toFloat(args[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := toFloat(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return math.Pow(x, y), nil
+}
+
+func callCoalesce(args []any) (any, error) {
+	for _, a := range args {
+		if a != nil {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+func callSubstr(args []any) (any, error) {
+	s := []rune(formatValue(args[0]))
+	p, err := convertValue(args[1], typeInt)
+	if err != nil {
+		return nil, err
+	}
+	// positions are 1-based, negative positions count from the end
+	pos := p.(int64)
+	switch {
+	case pos > 0:
+		pos--
+	case pos < 0:
+		pos += int64(len(s))
+	}
+	if pos < 0 || pos >= int64(len(s)) {
+		return "", nil
+	}
+	end := int64(len(s))
+	if len(args) > 2 {
+		n, err := convertValue(args[2], typeInt)
+		if err != nil {
+			return nil, err
+		}
+		if n.(int64) < 0 {
+			return "", nil
+		}
+		end = min(end, pos+n.(int64))
+	}
+	return string(s[pos:end]), nil
+}
+
+// extreme returns the largest (dir 1) or smallest (dir -1) argument.
+func extreme(args []any, dir int) (any, error) {
+	best := args[0]
+	for _, a := range args[1:] {
+		cmp, err := compareValues(a, best)
+		if err != nil {
+			return nil, err
+		}
+		if cmp*dir > 0 {
+			best = a
+		}
+	}
+	return best, nil
+}
+
+func anyNullable(args []typeInfo) bool {
+	for _, a := range args {
+		if a.nullable {
+			return true
+		}
+	}
+	return false
+}
+
+func requireNumeric(args []typeInfo) error {
+	for _, a := range args {
+		if a.typ != typeNull && !a.typ.numeric() && a.typ != typeBool {
+			return fmt.Errorf("expected a numeric argument but got %s", a.typ)
+		}
+	}
+	return nil
+}
+
+func fixedResult(t valueType) func(args []typeInfo) (typeInfo, error) {
+	return func(args []typeInfo) (typeInfo, error) {
+		return typeInfo{typ: t, nullable: anyNullable(args)}, nil
+	}
+}
+
+// floatResult returns a float result type. When mayBeNull is set the result
+// is NULL for arguments outside of the function domain.
+func floatResult(mayBeNull bool) func(args []typeInfo) (typeInfo, error) {
+	return func(args []typeInfo) (typeInfo, error) {
+		if err := requireNumeric(args); err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{typ: typeFloat, nullable: mayBeNull || anyNullable(args)}, nil
+	}
+}
+
+func sameNumericResult(args []typeInfo) (typeInfo, error) {
+	if err := requireNumeric(args); err != nil {
+		return typeInfo{}, err
+	}
+	t := args[0].typ
+	if t != typeFloat {
+		t = typeInt
+	}
+	return typeInfo{typ: t, nullable: anyNullable(args)}, nil
+}
+
+func unifiedResult(args []typeInfo) (typeInfo, error) {
+	t := args[0]
+	for _, a := range args[1:] {
+		var err error
+		if t, err = unifyTypes(t, a); err != nil {
+			return typeInfo{}, err
+		}
+	}
+	t.nullable = anyNullable(args)
+	return t, nil
+}
+
+func coalesceResult(args []typeInfo) (typeInfo, error) {
+	t, err := unifiedResult(args)
+	if err != nil {
+		return typeInfo{}, err
+	}
+	t.nullable = true
+	for _, a := range args {
+		if !a.nullable && a.typ != typeNull {
+			t.nullable = false
+		}
+	}
+	return t, nil
+}
+
+// arithmeticResult returns the result type of a binary arithmetic operator.
+func arithmeticResult(op string, l, r typeInfo) (typeInfo, error) {
+	if err := requireNumeric([]typeInfo{l, r}); err != nil {
+		return typeInfo{}, fmt.Errorf("operator %s: %w", op, err)
+	}
+	t := typeInfo{typ: typeFloat, nullable: l.nullable || r.nullable || op == "/" || op == "%"}
+	if op != "/" && l.typ != typeFloat && r.typ != typeFloat {
+		t.typ = typeInt
+	}
+	return t, nil
+}
+
+// aggregator accumulates the non-NULL values of a group.
+type aggregator interface {
+	add(v any) error
+	result() any
+}
+
+type aggregateFunc struct {
+	returnType    func(arg typeInfo) (typeInfo, error)
+	newAggregator func(arg typeInfo) aggregator
+}
+
+var aggregateFuncs = map[string]aggregateFunc{
+	"count": {
+		returnType:    func(typeInfo) (typeInfo, error) { return typeInfo{typ: typeInt}, nil },
+		newAggregator: func(typeInfo) aggregator { return &countAgg{} },
+	},
+	"sum": {
+		returnType: func(arg typeInfo) (typeInfo, error) {
+			t, err := sameNumericResult([]typeInfo{arg})
+			t.nullable = true
+			return t, err
+		},
+		newAggregator: func(arg typeInfo) aggregator { return &sumAgg{isFloat: arg.typ == typeFloat} },
+	},
+	"avg": {
+		returnType:    statResult,
+		newAggregator: func(typeInfo) aggregator { return &statAgg{stat: statMean} },
+	},
+	"min": {
+		returnType:    extremeResult,
+		newAggregator: func(typeInfo) aggregator { return &extremeAgg{dir: -1} },
+	},
+	"max": {
+		returnType:    extremeResult,
+		newAggregator: func(typeInfo) aggregator { return &extremeAgg{dir: 1} },
+	},
+	"stddev": {
+		returnType:    statResult,
+		newAggregator: func(typeInfo) aggregator { return &statAgg{stat: statStddevPop} },
+	},
+	"stddev_pop": {
+		returnType:    statResult,
+		newAggregator: func(typeInfo) aggregator { return &statAgg{stat: statStddevPop} },
+	},
+	"stddev_samp": {
+		returnType:    statResult,
+		newAggregator: func(typeInfo) aggregator { return &statAgg{stat: statStddevSamp} },
+	},
+	"variance": {
+		returnType:    statResult,
+		newAggregator: func(typeInfo) aggregator { return &statAgg{stat: statVarPop} },
+	},
+	"var_pop": {
+		returnType:    statResult,
+		newAggregator: func(typeInfo) aggregator { return &statAgg{stat: statVarPop} },
+	},
+	"var_samp": {
+		returnType:    statResult,
+		newAggregator: func(typeInfo) aggregator { return &statAgg{stat: statVarSamp} },
+	},
+	"group_concat": {
+		returnType: func(typeInfo) (typeInfo, error) {
+			return typeInfo{typ: typeString, nullable: true}, nil
+		},
+		newAggregator: func(typeInfo) aggregator { return &concatAgg{} },
+	},
+}
+
+func statResult(arg typeInfo) (typeInfo, error) {
+	if err := requireNumeric([]typeInfo{arg}); err != nil {
+		return typeInfo{}, err
+	}
+	return typeInfo{typ: typeFloat, nullable: true}, nil
+}
+
+func extremeResult(arg typeInfo) (typeInfo, error) {
+	return typeInfo{typ: arg.typ, nullable: true}, nil
+}
+
+type countAgg struct {
+	n int64
+}
+
+func (a *countAgg) add(any) error {
+	a.n++
+	return nil
+}
+
+func (a *countAgg) result() any {
+	return a.n
+}
+
+type sumAgg struct {
+	isFloat bool
+	seen    bool
+	i       int64
+	f       float64
+}
+
+func (a *sumAgg) add(v any) error {
+	a.seen = true
+	if x, ok := v.(int64); ok && !a.isFloat {
+		a.i += x
+		return nil
+	}
+	f, err := toFloat(v)
+	if err != nil {
+		return err
+	}
+	a.f += f
+	return nil
+}
+
+func (a *sumAgg) result() any {
+	switch {
+	case !a.seen:
+		return nil
+	case a.isFloat:
+		return a.f
+	}
+	return a.i
+}
+
+type stat int
+
+const (
+	statMean stat = iota
+	statVarPop
+	statVarSamp
+	statStddevPop
+	statStddevSamp
+)
+
+// statAgg computes the mean and variance with Welford's online algorithm.
+type statAgg struct {
+	stat stat
+	n    float64
+	mean float64
+	m2   float64
+}
+
+func (a *statAgg) add(v any) error {
+	x, err := toFloat(v)
+	if err != nil {
+		return err
+	}
+	a.n++
+	delta := x - a.mean
+	a.mean += delta / a.n
+	a.m2 += delta * (x - a.mean)
+	return nil
+}
+
+func (a *statAgg) result() any {
+	if a.n == 0 {
+		return nil
+	}
+	switch a.stat {
+	case statVarPop:
+		return a.m2 / a.n
+	case statStddevPop:
+		return math.Sqrt(a.m2 / a.n)
+	case statVarSamp, statStddevSamp:
+		if a.n < 2 {
+			return nil
+		}
+		if a.stat == statVarSamp {
+			return a.m2 / (a.n - 1)
+		}
+		return math.Sqrt(a.m2 / (a.n - 1))
+	}
+	return a.mean
+}
+
+type extremeAgg struct {
+	dir  int
+	best any
+}
+
+func (a *extremeAgg) add(v any) error {
+	if a.best == nil {
+		a.best = v
+		return nil
+	}
+	cmp, err := compareValues(v, a.best)
+	if err != nil {
+		return err
+	}
+	if cmp*a.dir > 0 {
+		a.best = v
+	}
+	return nil
+}
+
+func (a *extremeAgg) result() any {
+	return a.best
+}
+
+type concatAgg struct {
+	parts []string
+}
+
+func (a *concatAgg) add(v any) error {
+	a.parts = append(a.parts, formatValue(v))
+	return nil
+}
+
+func (a *concatAgg) result() any {
+	if len(a.parts) == 0 {
+		return nil
+	}
+	return strings.Join(a.parts, ",")
+}
+
+// distinctAgg wraps an aggregator so that it only sees each value once.
+type distinctAgg struct {
+	aggregator
+	seen map[string]bool
+}
+
+func (a *distinctAgg) add(v any) error {
+	k := groupKey([]any{v})
+	if a.seen[k] {
+		return nil
+	}
+	a.seen[k] = true
+	return a.aggregator.add(v)
+}
+
+func newAggregator(f *funcCall, arg typeInfo) aggregator {
+	agg := aggregateFuncs[f.name].newAggregator(arg)
+	if f.distinct {
+		return &distinctAgg{aggregator: agg, seen: map[string]bool{}}
+	}
+	return agg
+}
+
+// aggregate feeds the rows of a group into the aggregate function f.
+func aggregate(f *funcCall, arg typeInfo, rows []*rowCtx) (any, error) {
+	agg := newAggregator(f, arg)
+	for _, r := range rows {
+		if err := addToAggregate(f, agg, r); err != nil {
+			return nil, err
+		}
+	}
+	return agg.result(), nil
+}
+
+func addToAggregate(f *funcCall, agg aggregator, r *rowCtx) error {
+	if f.star {
+		return agg.add(true)
+	}
+	v, err := f.args[0].eval(r)
+	if err != nil || v == nil {
+		return err
+	}
+	if err := agg.add(v); err != nil {
+		return fmt.Errorf("%s: %w", f.name, err)
+	}
+	return nil
+}
+
+// windowFuncs lists the functions that are only valid with an OVER clause.
+// Aggregate functions can be used as window functions as well.
+var windowFuncs = map[string]bool{
+	"row_number": true, "rank": true, "dense_rank": true, "percent_rank": true, "cume_dist": true,
+	"ntile": true, "lag": true, "lead": true, "first_value": true, "last_value": true, "nth_value": true,
+}
+
+// windowResultType returns the type of a window function call given its
+// bound argument types.
+func windowResultType(f *funcCall, args []typeInfo) (typeInfo, error) {
+	if _, ok := aggregateFuncs[f.name]; ok {
+		if len(args) != 1 && !f.star {
+			return typeInfo{}, fmt.Errorf("%s expects exactly one argument", f.name)
+		}
+		if f.star {
+			return aggregateFuncs[f.name].returnType(typeInfo{})
+		}
+		return aggregateFuncs[f.name].returnType(args[0])
+	}
+
+	argCount := func(lo, hi int) error {
+		if len(args) < lo || len(args) > hi {
+			return fmt.Errorf("%s expects %d to %d arguments but got %d", f.name, lo, hi, len(args))
+		}
+		return nil
+	}
+	switch f.name {
+	case "row_number", "rank", "dense_rank":
+		return typeInfo{typ: typeInt}, argCount(0, 0)
+	case "percent_rank", "cume_dist":
+		return typeInfo{typ: typeFloat}, argCount(0, 0)
+	case "ntile":
+		return typeInfo{typ: typeInt}, argCount(1, 1)
+	case "lag", "lead":
+		if err := argCount(1, 3); err != nil {
+			return typeInfo{}, err
+		}
+		t := typeInfo{typ: args[0].typ, nullable: true}
+		if len(args) == 3 {
+			return unifyTypes(t, args[2])
+		}
+		return t, nil
+	case "first_value", "last_value":
+		if err := argCount(1, 1); err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{typ: args[0].typ, nullable: true}, nil
+	case "nth_value":
+		if err := argCount(2, 2); err != nil {
+			return typeInfo{}, err
+		}
+		return typeInfo{typ: args[0].typ, nullable: true}, nil
+	}
+	return typeInfo{}, fmt.Errorf("unknown window function %s", f.name)
+}
+
+// computeWindow evaluates the window function f for every row and stores the
+// result in the row's window slot.
+func computeWindow(f *funcCall, argType typeInfo, rows []*rowCtx) error {
+	partitions := map[string][]*rowCtx{}
+	var order []string
+	for _, r := range rows {
+		key := ""
+		if len(f.over.partitionBy) > 0 {
+			values := make([]any, len(f.over.partitionBy))
+			for i, e := range f.over.partitionBy {
+				v, err := e.eval(r)
+				if err != nil {
+					return err
+				}
+				values[i] = v
+			}
+			key = groupKey(values)
+		}
+		if _, ok := partitions[key]; !ok {
+			order = append(order, key)
+		}
+		partitions[key] = append(partitions[key], r)
+	}
+
+	for _, key := range order {
+		part := partitions[key]
+		keys, err := sortRows(part, f.over.orderBy)
+		if err != nil {
+			return err
+		}
+		if err := computePartition(f, argType, part, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortRows stably sorts rows by the order items and returns the sort keys of
+// the sorted rows.
+func sortRows(rows []*rowCtx, orderBy []orderItem) ([][]any, error) {
+	keys := make([][]any, len(rows))
+	for i, r := range rows {
+		keys[i] = make([]any, len(orderBy))
+		for j, o := range orderBy {
+			v, err := o.expr.eval(r)
+			if err != nil {
+				return nil, err
+			}
+			keys[i][j] = v
+		}
+	}
+	if len(orderBy) == 0 {
+		return keys, nil
+	}
+
+	idx := make([]int, len(rows))
+	for i := range idx {
+		idx[i] = i
+	}
+	var sortErr error
+	sort.SliceStable(idx, func(a, b int) bool {
+		cmp, err := compareKeys(keys[idx[a]], keys[idx[b]], orderBy)
+		if err != nil {
+			sortErr = err
+		}
+		return cmp < 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	sortedRows := make([]*rowCtx, len(rows))
+	sortedKeys := make([][]any, len(rows))
+	for i, j := range idx {
+		sortedRows[i] = rows[j]
+		sortedKeys[i] = keys[j]
+	}
+	copy(rows, sortedRows)
+	return sortedKeys, nil
+}
+
+// compareKeys compares two sort keys. NULLs sort first in ascending and last
+// in descending order.
+func compareKeys(a, b []any, orderBy []orderItem) (int, error) {
+	for i, o := range orderBy {
+		cmp, err := compareNullsFirst(a[i], b[i])
+		if err != nil {
+			return 0, err
+		}
+		if o.desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+	return 0, nil
+}
+
+func computePartition(f *funcCall, argType typeInfo, part []*rowCtx, keys [][]any) error {
+	n := len(part)
+	// peerStart[i] and peerEnd[i] delimit the rows with the same ORDER BY key as row i.
+	peerStart := make([]int, n)
+	peerEnd := make([]int, n)
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n {
+			cmp, err := compareKeys(keys[i], keys[j], f.over.orderBy)
+			if err != nil {
+				return err
+			}
+			if cmp != 0 {
+				break
+			}
+			j++
+		}
+		for k := i; k < j; k++ {
+			peerStart[k], peerEnd[k] = i, j
+		}
+		i = j
+	}
+
+	set := func(i int, v any) {
+		part[i].window[f.slot] = v
+	}
+
+	switch f.name {
+	case "row_number":
+		for i := range part {
+			set(i, int64(i+1))
+		}
+		return nil
+	case "rank":
+		for i := range part {
+			set(i, int64(peerStart[i]+1))
+		}
+		return nil
+	case "dense_rank":
+		rank := int64(0)
+		for i := range part {
+			if peerStart[i] == i {
+				rank++
+			}
+			set(i, rank)
+		}
+		return nil
+	case "percent_rank":
+		for i := range part {
+			if n == 1 {
+				set(i, float64(0))
+				continue
+			}
+			set(i, float64(peerStart[i])/float64(n-1))
+		}
+		return nil
+	case "cume_dist":
+		for i := range part {
+			set(i, float64(peerEnd[i])/float64(n))
+		}
+		return nil
+	case "ntile":
+		v, err := f.args[0].eval(part[0])
+		if err != nil {
+			return err
+		}
+		buckets, err := convertValue(v, typeInt)
+		if err != nil || buckets == nil || buckets.(int64) <= 0 {
+			return errors.New("ntile expects a positive number of buckets")
+		}
+		b := int(buckets.(int64))
+		size, extra := n/b, n%b
+		bucket, left := 1, size
+		if extra > 0 {
+			left++
+		}
+		for i := range part {
+			if left == 0 {
+				bucket++
+				left = size
+				if bucket <= extra {
+					left++
+				}
+			}
+			set(i, int64(bucket))
+			left--
+		}
+		return nil
+	case "lag", "lead":
+		return computeOffset(f, part)
+	}
+
+	bounds := func(i int) (int, int) {
+		frame := f.over.frame
+		if frame == nil {
+			if len(f.over.orderBy) == 0 {
+				return 0, n
+			}
+			return 0, peerEnd[i]
+		}
+		return frameIndex(frame.start, frame.rows, i, n, peerStart, peerEnd, true),
+			frameIndex(frame.end, frame.rows, i, n, peerStart, peerEnd, false)
+	}
+
+	switch f.name {
+	case "first_value", "last_value", "nth_value":
+		nth := int64(1)
+		if f.name == "nth_value" {
+			v, err := f.args[1].eval(part[0])
+			if err != nil {
+				return err
+			}
+			c, err := convertValue(v, typeInt)
+			if err != nil || c == nil || c.(int64) <= 0 {
+				return errors.New("nth_value expects a positive row number")
+			}
+			nth = c.(int64)
+		}
+		for i := range part {
+			lo, hi := bounds(i)
+			idx := lo + int(nth) - 1
+			if f.name == "last_value" {
+				idx = hi - 1
+			}
+			if idx < lo || idx >= hi {
+				set(i, nil)
+				continue
+			}
+			v, err := f.args[0].eval(part[idx])
+			if err != nil {
+				return err
+			}
+			set(i, v)
+		}
+		return nil
+	}
+
+	// Aggregate functions over a frame. Frames starting at the beginning of the
+	// partition grow monotonically, so the aggregate is computed incrementally.
+	if f.over.frame == nil || f.over.frame.start.kind == boundUnboundedPreceding {
+		agg := newAggregator(f, argType)
+		added := 0
+		for i := range part {
+			_, hi := bounds(i)
+			for ; added < hi; added++ {
+				if err := addToAggregate(f, agg, part[added]); err != nil {
+					return err
+				}
+			}
+			set(i, agg.result())
+		}
+		return nil
+	}
+	for i := range part {
+		lo, hi := bounds(i)
+		v, err := aggregate(f, argType, part[max(lo, 0):max(hi, lo)])
+		if err != nil {
+			return err
+		}
+		set(i, v)
+	}
+	return nil
+}
+
+// frameIndex resolves a frame bound for row i to a row index. Start bounds are
+// inclusive and end bounds exclusive; results are clamped to the partition.
+func frameIndex(b frameBound, rows bool, i, n int, peerStart, peerEnd []int, start bool) int {
+	var idx int
+	switch b.kind {
+	case boundUnboundedPreceding:
+		return 0
+	case boundUnboundedFollowing:
+		return n
+	case boundCurrentRow:
+		switch {
+		case !rows && start:
+			return peerStart[i]
+		case !rows:
+			return peerEnd[i]
+		case start:
+			return i
+		}
+		return i + 1
+	case boundPreceding:
+		idx = i - int(b.offset)
+	case boundFollowing:
+		idx = i + int(b.offset)
+	}
+	if !start {
+		idx++
+	}
+	return min(max(idx, 0), n)
+}
+
+func computeOffset(f *funcCall, part []*rowCtx) error {
+	offset := int64(1)
+	if len(f.args) > 1 {
+		v, err := f.args[1].eval(part[0])
+		if err != nil {
+			return err
+		}
+		c, err := convertValue(v, typeInt)
+		if err != nil || c == nil || c.(int64) < 0 {
+			return fmt.Errorf("%s expects a non-negative offset", f.name)
+		}
+		offset = c.(int64)
+	}
+	if f.name == "lag" {
+		offset = -offset
+	}
+	for i := range part {
+		j := i + int(offset)
+		if j < 0 || j >= len(part) {
+			var def any
+			if len(f.args) > 2 {
+				v, err := f.args[2].eval(part[i])
+				if err != nil {
+					return err
+				}
+				def = v
+			}
+			part[i].window[f.slot] = def
+			continue
+		}
+		v, err := f.args[0].eval(part[j])
+		if err != nil {
+			return err
+		}
+		part[i].window[f.slot] = v
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenNumber
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return fmt.Sprintf("'%s'", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// is reports whether the token is the given keyword or operator (case insensitive).
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokenIdent:
+		return strings.EqualFold(t.text, s)
+	case tokenOp:
+		return t.text == s
+	}
+	return false
+}
+
+// multi-character operators, longest first.
+var operators = []string{"<=>", "<>", "!=", "<=", ">=", "||", "==", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ".", ";"}
+
+// lex splits a SQL statement into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case strings.HasPrefix(input[i:], "--"):
+			end := strings.IndexByte(input[i:], '\n')
+			if end < 0 {
+				i = len(input)
+			} else {
+				i += end + 1
+			}
+		case strings.HasPrefix(input[i:], "/*"):
+			end := strings.Index(input[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at position %d", i)
+			}
+			i += end + 4
+		case r == '\'':
+			s, n, err := lexQuoted(input[i:], '\'')
+			if err != nil {
+				return nil, fmt.Errorf("%w at position %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s, pos: i})
+			i += n
+		case r == '"' || r == '`':
+			s, n, err := lexQuoted(input[i:], byte(r))
+			if err != nil {
+				return nil, fmt.Errorf("%w at position %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: s, pos: i})
+			i += n
+		case isDigit(r) || (r == '.' && i+1 < len(input) && isDigit(rune(input[i+1]))):
+			start := i
+			i = lexNumber(input, i)
+			tokens = append(tokens, token{kind: tokenNumber, text: input[start:i], pos: start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if r != '_' && r != '$' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[start:i], pos: start})
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(input[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(input)})
+	return tokens, nil
+}
+
+// lexQuoted reads a quoted string or identifier where the quote character
+// can be escaped by doubling it. It returns the unquoted text and the number
+// of bytes consumed.
+func lexQuoted(input string, quote byte) (string, int, error) {
+	var sb strings.Builder
+	i := 1
+	for i < len(input) {
+		c := input[i]
+		if c == quote {
+			if i+1 < len(input) && input[i+1] == quote {
+				sb.WriteByte(quote)
+				i += 2
+				continue
+			}
+			return sb.String(), i + 1, nil
+		}
+		if c == '\\' && quote == '\'' && i+1 < len(input) {
+			switch input[i+1] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(input[i+1])
+			}
+			i += 2
+			continue
+		}
+		sb.WriteByte(c)
+		i++
+	}
+	return "", 0, fmt.Errorf("unterminated quoted string")
+}
+
+func lexNumber(input string, i int) int {
+	for i < len(input) && isDigit(rune(input[i])) {
+		i++
+	}
+	if i < len(input) && input[i] == '.' {
+		i++
+		for i < len(input) && isDigit(rune(input[i])) {
+			i++
+		}
+	}
+	if i < len(input) && (input[i] == 'e' || input[i] == 'E') {
+		j := i + 1
+		if j < len(input) && (input[j] == '+' || input[j] == '-') {
+			j++
+		}
+		if j < len(input) && isDigit(rune(input[j])) {
+			i = j
+			for i < len(input) && isDigit(rune(input[i])) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
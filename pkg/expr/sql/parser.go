@@ -1,89 +1,887 @@
 package sql
 
 import (
-	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/log"
-	"github.com/jeremywohl/flatten"
-)
-
-const (
-	TABLE_NAME    = "table_name"
-	ERROR         = ".error"
-	ERROR_MESSAGE = ".error_message"
 )
 
 var logger = log.New("sql_expr")
 
 // TablesList returns a list of tables for the sql statement
 func TablesList(rawSQL string) ([]string, error) {
-	duckDB := NewInMemoryDB()
-	rawSQL = strings.Replace(rawSQL, "'", "''", -1)
-	cmd := fmt.Sprintf("SELECT json_serialize_sql('%s')", rawSQL)
-	ret, err := duckDB.RunCommands([]string{cmd})
+	stmt, err := parseSQL(rawSQL)
 	if err != nil {
-		logger.Error("error serializing sql", "error", err.Error(), "sql", rawSQL, "cmd", cmd)
-		return nil, fmt.Errorf("error serializing sql: %s", err.Error())
+		logger.Error("error parsing sql", "error", err.Error(), "sql", rawSQL)
+		return nil, fmt.Errorf("error parsing sql: %s", err.Error())
+	}
+
+	tables := []string{}
+	collectTables(stmt, nil, &tables)
+	sort.Strings(tables)
+
+	logger.Debug("tables found in sql", "tables", tables)
+
+	return tables, nil
+}
+
+// collectTables appends the names of all tables referenced by stmt that are
+// not common table expressions in scope.
+func collectTables(stmt *selectStmt, ctes []string, tables *[]string) {
+	scope := append([]string{}, ctes...)
+	for _, c := range stmt.with {
+		collectTables(c.query, scope, tables)
+		scope = append(scope, c.name)
 	}
 
-	ast := []map[string]any{}
-	err = json.Unmarshal([]byte(ret), &ast)
+	var walk func(t tableExpr)
+	walk = func(t tableExpr) {
+		switch t := t.(type) {
+		case *tableName:
+			if !existsInList(t.name, scope) && !existsInList(t.name, *tables) {
+				*tables = append(*tables, t.name)
+			}
+		case *subquery:
+			collectTables(t.query, scope, tables)
+		case *joinExpr:
+			walk(t.left)
+			walk(t.right)
+		}
+	}
+	if stmt.from != nil {
+		walk(stmt.from)
+	}
+}
+
+func existsInList(table string, list []string) bool {
+	for _, t := range list {
+		if strings.EqualFold(t, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// reserved lists the keywords that can not be used as an implicit alias.
+var reserved = map[string]bool{
+	"select": true, "from": true, "where": true, "group": true, "having": true, "order": true,
+	"limit": true, "offset": true, "join": true, "inner": true, "left": true, "right": true,
+	"full": true, "cross": true, "outer": true, "on": true, "union": true, "as": true,
+	"and": true, "or": true, "not": true, "with": true, "by": true, "asc": true, "desc": true,
+	"case": true, "when": true, "then": true, "else": true, "end": true, "is": true,
+	"null": true, "in": true, "between": true, "like": true, "over": true, "distinct": true,
+	"using": true, "partition": true, "rows": true, "range": true,
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+// parseSQL parses a single SELECT statement, optionally prefixed with a WITH clause.
+func parseSQL(rawSQL string) (*selectStmt, error) {
+	tokens, err := lex(rawSQL)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{input: rawSQL, tokens: tokens}
+	stmt, err := p.parseQuery()
 	if err != nil {
-		logger.Error("error converting json sql to ast", "error", err.Error(), "ret", ret)
-		return nil, fmt.Errorf("error converting json to ast: %s", err.Error())
+		return nil, err
+	}
+	for p.peek().is(";") {
+		p.next()
 	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return stmt, nil
+}
 
-	return tablesFromAST(ast)
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
 }
 
-// tablesFromAST returns a list of tables from the ast
-func tablesFromAST(ast []map[string]any) ([]string, error) {
-	flat, err := flatten.Flatten(ast[0], "", flatten.DotStyle)
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the given keyword or operator.
+func (p *parser) accept(s string) bool {
+	if p.peek().is(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return fmt.Errorf("expected %s but got %s at position %d", strings.ToUpper(s), p.peek(), p.peek().pos)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	return fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
+
+// parseIdent consumes a plain or quoted identifier.
+func (p *parser) parseIdent() (string, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenQuotedIdent:
+		p.next()
+		return t.text, nil
+	case t.kind == tokenIdent && !reserved[strings.ToLower(t.text)]:
+		p.next()
+		return t.text, nil
+	}
+	return "", fmt.Errorf("expected identifier but got %s at position %d", t, t.pos)
+}
+
+// parseAlias consumes an optional alias, with or without AS.
+func (p *parser) parseAlias() (string, error) {
+	if p.accept("as") {
+		if t := p.peek(); t.kind == tokenString {
+			p.next()
+			return t.text, nil
+		}
+		return p.parseIdent()
+	}
+	t := p.peek()
+	if t.kind == tokenQuotedIdent || (t.kind == tokenIdent && !reserved[strings.ToLower(t.text)]) {
+		p.next()
+		return t.text, nil
+	}
+	return "", nil
+}
+
+func (p *parser) parseQuery() (*selectStmt, error) {
+	var ctes []cte
+	if p.accept("with") {
+		for {
+			name, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("as"); err != nil {
+				return nil, err
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			q, err := p.parseQuery()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			ctes = append(ctes, cte{name: name, query: q})
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	stmt, err := p.parseSelect()
 	if err != nil {
-		logger.Error("error flattening ast", "error", err.Error(), "ast", ast)
-		return nil, fmt.Errorf("error flattening ast: %s", err.Error())
+		return nil, err
 	}
+	stmt.with = ctes
+	return stmt, nil
+}
 
-	tables := []string{}
-	for k, v := range flat {
-		if strings.HasSuffix(k, ERROR) {
-			v, ok := v.(bool)
-			if ok && v {
-				logger.Error("error in sql", "error", k)
-				return nil, astError(k, flat)
+func (p *parser) parseSelect() (*selectStmt, error) {
+	if err := p.expect("select"); err != nil {
+		return nil, err
+	}
+	stmt := &selectStmt{limit: -1}
+	if p.accept("distinct") {
+		stmt.distinct = true
+	} else {
+		p.accept("all")
+	}
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.columns = append(stmt.columns, item)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	var err error
+	if p.accept("from") {
+		if stmt.from, err = p.parseFrom(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("where") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("group") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		if stmt.groupBy, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("having") {
+		if stmt.having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("order") {
+		if stmt.orderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("limit") {
+		n, err := p.parseCount()
+		if err != nil {
+			return nil, err
+		}
+		stmt.limit = n
+		if p.accept(",") {
+			// LIMIT offset, count
+			stmt.offset = n
+			if stmt.limit, err = p.parseCount(); err != nil {
+				return nil, err
 			}
 		}
-		if strings.Contains(k, TABLE_NAME) {
-			table, ok := v.(string)
-			if ok && !existsInList(table, tables) {
-				tables = append(tables, v.(string))
+	}
+	if p.accept("offset") {
+		if stmt.offset, err = p.parseCount(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseCount() (int64, error) {
+	t := p.next()
+	if t.kind != tokenNumber {
+		return 0, fmt.Errorf("expected number but got %s at position %d", t, t.pos)
+	}
+	n, err := strconv.ParseInt(t.text, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count %s at position %d", t.text, t.pos)
+	}
+	return n, nil
+}
+
+func (p *parser) parseSelectItem() (selectItem, error) {
+	if p.accept("*") {
+		return selectItem{star: true, text: "*"}, nil
+	}
+	if t := p.peek(); (t.kind == tokenIdent || t.kind == tokenQuotedIdent) && p.peekAt(1).is(".") && p.peekAt(2).is("*") {
+		p.pos += 3
+		return selectItem{star: true, starTable: t.text, text: t.text + ".*"}, nil
+	}
+
+	start := p.peek().pos
+	e, err := p.parseExpr()
+	if err != nil {
+		return selectItem{}, err
+	}
+	item := selectItem{expr: e, text: strings.TrimSpace(p.input[start:p.peek().pos])}
+	if item.alias, err = p.parseAlias(); err != nil {
+		return selectItem{}, err
+	}
+	return item, nil
+}
+
+func (p *parser) parseFrom() (tableExpr, error) {
+	left, err := p.parseTableFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if p.accept(",") {
+			right, err := p.parseTableFactor()
+			if err != nil {
+				return nil, err
+			}
+			left = &joinExpr{kind: joinCross, left: left, right: right}
+			continue
+		}
+
+		kind, ok, err := p.parseJoinKind()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTableFactor()
+		if err != nil {
+			return nil, err
+		}
+		join := &joinExpr{kind: kind, left: left, right: right}
+		if kind != joinCross {
+			if err := p.expect("on"); err != nil {
+				return nil, err
+			}
+			if join.on, err = p.parseExpr(); err != nil {
+				return nil, err
 			}
 		}
+		left = join
 	}
-	sort.Strings(tables)
+}
 
-	logger.Debug("tables found in sql", "tables", tables)
+func (p *parser) parseJoinKind() (joinKind, bool, error) {
+	kind := joinInner
+	switch {
+	case p.peek().is("join"):
+	case p.peek().is("inner"):
+		p.next()
+	case p.peek().is("cross"):
+		p.next()
+		kind = joinCross
+	case p.peek().is("left"), p.peek().is("right"), p.peek().is("full"):
+		switch strings.ToLower(p.next().text) {
+		case "left":
+			kind = joinLeft
+		case "right":
+			kind = joinRight
+		default:
+			kind = joinFull
+		}
+		p.accept("outer")
+	default:
+		return 0, false, nil
+	}
+	return kind, true, p.expect("join")
+}
 
-	return tables, nil
+func (p *parser) parseTableFactor() (tableExpr, error) {
+	if p.accept("(") {
+		if p.peek().is("select") || p.peek().is("with") {
+			q, err := p.parseQuery()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			alias, err := p.parseAlias()
+			if err != nil {
+				return nil, err
+			}
+			return &subquery{query: q, alias: alias}, nil
+		}
+		t, err := p.parseFrom()
+		if err != nil {
+			return nil, err
+		}
+		return t, p.expect(")")
+	}
+
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	alias, err := p.parseAlias()
+	if err != nil {
+		return nil, err
+	}
+	return &tableName{name: name, alias: alias}, nil
+}
+
+func (p *parser) parseExprList() ([]expr, error) {
+	var list []expr
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		if !p.accept(",") {
+			return list, nil
+		}
+	}
+}
+
+func (p *parser) parseOrderBy() ([]orderItem, error) {
+	if err := p.expect("by"); err != nil {
+		return nil, err
+	}
+	var items []orderItem
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		item := orderItem{expr: e}
+		if p.accept("desc") {
+			item.desc = true
+		} else {
+			p.accept("asc")
+		}
+		items = append(items, item)
+		if !p.accept(",") {
+			return items, nil
+		}
+	}
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.accept("not") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "not", x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+var comparisonOps = map[string]string{"=": "=", "==": "=", "!=": "!=", "<>": "!=", "<": "<", "<=": "<=", ">": ">", ">=": ">="}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if op, ok := comparisonOps[t.text]; ok && t.kind == tokenOp {
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			left = &binaryExpr{op: op, left: left, right: right}
+			continue
+		}
+
+		if p.accept("is") {
+			not := p.accept("not")
+			if err := p.expect("null"); err != nil {
+				return nil, err
+			}
+			left = &isNullExpr{x: left, not: not}
+			continue
+		}
+
+		not := false
+		if t.is("not") && (p.peekAt(1).is("in") || p.peekAt(1).is("between") || p.peekAt(1).is("like")) {
+			p.next()
+			not = true
+		}
+		switch {
+		case p.accept("in"):
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			list, err := p.parseExprList()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			left = &inExpr{x: left, list: list, not: not}
+		case p.accept("between"):
+			lo, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("and"); err != nil {
+				return nil, err
+			}
+			hi, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			left = &betweenExpr{x: left, lo: lo, hi: hi, not: not}
+		case p.accept("like"):
+			pattern, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			left = &likeExpr{x: left, pattern: pattern, not: not}
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOp || (t.text != "+" && t.text != "-" && t.text != "||") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOp || (t.text != "*" && t.text != "/" && t.text != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.accept("-") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", x: x}, nil
+	}
+	if p.accept("+") {
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		if !strings.ContainsAny(t.text, ".eE") {
+			if n, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+				return &literal{value: n}, nil
+			}
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", t.text, t.pos)
+		}
+		return &literal{value: f}, nil
+	case tokenString:
+		p.next()
+		return &literal{value: t.text}, nil
+	case tokenQuotedIdent:
+		return p.parseColumnRef()
+	case tokenOp:
+		if t.text != "(" {
+			return nil, p.unexpected(t)
+		}
+		p.next()
+		if p.peek().is("select") || p.peek().is("with") {
+			return nil, fmt.Errorf("scalar subqueries are not supported (position %d)", t.pos)
+		}
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(")")
+	case tokenIdent:
+		switch strings.ToLower(t.text) {
+		case "null":
+			p.next()
+			return &literal{value: nil}, nil
+		case "true":
+			p.next()
+			return &literal{value: true}, nil
+		case "false":
+			p.next()
+			return &literal{value: false}, nil
+		case "case":
+			return p.parseCase()
+		case "cast":
+			if p.peekAt(1).is("(") {
+				return p.parseCast()
+			}
+		case "timestamp", "datetime", "date":
+			if s := p.peekAt(1); s.kind == tokenString {
+				p.pos += 2
+				ts, err := parseTime(s.text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid timestamp literal at position %d: %w", s.pos, err)
+				}
+				return &literal{value: ts}, nil
+			}
+		}
+		if p.peekAt(1).is("(") {
+			return p.parseFuncCall()
+		}
+		return p.parseColumnRef()
+	}
+	return nil, p.unexpected(t)
+}
+
+func (p *parser) parseColumnRef() (expr, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().is(".") {
+		p.next()
+		column, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		return &columnRef{table: name, name: column, idx: -1}, nil
+	}
+	return &columnRef{name: name, idx: -1}, nil
 }
 
-func astError(k string, flat map[string]any) error {
-	key := strings.Replace(k, ERROR, "", 1)
-	message, ok := flat[key+ERROR_MESSAGE]
+func (p *parser) parseCase() (expr, error) {
+	p.next() // CASE
+	c := &caseExpr{}
+	var err error
+	if !p.peek().is("when") {
+		if c.operand, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	for p.accept("when") {
+		var w whenClause
+		if w.cond, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		if w.result, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		c.whens = append(c.whens, w)
+	}
+	if len(c.whens) == 0 {
+		return nil, fmt.Errorf("CASE without WHEN at position %d", p.peek().pos)
+	}
+	if p.accept("else") {
+		if c.els, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return c, p.expect("end")
+}
+
+func (p *parser) parseCast() (expr, error) {
+	p.pos += 2 // CAST (
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("as"); err != nil {
+		return nil, err
+	}
+	t := p.next()
+	if t.kind != tokenIdent {
+		return nil, fmt.Errorf("expected type name but got %s at position %d", t, t.pos)
+	}
+	to, ok := castTypes[strings.ToLower(t.text)]
 	if !ok {
-		message = "unknown error in sql"
+		return nil, fmt.Errorf("unsupported type %s in CAST at position %d", t.text, t.pos)
+	}
+	// Ignore type modifiers like VARCHAR(255), DOUBLE PRECISION or SIGNED INTEGER.
+	for p.peek().kind == tokenIdent && !p.peek().is("as") {
+		p.next()
+	}
+	if p.accept("(") {
+		for !p.peek().is(")") && p.peek().kind != tokenEOF {
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("error in sql: %s", message)
+	return &castExpr{x: x, to: to}, nil
 }
 
-func existsInList(table string, list []string) bool {
-	for _, t := range list {
-		if t == table {
-			return true
+var castTypes = map[string]valueType{
+	"int": typeInt, "integer": typeInt, "bigint": typeInt, "smallint": typeInt, "tinyint": typeInt,
+	"signed": typeInt, "unsigned": typeInt,
+	"float": typeFloat, "double": typeFloat, "real": typeFloat, "decimal": typeFloat, "numeric": typeFloat,
+	"varchar": typeString, "char": typeString, "text": typeString, "string": typeString,
+	"bool": typeBool, "boolean": typeBool,
+	"timestamp": typeTime, "datetime": typeTime, "date": typeTime,
+}
+
+func (p *parser) parseFuncCall() (expr, error) {
+	name := strings.ToLower(p.next().text)
+	p.next() // (
+	f := &funcCall{name: name}
+	switch {
+	case p.accept(")"):
+	case p.peek().is("*"):
+		p.next()
+		f.star = true
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	default:
+		f.distinct = p.accept("distinct")
+		args, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		f.args = args
+		if err := p.expect(")"); err != nil {
+			return nil, err
 		}
 	}
-	return false
+
+	if p.accept("over") {
+		w, err := p.parseWindowSpec()
+		if err != nil {
+			return nil, err
+		}
+		f.over = w
+	}
+	return f, nil
+}
+
+func (p *parser) parseWindowSpec() (*windowSpec, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	w := &windowSpec{}
+	var err error
+	if p.accept("partition") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		if w.partitionBy, err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("order") {
+		if w.orderBy, err = p.parseOrderBy(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek().is("rows") || p.peek().is("range") {
+		frame := &windowFrame{rows: p.next().is("rows")}
+		if p.accept("between") {
+			if frame.start, err = p.parseFrameBound(); err != nil {
+				return nil, err
+			}
+			if err := p.expect("and"); err != nil {
+				return nil, err
+			}
+			if frame.end, err = p.parseFrameBound(); err != nil {
+				return nil, err
+			}
+		} else {
+			if frame.start, err = p.parseFrameBound(); err != nil {
+				return nil, err
+			}
+			frame.end = frameBound{kind: boundCurrentRow}
+		}
+		if frame.start.kind > frame.end.kind {
+			return nil, fmt.Errorf("invalid window frame at position %d", p.peek().pos)
+		}
+		if !frame.rows && (frame.start.kind == boundPreceding || frame.start.kind == boundFollowing ||
+			frame.end.kind == boundPreceding || frame.end.kind == boundFollowing) {
+			return nil, fmt.Errorf("RANGE frames with offsets are not supported, use ROWS (position %d)", p.peek().pos)
+		}
+		w.frame = frame
+	}
+	return w, p.expect(")")
+}
+
+func (p *parser) parseFrameBound() (frameBound, error) {
+	switch {
+	case p.accept("unbounded"):
+		if p.accept("preceding") {
+			return frameBound{kind: boundUnboundedPreceding}, nil
+		}
+		if err := p.expect("following"); err != nil {
+			return frameBound{}, err
+		}
+		return frameBound{kind: boundUnboundedFollowing}, nil
+	case p.accept("current"):
+		return frameBound{kind: boundCurrentRow}, p.expect("row")
+	}
+	n, err := p.parseCount()
+	if err != nil {
+		return frameBound{}, err
+	}
+	if p.accept("preceding") {
+		return frameBound{kind: boundPreceding, offset: n}, nil
+	}
+	if err := p.expect("following"); err != nil {
+		return frameBound{}, err
+	}
+	return frameBound{kind: boundFollowing, offset: n}, nil
 }
@@ -0,0 +1,353 @@
+package sql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// valueType is the static type of a column or expression. At runtime values
+// are represented as nil, bool, int64, float64, string or time.Time.
+type valueType int
+
+const (
+	typeNull valueType = iota
+	typeBool
+	typeInt
+	typeFloat
+	typeString
+	typeTime
+)
+
+func (t valueType) String() string {
+	switch t {
+	case typeBool:
+		return "BOOLEAN"
+	case typeInt:
+		return "BIGINT"
+	case typeFloat:
+		return "DOUBLE"
+	case typeString:
+		return "VARCHAR"
+	case typeTime:
+		return "TIMESTAMP"
+	}
+	return "NULL"
+}
+
+func (t valueType) numeric() bool {
+	return t == typeInt || t == typeFloat
+}
+
+// typeInfo is the static type of an expression together with whether it may
+// evaluate to NULL.
+type typeInfo struct {
+	typ      valueType
+	nullable bool
+}
+
+// unifyTypes returns the common type of two expressions, for example the
+// branches of a CASE. It fails if the types can not be reconciled.
+func unifyTypes(a, b typeInfo) (typeInfo, error) {
+	nullable := a.nullable || b.nullable
+	switch {
+	case a.typ == typeNull:
+		return typeInfo{typ: b.typ, nullable: true}, nil
+	case b.typ == typeNull:
+		return typeInfo{typ: a.typ, nullable: true}, nil
+	case a.typ == b.typ:
+		return typeInfo{typ: a.typ, nullable: nullable}, nil
+	case a.typ.numeric() && b.typ.numeric():
+		return typeInfo{typ: typeFloat, nullable: nullable}, nil
+	}
+	return typeInfo{}, fmt.Errorf("incompatible types %s and %s", a.typ, b.typ)
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime parses a timestamp in one of the commonly used SQL formats. Times
+// without a zone are interpreted as UTC.
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can not parse %q as a timestamp", s)
+}
+
+// convertValue converts a runtime value to the given type.
+func convertValue(v any, to valueType) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch to {
+	case typeNull:
+		return v, nil
+	case typeBool:
+		switch x := v.(type) {
+		case bool:
+			return x, nil
+		case int64:
+			return x != 0, nil
+		case float64:
+			return x != 0, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(x))
+			if err != nil {
+				return nil, fmt.Errorf("can not convert %q to %s", x, to)
+			}
+			return b, nil
+		}
+	case typeInt:
+		switch x := v.(type) {
+		case bool:
+			if x {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case int64:
+			return x, nil
+		case float64:
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return nil, nil
+			}
+			return int64(x), nil
+		case string:
+			s := strings.TrimSpace(x)
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return n, nil
+			}
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("can not convert %q to %s", x, to)
+			}
+			return int64(f), nil
+		case time.Time:
+			return x.Unix(), nil
+		}
+	case typeFloat:
+		switch x := v.(type) {
+		case bool:
+			if x {
+				return float64(1), nil
+			}
+			return float64(0), nil
+		case int64:
+			return float64(x), nil
+		case float64:
+			return x, nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+			if err != nil {
+				return nil, fmt.Errorf("can not convert %q to %s", x, to)
+			}
+			return f, nil
+		case time.Time:
+			return float64(x.UnixNano()) / 1e9, nil
+		}
+	case typeString:
+		return formatValue(v), nil
+	case typeTime:
+		switch x := v.(type) {
+		case time.Time:
+			return x, nil
+		case int64:
+			return time.Unix(x, 0).UTC(), nil
+		case float64:
+			sec, frac := math.Modf(x)
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+		case string:
+			return parseTime(x)
+		}
+	}
+	return nil, fmt.Errorf("can not convert %v (%T) to %s", v, v, to)
+}
+
+// formatValue renders a runtime value as a string.
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// compareValues orders two non-NULL values. Numbers compare numerically,
+// times chronologically and strings lexically. Mixed string and number or
+// time operands are compared after converting the string.
+func compareValues(a, b any) (int, error) {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return cmpOrdered(x, y), nil
+		case float64:
+			return cmpFloat(float64(x), y), nil
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return cmpFloat(x, float64(y)), nil
+		case float64:
+			return cmpFloat(x, y), nil
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, nil
+			case !x:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), nil
+		}
+	}
+
+	// Mixed types: coerce strings to the type of the other operand.
+	if s, ok := a.(string); ok {
+		c, err := compareValues(b, s)
+		return -c, err
+	}
+	if s, ok := b.(string); ok {
+		var to valueType
+		switch a.(type) {
+		case int64, float64:
+			to = typeFloat
+		case time.Time:
+			to = typeTime
+		case bool:
+			to = typeBool
+		}
+		if to != typeNull {
+			if conv, err := convertValue(s, to); err == nil {
+				return compareValues(a, conv)
+			}
+		}
+		return strings.Compare(formatValue(a), s), nil
+	}
+	if x, ok := a.(bool); ok {
+		return compareValues(boolToInt(x), b)
+	}
+	if y, ok := b.(bool); ok {
+		return compareValues(a, boolToInt(y))
+	}
+	return 0, fmt.Errorf("can not compare %T with %T", a, b)
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func cmpOrdered[T int64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// cmpFloat orders floats, treating NaN as smaller than any other number.
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	case a == b:
+		return 0
+	case math.IsNaN(a) && math.IsNaN(b):
+		return 0
+	case math.IsNaN(a):
+		return -1
+	}
+	return 1
+}
+
+// compareNullsFirst orders values where NULL sorts before everything else.
+func compareNullsFirst(a, b any) (int, error) {
+	switch {
+	case a == nil && b == nil:
+		return 0, nil
+	case a == nil:
+		return -1, nil
+	case b == nil:
+		return 1, nil
+	}
+	return compareValues(a, b)
+}
+
+// truthy reports whether a value is considered TRUE in a boolean context.
+// NULL is reported as not true.
+func truthy(v any) bool {
+	switch x := v.(type) {
+	case bool:
+		return x
+	case int64:
+		return x != 0
+	case float64:
+		return x != 0 && !math.IsNaN(x)
+	case string:
+		b, err := strconv.ParseBool(x)
+		return err == nil && b
+	}
+	return false
+}
+
+// groupKey returns a string that is equal for values considered equal when
+// grouping or removing duplicates.
+func groupKey(values []any) string {
+	var sb strings.Builder
+	for _, v := range values {
+		switch x := v.(type) {
+		case nil:
+			sb.WriteString("n;")
+		case int64:
+			// ints and integral floats group together
+			sb.WriteString("f" + strconv.FormatFloat(float64(x), 'g', -1, 64) + ";")
+		case float64:
+			sb.WriteString("f" + strconv.FormatFloat(x, 'g', -1, 64) + ";")
+		case string:
+			sb.WriteString("s" + strconv.Quote(x) + ";")
+		case bool:
+			sb.WriteString("b" + strconv.FormatBool(x) + ";")
+		case time.Time:
+			sb.WriteString("t" + strconv.FormatInt(x.UnixNano(), 10) + ";")
+		default:
+			sb.WriteString(fmt.Sprintf("?%v;", x))
+		}
+	}
+	return sb.String()
+}
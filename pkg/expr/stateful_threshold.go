@@ -0,0 +1,309 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// maxBreachWindowEvaluations limits the number of evaluations in a breach window, and therefore the number of
+// previous evaluations that are kept for each dimension.
+const maxBreachWindowEvaluations = 100
+
+// ThresholdEvaluation is the result of a previous evaluation of a StatefulThresholdCommand for a single dimension.
+type ThresholdEvaluation struct {
+	// Level is the result of the command: the index of the firing level starting with 1, or 0 if no level was firing.
+	Level float64
+	// Value is the value of the reference variable.
+	Value *float64
+}
+
+// ThresholdStates contains the previous evaluations of a StatefulThresholdCommand for each dimension,
+// ordered from the oldest to the most recent.
+type ThresholdStates map[data.Fingerprint][]ThresholdEvaluation
+
+// ThresholdLevel is a level of a StatefulThresholdCommand, e.g. warning or critical.
+type ThresholdLevel struct {
+	// Threshold is the condition a dimension must meet to enter the level.
+	Threshold ThresholdCommand
+	// Recovery is the condition a dimension must meet to leave the level once it entered it. If nil, the dimension
+	// leaves the level as soon as it does not meet Threshold.
+	Recovery *ThresholdCommand
+}
+
+// BreachWindow requires a threshold to be breached in Breaches of the last Evaluations evaluations, including the current one.
+type BreachWindow struct {
+	Breaches    int
+	Evaluations int
+}
+
+// StatefulThresholdCommand is a threshold with several levels, e.g. warning and critical, that takes the previous
+// evaluations into account:
+// - each level can have its own recovery threshold, similar to HysteresisCommand.
+// - with a BreachWindow, a level fires only if its threshold was breached in N of the last M evaluations.
+// The result for each dimension is the index of the highest firing level, starting with 1, or 0 if no level fires.
+// The previous evaluations are supplied in PreviousStates, keyed by the fingerprint of the dimension.
+type StatefulThresholdCommand struct {
+	RefID          string
+	ReferenceVar   string
+	Levels         []ThresholdLevel
+	Window         *BreachWindow
+	PreviousStates ThresholdStates
+	// Unit is the unit of the thresholds. The values, and the previous values, are converted to it before they are compared.
+	Unit string
+}
+
+func (c *StatefulThresholdCommand) NeedsVars() []string {
+	return []string{c.ReferenceVar}
+}
+
+func (c *StatefulThresholdCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	refVarResult := vars[c.ReferenceVar]
+
+	_, span := tracer.Start(ctx, "SSE.ExecuteStatefulThreshold")
+	span.SetAttributes(attribute.Int("levels", len(c.Levels)))
+	span.SetAttributes(attribute.Int("previousStates", len(c.PreviousStates)))
+	span.SetAttributes(attribute.Int("totalDimensions", len(refVarResult.Values)))
+	defer span.End()
+
+	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
+	for _, val := range refVarResult.Values {
+		factor := float64(1)
+		if c.Unit != "" {
+			var err error
+			factor, err = mathexp.UnitFactor(mathexp.GetUnit(val), c.Unit)
+			if err != nil {
+				return newRes, makeUnitError(c.RefID, err)
+			}
+		}
+		switch v := val.(type) {
+		case mathexp.Number:
+			copyV := mathexp.NewNumber(c.RefID, v.GetLabels())
+			copyV.SetValue(c.level(v.GetLabels(), v.GetFloat64Value(), factor))
+			newRes.Values = append(newRes.Values, copyV)
+		case mathexp.Scalar:
+			copyV := mathexp.NewScalar(c.RefID, c.level(nil, v.GetFloat64Value(), factor))
+			newRes.Values = append(newRes.Values, copyV)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+		default:
+			return newRes, fmt.Errorf("unsupported format of the input data, got type %v, but threshold with several levels or a breach window requires reduced data", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (c *StatefulThresholdCommand) Type() string {
+	return TypeThreshold.String()
+}
+
+// level returns the index of the highest firing level for the dimension with the labels, starting with 1, or 0 if no level fires.
+// The value and the previous values are multiplied by factor to convert them to the unit of the thresholds.
+func (c *StatefulThresholdCommand) level(labels data.Labels, value *float64, factor float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	converted := *value * factor
+	previous := c.PreviousStates[labels.Fingerprint()]
+	var previousLevel float64
+	if len(previous) > 0 {
+		previousLevel = previous[len(previous)-1].Level
+	}
+
+	result := 0
+	for i, l := range c.Levels {
+		firing := c.breached(l.Threshold.predicate, converted, previous, factor)
+		if !firing && l.Recovery != nil && previousLevel >= float64(i+1) {
+			firing = !l.Recovery.predicate.Eval(converted)
+		}
+		if firing {
+			result = i + 1
+		}
+	}
+	return util.Pointer(float64(result))
+}
+
+// breached returns true if the value breaches the threshold. With a breach window, it returns true if the threshold
+// is breached by enough values in the window, which includes the value and the most recent previous values.
+func (c *StatefulThresholdCommand) breached(p predicate, value float64, previous []ThresholdEvaluation, factor float64) bool {
+	if c.Window == nil {
+		return p.Eval(value)
+	}
+	breaches := 0
+	if p.Eval(value) {
+		breaches++
+	}
+	start := len(previous) - (c.Window.Evaluations - 1)
+	if start < 0 {
+		start = 0
+	}
+	for _, e := range previous[start:] {
+		if e.Value != nil && p.Eval(*e.Value*factor) {
+			breaches++
+		}
+	}
+	return breaches >= c.Window.Breaches
+}
+
+// NewStatefulThresholdCommand creates a StatefulThresholdCommand. Recovery thresholds are ignored unless allowRecovery is true.
+func NewStatefulThresholdCommand(refID, referenceVar string, conditions []ThresholdConditionJSON, window *ThresholdWindowJSON, previousState *data.Frame, allowRecovery bool) (*StatefulThresholdCommand, error) {
+	cmd := &StatefulThresholdCommand{
+		RefID:        refID,
+		ReferenceVar: referenceVar,
+		Levels:       make([]ThresholdLevel, 0, len(conditions)),
+	}
+	for i, condition := range conditions {
+		threshold, err := NewThresholdCommand(refID, referenceVar, condition.Evaluator.Type, condition.Evaluator.Params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition %d: %w", i, err)
+		}
+		level := ThresholdLevel{Threshold: *threshold}
+		if condition.UnloadEvaluator != nil && allowRecovery {
+			level.Recovery, err = NewThresholdCommand(refID, referenceVar, condition.UnloadEvaluator.Type, condition.UnloadEvaluator.Params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid unloadCondition %d: %w", i, err)
+			}
+		}
+		cmd.Levels = append(cmd.Levels, level)
+	}
+
+	if window != nil {
+		if window.Evaluations < 1 || window.Evaluations > maxBreachWindowEvaluations {
+			return nil, fmt.Errorf("invalid window: evaluations must be between 1 and %d, got %d", maxBreachWindowEvaluations, window.Evaluations)
+		}
+		if window.Breaches < 1 || window.Breaches > window.Evaluations {
+			return nil, fmt.Errorf("invalid window: breaches must be between 1 and the number of evaluations %d, got %d", window.Evaluations, window.Breaches)
+		}
+		cmd.Window = &BreachWindow{Breaches: window.Breaches, Evaluations: window.Evaluations}
+	}
+
+	if previousState != nil {
+		states, err := ThresholdStatesFromFrame(previousState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse previous state: %w", err)
+		}
+		cmd.PreviousStates = states
+	}
+	return cmd, nil
+}
+
+// IsStatefulThresholdExpression returns true if the raw model describes a StatefulThresholdCommand:
+// - field 'type' has value "threshold",
+// - field 'conditions' has more than one element or field 'window' is specified
+func IsStatefulThresholdExpression(query map[string]any) bool {
+	_, _, ok := GetStatefulThresholdHistory(query)
+	return ok
+}
+
+// GetStatefulThresholdHistory returns the reference variable of a stateful threshold command described by the raw model,
+// and the number of previous evaluations the command needs for each dimension. Returns false if the raw model does not
+// describe a StatefulThresholdCommand.
+func GetStatefulThresholdHistory(query map[string]any) (string, int, bool) {
+	t, err := GetExpressionCommandType(query)
+	if err != nil || t != TypeThreshold {
+		return "", 0, false
+	}
+	conditions, _ := query["conditions"].([]any)
+	window, hasWindow := query["window"].(map[string]any)
+	if len(conditions) < 2 && !hasWindow {
+		return "", 0, false
+	}
+	expression, _ := query["expression"].(string)
+
+	// the most recent evaluation is always needed to determine the previous level
+	size := 1
+	if hasWindow {
+		if evaluations, ok := window["evaluations"].(float64); ok {
+			size = max(1, min(int(evaluations), maxBreachWindowEvaluations)-1)
+		}
+	}
+	return strings.TrimPrefix(expression, "$"), size, true
+}
+
+// SetPreviousStateToStatefulThresholdCommand mutates the input map and sets field "previousState" with the data frame created from the provided states.
+func SetPreviousStateToStatefulThresholdCommand(query map[string]any, states ThresholdStates) error {
+	if !IsStatefulThresholdExpression(query) {
+		return errors.New("not a threshold command with several levels or a breach window")
+	}
+	query["previousState"] = ThresholdStatesToFrame(states)
+	return nil
+}
+
+// ThresholdStatesToFrame converts ThresholdStates to data.Frame. Each row is a previous evaluation of a dimension.
+// The rows of a dimension are ordered from the oldest to the most recent evaluation.
+func ThresholdStatesToFrame(states ThresholdStates) *data.Frame {
+	fingerprints := make([]data.Fingerprint, 0, len(states))
+	size := 0
+	for fingerprint, evaluations := range states {
+		fingerprints = append(fingerprints, fingerprint)
+		size += len(evaluations)
+	}
+	sort.Slice(fingerprints, func(i, j int) bool { return fingerprints[i] < fingerprints[j] })
+
+	fp := make([]uint64, 0, size)
+	levels := make([]float64, 0, size)
+	values := make([]*float64, 0, size)
+	for _, fingerprint := range fingerprints {
+		for _, e := range states[fingerprint] {
+			fp = append(fp, uint64(fingerprint))
+			levels = append(levels, e.Level)
+			values = append(values, e.Value)
+		}
+	}
+	frame := data.NewFrame("",
+		data.NewField("fingerprint", nil, fp),
+		data.NewField("level", nil, levels),
+		data.NewField("value", nil, values),
+	)
+	frame.SetMeta(&data.FrameMeta{
+		Type:        "threshold_states",
+		TypeVersion: data.FrameTypeVersion{1, 0},
+	})
+	return frame
+}
+
+// ThresholdStatesFromFrame converts data.Frame to ThresholdStates.
+// The input data frame must have the fields created by ThresholdStatesToFrame.
+// Returns error if the input data frame has invalid format
+func ThresholdStatesFromFrame(frame *data.Frame) (ThresholdStates, error) {
+	frameType, frameVersion := frame.TypeInfo("")
+	if frameType != "threshold_states" {
+		return nil, fmt.Errorf("invalid format of previous state frame: expected frame type 'threshold_states'")
+	}
+	if frameVersion.Greater(data.FrameTypeVersion{1, 0}) {
+		return nil, fmt.Errorf("invalid format of previous state frame: expected frame type 'threshold_states' of version 1.0 or lower")
+	}
+	if len(frame.Fields) != 3 {
+		return nil, fmt.Errorf("invalid format of previous state frame: expected 3 fields but got %d", len(frame.Fields))
+	}
+	fpField, levelField, valueField := frame.Fields[0], frame.Fields[1], frame.Fields[2]
+	if fpField.Type() != data.FieldTypeUint64 {
+		return nil, fmt.Errorf("invalid format of previous state frame: the type of field 'fingerprint' must be uint64 but got %s", fpField.Type().String())
+	}
+	if levelField.Type() != data.FieldTypeFloat64 {
+		return nil, fmt.Errorf("invalid format of previous state frame: the type of field 'level' must be float64 but got %s", levelField.Type().String())
+	}
+	if valueField.Type() != data.FieldTypeNullableFloat64 {
+		return nil, fmt.Errorf("invalid format of previous state frame: the type of field 'value' must be nullable float64 but got %s", valueField.Type().String())
+	}
+
+	result := ThresholdStates{}
+	for i := 0; i < fpField.Len(); i++ {
+		fingerprint := data.Fingerprint(fpField.At(i).(uint64))
+		result[fingerprint] = append(result[fingerprint], ThresholdEvaluation{
+			Level: levelField.At(i).(float64),
+			Value: valueField.At(i).(*float64),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,327 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestStatefulThresholdExecute(t *testing.T) {
+	number := func(label string, value float64) mathexp.Number {
+		n := mathexp.NewNumber("B", data.Labels{"label": label})
+		n.SetValue(&value)
+		return n
+	}
+	fingerprint := func(label string) data.Fingerprint {
+		return data.Labels{"label": label}.Fingerprint()
+	}
+	threshold := func(value float64) ThresholdCommand {
+		return ThresholdCommand{
+			ReferenceVar:  "A",
+			RefID:         "B",
+			ThresholdFunc: ThresholdIsAbove,
+			predicate:     greaterThanPredicate{value},
+		}
+	}
+	recovery := func(value float64) *ThresholdCommand {
+		return &ThresholdCommand{
+			ReferenceVar:  "A",
+			RefID:         "B",
+			ThresholdFunc: ThresholdIsBelow,
+			predicate:     lessThanPredicate{value},
+		}
+	}
+
+	tracer := tracing.InitializeTracerForTest()
+
+	testCases := []struct {
+		name           string
+		levels         []ThresholdLevel
+		window         *BreachWindow
+		previousStates ThresholdStates
+		input          mathexp.Values
+		expected       mathexp.Values
+	}{
+		{
+			name:     "return NoData when no data",
+			levels:   []ThresholdLevel{{Threshold: threshold(50)}, {Threshold: threshold(100)}},
+			input:    mathexp.Values{mathexp.NewNoData()},
+			expected: mathexp.Values{mathexp.NewNoData()},
+		},
+		{
+			name:   "return the highest firing level",
+			levels: []ThresholdLevel{{Threshold: threshold(50)}, {Threshold: threshold(100)}},
+			input: mathexp.Values{
+				number("value1", 101),
+				number("value2", 100),
+				number("value3", 51),
+				number("value4", 50),
+			},
+			expected: mathexp.Values{
+				number("value1", 2),
+				number("value2", 1),
+				number("value3", 1),
+				number("value4", 0),
+			},
+		},
+		{
+			name: "keep the previous level until the value meets the recovery threshold",
+			levels: []ThresholdLevel{
+				{Threshold: threshold(50), Recovery: recovery(40)},
+				{Threshold: threshold(100), Recovery: recovery(90)},
+			},
+			previousStates: ThresholdStates{
+				fingerprint("value1"): {{Level: 2}},
+				fingerprint("value2"): {{Level: 2}},
+				fingerprint("value3"): {{Level: 1}},
+				fingerprint("value4"): {{Level: 1}},
+			},
+			input: mathexp.Values{
+				number("value1", 95),
+				number("value2", 85),
+				number("value3", 95),
+				number("value4", 35),
+				number("value5", 95),
+			},
+			expected: mathexp.Values{
+				number("value1", 2),
+				number("value2", 1),
+				number("value3", 1),
+				number("value4", 0),
+				number("value5", 1),
+			},
+		},
+		{
+			name:   "fire when the threshold is breached in enough evaluations of the window",
+			levels: []ThresholdLevel{{Threshold: threshold(50)}},
+			window: &BreachWindow{Breaches: 2, Evaluations: 3},
+			previousStates: ThresholdStates{
+				fingerprint("value1"): {{Value: util.Pointer(60.0)}, {Value: util.Pointer(10.0)}},
+				fingerprint("value2"): {{Value: util.Pointer(60.0)}, {Value: util.Pointer(10.0)}, {Value: util.Pointer(10.0)}},
+				fingerprint("value3"): {{Value: nil}, {Value: util.Pointer(60.0)}},
+			},
+			input: mathexp.Values{
+				number("value1", 60),
+				number("value2", 60),
+				number("value3", 10),
+				number("value4", 60),
+			},
+			expected: mathexp.Values{
+				number("value1", 1),
+				number("value2", 0),
+				number("value3", 0),
+				number("value4", 0),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &StatefulThresholdCommand{
+				RefID:          "B",
+				ReferenceVar:   "A",
+				Levels:         tc.levels,
+				Window:         tc.window,
+				PreviousStates: tc.previousStates,
+			}
+
+			result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+				"A": mathexp.Results{Values: tc.input},
+			}, tracer)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, result.Values)
+		})
+	}
+}
+
+func TestUnmarshalStatefulThresholdCommand(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectedError string
+		assert        func(*testing.T, *StatefulThresholdCommand)
+	}{
+		{
+			name: "several conditions",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [
+					{ "evaluator": { "type": "gt", "params": [50] }, "unloadEvaluator": { "type": "lt", "params": [40] } },
+					{ "evaluator": { "type": "gt", "params": [100] } }
+				]
+			}`,
+			assert: func(t *testing.T, cmd *StatefulThresholdCommand) {
+				require.Equal(t, []string{"A"}, cmd.NeedsVars())
+				require.Len(t, cmd.Levels, 2)
+				require.Equal(t, greaterThanPredicate{50}, cmd.Levels[0].Threshold.predicate)
+				require.NotNil(t, cmd.Levels[0].Recovery)
+				require.Equal(t, lessThanPredicate{40}, cmd.Levels[0].Recovery.predicate)
+				require.Equal(t, greaterThanPredicate{100}, cmd.Levels[1].Threshold.predicate)
+				require.Nil(t, cmd.Levels[1].Recovery)
+				require.Nil(t, cmd.Window)
+			},
+		},
+		{
+			name: "single condition with window and previous state",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{ "evaluator": { "type": "gt", "params": [50] } }],
+				"window": { "breaches": 2, "evaluations": 3 },
+				"previousState": ` + string(mustMarshalFrame(t, ThresholdStatesToFrame(ThresholdStates{1: {{Level: 1, Value: util.Pointer(60.0)}}}))) + `
+			}`,
+			assert: func(t *testing.T, cmd *StatefulThresholdCommand) {
+				require.Len(t, cmd.Levels, 1)
+				require.Equal(t, &BreachWindow{Breaches: 2, Evaluations: 3}, cmd.Window)
+				require.Equal(t, ThresholdStates{1: {{Level: 1, Value: util.Pointer(60.0)}}}, cmd.PreviousStates)
+			},
+		},
+		{
+			name: "window with too many breaches",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{ "evaluator": { "type": "gt", "params": [50] } }],
+				"window": { "breaches": 4, "evaluations": 3 }
+			}`,
+			expectedError: "breaches must be between 1 and the number of evaluations",
+		},
+		{
+			name: "window with too many evaluations",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{ "evaluator": { "type": "gt", "params": [50] } }],
+				"window": { "breaches": 1, "evaluations": 101 }
+			}`,
+			expectedError: "evaluations must be between 1 and 100",
+		},
+		{
+			name: "invalid level",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [
+					{ "evaluator": { "type": "gt", "params": [50] } },
+					{ "evaluator": { "type": "foo", "params": [100] } }
+				]
+			}`,
+			expectedError: "invalid condition 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(tc.query), &qmap))
+
+			cmd, err := UnmarshalThresholdCommand(&rawNode{
+				RefID:    "B",
+				Query:    qmap,
+				QueryRaw: []byte(tc.query),
+			}, featuremgmt.WithFeatures(featuremgmt.FlagRecoveryThreshold))
+			if tc.expectedError != "" {
+				require.Nil(t, cmd)
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.IsType(t, &StatefulThresholdCommand{}, cmd)
+			tc.assert(t, cmd.(*StatefulThresholdCommand))
+		})
+	}
+}
+
+func TestGetStatefulThresholdHistory(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		expectedOk   bool
+		expectedVar  string
+		expectedSize int
+	}{
+		{
+			name:  "single condition",
+			query: `{"type": "threshold", "expression": "A", "conditions": [{}]}`,
+		},
+		{
+			name:  "not a threshold",
+			query: `{"type": "math", "expression": "$A", "conditions": [{}, {}]}`,
+		},
+		{
+			name:         "several conditions",
+			query:        `{"type": "threshold", "expression": "A", "conditions": [{}, {}]}`,
+			expectedOk:   true,
+			expectedVar:  "A",
+			expectedSize: 1,
+		},
+		{
+			name:         "window",
+			query:        `{"type": "threshold", "expression": "$A", "conditions": [{}], "window": {"breaches": 3, "evaluations": 5}}`,
+			expectedOk:   true,
+			expectedVar:  "A",
+			expectedSize: 4,
+		},
+		{
+			name:         "window of one evaluation",
+			query:        `{"type": "threshold", "expression": "A", "conditions": [{}], "window": {"breaches": 1, "evaluations": 1}}`,
+			expectedOk:   true,
+			expectedVar:  "A",
+			expectedSize: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(tc.query), &qmap))
+
+			refVar, size, ok := GetStatefulThresholdHistory(qmap)
+			require.Equal(t, tc.expectedOk, ok)
+			require.Equal(t, tc.expectedVar, refVar)
+			require.Equal(t, tc.expectedSize, size)
+			require.Equal(t, tc.expectedOk, IsStatefulThresholdExpression(qmap))
+			if tc.expectedOk {
+				require.False(t, IsHysteresisExpression(qmap))
+			}
+		})
+	}
+}
+
+func TestThresholdStatesToFrame(t *testing.T) {
+	states := ThresholdStates{
+		2: {{Level: 0, Value: util.Pointer(1.0)}, {Level: 1, Value: nil}},
+		1: {{Level: 2, Value: util.Pointer(3.0)}},
+	}
+
+	frame := ThresholdStatesToFrame(states)
+	require.Equal(t, []uint64{1, 2, 2}, []uint64{
+		frame.Fields[0].At(0).(uint64),
+		frame.Fields[0].At(1).(uint64),
+		frame.Fields[0].At(2).(uint64),
+	})
+
+	actual, err := ThresholdStatesFromFrame(frame)
+	require.NoError(t, err)
+	require.Equal(t, states, actual)
+
+	t.Run("should fail if frame has wrong type", func(t *testing.T) {
+		_, err := ThresholdStatesFromFrame(FingerprintsToFrame(Fingerprints{1: {}}))
+		require.ErrorContains(t, err, "expected frame type 'threshold_states'")
+	})
+}
+
+func mustMarshalFrame(t *testing.T, frame *data.Frame) []byte {
+	t.Helper()
+	b, err := json.Marshal(frame)
+	require.NoError(t, err)
+	return b
+}
@@ -25,7 +25,9 @@ type ThresholdCommand struct {
 	RefID         string
 	ThresholdFunc ThresholdType
 	Invert        bool
-	predicate     predicate
+	// Unit is the unit of the threshold values. The values are converted to it before they are compared.
+	Unit      string
+	predicate predicate
 }
 
 // +enum
@@ -98,22 +100,47 @@ func UnmarshalThresholdCommand(rn *rawNode, features featuremgmt.FeatureToggles)
 	}
 	referenceVar := cmdConfig.Expression
 
-	// we only support one condition for now, we might want to turn this in to "OR" expressions later
-	if len(cmdConfig.Conditions) != 1 {
-		return nil, fmt.Errorf("threshold expression requires exactly one condition")
+	return newThresholdFromConditions(rn.RefID, referenceVar, cmdConfig.Conditions, cmdConfig.Window, cmdConfig.PreviousState, cmdConfig.Unit, features)
+}
+
+// newThresholdFromConditions creates the command described by the threshold conditions:
+// - StatefulThresholdCommand if there are several conditions, one per level, or a breach window,
+// - HysteresisCommand if the condition has an unload evaluator,
+// - ThresholdCommand otherwise.
+// The values of the conditions are in the unit, if it is not empty.
+func newThresholdFromConditions(refID, referenceVar string, conditions []ThresholdConditionJSON, window *ThresholdWindowJSON, previousState *data.Frame, unit string, features featuremgmt.FeatureToggles) (Command, error) {
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("threshold expression requires at least one condition")
+	}
+	allowRecovery := false
+	for _, c := range conditions {
+		if c.UnloadEvaluator != nil {
+			allowRecovery = features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold)
+			break
+		}
+	}
+	if len(conditions) > 1 || window != nil {
+		cmd, err := NewStatefulThresholdCommand(refID, referenceVar, conditions, window, previousState, allowRecovery)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Unit = unit
+		return cmd, nil
 	}
-	firstCondition := cmdConfig.Conditions[0]
+	firstCondition := conditions[0]
 
-	threshold, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
+	threshold, err := NewThresholdCommand(refID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
 	if err != nil {
 		return nil, fmt.Errorf("invalid condition: %w", err)
 	}
-	if firstCondition.UnloadEvaluator != nil && features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold) {
-		unloading, err := NewThresholdCommand(rn.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
+	threshold.Unit = unit
+	if firstCondition.UnloadEvaluator != nil && allowRecovery {
+		unloading, err := NewThresholdCommand(refID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
 		if err != nil {
 			return nil, fmt.Errorf("invalid unloadCondition: %w", err)
 		}
 		unloading.Invert = true
+		unloading.Unit = unit
 		var d Fingerprints
 		if firstCondition.LoadedDimensions != nil {
 			d, err = FingerprintsFromFrame(firstCondition.LoadedDimensions)
@@ -121,7 +148,7 @@ func UnmarshalThresholdCommand(rn *rawNode, features featuremgmt.FeatureToggles)
 				return nil, fmt.Errorf("failed to parse loaded dimensions: %w", err)
 			}
 		}
-		return NewHysteresisCommand(rn.RefID, referenceVar, *threshold, *unloading, d)
+		return NewHysteresisCommand(refID, referenceVar, *threshold, *unloading, d)
 	}
 	return threshold, nil
 }
@@ -133,11 +160,13 @@ func (tc *ThresholdCommand) NeedsVars() []string {
 }
 
 func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars, _ tracing.Tracer) (mathexp.Results, error) {
+	// the values are only converted if the threshold has a unit, otherwise they are compared as they are
+	factor := float64(1)
 	eval := func(maybeValue *float64) *float64 {
 		if maybeValue == nil {
 			return nil
 		}
-		result := tc.predicate.Eval(*maybeValue)
+		result := tc.predicate.Eval(*maybeValue * factor)
 		if tc.Invert {
 			result = !result
 		}
@@ -150,6 +179,13 @@ func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp
 	refVarResult := vars[tc.ReferenceVar]
 	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
 	for _, val := range refVarResult.Values {
+		if tc.Unit != "" {
+			var err error
+			factor, err = mathexp.UnitFactor(mathexp.GetUnit(val), tc.Unit)
+			if err != nil {
+				return newRes, makeUnitError(tc.RefID, err)
+			}
+		}
 		switch v := val.(type) {
 		case mathexp.Series:
 			s := mathexp.NewSeries(tc.RefID, v.GetLabels(), v.Len())
@@ -190,8 +226,18 @@ func IsSupportedThresholdFunc(name string) bool {
 }
 
 type ThresholdCommandConfig struct {
-	Expression string                   `json:"expression"`
-	Conditions []ThresholdConditionJSON `json:"conditions"`
+	Expression    string                   `json:"expression"`
+	Conditions    []ThresholdConditionJSON `json:"conditions"`
+	Window        *ThresholdWindowJSON     `json:"window,omitempty"`
+	PreviousState *data.Frame              `json:"previousState,omitempty"`
+	Unit          string                   `json:"unit,omitempty"`
+}
+
+type ThresholdWindowJSON struct {
+	// Number of evaluations in the window that must breach the threshold
+	Breaches int `json:"breaches"`
+	// Number of the most recent evaluations, including the current one
+	Evaluations int `json:"evaluations"`
 }
 
 type ThresholdConditionJSON struct {
@@ -204,7 +250,11 @@ type ThresholdConditionJSON struct {
 // - field 'type' has value "threshold",
 // - field 'conditions' is array of objects and has exactly one element
 // - field 'conditions[0].unloadEvaluator is not nil
+// - field 'window' is not specified
 func IsHysteresisExpression(query map[string]any) bool {
+	if IsStatefulThresholdExpression(query) {
+		return false
+	}
 	c, err := getConditionForHysteresisCommand(query)
 	if err != nil {
 		return false
@@ -126,7 +126,7 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 				"conditions": []
 			}`,
 			shouldError:   true,
-			expectedError: "threshold expression requires exactly one condition",
+			expectedError: "threshold expression requires at least one condition",
 		},
 		{
 			description: "unmarshal with unsupported threshold function",
@@ -557,3 +557,81 @@ func TestThresholdExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestThresholdExecuteWithUnit(t *testing.T) {
+	cmd, err := newThresholdFromConditions("", "A", []ThresholdConditionJSON{
+		{Evaluator: ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{1.5}}},
+	}, nil, nil, "mbytes", featuremgmt.WithFeatures())
+	require.NoError(t, err)
+
+	number := func(value float64, unit string) mathexp.Number {
+		n := newNumber(data.Labels{"number": "test"}, util.Pointer(value))
+		mathexp.SetUnit(n, unit)
+		return n
+	}
+
+	t.Run("should convert values to the unit of the threshold", func(t *testing.T) {
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(number(2<<20, "bytes")),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(data.Labels{"number": "test"}, util.Pointer(float64(1)))), result)
+
+		result, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(number(1, "decmbytes")),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(data.Labels{"number": "test"}, util.Pointer(float64(0)))), result)
+	})
+
+	t.Run("should compare values without unit as they are", func(t *testing.T) {
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(newNumber(data.Labels{"number": "test"}, util.Pointer(float64(2)))),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(data.Labels{"number": "test"}, util.Pointer(float64(1)))), result)
+	})
+
+	t.Run("should fail if values have a unit of a different kind", func(t *testing.T) {
+		_, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(number(2, "s")),
+		}, tracing.InitializeTracerForTest())
+		require.ErrorIs(t, err, IncompatibleUnitsError)
+	})
+}
+
+func TestThresholdExecuteWithoutUnit(t *testing.T) {
+	cmd, err := newThresholdFromConditions("", "A", []ThresholdConditionJSON{
+		{Evaluator: ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{2000}}},
+	}, nil, nil, "", featuremgmt.WithFeatures())
+	require.NoError(t, err)
+
+	t.Run("should compare values with unit as they are", func(t *testing.T) {
+		n := newNumber(data.Labels{"number": "test"}, util.Pointer(float64(1)))
+		mathexp.SetUnit(n, "mbytes")
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": newResults(n),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(data.Labels{"number": "test"}, util.Pointer(float64(0)))), result)
+	})
+
+	t.Run("should get the same result from a reducer as without units", func(t *testing.T) {
+		series := mathexp.NewSeries("A", data.Labels{"number": "test"}, 2)
+		series.SetPoint(0, time.Unix(0, 0), util.Pointer(float64(0)))
+		series.SetPoint(1, time.Unix(10, 0), util.Pointer(float64(10000)))
+		mathexp.SetUnit(series, "kbytes")
+
+		reduce, err := NewReduceCommand("A", mathexp.ReducerRate, "S", nil, featuremgmt.WithFeatures())
+		require.NoError(t, err)
+		reduced, err := reduce.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"S": newResults(series),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+
+		// the rate is 1000 kbytes per second, which is not above 2000, even if it is above 2000 bytes per second
+		result, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": reduced}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(newNumber(data.Labels{"number": "test"}, util.Pointer(float64(0)))), result)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -16,6 +17,8 @@ import (
 type Request struct {
 	Headers map[string]string
 	Debug   bool
+	// Explain adds the execution profile of the pipeline to the response, under ExplainRefID.
+	Explain bool
 	OrgId   int64
 	Queries []Query
 	User    identity.Requester
@@ -93,6 +96,12 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		return nil, err
 	}
 
+	var profile *executionProfile
+	if req.Explain {
+		profile = newExecutionProfile(pipeline)
+		ctx = withExecutionProfile(ctx, profile)
+	}
+
 	// Execute the pipeline
 	responses, err := s.ExecutePipeline(ctx, now, pipeline)
 	if err != nil {
@@ -116,6 +125,10 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		responses = filteredRes
 	}
 
+	if profile != nil {
+		responses.Responses[ExplainRefID] = backend.DataResponse{Frames: data.Frames{profile.frame()}}
+	}
+
 	return responses, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -111,6 +112,10 @@ func (b *SearchAPIBuilder) GetAPIRoutes() *builder.APIRoutes {
 						Query:     queryParams.Get("query"),
 						Limit:     int64(limit),
 						Offset:    int64(offset),
+						Filters:   searchFilters(queryParams),
+						Sort:      searchSort(queryParams),
+						Facets:    searchFacets(queryParams),
+						Highlight: queryParams.Get("highlight") == "true",
 					}
 
 					res, err := b.unified.Search(r.Context(), searchRequest)
@@ -126,7 +131,11 @@ func (b *SearchAPIBuilder) GetAPIRoutes() *builder.APIRoutes {
 					}
 
 					w.Header().Set("Content-Type", "application/json")
-					if err := json.NewEncoder(w).Encode(rawMessages); err != nil {
+					if err := json.NewEncoder(w).Encode(searchResults{
+						Hits:      rawMessages,
+						TotalHits: res.GetTotalHits(),
+						Facets:    res.GetFacets(),
+					}); err != nil {
 						response.Error(500, "failed to json encode raw response", err)
 					}
 				},
@@ -135,6 +144,48 @@ func (b *SearchAPIBuilder) GetAPIRoutes() *builder.APIRoutes {
 	}
 }
 
+type searchResults struct {
+	Hits      []json.RawMessage                      `json:"hits"`
+	TotalHits int64                                  `json:"totalHits"`
+	Facets    map[string]*resource.SearchFacetResult `json:"facets,omitempty"`
+}
+
+// searchFilters reads the filters from the folder, tag, label (key=value) and createdBy query parameters.
+func searchFilters(queryParams url.Values) *resource.SearchFilters {
+	filters := &resource.SearchFilters{
+		Folders:   queryParams["folder"],
+		Tags:      queryParams["tag"],
+		CreatedBy: queryParams["createdBy"],
+	}
+	for _, label := range queryParams["label"] {
+		key, value, _ := strings.Cut(label, "=")
+		if filters.Labels == nil {
+			filters.Labels = map[string]string{}
+		}
+		filters.Labels[key] = value
+	}
+	return filters
+}
+
+// searchSort reads the sort query parameters, fields prefixed with a dash are sorted in descending order.
+func searchSort(queryParams url.Values) []*resource.SearchSort {
+	var sort []*resource.SearchSort
+	for _, field := range queryParams["sort"] {
+		desc := strings.HasPrefix(field, "-")
+		sort = append(sort, &resource.SearchSort{Field: strings.TrimPrefix(field, "-"), Desc: desc})
+	}
+	return sort
+}
+
+// searchFacets reads the facet query parameters, which are the fields to count the values of.
+func searchFacets(queryParams url.Values) []*resource.SearchFacetRequest {
+	var facets []*resource.SearchFacetRequest
+	for _, field := range queryParams["facet"] {
+		facets = append(facets, &resource.SearchFacetRequest{Name: field, Field: field})
+	}
+	return facets
+}
+
 func (b *SearchAPIBuilder) GetAuthorizer() authorizer.Authorizer {
 	return nil
 }
@@ -1548,6 +1548,12 @@ var (
 			Stage:       FeatureStageExperimental,
 			Owner:       grafanaObservabilityMetricsSquad,
 		},
+		{
+			Name:        "sseUnitConversion",
+			Description: "Propagate and convert the units of values in math and reduce server-side expressions",
+			Stage:       FeatureStageExperimental,
+			Owner:       grafanaAlertingSquad,
+		},
 	}
 )
 
@@ -834,4 +834,8 @@ const (
 	// FlagPrometheusUsesCombobox
 	// Use new combobox component for Prometheus query editor
 	FlagPrometheusUsesCombobox = "prometheusUsesCombobox"
+
+	// FlagSseUnitConversion
+	// Propagate and convert the units of values in math and reduce server-side expressions
+	FlagSseUnitConversion = "sseUnitConversion"
 )
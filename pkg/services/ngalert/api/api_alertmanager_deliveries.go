@@ -0,0 +1,166 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+// maxNotificationDeliveriesLimit is the maximum number of notification deliveries returned by a single request.
+const maxNotificationDeliveriesLimit = 1000
+
+func (srv AlertmanagerSrv) RouteGetNotificationDeliveries(c *contextmodel.ReqContext) response.Response {
+	query := ngmodels.ListNotificationDeliveriesQuery{
+		OrgID:       c.SignedInUser.GetOrgID(),
+		Integration: c.Query("integration"),
+		Status:      ngmodels.NotificationDeliveryStatus(c.Query("status")),
+		Limit:       c.QueryInt("limit"),
+	}
+	switch query.Status {
+	case "", ngmodels.NotificationDeliveryStatusSuccess, ngmodels.NotificationDeliveryStatusFailure:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, must be %q or %q", query.Status, ngmodels.NotificationDeliveryStatusSuccess, ngmodels.NotificationDeliveryStatusFailure), "")
+	}
+	if query.Limit < 0 || query.Limit > maxNotificationDeliveriesLimit {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must be between 0 and %d", maxNotificationDeliveriesLimit), "")
+	}
+	var err error
+	if query.From, err = parseDeliveryTime(c.Query("from")); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid from")
+	}
+	if query.To, err = parseDeliveryTime(c.Query("to")); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "invalid to")
+	}
+
+	readable, errResp := srv.readableReceivers(c)
+	if errResp != nil {
+		return errResp
+	}
+	query.Receivers = readable
+	if receiver := c.Query("receiver"); receiver != "" {
+		query.Receivers = []string{}
+		if readable == nil || slices.Contains(readable, receiver) {
+			query.Receivers = []string{receiver}
+		}
+	}
+
+	deliveries, err := srv.mam.ListNotificationDeliveries(c.Req.Context(), query)
+	if err != nil {
+		if errors.Is(err, notifier.ErrDeliveryLogDisabled) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to list notification deliveries")
+	}
+	result := make(apimodels.NotificationDeliveries, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, notificationDeliveryToAPI(d))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv AlertmanagerSrv) RoutePostNotificationDeliveryReplay(c *contextmodel.ReqContext, id string) response.Response {
+	deliveryID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse delivery id")
+	}
+
+	d, err := srv.mam.GetNotificationDelivery(c.Req.Context(), c.SignedInUser.GetOrgID(), deliveryID)
+	if err != nil {
+		if errors.Is(err, notifier.ErrDeliveryLogDisabled) || errors.Is(err, ngmodels.ErrNotificationDeliveryNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification delivery")
+	}
+	readable, errResp := srv.readableReceivers(c)
+	if errResp != nil {
+		return errResp
+	}
+	// Deliveries of contact points that the user cannot read are reported as missing so that they are not disclosed.
+	if readable != nil && !slices.Contains(readable, d.Receiver) {
+		return ErrResp(http.StatusNotFound, ngmodels.ErrNotificationDeliveryNotFound, "")
+	}
+
+	replayed, err := srv.mam.ReplayNotificationDelivery(c.Req.Context(), d)
+	if err != nil {
+		switch {
+		case errors.Is(err, notifier.ErrNoAlertmanagerForOrg):
+			return ErrResp(http.StatusNotFound, err, "")
+		case errors.Is(err, notifier.ErrAlertmanagerNotReady):
+			return ErrResp(http.StatusConflict, err, "")
+		case errors.Is(err, notifier.ErrDeliveryIntegrationNotFound), errors.Is(err, notifier.ErrDeliveryNotFailed), errors.Is(err, notifier.ErrDeliveryReplayNotSupported), errors.Is(err, notifier.ErrDeliveryLogDisabled):
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to replay notification delivery")
+	}
+	return response.JSON(http.StatusOK, notificationDeliveryToAPI(replayed))
+}
+
+// readableReceivers returns the names of the receivers whose notification deliveries the user can read.
+// It returns nil if the user can read the deliveries of all receivers, including the receivers that no longer exist.
+func (srv AlertmanagerSrv) readableReceivers(c *contextmodel.ReqContext) ([]string, response.Response) {
+	readAll, err := srv.ac.Evaluate(c.Req.Context(), c.SignedInUser, accesscontrol.EvalPermission(accesscontrol.ActionAlertingNotificationsRead))
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to evaluate permissions")
+	}
+	if readAll {
+		return nil, nil
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.SignedInUser.GetOrgID())
+	if errResp != nil {
+		return nil, errResp
+	}
+	rcvs, err := am.GetReceivers(c.Req.Context())
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to retrieve receivers")
+	}
+	statuses := make([]ReceiverStatus, 0, len(rcvs))
+	for _, rcv := range rcvs {
+		statuses = append(statuses, ReceiverStatus(rcv))
+	}
+	statuses, err = srv.receiverAuthz.FilterRead(c.Req.Context(), c.SignedInUser, statuses...)
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "failed to apply permissions to the receivers")
+	}
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, status.Name)
+	}
+	return names, nil
+}
+
+func parseDeliveryTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func notificationDeliveryToAPI(d *ngmodels.NotificationDelivery) apimodels.NotificationDelivery {
+	return apimodels.NotificationDelivery{
+		ID:               d.ID,
+		Receiver:         d.Receiver,
+		Integration:      d.Integration,
+		IntegrationIndex: d.IntegrationIndex,
+		GroupKey:         d.GroupKey,
+		GroupLabels:      d.GroupLabels,
+		AlertCount:       len(d.Alerts),
+		Status:           apimodels.NotificationDeliveryStatus(d.Status),
+		HTTPStatus:       d.HTTPStatus,
+		Error:            d.Error,
+		Latency:          d.Latency.String(),
+		PayloadHash:      d.PayloadHash,
+		ReplayOf:         d.ReplayOf,
+		Timestamp:        d.Timestamp,
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestRouteGetNotificationDeliveries(t *testing.T) {
+	sut := createSut(t)
+
+	createRequest := func(t *testing.T, query string) *contextmodel.ReqContext {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "https://grafana.net?"+query, nil)
+		require.NoError(t, err)
+		return &contextmodel.ReqContext{
+			Context: &web.Context{Req: req},
+			SignedInUser: &user.SignedInUser{
+				OrgID:       1,
+				Permissions: map[int64]map[string][]string{1: {ac.ActionAlertingNotificationsRead: {}}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "invalid status", query: "status=pending", expectedStatus: http.StatusBadRequest},
+		{name: "negative limit", query: "limit=-1", expectedStatus: http.StatusBadRequest},
+		{name: "limit above maximum", query: "limit=1001", expectedStatus: http.StatusBadRequest},
+		{name: "invalid from", query: "from=yesterday", expectedStatus: http.StatusBadRequest},
+		{name: "delivery log disabled", query: "status=failure&limit=10", expectedStatus: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := sut.RouteGetNotificationDeliveries(createRequest(t, tc.query))
+			require.Equal(t, tc.expectedStatus, response.Status())
+		})
+	}
+}
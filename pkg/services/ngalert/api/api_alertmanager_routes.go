@@ -0,0 +1,201 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// RoutePostTestRoutes returns the notification policies that match the labels in the request, along with the
+// receivers, grouping, timings and time intervals that would apply to an alert with these labels.
+// The policies of the current configuration of the organization are used unless the request contains a configuration.
+// The current configuration always includes the policies autogenerated from the notification settings of alert rules,
+// so that the labels are routed as they are by the Alertmanager, but these policies are only returned to admins.
+func (srv AlertmanagerSrv) RoutePostTestRoutes(c *contextmodel.ReqContext, body apimodels.TestRoutesConfigBodyParams) response.Response {
+	cfg := body.AlertmanagerConfig
+	if cfg == nil {
+		current, err := srv.mam.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID(), true)
+		if err != nil {
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				return ErrResp(http.StatusNotFound, err, "")
+			}
+			return ErrResp(http.StatusInternalServerError, err, "failed to get the current Alertmanager configuration")
+		}
+		cfg = &apimodels.PostableApiAlertingConfig{Config: current.AlertmanagerConfig.Config}
+	}
+
+	now := time.Now()
+	if body.Time != nil {
+		now = *body.Time
+	}
+
+	hideAutogen := !c.SignedInUser.HasRole(org.RoleAdmin)
+	result, err := testRoutes(cfg.Config, body.Labels, now, hideAutogen)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// testRoutes matches the labels against the notification policy tree of the configuration and evaluates the time
+// intervals of the matching policies at the given time. If hideAutogen is true, the matching autogenerated policies
+// are left out of the result, and paths are indexed as if the tree had no autogenerated policies.
+func testRoutes(cfg apimodels.Config, labels model.LabelSet, now time.Time, hideAutogen bool) (apimodels.TestRoutesResult, error) {
+	if cfg.Route == nil {
+		return apimodels.TestRoutesResult{}, errors.New("configuration has no notification policy tree")
+	}
+
+	intervals := timeIntervals(cfg)
+	root := dispatch.NewRoute(cfg.Route.AsAMRoute(), nil)
+	paths := make(map[*dispatch.Route][]apimodels.TestRoutesPathElement)
+	walkRoutes(root, 0, nil, paths)
+	// autogen is the index of the top-level autogenerated policy, or -1 if there is none
+	autogen := -1
+	for i, route := range root.Routes {
+		if isAutogeneratedRoute(route) {
+			autogen = i
+			break
+		}
+	}
+
+	result := apimodels.TestRoutesResult{
+		Time:   now,
+		Routes: make([]apimodels.TestRoutesMatch, 0, 1),
+	}
+	for _, route := range root.Match(labels) {
+		path := paths[route]
+		if hideAutogen && autogen >= 0 && len(path) > 1 {
+			if path[1].Index == autogen {
+				continue
+			}
+			if path[1].Index > autogen {
+				path = slices.Clone(path)
+				path[1].Index--
+			}
+		}
+		match := apimodels.TestRoutesMatch{
+			Path:                path,
+			Receiver:            route.RouteOpts.Receiver,
+			GroupBy:             groupByLabels(route.RouteOpts),
+			GroupWait:           model.Duration(route.RouteOpts.GroupWait),
+			GroupInterval:       model.Duration(route.RouteOpts.GroupInterval),
+			RepeatInterval:      model.Duration(route.RouteOpts.RepeatInterval),
+			MuteTimeIntervals:   route.RouteOpts.MuteTimeIntervals,
+			ActiveTimeIntervals: route.RouteOpts.ActiveTimeIntervals,
+		}
+		mutedBy, err := routeMutedBy(route.RouteOpts, intervals, now)
+		if err != nil {
+			return apimodels.TestRoutesResult{}, err
+		}
+		match.MutedBy = mutedBy
+		match.Muted = len(match.MutedBy) > 0
+		result.Routes = append(result.Routes, match)
+	}
+	return result, nil
+}
+
+// walkRoutes records the path from the root of the tree to every route.
+func walkRoutes(route *dispatch.Route, idx int, parent []apimodels.TestRoutesPathElement, paths map[*dispatch.Route][]apimodels.TestRoutesPathElement) {
+	matchers := make([]string, 0, len(route.Matchers))
+	for _, m := range route.Matchers {
+		matchers = append(matchers, m.String())
+	}
+	path := make([]apimodels.TestRoutesPathElement, 0, len(parent)+1)
+	path = append(path, parent...)
+	path = append(path, apimodels.TestRoutesPathElement{
+		Index:    idx,
+		Receiver: route.RouteOpts.Receiver,
+		Matchers: matchers,
+		Continue: route.Continue,
+	})
+	paths[route] = path
+	for i, child := range route.Routes {
+		walkRoutes(child, i, path, paths)
+	}
+}
+
+// isAutogeneratedRoute returns true if the route is the root of the policies autogenerated from the notification settings of alert rules.
+func isAutogeneratedRoute(route *dispatch.Route) bool {
+	return len(route.Matchers) == 1 && route.Matchers[0].Name == ngmodels.AutogeneratedRouteLabel
+}
+
+func groupByLabels(opts dispatch.RouteOpts) []string {
+	if opts.GroupByAll {
+		return []string{"..."}
+	}
+	groupBy := make([]string, 0, len(opts.GroupBy))
+	for label := range opts.GroupBy {
+		groupBy = append(groupBy, string(label))
+	}
+	sort.Strings(groupBy)
+	return groupBy
+}
+
+// timeIntervals returns the mute time intervals and time intervals of the configuration by name.
+func timeIntervals(cfg apimodels.Config) map[string][]timeinterval.TimeInterval {
+	intervals := make(map[string][]timeinterval.TimeInterval, len(cfg.MuteTimeIntervals)+len(cfg.TimeIntervals))
+	for _, ti := range cfg.MuteTimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	for _, ti := range cfg.TimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	return intervals
+}
+
+// routeMutedBy returns the names of the time intervals that mute the policy at the given time. A policy is muted if
+// any of its mute time intervals is active, or if it has active time intervals and none of them is active.
+// This is the same logic as the time mute and active stages of the notification pipeline.
+func routeMutedBy(opts dispatch.RouteOpts, intervals map[string][]timeinterval.TimeInterval, now time.Time) ([]string, error) {
+	var mutedBy []string
+	for _, name := range opts.MuteTimeIntervals {
+		active, err := intervalContains(intervals, name, now)
+		if err != nil {
+			return nil, err
+		}
+		if active {
+			mutedBy = append(mutedBy, name)
+		}
+	}
+	var inactive []string
+	for _, name := range opts.ActiveTimeIntervals {
+		active, err := intervalContains(intervals, name, now)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			inactive = append(inactive, name)
+		}
+	}
+	if len(inactive) == len(opts.ActiveTimeIntervals) {
+		mutedBy = append(mutedBy, inactive...)
+	}
+	return mutedBy, nil
+}
+
+func intervalContains(intervals map[string][]timeinterval.TimeInterval, name string, now time.Time) (bool, error) {
+	ti, ok := intervals[name]
+	if !ok {
+		return false, fmt.Errorf("time interval %q is not defined", name)
+	}
+	for _, interval := range ti {
+		if interval.ContainsTime(now.UTC()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
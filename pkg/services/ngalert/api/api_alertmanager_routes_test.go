@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+var routingConfig = `{
+	"route": {
+		"receiver": "default",
+		"group_by": ["alertname"],
+		"routes": [{
+			"receiver": "team-a",
+			"object_matchers": [["team", "=", "a"]],
+			"group_by": ["alertname", "cluster"],
+			"mute_time_intervals": ["weekends"],
+			"continue": true
+		}, {
+			"receiver": "team-a-oncall",
+			"object_matchers": [["team", "=", "a"]],
+			"active_time_intervals": ["weekdays"],
+			"group_wait": "1m"
+		}, {
+			"receiver": "team-b",
+			"object_matchers": [["team", "=", "b"]]
+		}]
+	},
+	"time_intervals": [{
+		"name": "weekends",
+		"time_intervals": [{"weekdays": ["saturday", "sunday"]}]
+	}, {
+		"name": "weekdays",
+		"time_intervals": [{"weekdays": ["monday:friday"]}]
+	}],
+	"receivers": [{"name": "default"}, {"name": "team-a"}, {"name": "team-a-oncall"}, {"name": "team-b"}]
+}`
+
+func createRoutingConfig(t *testing.T) *apimodels.PostableApiAlertingConfig {
+	t.Helper()
+
+	cfg := &apimodels.PostableApiAlertingConfig{}
+	require.NoError(t, json.Unmarshal([]byte(routingConfig), cfg))
+	return cfg
+}
+
+func TestTestRoutes(t *testing.T) {
+	cfg := createRoutingConfig(t)
+
+	saturday := time.Date(2024, time.October, 19, 12, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2024, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("returns the root policy if no child policy matches", func(t *testing.T) {
+		result, err := testRoutes(cfg.Config, model.LabelSet{"alertname": "test"}, wednesday, false)
+		require.NoError(t, err)
+		require.Equal(t, wednesday, result.Time)
+		require.Len(t, result.Routes, 1)
+		match := result.Routes[0]
+		require.Equal(t, "default", match.Receiver)
+		require.Equal(t, []string{"alertname"}, match.GroupBy)
+		require.Equal(t, []apimodels.TestRoutesPathElement{{Index: 0, Receiver: "default", Matchers: []string{}}}, match.Path)
+		require.False(t, match.Muted)
+	})
+
+	t.Run("returns all matching policies with their path and options", func(t *testing.T) {
+		result, err := testRoutes(cfg.Config, model.LabelSet{"alertname": "test", "team": "a"}, wednesday, false)
+		require.NoError(t, err)
+		require.Len(t, result.Routes, 2)
+
+		teamA := result.Routes[0]
+		require.Equal(t, "team-a", teamA.Receiver)
+		require.Equal(t, []string{"alertname", "cluster"}, teamA.GroupBy)
+		require.Equal(t, []string{"weekends"}, teamA.MuteTimeIntervals)
+		require.Len(t, teamA.Path, 2)
+		require.Equal(t, apimodels.TestRoutesPathElement{Index: 0, Receiver: "team-a", Matchers: []string{`team="a"`}, Continue: true}, teamA.Path[1])
+		require.False(t, teamA.Muted)
+
+		oncall := result.Routes[1]
+		require.Equal(t, "team-a-oncall", oncall.Receiver)
+		require.Equal(t, []string{"alertname"}, oncall.GroupBy, "group_by should be inherited from the parent policy")
+		require.Equal(t, model.Duration(time.Minute), oncall.GroupWait)
+		require.Equal(t, 1, oncall.Path[1].Index)
+		require.False(t, oncall.Muted)
+	})
+
+	t.Run("returns the time intervals that mute the matching policies", func(t *testing.T) {
+		result, err := testRoutes(cfg.Config, model.LabelSet{"alertname": "test", "team": "a"}, saturday, false)
+		require.NoError(t, err)
+		require.Len(t, result.Routes, 2)
+
+		require.True(t, result.Routes[0].Muted)
+		require.Equal(t, []string{"weekends"}, result.Routes[0].MutedBy)
+		require.True(t, result.Routes[1].Muted)
+		require.Equal(t, []string{"weekdays"}, result.Routes[1].MutedBy)
+	})
+
+	t.Run("fails if a matching policy uses an unknown time interval", func(t *testing.T) {
+		cfg := createRoutingConfig(t)
+		cfg.Route.Routes[2].MuteTimeIntervals = []string{"unknown"}
+		_, err := testRoutes(cfg.Config, model.LabelSet{"alertname": "test", "team": "b"}, saturday, false)
+		require.ErrorContains(t, err, `time interval "unknown" is not defined`)
+	})
+
+	t.Run("hides autogenerated policies if requested", func(t *testing.T) {
+		cfg := createRoutingConfig(t)
+		autogen := apimodels.Route{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"receiver": "default",
+			"object_matchers": [["__grafana_autogenerated__", "=", "true"]],
+			"routes": [{"receiver": "team-b", "object_matchers": [["__grafana_receiver__", "=", "team-b"]]}]
+		}`), &autogen))
+		cfg.Route.Routes = append([]*apimodels.Route{&autogen}, cfg.Route.Routes...)
+		autogenLabels := model.LabelSet{"__grafana_autogenerated__": "true", "__grafana_receiver__": "team-b"}
+
+		result, err := testRoutes(cfg.Config, autogenLabels, wednesday, false)
+		require.NoError(t, err)
+		require.Len(t, result.Routes, 1)
+		require.Equal(t, "team-b", result.Routes[0].Receiver)
+
+		result, err = testRoutes(cfg.Config, autogenLabels, wednesday, true)
+		require.NoError(t, err)
+		require.Empty(t, result.Routes)
+
+		result, err = testRoutes(cfg.Config, model.LabelSet{"team": "b"}, wednesday, false)
+		require.NoError(t, err)
+		require.Equal(t, 3, result.Routes[0].Path[1].Index)
+
+		result, err = testRoutes(cfg.Config, model.LabelSet{"team": "b"}, wednesday, true)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Routes[0].Path[1].Index, "paths should be indexed as if there were no autogenerated policies")
+	})
+}
+
+func TestRoutePostTestRoutes(t *testing.T) {
+	sut := createSut(t)
+
+	t.Run("assert 404 when there is no configuration", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(10), apimodels.TestRoutesConfigBodyParams{})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("assert 200 with the current configuration", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(1), apimodels.TestRoutesConfigBodyParams{
+			Labels: model.LabelSet{"alertname": "test"},
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+
+		result := apimodels.TestRoutesResult{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Routes, 1)
+		require.Equal(t, "grafana-default-email", result.Routes[0].Receiver)
+	})
+
+	t.Run("assert 200 with a proposed configuration", func(t *testing.T) {
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(10), apimodels.TestRoutesConfigBodyParams{
+			Labels:             model.LabelSet{"team": "a"},
+			AlertmanagerConfig: createRoutingConfig(t),
+		})
+		require.Equal(t, http.StatusOK, response.Status())
+
+		result := apimodels.TestRoutesResult{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Routes, 2)
+	})
+
+	t.Run("assert 400 when the configuration is invalid", func(t *testing.T) {
+		cfg := createRoutingConfig(t)
+		cfg.Route.Routes[2].MuteTimeIntervals = []string{"unknown"}
+		response := sut.RoutePostTestRoutes(createRequestCtxInOrg(10), apimodels.TestRoutesConfigBodyParams{
+			Labels:             model.LabelSet{"team": "b"},
+			AlertmanagerConfig: cfg,
+		})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/authz/zanzana"
@@ -383,6 +384,42 @@ func TestAlertmanagerAutogenConfig(t *testing.T) {
 			compare(t, validConfigWithoutAutogen, string(configBody))
 		})
 	})
+
+	t.Run("route POST test routes", func(t *testing.T) {
+		postTestRoutes := func(t *testing.T, rc *contextmodel.ReqContext, labels model.LabelSet) apimodels.TestRoutesResult {
+			t.Helper()
+			sut, _ := createSutForAutogen(t)
+			response := sut.RoutePostTestRoutes(rc, apimodels.TestRoutesConfigBodyParams{Labels: labels})
+			require.Equal(t, 200, response.Status())
+
+			result := apimodels.TestRoutesResult{}
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			return result
+		}
+		autogenLabels := model.LabelSet{"__grafana_autogenerated__": "true", "__grafana_receiver__": "other email"}
+
+		t.Run("when admin return autogen routes", func(t *testing.T) {
+			rc := createRequestCtxInOrg(2)
+			rc.SignedInUser.OrgRole = org.RoleAdmin
+
+			result := postTestRoutes(t, rc, autogenLabels)
+			require.Len(t, result.Routes, 1)
+			require.Equal(t, "other email", result.Routes[0].Receiver)
+			require.Len(t, result.Routes[0].Path, 3)
+		})
+
+		t.Run("when not admin route with autogen routes but do not return them", func(t *testing.T) {
+			rc := createRequestCtxInOrg(2)
+
+			result := postTestRoutes(t, rc, autogenLabels)
+			require.Empty(t, result.Routes, "labels should be routed by the hidden autogen routes, not by the root route")
+
+			result = postTestRoutes(t, rc, model.LabelSet{"a": "b"})
+			require.Len(t, result.Routes, 1)
+			require.Equal(t, "other email", result.Routes[0].Receiver)
+			require.Equal(t, 0, result.Routes[0].Path[1].Index, "paths should be indexed as in the configuration without autogen routes")
+		})
+	})
 }
 
 func TestRouteGetAlertingConfigHistory(t *testing.T) {
@@ -472,6 +472,7 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, sr StatusRe
 			Type:           rule.Type().String(),
 			LastEvaluation: status.EvaluationTimestamp,
 			EvaluationTime: status.EvaluationDuration.Seconds(),
+			QueryOffset:    rule.QueryOffset.Seconds(),
 		}
 
 		states := manager.GetStatesForRuleUID(rule.OrgID, rule.UID)
@@ -531,6 +531,31 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 		})
 	})
 
+	t.Run("should return query offset of the rule in seconds", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		fakeAIM := NewFakeAlertInstanceManager(t)
+		fakeSch := newFakeSchedulerReader(t).setupStates(fakeAIM)
+		rule := gen.With(gen.WithQueryOffset(90 * time.Second)).GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+
+		api := PrometheusSrv{
+			log:     log.NewNopLogger(),
+			manager: fakeAIM,
+			status:  fakeSch,
+			store:   ruleStore,
+			authz:   &fakeRuleAccessControlService{},
+		}
+
+		response := api.RouteGetRuleStatuses(c)
+		require.Equal(t, http.StatusOK, response.Status())
+		result := &apimodels.RuleResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), result))
+
+		require.Len(t, result.Data.RuleGroups, 1)
+		require.Len(t, result.Data.RuleGroups[0].Rules, 1)
+		require.Equal(t, float64(90), result.Data.RuleGroups[0].Rules[0].QueryOffset)
+	})
+
 	t.Run("test folder, group and rule name query params", func(t *testing.T) {
 		ruleStore := fakes.NewRuleStore(t)
 		fakeAIM := NewFakeAlertInstanceManager(t)
@@ -366,148 +366,169 @@ func (srv RulerSrv) checkGroupLimits(group apimodels.PostableRuleGroupConfig) er
 
 // updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
 // All operations are performed in a single transaction
-//
-//nolint:gocyclo
 func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
 	var finalChanges *store.GroupDelta
 	var dbConfig *ngmodels.AlertConfiguration
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
-		id, _ := c.SignedInUser.GetInternalID()
-		userNamespace := c.SignedInUser.GetIdentityType()
+		var err error
+		finalChanges, dbConfig, err = srv.applyAlertRuleGroupChanges(tranCtx, c, groupKey, rules)
+		return err
+	})
 
-		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
-			groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
-		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return ruleGroupUpdateErrorToResponse(err)
+	}
 
-		if groupChanges.IsEmpty() {
-			finalChanges = groupChanges
-			logger.Info("No changes detected in the request. Do nothing")
-			return nil
-		}
+	srv.refreshAlertmanagerConfig(c, dbConfig)
+
+	return changesToResponse(finalChanges)
+}
+
+// applyAlertRuleGroupChanges does the work of updateAlertRulesInGroup in the transaction tranCtx.
+// It returns the applied changes, and the latest Alertmanager configuration if the changes affect notification settings.
+//
+//nolint:gocyclo
+func (srv RulerSrv) applyAlertRuleGroupChanges(tranCtx context.Context, c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) (*store.GroupDelta, *ngmodels.AlertConfiguration, error) {
+	var dbConfig *ngmodels.AlertConfiguration
+	id, _ := c.SignedInUser.GetInternalID()
+	userNamespace := c.SignedInUser.GetIdentityType()
+
+	logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
+		groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
+	groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if groupChanges.IsEmpty() {
+		logger.Info("No changes detected in the request. Do nothing")
+		return groupChanges, nil, nil
+	}
 
-		err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+	err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
+		return nil, nil, err
+	}
+
+	if err := srv.validateDependencies(tranCtx, c.SignedInUser, groupChanges); err != nil {
+		return nil, nil, err
+	}
+
+	newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
+	if len(newOrUpdatedNotificationSettings) > 0 {
+		dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
 		if err != nil {
-			return err
+			return nil, nil, fmt.Errorf("failed to get latest configuration: %w", err)
 		}
-
-		if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
-			return err
+		cfg, err := notifier.Load([]byte(dbConfig.AlertmanagerConfiguration))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse configuration: %w", err)
 		}
-
-		newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
-		if len(newOrUpdatedNotificationSettings) > 0 {
-			dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
-			if err != nil {
-				return fmt.Errorf("failed to get latest configuration: %w", err)
+		validator := notifier.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
+		for _, s := range newOrUpdatedNotificationSettings {
+			if err := validator.Validate(s); err != nil {
+				return nil, nil, errors.Join(ngmodels.ErrAlertRuleFailedValidation, err)
 			}
-			cfg, err := notifier.Load([]byte(dbConfig.AlertmanagerConfiguration))
-			if err != nil {
-				return fmt.Errorf("failed to parse configuration: %w", err)
-			}
-			validator := notifier.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
-			for _, s := range newOrUpdatedNotificationSettings {
-				if err := validator.Validate(s); err != nil {
-					return errors.Join(ngmodels.ErrAlertRuleFailedValidation, err)
-				}
-			}
-		}
-
-		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
-			return err
 		}
+	}
 
-		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
-		logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+	if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
+		return nil, nil, err
+	}
 
-		// Delete first as this could prevent future unique constraint violations.
-		if len(finalChanges.Delete) > 0 {
-			UIDs := make([]string, 0, len(finalChanges.Delete))
-			for _, rule := range finalChanges.Delete {
-				UIDs = append(UIDs, rule.UID)
-			}
+	finalChanges := store.UpdateCalculatedRuleFields(groupChanges)
+	logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
 
-			if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
-				return fmt.Errorf("failed to delete rules: %w", err)
-			}
+	// Delete first as this could prevent future unique constraint violations.
+	if len(finalChanges.Delete) > 0 {
+		UIDs := make([]string, 0, len(finalChanges.Delete))
+		for _, rule := range finalChanges.Delete {
+			UIDs = append(UIDs, rule.UID)
 		}
 
-		if len(finalChanges.Update) > 0 {
-			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
-			for _, update := range finalChanges.Update {
-				logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
-				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
-				})
-			}
-			err = srv.store.UpdateAlertRules(tranCtx, updates)
-			if err != nil {
-				return fmt.Errorf("failed to update rules: %w", err)
-			}
+		if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
+			return nil, nil, fmt.Errorf("failed to delete rules: %w", err)
 		}
+	}
 
-		if len(finalChanges.New) > 0 {
-			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
-			for _, rule := range finalChanges.New {
-				inserts = append(inserts, *rule)
-			}
-			added, err := srv.store.InsertAlertRules(tranCtx, inserts)
-			if err != nil {
-				return fmt.Errorf("failed to add rules: %w", err)
-			}
-			if len(added) != len(finalChanges.New) {
-				logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
-			} else {
-				for i, newRule := range finalChanges.New {
-					newRule.ID = added[i].ID
-					newRule.UID = added[i].UID
-				}
-			}
+	if len(finalChanges.Update) > 0 {
+		updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
+		for _, update := range finalChanges.Update {
+			logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+			updates = append(updates, ngmodels.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
 		}
+		err = srv.store.UpdateAlertRules(tranCtx, updates)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update rules: %w", err)
+		}
+	}
 
-		if len(finalChanges.New) > 0 {
-			userID, _ := identity.UserIdentifier(c.SignedInUser.GetID())
-			limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
-				OrgID:  c.SignedInUser.GetOrgID(),
-				UserID: userID,
-			}) // alert rule is table name
-			if err != nil {
-				return fmt.Errorf("failed to get alert rules quota: %w", err)
-			}
-			if limitReached {
-				return ngmodels.ErrQuotaReached
+	if len(finalChanges.New) > 0 {
+		inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
+		for _, rule := range finalChanges.New {
+			inserts = append(inserts, *rule)
+		}
+		added, err := srv.store.InsertAlertRules(tranCtx, inserts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add rules: %w", err)
+		}
+		if len(added) != len(finalChanges.New) {
+			logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
+		} else {
+			for i, newRule := range finalChanges.New {
+				newRule.ID = added[i].ID
+				newRule.UID = added[i].UID
 			}
 		}
-		return nil
-	})
+	}
 
-	if err != nil {
-		if errors.As(err, &errutil.Error{}) {
-			return response.Err(err)
-		} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-			return ErrResp(http.StatusNotFound, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
-			return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
-		} else if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+	if len(finalChanges.New) > 0 {
+		userID, _ := identity.UserIdentifier(c.SignedInUser.GetID())
+		limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
+			OrgID:  c.SignedInUser.GetOrgID(),
+			UserID: userID,
+		}) // alert rule is table name
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get alert rules quota: %w", err)
+		}
+		if limitReached {
+			return nil, nil, ngmodels.ErrQuotaReached
 		}
-		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
+	return finalChanges, dbConfig, nil
+}
 
+func ruleGroupUpdateErrorToResponse(err error) response.Response {
+	if errors.As(err, &errutil.Error{}) {
+		return response.Err(err)
+	} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+		return ErrResp(http.StatusNotFound, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
+		return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	} else if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
+}
+
+// refreshAlertmanagerConfig applies the Alertmanager configuration returned by applyAlertRuleGroupChanges, if any.
+func (srv RulerSrv) refreshAlertmanagerConfig(c *contextmodel.ReqContext, dbConfig *ngmodels.AlertConfiguration) {
 	if srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingSimplifiedRouting) && dbConfig != nil {
 		// This isn't strictly necessary since the alertmanager config is periodically synced.
-		err := srv.amRefresher.ApplyConfig(c.Req.Context(), groupKey.OrgID, dbConfig)
+		err := srv.amRefresher.ApplyConfig(c.Req.Context(), dbConfig.OrgID, dbConfig)
 		if err != nil {
 			srv.log.Warn("Failed to refresh Alertmanager config for org after change in notification settings", "org", c.SignedInUser.GetOrgID(), "error", err)
 		}
 	}
-
-	return changesToResponse(finalChanges)
 }
 
 func changesToResponse(finalChanges *store.GroupDelta) response.Response {
@@ -576,6 +597,8 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
 			Record:               ApiRecordFromModelRecord(r.Record),
 			Metadata:             AlertRuleMetadataFromModelMetadata(r.Metadata),
+			Dependencies:         ApiDependenciesFromModelDependencies(r.Dependencies),
+			EvaluationLimits:     ApiEvaluationLimitsFromModelEvaluationLimits(r.EvaluationLimits),
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -584,6 +607,14 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 		Annotations: r.Annotations,
 		Labels:      r.Labels,
 	}
+	if r.KeepFiringFor > 0 {
+		keepFiringFor := model.Duration(r.KeepFiringFor)
+		gettableExtendedRuleNode.ApiRuleNode.KeepFiringFor = &keepFiringFor
+	}
+	if r.QueryOffset > 0 {
+		queryOffset := model.Duration(r.QueryOffset)
+		gettableExtendedRuleNode.GrafanaManagedAlert.QueryOffset = &queryOffset
+	}
 	return gettableExtendedRuleNode
 }
 
@@ -643,6 +674,168 @@ func validateQueries(ctx context.Context, groupChanges *store.GroupDelta, valida
 	return nil
 }
 
+// validateDependencies checks that the rules that new and updated rules depend on exist in the organization
+// and that the user can read them, and that the rules do not depend on themselves through other rules.
+// Rules of the group that are created or updated in the same request can be dependencies too.
+// Dependencies of updated rules are checked only if they changed, so that unrelated changes are not rejected.
+func (srv RulerSrv) validateDependencies(ctx context.Context, user identity.Requester, groupChanges *store.GroupDelta) error {
+	rules := make([]*ngmodels.AlertRule, 0, len(groupChanges.New)+len(groupChanges.Update))
+	rules = append(rules, groupChanges.New...)
+	inGroup := make(map[string]struct{}, len(groupChanges.New)+len(groupChanges.Update))
+	for _, rule := range groupChanges.New {
+		if rule.UID != "" {
+			inGroup[rule.UID] = struct{}{}
+		}
+	}
+	for _, upd := range groupChanges.Update {
+		inGroup[upd.New.UID] = struct{}{}
+		if len(upd.Diff.GetDiffsForField("Dependencies")) > 0 {
+			rules = append(rules, upd.New)
+		}
+	}
+	deleted := make(map[string]struct{}, len(groupChanges.Delete))
+	for _, rule := range groupChanges.Delete {
+		deleted[rule.UID] = struct{}{}
+	}
+
+	var uids []string
+	for _, rule := range rules {
+		for _, d := range rule.Dependencies {
+			if _, ok := inGroup[d.RuleUID]; ok || slices.Contains(uids, d.RuleUID) {
+				continue
+			}
+			uids = append(uids, d.RuleUID)
+		}
+	}
+	if len(uids) == 0 {
+		return srv.validateDependencyCycles(ctx, groupChanges, rules)
+	}
+
+	existing, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:    groupChanges.GroupKey.OrgID,
+		RuleUIDs: uids,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	readable := make(map[string]struct{}, len(existing))
+	for _, rule := range existing {
+		if _, ok := deleted[rule.UID]; ok {
+			continue
+		}
+		if err := srv.authz.AuthorizeAccessInFolder(ctx, user, rule); err != nil {
+			if errors.Is(err, authz.ErrAuthorizationBase) {
+				continue
+			}
+			return err
+		}
+		readable[rule.UID] = struct{}{}
+	}
+
+	for _, rule := range rules {
+		for _, d := range rule.Dependencies {
+			if _, ok := inGroup[d.RuleUID]; ok {
+				continue
+			}
+			if _, ok := readable[d.RuleUID]; !ok {
+				return fmt.Errorf("%w '%s': dependency rule %s does not exist or cannot be accessed", ngmodels.ErrAlertRuleFailedValidation, rule.Title, d.RuleUID)
+			}
+		}
+	}
+	return srv.validateDependencyCycles(ctx, groupChanges, rules)
+}
+
+// validateDependencyCycles checks that none of the rules depends on itself through its dependencies, e.g. rule A
+// depends on rule B and rule B depends on rule A, because such rules would inhibit each other while they fire.
+// The rules of the request replace the stored ones, and the dependencies of other rules are read from the store.
+func (srv RulerSrv) validateDependencyCycles(ctx context.Context, groupChanges *store.GroupDelta, rules []*ngmodels.AlertRule) error {
+	dependencyUIDs := func(rule *ngmodels.AlertRule) []string {
+		result := make([]string, 0, len(rule.Dependencies))
+		for _, d := range rule.Dependencies {
+			result = append(result, d.RuleUID)
+		}
+		return result
+	}
+	graph := make(map[string][]string)
+	for _, group := range groupChanges.AffectedGroups {
+		for _, rule := range group {
+			graph[rule.UID] = dependencyUIDs(rule)
+		}
+	}
+	for _, rule := range groupChanges.New {
+		if rule.UID != "" {
+			graph[rule.UID] = dependencyUIDs(rule)
+		}
+	}
+	for _, upd := range groupChanges.Update {
+		graph[upd.New.UID] = dependencyUIDs(upd.New)
+	}
+	for _, rule := range groupChanges.Delete {
+		graph[rule.UID] = nil
+	}
+
+	// read the dependencies of the rules that are not in the request, level by level, until all reachable rules are known
+	for {
+		var unknown []string
+		for _, deps := range graph {
+			for _, uid := range deps {
+				if _, ok := graph[uid]; !ok && !slices.Contains(unknown, uid) {
+					unknown = append(unknown, uid)
+				}
+			}
+		}
+		if len(unknown) == 0 {
+			break
+		}
+		existing, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+			OrgID:    groupChanges.GroupKey.OrgID,
+			RuleUIDs: unknown,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get dependencies: %w", err)
+		}
+		for _, rule := range existing {
+			graph[rule.UID] = dependencyUIDs(rule)
+		}
+		for _, uid := range unknown {
+			if _, ok := graph[uid]; !ok {
+				graph[uid] = nil
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.UID == "" {
+			continue
+		}
+		for _, d := range rule.Dependencies {
+			if dependsOn(graph, d.RuleUID, rule.UID) {
+				return fmt.Errorf("%w '%s': dependency rule %s depends on this rule, rules cannot depend on each other", ngmodels.ErrAlertRuleFailedValidation, rule.Title, d.RuleUID)
+			}
+		}
+	}
+	return nil
+}
+
+// dependsOn returns true if the rule with UID from depends on the rule with UID to, directly or through other rules of the graph.
+func dependsOn(graph map[string][]string, from, to string) bool {
+	visited := map[string]struct{}{}
+	stack := []string{from}
+	for len(stack) > 0 {
+		uid := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if uid == to {
+			return true
+		}
+		if _, ok := visited[uid]; ok {
+			continue
+		}
+		visited[uid] = struct{}{}
+		stack = append(stack, graph[uid]...)
+	}
+	return false
+}
+
 // shouldValidate returns true if the rule is not paused and there are changes in the rule that are not ignored
 func shouldValidate(delta store.RuleDelta) bool {
 	for _, diff := range delta.Diff {
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ImportPrometheusRules converts the groups of the Prometheus rule file in the request body to Grafana-managed rules
+// that query the data source ds, and saves them in the folder `namespaceUID`. Existing groups with the same names are replaced,
+// and their rules keep their UIDs if converted rules have the same titles.
+// If the query parameter `dryRun` is true, the rules are only converted and validated.
+// Returns http.StatusBadRequest with the report of the conversion if any group or rule cannot be converted, or if the title
+// of a converted rule is used by a rule of another group in the folder. Nothing is saved in this case.
+func (srv RulerSrv) ImportPrometheusRules(c *contextmodel.ReqContext, namespaceUID string, ds *datasources.DataSource) response.Response {
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	body, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to read request body")
+	}
+	file, err := prom.ParseRulesFile(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:   ds.UID,
+		DatasourceType:  ds.Type,
+		DefaultInterval: srv.cfg.DefaultRuleEvaluationInterval,
+	})
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	groups := converter.Convert(c.SignedInUser.GetOrgID(), namespace.UID, file)
+	limits := RuleLimitsFromConfig(srv.cfg, srv.featureManager)
+	for i := range groups {
+		if groups[i].Err == nil {
+			groups[i].Err = validateImportedRuleGroup(groups[i].Group, limits)
+		}
+		for j := range groups[i].Rules {
+			if groups[i].Rules[j].Err == nil {
+				groups[i].Rules[j].Err = validateImportedRule(&groups[i].Rules[j].AlertRule, *srv.cfg, limits)
+			}
+		}
+	}
+
+	if err := srv.matchExistingRules(c.Req.Context(), c.SignedInUser.GetOrgID(), namespace.UID, groups); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get existing rules")
+	}
+	failed := false
+	for i := range groups {
+		failed = failed || groups[i].HasErrors()
+	}
+
+	dryRun := c.QueryBool("dryRun")
+	if failed {
+		return response.JSON(http.StatusBadRequest, toPrometheusRulesImportResponse("some rules cannot be converted", dryRun, groups))
+	}
+	if dryRun {
+		return response.JSON(http.StatusOK, toPrometheusRulesImportResponse("rules converted successfully", dryRun, groups))
+	}
+
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		for _, group := range groups {
+			groupKey := ngmodels.AlertRuleGroupKey{
+				OrgID:        c.SignedInUser.GetOrgID(),
+				NamespaceUID: namespace.UID,
+				RuleGroup:    group.Group.Name,
+			}
+			rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(group.Rules))
+			for i := range group.Rules {
+				rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: group.Rules[i].AlertRule})
+			}
+			// converted rules have no notification settings, so the Alertmanager configuration never needs to be refreshed.
+			if _, _, err := srv.applyAlertRuleGroupChanges(tranCtx, c, groupKey, rules); err != nil {
+				return fmt.Errorf("failed to import rule group %s: %w", group.Group.Name, err)
+			}
+			// new rules get their UIDs when they are inserted
+			for i := range rules {
+				group.Rules[i].AlertRule.UID = rules[i].UID
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ruleGroupUpdateErrorToResponse(err)
+	}
+
+	return response.JSON(http.StatusAccepted, toPrometheusRulesImportResponse("rules imported successfully", dryRun, groups))
+}
+
+// matchExistingRules sets the UIDs of converted rules to the UIDs of rules with the same titles in the groups that are going to be replaced.
+// This way, re-importing a rule file updates the rules instead of re-creating them.
+// Titles must be unique in the namespace, so converted rules whose titles are used by rules of other groups
+// of the namespace get an error.
+func (srv RulerSrv) matchExistingRules(ctx context.Context, orgID int64, namespaceUID string, groups []prom.GroupResult) error {
+	existing, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespaceUID},
+	})
+	if err != nil {
+		return err
+	}
+
+	replaced := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		replaced[group.Group.Name] = struct{}{}
+	}
+	uids := make(map[ngmodels.AlertRuleGroupKey]map[string]string)
+	others := make(map[string]*ngmodels.AlertRule)
+	for _, rule := range existing {
+		if _, ok := replaced[rule.RuleGroup]; !ok {
+			others[rule.Title] = rule
+			continue
+		}
+		key := rule.GetGroupKey()
+		if uids[key] == nil {
+			uids[key] = make(map[string]string)
+		}
+		uids[key][rule.Title] = rule.UID
+	}
+	for i := range groups {
+		for j := range groups[i].Rules {
+			rule := &groups[i].Rules[j].AlertRule
+			if groups[i].Rules[j].Err != nil {
+				continue
+			}
+			if other, ok := others[rule.Title]; ok {
+				groups[i].Rules[j].Err = fmt.Errorf("%w: title %q is already used by rule %s in group %q of the folder",
+					ngmodels.ErrAlertRuleUniqueConstraintViolation, rule.Title, other.UID, other.RuleGroup)
+				continue
+			}
+			rule.UID = uids[rule.GetGroupKey()][rule.Title]
+		}
+	}
+	return nil
+}
+
+func validateImportedRuleGroup(group prom.RuleGroup, limits RuleLimits) error {
+	if len(group.Name) > store.AlertRuleMaxRuleGroupNameLength {
+		return fmt.Errorf("rule group name is too long. Max length is %d", store.AlertRuleMaxRuleGroupNameLength)
+	}
+	interval := time.Duration(group.Interval)
+	if interval == 0 {
+		interval = limits.DefaultRuleEvaluationInterval
+	}
+	_, err := validateInterval(interval, limits.BaseInterval)
+	return err
+}
+
+func validateImportedRule(rule *ngmodels.AlertRule, cfg setting.UnifiedAlertingSettings, limits RuleLimits) error {
+	if len(rule.Title) > store.AlertRuleMaxTitleLength {
+		return fmt.Errorf("alert rule title is too long. Max length is %d", store.AlertRuleMaxTitleLength)
+	}
+	if rule.Type() == ngmodels.RuleTypeRecording && !limits.RecordingRulesAllowed {
+		return fmt.Errorf("%w: recording rules cannot be created on this instance", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if err := rule.SetDashboardAndPanelFromAnnotations(); err != nil {
+		return err
+	}
+	return rule.ValidateAlertRule(cfg)
+}
+
+func toPrometheusRulesImportResponse(message string, dryRun bool, groups []prom.GroupResult) apimodels.PrometheusRulesImportResponse {
+	result := apimodels.PrometheusRulesImportResponse{
+		Message: message,
+		DryRun:  dryRun,
+		Groups:  make([]apimodels.PrometheusRuleGroupImportResult, 0, len(groups)),
+	}
+	for _, group := range groups {
+		g := apimodels.PrometheusRuleGroupImportResult{
+			Name:  group.Group.Name,
+			Rules: make([]apimodels.PrometheusRuleImportResult, 0, len(group.Rules)),
+		}
+		if group.Err != nil {
+			g.Error = group.Err.Error()
+		}
+		for _, rule := range group.Rules {
+			r := apimodels.PrometheusRuleImportResult{
+				Name: rule.Rule.Name(),
+			}
+			if rule.Err != nil {
+				r.Error = rule.Err.Error()
+			} else {
+				r.UID = rule.AlertRule.UID
+				export, err := AlertRuleExportFromAlertRule(rule.AlertRule)
+				if err != nil {
+					r.Error = fmt.Sprintf("failed to export the converted rule: %s", err)
+				} else {
+					r.Rule = &export
+				}
+			}
+			g.Rules = append(g.Rules, r)
+		}
+		result.Groups = append(result.Groups, g)
+	}
+	return result
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+const prometheusRulesFile = `
+groups:
+  - name: api
+    interval: 1m
+    rules:
+      - alert: HighErrorRate
+        expr: sum(rate(http_requests_total{code=~"5.."}[5m])) by (job) > 0.1
+        for: 5m
+        labels:
+          severity: page
+        annotations:
+          summary: High error rate in {{ $labels.job }}
+      - record: job:http_requests:rate5m
+        expr: sum(rate(http_requests_total[5m])) by (job)
+`
+
+func TestImportPrometheusRules(t *testing.T) {
+	orgID := int64(1)
+	f := &folder.Folder{UID: "folder-uid", Title: "Prometheus"}
+	ds := &datasources.DataSource{UID: "prom-uid", Type: datasources.DS_PROMETHEUS}
+
+	setup := func() (*RulerSrv, *fakes.RuleStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+		srv := createService(ruleStore)
+		srv.cfg.DefaultRuleEvaluationInterval = time.Minute
+		srv.conditionValidator = &recordingConditionValidator{}
+		return srv, ruleStore
+	}
+
+	createRequest := func(body string, dryRun bool) *contextmodel.ReqContext {
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)
+		rc := createRequestContextWithPerms(orgID, map[int64]map[string][]string{orgID: {
+			ac.ActionAlertingRuleRead:    {scope},
+			ac.ActionAlertingRuleCreate:  {scope},
+			ac.ActionAlertingRuleUpdate:  {scope},
+			ac.ActionAlertingRuleDelete:  {scope},
+			dashboards.ActionFoldersRead: {scope},
+			datasources.ActionQuery:      {datasources.ScopeAll},
+		}}, nil)
+		rc.Req.Body = io.NopCloser(strings.NewReader(body))
+		if dryRun {
+			rc.Req.Form.Set("dryRun", "true")
+		}
+		return rc
+	}
+
+	writes := func(ruleStore *fakes.RuleStore) []any {
+		return ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			switch cmd.(type) {
+			case []models.AlertRule, []models.UpdateRule:
+				return cmd, true
+			}
+			return nil, false
+		})
+	}
+
+	t.Run("dry run converts rules without saving them", func(t *testing.T) {
+		srv, ruleStore := setup()
+
+		response := srv.ImportPrometheusRules(createRequest(prometheusRulesFile, true), f.UID, ds)
+
+		require.Equalf(t, 200, response.Status(), "Expected 200 but got %d: %s", response.Status(), string(response.Body()))
+		var result apimodels.PrometheusRulesImportResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.True(t, result.DryRun)
+		require.Len(t, result.Groups, 1)
+		require.Equal(t, "api", result.Groups[0].Name)
+		require.Len(t, result.Groups[0].Rules, 2)
+
+		alert := result.Groups[0].Rules[0]
+		require.Empty(t, alert.Error)
+		require.Equal(t, "HighErrorRate", alert.Name)
+		require.NotNil(t, alert.Rule)
+		require.Equal(t, "HighErrorRate", alert.Rule.Title)
+		require.Equal(t, map[string]string{"severity": "page"}, *alert.Rule.Labels)
+		require.Len(t, alert.Rule.Data, 2)
+		require.Equal(t, ds.UID, alert.Rule.Data[0].DatasourceUID)
+
+		record := result.Groups[0].Rules[1]
+		require.Empty(t, record.Error)
+		require.NotNil(t, record.Rule.Record)
+		require.Equal(t, "job:http_requests:rate5m", record.Rule.Record.Metric)
+
+		require.Empty(t, writes(ruleStore))
+	})
+
+	t.Run("returns 400 and does not save anything if a rule cannot be converted", func(t *testing.T) {
+		srv, ruleStore := setup()
+		file := prometheusRulesFile + `
+  - name: invalid
+    rules:
+      - alert: Broken
+        expr: sum(rate(
+`
+		response := srv.ImportPrometheusRules(createRequest(file, false), f.UID, ds)
+
+		require.Equalf(t, 400, response.Status(), "Expected 400 but got %d: %s", response.Status(), string(response.Body()))
+		var result apimodels.PrometheusRulesImportResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Groups, 2)
+		require.Empty(t, result.Groups[0].Rules[0].Error)
+		require.Contains(t, result.Groups[1].Rules[0].Error, "cannot parse expr")
+		require.Nil(t, result.Groups[1].Rules[0].Rule)
+
+		require.Empty(t, writes(ruleStore))
+	})
+
+	t.Run("returns 400 if the rule file cannot be parsed", func(t *testing.T) {
+		srv, _ := setup()
+
+		response := srv.ImportPrometheusRules(createRequest("groups: [", false), f.UID, ds)
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("returns 400 if a title is used by a rule of another group in the folder", func(t *testing.T) {
+		srv, ruleStore := setup()
+		existing := models.RuleGen.With(
+			models.RuleMuts.WithGroupKey(models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: f.UID, RuleGroup: "other"}),
+			models.RuleMuts.WithTitle("HighErrorRate"),
+		).GenerateRef()
+		ruleStore.PutRule(context.Background(), existing)
+
+		response := srv.ImportPrometheusRules(createRequest(prometheusRulesFile, false), f.UID, ds)
+
+		require.Equalf(t, 400, response.Status(), "Expected 400 but got %d: %s", response.Status(), string(response.Body()))
+		var result apimodels.PrometheusRulesImportResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Contains(t, result.Groups[0].Rules[0].Error, existing.UID)
+		require.Empty(t, result.Groups[0].Rules[1].Error)
+
+		require.Empty(t, writes(ruleStore))
+	})
+
+	t.Run("saves rules and keeps UIDs of existing rules with the same titles", func(t *testing.T) {
+		srv, ruleStore := setup()
+		existing := models.RuleGen.With(
+			models.RuleMuts.WithGroupKey(models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: f.UID, RuleGroup: "api"}),
+			models.RuleMuts.WithTitle("HighErrorRate"),
+		).GenerateRef()
+		ruleStore.PutRule(context.Background(), existing)
+
+		response := srv.ImportPrometheusRules(createRequest(prometheusRulesFile, false), f.UID, ds)
+
+		require.Equalf(t, 202, response.Status(), "Expected 202 but got %d: %s", response.Status(), string(response.Body()))
+		var result apimodels.PrometheusRulesImportResponse
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.False(t, result.DryRun)
+		require.Equal(t, existing.UID, result.Groups[0].Rules[0].UID)
+
+		ops := writes(ruleStore)
+		require.Len(t, ops, 2)
+		updates := ops[0].([]models.UpdateRule)
+		require.Len(t, updates, 1)
+		require.Equal(t, existing.UID, updates[0].New.UID)
+		require.Equal(t, "HighErrorRate", updates[0].New.Title)
+		inserts := ops[1].([]models.AlertRule)
+		require.Len(t, inserts, 1)
+		require.Equal(t, "job:http_requests:rate5m", inserts[0].Title)
+	})
+}
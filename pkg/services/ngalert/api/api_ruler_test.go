@@ -638,6 +638,85 @@ func TestValidateQueries(t *testing.T) {
 	})
 }
 
+func TestValidateDependencies(t *testing.T) {
+	orgID := rand.Int63()
+	gen := models.RuleGen.With(models.RuleMuts.WithOrgID(orgID))
+	readable := gen.With(gen.WithNamespaceUID("readable")).GenerateRef()
+	hidden := gen.With(gen.WithNamespaceUID("hidden")).GenerateRef()
+
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), readable, hidden)
+	srv := createService(ruleStore)
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID("readable")
+	usr := createRequestContextWithPerms(orgID, map[int64]map[string][]string{orgID: {
+		ac.ActionAlertingRuleRead:    {scope},
+		dashboards.ActionFoldersRead: {scope},
+	}}, nil).SignedInUser
+
+	dependent := func(uid string) *models.AlertRule {
+		return gen.With(gen.WithDependencies(models.AlertRuleDependency{RuleUID: uid})).GenerateRef()
+	}
+
+	t.Run("should accept dependencies on readable rules", func(t *testing.T) {
+		delta := &store.GroupDelta{GroupKey: readable.GetGroupKey(), New: []*models.AlertRule{dependent(readable.UID)}}
+		require.NoError(t, srv.validateDependencies(context.Background(), usr, delta))
+	})
+
+	t.Run("should accept dependencies on rules of the same request", func(t *testing.T) {
+		parent := gen.GenerateRef()
+		delta := &store.GroupDelta{GroupKey: readable.GetGroupKey(), New: []*models.AlertRule{parent, dependent(parent.UID)}}
+		require.NoError(t, srv.validateDependencies(context.Background(), usr, delta))
+	})
+
+	t.Run("should reject dependencies on rules that do not exist", func(t *testing.T) {
+		delta := &store.GroupDelta{GroupKey: readable.GetGroupKey(), New: []*models.AlertRule{dependent("missing")}}
+		err := srv.validateDependencies(context.Background(), usr, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "missing")
+	})
+
+	t.Run("should reject dependencies on rules the user cannot read", func(t *testing.T) {
+		delta := &store.GroupDelta{GroupKey: readable.GetGroupKey(), New: []*models.AlertRule{dependent(hidden.UID)}}
+		err := srv.validateDependencies(context.Background(), usr, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, hidden.UID)
+	})
+
+	t.Run("should not check dependencies of updated rules if they did not change", func(t *testing.T) {
+		rule := dependent("missing")
+		delta := &store.GroupDelta{GroupKey: readable.GetGroupKey(), Update: []store.RuleDelta{
+			{Existing: rule, New: rule, Diff: cmputil.DiffReport{cmputil.Diff{Path: "Title"}}},
+		}}
+		require.NoError(t, srv.validateDependencies(context.Background(), usr, delta))
+	})
+
+	t.Run("should reject rules of the same request that depend on each other", func(t *testing.T) {
+		a := gen.With(gen.WithUID("a"), gen.WithDependencies(models.AlertRuleDependency{RuleUID: "b"})).GenerateRef()
+		b := gen.With(gen.WithUID("b"), gen.WithDependencies(models.AlertRuleDependency{RuleUID: "a"})).GenerateRef()
+		delta := &store.GroupDelta{GroupKey: readable.GetGroupKey(), New: []*models.AlertRule{a, b}}
+		err := srv.validateDependencies(context.Background(), usr, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "rules cannot depend on each other")
+	})
+
+	t.Run("should reject dependencies on stored rules that depend on the rule", func(t *testing.T) {
+		// first -> second -> third -> first, where first and second are stored
+		second := gen.With(gen.WithNamespaceUID("readable"), gen.WithDependencies(models.AlertRuleDependency{RuleUID: "third"})).GenerateRef()
+		first := gen.With(gen.WithNamespaceUID("readable"), gen.WithDependencies(models.AlertRuleDependency{RuleUID: second.UID})).GenerateRef()
+		ruleStore.PutRule(context.Background(), first, second)
+
+		third := gen.With(gen.WithUID("third"), gen.WithDependencies(models.AlertRuleDependency{RuleUID: first.UID})).GenerateRef()
+		delta := &store.GroupDelta{GroupKey: readable.GetGroupKey(), New: []*models.AlertRule{third}}
+		err := srv.validateDependencies(context.Background(), usr, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, first.UID)
+
+		other := gen.With(gen.WithUID("other"), gen.WithDependencies(models.AlertRuleDependency{RuleUID: first.UID})).GenerateRef()
+		delta = &store.GroupDelta{GroupKey: readable.GetGroupKey(), New: []*models.AlertRule{other}}
+		require.NoError(t, srv.validateDependencies(context.Background(), usr, delta))
+	})
+}
+
 func createServiceWithProvenanceStore(store *fakes.RuleStore, provenanceStore provisioning.ProvisioningStore) *RulerSrv {
 	svc := createService(store)
 	svc.provenanceStore = provenanceStore
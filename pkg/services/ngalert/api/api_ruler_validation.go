@@ -22,6 +22,8 @@ type RuleLimits struct {
 	BaseInterval time.Duration
 	// Whether recording rules are allowed.
 	RecordingRulesAllowed bool
+	// The types of the writers that recording rules can select.
+	RecordingWriters []string
 }
 
 func RuleLimitsFromConfig(cfg *setting.UnifiedAlertingSettings, toggles featuremgmt.FeatureToggles) RuleLimits {
@@ -29,6 +31,7 @@ func RuleLimitsFromConfig(cfg *setting.UnifiedAlertingSettings, toggles featurem
 		DefaultRuleEvaluationInterval: cfg.DefaultRuleEvaluationInterval,
 		BaseInterval:                  cfg.BaseInterval,
 		RecordingRulesAllowed:         toggles.IsEnabledGlobally(featuremgmt.FlagGrafanaManagedRecordingRules),
+		RecordingWriters:              ngmodels.EnabledRecordingWriterTypes(cfg.RecordingRules),
 	}
 }
 
@@ -150,11 +153,19 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		}
 	}
 
+	newRule.Dependencies = ModelDependenciesFromApiDependencies(in.GrafanaManagedAlert.Dependencies)
+	newRule.EvaluationLimits = ModelEvaluationLimitsFromApiEvaluationLimits(in.GrafanaManagedAlert.EvaluationLimits)
+
 	newRule.For, err = validateForInterval(in)
 	if err != nil {
 		return ngmodels.AlertRule{}, err
 	}
 
+	newRule.KeepFiringFor, err = validateKeepFiringForInterval(in)
+	if err != nil {
+		return ngmodels.AlertRule{}, err
+	}
+
 	return newRule, nil
 }
 
@@ -177,13 +188,19 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 	if !prommodels.IsValidMetricName(metricName) {
 		return ngmodels.AlertRule{}, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, "metric name for recording rule must be a valid Prometheus metric name")
 	}
+	if err := ngmodels.ValidateRecordingWriter(in.GrafanaManagedAlert.Record.Writer, limits.RecordingWriters); err != nil {
+		return ngmodels.AlertRule{}, err
+	}
 	newRule.Record = ModelRecordFromApiRecord(in.GrafanaManagedAlert.Record)
 
 	newRule.NoDataState = ""
 	newRule.ExecErrState = ""
 	newRule.Condition = ""
 	newRule.For = 0
+	newRule.KeepFiringFor = 0
 	newRule.NotificationSettings = nil
+	newRule.Dependencies = nil
+	newRule.EvaluationLimits = ngmodels.AlertRuleEvaluationLimits{}
 
 	return newRule, nil
 }
@@ -270,6 +287,43 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringForInterval validates ApiRuleNode.KeepFiringFor and converts it to time.Duration.
+// Like validateForInterval, it returns -1 if the field is not specified and GrafanaManagedAlert.UID is not empty.
+func validateKeepFiringForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
+// validateQueryOffset validates GrafanaManagedAlert.QueryOffset and converts it to time.Duration.
+// Rules that do not specify the offset inherit query_offset of the group.
+// Like validateForInterval, it returns -1 if neither is specified and GrafanaManagedAlert.UID is not empty.
+func validateQueryOffset(ruleNode *apimodels.PostableExtendedRuleNode, groupOffset *prommodels.Duration) (time.Duration, error) {
+	offset := ruleNode.GrafanaManagedAlert.QueryOffset
+	if offset == nil {
+		offset = groupOffset
+	}
+	if offset == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*offset)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `query_offset` cannot be negative [%v]. 0 or any positive duration are allowed", *offset)
+	}
+	return duration, nil
+}
+
 // ValidateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
@@ -306,6 +360,10 @@ func ValidateRuleGroup(
 		if err != nil {
 			return nil, fmt.Errorf("invalid rule specification at index [%d]: %w", idx, err)
 		}
+		rule.QueryOffset, err = validateQueryOffset(&ruleGroupConfig.Rules[idx], ruleGroupConfig.QueryOffset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule specification at index [%d]: %w", idx, err)
+		}
 		if rule.UID != "" {
 			if existingIdx, ok := uids[rule.UID]; ok {
 				return nil, fmt.Errorf("rule [%d] has UID %s that is already assigned to another rule at index %d", idx, rule.UID, existingIdx)
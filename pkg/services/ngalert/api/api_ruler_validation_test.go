@@ -239,6 +239,27 @@ func TestValidateRuleGroup(t *testing.T) {
 			require.True(t, alert.HasPause)
 		}
 	})
+
+	t.Run("should use query offset of the group if rule does not specify it", func(t *testing.T) {
+		groupOffset := model.Duration(time.Minute)
+		ruleOffset := model.Duration(30 * time.Second)
+		r1 := validRule()
+		r2 := validRule()
+		r2.GrafanaManagedAlert.QueryOffset = &ruleOffset
+		g := validGroup(cfg, r1, r2)
+		g.QueryOffset = &groupOffset
+		alerts, err := ValidateRuleGroup(&g, orgId, folder.UID, limits)
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, alerts[0].QueryOffset)
+		require.Equal(t, 30*time.Second, alerts[1].QueryOffset)
+	})
+
+	t.Run("should keep query offset of existing rule if neither rule nor group specify it", func(t *testing.T) {
+		g := validGroup(cfg, validRule())
+		alerts, err := ValidateRuleGroup(&g, orgId, folder.UID, limits)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(-1), alerts[0].QueryOffset)
+	})
 }
 
 func TestValidateRuleGroupFailures(t *testing.T) {
@@ -301,6 +322,25 @@ func TestValidateRuleGroupFailures(t *testing.T) {
 				require.Contains(t, err.Error(), apiModel.Rules[0].GrafanaManagedAlert.UID)
 			},
 		},
+		{
+			name: "fail if query offset of the group is negative",
+			group: func() *apimodels.PostableRuleGroupConfig {
+				g := validGroup(cfg, validRule())
+				offset := model.Duration(-time.Minute)
+				g.QueryOffset = &offset
+				return &g
+			},
+		},
+		{
+			name: "fail if query offset of a rule is negative",
+			group: func() *apimodels.PostableRuleGroupConfig {
+				r := validRule()
+				offset := model.Duration(-time.Minute)
+				r.GrafanaManagedAlert.QueryOffset = &offset
+				g := validGroup(cfg, r)
+				return &g
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -366,10 +406,50 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 			},
 			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
 				require.Equal(t, time.Duration(0), alert.For)
+				require.Equal(t, time.Duration(0), alert.KeepFiringFor)
 				require.Nil(t, alert.Annotations)
 				require.Nil(t, alert.Labels)
 			},
 		},
+		{
+			name: "converts keep_firing_for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(5 * time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, 5*time.Minute, alert.KeepFiringFor)
+			},
+		},
+		{
+			name: "converts dependencies",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Dependencies = []apimodels.AlertRuleDependency{
+					{RuleUID: "parent", Equal: []string{"cluster"}},
+				}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, []models.AlertRuleDependency{{RuleUID: "parent", Equal: []string{"cluster"}}}, alert.Dependencies)
+			},
+		},
+		{
+			name: "converts evaluation limits",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.EvaluationLimits = &apimodels.AlertRuleEvaluationLimits{
+					MaxDuration: model.Duration(30 * time.Second),
+					MaxSeries:   100,
+				}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, models.AlertRuleEvaluationLimits{MaxDuration: 30 * time.Second, MaxSeries: 100}, alert.EvaluationLimits)
+			},
+		},
 		{
 			name: "defaults to NoData if NoDataState is empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -414,7 +494,7 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 			limits: allowRecording(limits),
 			rule: func() *apimodels.PostableExtendedRuleNode {
 				r := validRule()
-				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "some_metric", From: "A"}
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "some_metric", From: "A", Writer: "sql"}
 				r.GrafanaManagedAlert.Condition = ""
 				r.GrafanaManagedAlert.NoDataState = ""
 				r.GrafanaManagedAlert.ExecErrState = ""
@@ -447,6 +527,7 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				// Recording fields
 				require.Equal(t, api.GrafanaManagedAlert.Record.From, alert.Record.From)
 				require.Equal(t, api.GrafanaManagedAlert.Record.Metric, alert.Record.Metric)
+				require.Equal(t, api.GrafanaManagedAlert.Record.Writer, alert.Record.Writer)
 			},
 		},
 		{
@@ -460,6 +541,9 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				r.GrafanaManagedAlert.ExecErrState = apimodels.AlertingErrState
 				r.GrafanaManagedAlert.NotificationSettings = &apimodels.AlertRuleNotificationSettings{}
 				r.ApiRuleNode.For = func() *model.Duration { five := model.Duration(time.Second * 5); return &five }()
+				r.ApiRuleNode.KeepFiringFor = func() *model.Duration { five := model.Duration(time.Second * 5); return &five }()
+				r.GrafanaManagedAlert.Dependencies = []apimodels.AlertRuleDependency{{RuleUID: "parent"}}
+				r.GrafanaManagedAlert.EvaluationLimits = &apimodels.AlertRuleEvaluationLimits{MaxSeries: 10}
 				return &r
 			},
 			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
@@ -468,6 +552,9 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Empty(t, alert.ExecErrState)
 				require.Nil(t, alert.NotificationSettings)
 				require.Zero(t, alert.For)
+				require.Zero(t, alert.KeepFiringFor)
+				require.Nil(t, alert.Dependencies)
+				require.Zero(t, alert.EvaluationLimits)
 			},
 		},
 	}
@@ -599,6 +686,15 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if keep_firing_for is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
 		{
 			name: "fail if Condition is empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -690,6 +786,40 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 			},
 			expErr: "NOTEXIST does not exist",
 		},
+		{
+			name:   "rejects recording rule with unknown writer",
+			limits: allowRecording(limits),
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "my_metric", From: "A", Writer: "unknown"}
+				r.GrafanaManagedAlert.Condition = ""
+				r.GrafanaManagedAlert.NoDataState = ""
+				r.GrafanaManagedAlert.ExecErrState = ""
+				r.GrafanaManagedAlert.NotificationSettings = nil
+				r.ApiRuleNode.For = nil
+				return &r
+			},
+			expErr: "unknown writer",
+		},
+		{
+			name: "rejects recording rule with writer that is not enabled",
+			limits: func() *RuleLimits {
+				lim := allowRecording(limits)
+				lim.RecordingWriters = []string{models.RecordingWriterPrometheus}
+				return lim
+			}(),
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "my_metric", From: "A", Writer: models.RecordingWriterSQL}
+				r.GrafanaManagedAlert.Condition = ""
+				r.GrafanaManagedAlert.NoDataState = ""
+				r.GrafanaManagedAlert.ExecErrState = ""
+				r.GrafanaManagedAlert.NotificationSettings = nil
+				r.ApiRuleNode.For = nil
+				return &r
+			},
+			expErr: "is not enabled",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -10,6 +10,9 @@ import (
 
 	"github.com/benbjohnson/clock"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -39,6 +42,10 @@ type folderService interface {
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*folder.Folder, error)
 }
 
+type alertmanagerConfigProvider interface {
+	GetAlertmanagerConfiguration(ctx context.Context, org int64, withAutogen bool) (apimodels.GettableUserConfig, error)
+}
+
 type TestingApiSrv struct {
 	*AlertingProxy
 	DatasourceCache datasources.CacheService
@@ -51,6 +58,8 @@ type TestingApiSrv struct {
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 	folderService   folderService
+	ruleStore       RuleStore
+	mam             alertmanagerConfigProvider
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -238,6 +247,10 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	if forInterval < 0 {
 		return ErrResp(400, nil, "Bad For interval")
 	}
+	queryOffset := time.Duration(cmd.QueryOffset)
+	if queryOffset < 0 {
+		return ErrResp(400, nil, "Bad query offset")
+	}
 
 	intervalSeconds, err := validateInterval(time.Duration(cmd.Interval), srv.cfg.BaseInterval)
 	if err != nil {
@@ -268,6 +281,7 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		IntervalSeconds: intervalSeconds,
 		NoDataState:     noDataState,
 		For:             forInterval,
+		QueryOffset:     queryOffset,
 		Annotations:     cmd.Annotations,
 		Labels:          cmd.Labels,
 	}
@@ -286,3 +300,182 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// BacktestRuleGroup tests the current version of a rule group and, if it is provided, its proposed version.
+// The result contains the state transitions of the alert instances of every rule, and the notifications they would have
+// produced with the receivers of the current notification policy tree.
+func (srv TestingApiSrv) BacktestRuleGroup(c *contextmodel.ReqContext, cmd apimodels.BacktestGroupConfig) response.Response {
+	ctx := c.Req.Context()
+	if !srv.featureManager.IsEnabled(ctx, featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	if cmd.From.After(cmd.To) {
+		return ErrResp(http.StatusBadRequest, nil, "From cannot be greater than To")
+	}
+	if cmd.RuleGroup == "" {
+		return ErrResp(http.StatusBadRequest, nil, "Rule group cannot be empty")
+	}
+
+	orgID := c.SignedInUser.GetOrgID()
+	namespace, err := srv.folderService.GetNamespaceByUID(ctx, cmd.NamespaceUID, orgID, c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	current, err := srv.ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespace.UID},
+		RuleGroups:    []string{cmd.RuleGroup},
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "Failed to get rule group")
+	}
+	if len(current) == 0 && cmd.Proposed == nil {
+		return ErrResp(http.StatusNotFound, nil, "Rule group does not exist")
+	}
+	if len(current) > 0 {
+		if err := srv.authz.AuthorizeAccessToRuleGroup(ctx, c.SignedInUser, current); err != nil {
+			return errorToResponse(err)
+		}
+	}
+
+	var proposed ngmodels.RulesGroup
+	if cmd.Proposed != nil {
+		group := *cmd.Proposed
+		group.Name = cmd.RuleGroup
+		rules, err := ValidateRuleGroup(&group, orgID, namespace.UID, RuleLimitsFromConfig(srv.cfg, srv.featureManager))
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		proposed = make(ngmodels.RulesGroup, 0, len(rules))
+		for _, r := range rules {
+			rule := r.AlertRule
+			if rule.UID == "" {
+				// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs
+				rule.UID = "backtesting-" + util.GenerateShortUID()
+			}
+			proposed = append(proposed, &rule)
+		}
+		if err := srv.authz.AuthorizeDatasourceAccessForRuleGroup(ctx, c.SignedInUser, proposed); err != nil {
+			return errorToResponse(err)
+		}
+	}
+
+	userConfig, err := srv.mam.GetAlertmanagerConfiguration(ctx, orgID, true)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "Failed to get Alertmanager configuration")
+	}
+	var router backtesting.NotificationRouter
+	if userConfig.AlertmanagerConfig.Route != nil {
+		router = newNotificationPolicyRouter(userConfig.AlertmanagerConfig.Config)
+	}
+
+	includeFolder := !srv.cfg.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel)
+	run := func(rules ngmodels.RulesGroup) (*apimodels.BacktestGroupRun, error) {
+		alerting := make([]*ngmodels.AlertRule, 0, len(rules))
+		for _, rule := range rules {
+			// Recording rules do not produce alerts.
+			if rule.Type() == ngmodels.RuleTypeRecording {
+				continue
+			}
+			alerting = append(alerting, rule)
+		}
+		if len(alerting) == 0 {
+			return &apimodels.BacktestGroupRun{Rules: []apimodels.BacktestRuleResult{}}, nil
+		}
+		result, err := srv.backtesting.TestGroup(ctx, c.SignedInUser, alerting, namespace.Fullpath, includeFolder, router, cmd.From, cmd.To)
+		if err != nil {
+			return nil, err
+		}
+		return toBacktestGroupRun(result), nil
+	}
+
+	result := apimodels.BacktestGroupResult{}
+	if len(current) > 0 {
+		if result.Current, err = run(current); err != nil {
+			return backtestErrorResponse(err)
+		}
+	}
+	if proposed != nil {
+		if result.Proposed, err = run(proposed); err != nil {
+			return backtestErrorResponse(err)
+		}
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func backtestErrorResponse(err error) response.Response {
+	if errors.Is(err, backtesting.ErrInvalidInputData) {
+		return ErrResp(http.StatusBadRequest, err, "Failed to evaluate")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate")
+}
+
+func toBacktestGroupRun(result *backtesting.GroupResult) *apimodels.BacktestGroupRun {
+	run := &apimodels.BacktestGroupRun{Rules: make([]apimodels.BacktestRuleResult, 0, len(result.Rules))}
+	for _, r := range result.Rules {
+		ruleResult := apimodels.BacktestRuleResult{
+			UID:           r.Rule.UID,
+			Title:         r.Rule.Title,
+			Transitions:   make([]apimodels.BacktestStateTransition, 0, len(r.Transitions)),
+			Notifications: make([]apimodels.BacktestNotification, 0, len(r.Notifications)),
+		}
+		for _, t := range r.Transitions {
+			ruleResult.Transitions = append(ruleResult.Transitions, apimodels.BacktestStateTransition{
+				Time:          t.Time,
+				Labels:        t.Labels,
+				PreviousState: t.PreviousState,
+				State:         t.State,
+			})
+		}
+		for _, n := range r.Notifications {
+			status := "firing"
+			if n.Resolved {
+				status = "resolved"
+			}
+			ruleResult.Notifications = append(ruleResult.Notifications, apimodels.BacktestNotification{
+				Time:      n.Time,
+				Labels:    n.Labels,
+				Status:    status,
+				Receivers: n.Receivers,
+			})
+		}
+		run.Rules = append(run.Rules, ruleResult)
+	}
+	return run
+}
+
+// notificationPolicyRouter returns the receivers of the notification policies that match an alert
+// and are not muted by their time intervals.
+type notificationPolicyRouter struct {
+	route     *dispatch.Route
+	intervals map[string][]timeinterval.TimeInterval
+}
+
+func newNotificationPolicyRouter(cfg apimodels.Config) *notificationPolicyRouter {
+	return &notificationPolicyRouter{
+		route:     dispatch.NewRoute(cfg.Route.AsAMRoute(), nil),
+		intervals: timeIntervals(cfg),
+	}
+}
+
+func (r *notificationPolicyRouter) Receivers(labels data.Labels, at time.Time) ([]string, error) {
+	lset := make(model.LabelSet, len(labels))
+	for k, v := range labels {
+		lset[model.LabelName(k)] = model.LabelValue(v)
+	}
+	routes := r.route.Match(lset)
+	receivers := make([]string, 0, len(routes))
+	for _, route := range routes {
+		mutedBy, err := routeMutedBy(route.RouteOpts, r.intervals, at)
+		if err != nil {
+			return nil, err
+		}
+		if len(mutedBy) > 0 {
+			continue
+		}
+		receivers = append(receivers, route.RouteOpts.Receiver)
+	}
+	return receivers, nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
@@ -406,3 +407,80 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		folderService:   ruleStore,
 	}
 }
+
+func TestBacktestRuleGroup(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	from := time.Now()
+	to := from.Add(time.Hour)
+
+	t.Run("should return 404 if backtesting is disabled", func(t *testing.T) {
+		srv := createTestingApiSrv(t, nil, nil, nil, featuremgmt.WithFeatures(), fakes2.NewRuleStore(t))
+
+		response := srv.BacktestRuleGroup(rc, definitions.BacktestGroupConfig{From: from, To: to, RuleGroup: "test"})
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 404 if rule group does not exist", func(t *testing.T) {
+		f := randFolder()
+		ruleStore := fakes2.NewRuleStore(t)
+		ruleStore.Folders[rc.OrgID] = []*folder.Folder{f}
+		srv := createTestingApiSrv(t, nil, nil, nil, featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting), ruleStore)
+
+		response := srv.BacktestRuleGroup(rc, definitions.BacktestGroupConfig{From: from, To: to, NamespaceUID: f.UID, RuleGroup: "test"})
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return Forbidden if user cannot query a data source of the group", func(t *testing.T) {
+		f := randFolder()
+		ruleStore := fakes2.NewRuleStore(t)
+		ruleStore.Folders[rc.OrgID] = []*folder.Folder{f}
+		gen := models.RuleGen
+		ruleStore.PutRule(context.Background(), gen.With(gen.WithOrgID(rc.OrgID), gen.WithNamespaceUID(f.UID), gen.WithGroupName("test")).GenerateRef())
+
+		ac := acMock.New().WithPermissions([]ac.Permission{
+			{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+		})
+		srv := createTestingApiSrv(t, nil, ac, nil, featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting), ruleStore)
+
+		response := srv.BacktestRuleGroup(rc, definitions.BacktestGroupConfig{From: from, To: to, NamespaceUID: f.UID, RuleGroup: "test"})
+
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+}
+
+func TestNotificationPolicyRouter(t *testing.T) {
+	router := newNotificationPolicyRouter(createRoutingConfig(t).Config)
+
+	saturday := time.Date(2024, time.October, 19, 12, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2024, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("returns the receivers of all matching policies", func(t *testing.T) {
+		receivers, err := router.Receivers(data.Labels{"team": "a"}, wednesday)
+		require.NoError(t, err)
+		require.Equal(t, []string{"team-a", "team-a-oncall"}, receivers)
+
+		receivers, err = router.Receivers(data.Labels{"team": "c"}, wednesday)
+		require.NoError(t, err)
+		require.Equal(t, []string{"default"}, receivers)
+	})
+
+	t.Run("does not return the receivers of policies muted by their time intervals", func(t *testing.T) {
+		// team-a is muted on weekends, and team-a-oncall is only active on weekdays
+		receivers, err := router.Receivers(data.Labels{"team": "a"}, saturday)
+		require.NoError(t, err)
+		require.Empty(t, receivers)
+
+		receivers, err = router.Receivers(data.Labels{"team": "b"}, saturday)
+		require.NoError(t, err)
+		require.Equal(t, []string{"team-b"}, receivers)
+	})
+}
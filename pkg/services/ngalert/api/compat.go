@@ -29,11 +29,15 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		NoDataState:          models.NoDataState(a.NoDataState),          // TODO there must be a validation
 		ExecErrState:         models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
 		For:                  time.Duration(a.For),
+		KeepFiringFor:        time.Duration(a.KeepFiringFor),
+		QueryOffset:          time.Duration(a.QueryOffset),
 		Annotations:          a.Annotations,
 		Labels:               a.Labels,
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
+		Dependencies:         ModelDependenciesFromApiDependencies(a.Dependencies),
+		EvaluationLimits:     ModelEvaluationLimitsFromApiEvaluationLimits(a.EvaluationLimits),
 	}
 
 	if rule.Type() == models.RuleTypeRecording {
@@ -53,6 +57,8 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		RuleGroup:            rule.RuleGroup,
 		Title:                rule.Title,
 		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
+		QueryOffset:          model.Duration(rule.QueryOffset),
 		Condition:            rule.Condition,
 		Data:                 ApiAlertQueriesFromAlertQueries(rule.Data),
 		Updated:              rule.Updated,
@@ -64,6 +70,8 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+		Dependencies:         ApiDependenciesFromModelDependencies(rule.Dependencies),
+		EvaluationLimits:     ApiEvaluationLimitsFromModelEvaluationLimits(rule.EvaluationLimits),
 	}
 }
 
@@ -206,6 +214,8 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		UID:                  rule.UID,
 		Title:                rule.Title,
 		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
+		QueryOffset:          model.Duration(rule.QueryOffset),
 		Condition:            cPtr,
 		Data:                 data,
 		DashboardUID:         rule.DashboardUID,
@@ -215,10 +225,18 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
 		Record:               AlertRuleRecordExportFromRecord(rule.Record),
+		Dependencies:         AlertRuleDependencyExportsFromDependencies(rule.Dependencies),
+		EvaluationLimits:     AlertRuleEvaluationLimitsExportFromEvaluationLimits(rule.EvaluationLimits),
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
 	}
+	if rule.KeepFiringFor.Seconds() > 0 {
+		result.KeepFiringForString = util.Pointer(model.Duration(rule.KeepFiringFor).String())
+	}
+	if rule.QueryOffset.Seconds() > 0 {
+		result.QueryOffsetString = util.Pointer(model.Duration(rule.QueryOffset).String())
+	}
 	if rule.Annotations != nil {
 		result.Annotations = &rule.Annotations
 	}
@@ -500,6 +518,7 @@ func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRec
 	return &definitions.AlertRuleRecordExport{
 		Metric: r.Metric,
 		From:   r.From,
+		Writer: r.Writer,
 	}
 }
 
@@ -510,6 +529,7 @@ func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
 	return &models.Record{
 		Metric: r.Metric,
 		From:   r.From,
+		Writer: r.Writer,
 	}
 }
 
@@ -520,6 +540,88 @@ func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
 	return &definitions.Record{
 		Metric: r.Metric,
 		From:   r.From,
+		Writer: r.Writer,
+	}
+}
+
+func AlertRuleDependencyExportsFromDependencies(deps []models.AlertRuleDependency) []definitions.AlertRuleDependencyExport {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleDependencyExport, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, definitions.AlertRuleDependencyExport{
+			RuleUID: d.RuleUID,
+			Equal:   d.Equal,
+		})
+	}
+	return result
+}
+
+func ModelDependenciesFromApiDependencies(deps []definitions.AlertRuleDependency) []models.AlertRuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]models.AlertRuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, models.AlertRuleDependency{
+			RuleUID: d.RuleUID,
+			Equal:   d.Equal,
+		})
+	}
+	return result
+}
+
+func ApiDependenciesFromModelDependencies(deps []models.AlertRuleDependency) []definitions.AlertRuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, definitions.AlertRuleDependency{
+			RuleUID: d.RuleUID,
+			Equal:   d.Equal,
+		})
+	}
+	return result
+}
+
+func AlertRuleEvaluationLimitsExportFromEvaluationLimits(limits models.AlertRuleEvaluationLimits) *definitions.AlertRuleEvaluationLimitsExport {
+	if limits.IsZero() {
+		return nil
+	}
+	result := &definitions.AlertRuleEvaluationLimitsExport{}
+	if limits.MaxDuration > 0 {
+		result.MaxDuration = util.Pointer(model.Duration(limits.MaxDuration).String())
+	}
+	if limits.MaxSeries > 0 {
+		result.MaxSeries = util.Pointer(limits.MaxSeries)
+	}
+	if limits.MaxQueries > 0 {
+		result.MaxQueries = util.Pointer(limits.MaxQueries)
+	}
+	return result
+}
+
+func ModelEvaluationLimitsFromApiEvaluationLimits(limits *definitions.AlertRuleEvaluationLimits) models.AlertRuleEvaluationLimits {
+	if limits == nil {
+		return models.AlertRuleEvaluationLimits{}
+	}
+	return models.AlertRuleEvaluationLimits{
+		MaxDuration: time.Duration(limits.MaxDuration),
+		MaxSeries:   limits.MaxSeries,
+		MaxQueries:  limits.MaxQueries,
+	}
+}
+
+func ApiEvaluationLimitsFromModelEvaluationLimits(limits models.AlertRuleEvaluationLimits) *definitions.AlertRuleEvaluationLimits {
+	if limits.IsZero() {
+		return nil
+	}
+	return &definitions.AlertRuleEvaluationLimits{
+		MaxDuration: model.Duration(limits.MaxDuration),
+		MaxSeries:   limits.MaxSeries,
+		MaxQueries:  limits.MaxQueries,
 	}
 }
 
@@ -51,7 +51,8 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 		len(cp.Pagerduty) + len(cp.OnCall) + len(cp.Pushover) + len(cp.Sensugo) +
 		len(cp.Sns) + len(cp.Slack) + len(cp.Teams) + len(cp.Telegram) +
 		len(cp.Threema) + len(cp.Victorops) + len(cp.Webhook) + len(cp.Wecom) +
-		len(cp.Webex) + len(cp.Mqtt)
+		len(cp.Webex) + len(cp.Mqtt) + len(cp.Jira) + len(cp.Matrix) +
+		len(cp.Rocketchat) + len(cp.Zulip)
 
 	integration := make([]*notify.GrafanaIntegrationConfig, 0, contactPointsLength)
 
@@ -91,6 +92,13 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 		}
 		integration = append(integration, el)
 	}
+	for _, i := range cp.Jira {
+		el, err := marshallIntegration(j, "jira", i, i.DisableResolveMessage)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		integration = append(integration, el)
+	}
 	for _, i := range cp.Kafka {
 		el, err := marshallIntegration(j, "kafka", i, i.DisableResolveMessage)
 		if err != nil {
@@ -105,6 +113,13 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 		}
 		integration = append(integration, el)
 	}
+	for _, i := range cp.Matrix {
+		el, err := marshallIntegration(j, "matrix", i, i.DisableResolveMessage)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		integration = append(integration, el)
+	}
 	for _, i := range cp.Mqtt {
 		el, err := marshallIntegration(j, "mqtt", i, i.DisableResolveMessage)
 		if err != nil {
@@ -140,6 +155,13 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 		}
 		integration = append(integration, el)
 	}
+	for _, i := range cp.Rocketchat {
+		el, err := marshallIntegration(j, "rocketchat", i, i.DisableResolveMessage)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		integration = append(integration, el)
+	}
 	for _, i := range cp.Sensugo {
 		el, err := marshallIntegration(j, "sensugo", i, i.DisableResolveMessage)
 		if err != nil {
@@ -210,6 +232,13 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 		}
 		integration = append(integration, el)
 	}
+	for _, i := range cp.Zulip {
+		el, err := marshallIntegration(j, "zulip", i, i.DisableResolveMessage)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		integration = append(integration, el)
+	}
 
 	if len(errs) > 0 {
 		return notify.APIReceiver{}, errors.Join(errs...)
@@ -271,6 +300,11 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Googlechat = append(result.Googlechat, integration)
 		}
+	case "jira":
+		integration := definitions.JiraIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.Jira = append(result.Jira, integration)
+		}
 	case "kafka":
 		integration := definitions.KafkaIntegration{DisableResolveMessage: disable}
 		if err = json.Unmarshal(data, &integration); err == nil {
@@ -281,6 +315,11 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Line = append(result.Line, integration)
 		}
+	case "matrix":
+		integration := definitions.MatrixIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.Matrix = append(result.Matrix, integration)
+		}
 	case "mqtt":
 		integration := definitions.MqttIntegration{DisableResolveMessage: disable}
 		if err = json.Unmarshal(data, &integration); err == nil {
@@ -306,6 +345,11 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Pushover = append(result.Pushover, integration)
 		}
+	case "rocketchat":
+		integration := definitions.RocketchatIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.Rocketchat = append(result.Rocketchat, integration)
+		}
 	case "sensugo":
 		integration := definitions.SensugoIntegration{DisableResolveMessage: disable}
 		if err = json.Unmarshal(data, &integration); err == nil {
@@ -356,6 +400,11 @@ func parseIntegration(json jsoniter.API, result *definitions.ContactPoint, recei
 		if err = json.Unmarshal(data, &integration); err == nil {
 			result.Webex = append(result.Webex, integration)
 		}
+	case "zulip":
+		integration := definitions.ZulipIntegration{DisableResolveMessage: disable}
+		if err = json.Unmarshal(data, &integration); err == nil {
+			result.Zulip = append(result.Zulip, integration)
+		}
 	default:
 		err = fmt.Errorf("integration %s is not supported", receiverType)
 	}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -13,6 +14,10 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/jira"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/matrix"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/rocketchat"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/zulip"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 )
 
@@ -89,6 +94,53 @@ func TestContactPointFromContactPointExports(t *testing.T) {
 		})
 	}
 
+	// integrations implemented in Grafana are not known to the alerting module
+	grafanaIntegrations := map[string]struct {
+		settings string
+		secrets  string
+	}{
+		"jira":       {settings: jira.FullValidConfigForTesting, secrets: jira.FullValidSecretsForTesting},
+		"matrix":     {settings: matrix.FullValidConfigForTesting, secrets: matrix.FullValidSecretsForTesting},
+		"rocketchat": {settings: rocketchat.FullValidConfigForTesting, secrets: rocketchat.FullValidSecretsForTesting},
+		"zulip":      {settings: zulip.FullValidConfigForTesting, secrets: zulip.FullValidSecretsForTesting},
+	}
+	for integrationType, cfg := range grafanaIntegrations {
+		t.Run(integrationType, func(t *testing.T) {
+			expected := map[string]any{}
+			require.NoError(t, json.Unmarshal([]byte(cfg.settings), &expected))
+			secureSettings := map[string]string{}
+			for k, v := range receiversTesting.ReadSecretsJSONForTesting(cfg.secrets) {
+				secureSettings[k] = base64.StdEncoding.EncodeToString(v)
+				expected[k] = string(v)
+			}
+			recCfg := &notify.APIReceiver{
+				ConfigReceiver: notify.ConfigReceiver{Name: "test-receiver"},
+				GrafanaIntegrations: notify.GrafanaIntegrations{
+					Integrations: []*notify.GrafanaIntegrationConfig{
+						{
+							Name:           "test",
+							Type:           integrationType,
+							Settings:       json.RawMessage(cfg.settings),
+							SecureSettings: secureSettings,
+						},
+					},
+				},
+			}
+
+			result, err := ContactPointFromContactPointExport(getContactPointExport(t, recCfg))
+			require.NoError(t, err)
+
+			back, err := ContactPointToContactPointExport(result)
+			require.NoError(t, err)
+			require.Len(t, back.Integrations, 1)
+			require.Equal(t, integrationType, back.Integrations[0].Type)
+
+			expectedJSON, err := json.Marshal(expected)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expectedJSON), string(back.Integrations[0].Settings))
+		})
+	}
+
 	t.Run("pushover optional numbers as string", func(t *testing.T) {
 		export := definitions.ContactPointExport{
 			Name: "test",
@@ -190,6 +190,18 @@ func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contex
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationDeliveries(ctx *contextmodel.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationDeliveries(ctx)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaNotificationDeliveryReplay(ctx *contextmodel.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RoutePostNotificationDeliveryReplay(ctx, id)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaRoutes(ctx *contextmodel.ReqContext, conf apimodels.TestRoutesConfigBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostTestRoutes(ctx, conf)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *contextmodel.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
 }
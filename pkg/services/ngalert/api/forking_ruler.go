@@ -1,6 +1,9 @@
 package api
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -117,6 +120,21 @@ func (f *RulerApiHandler) handleRouteGetRulesForExport(ctx *contextmodel.ReqCont
 	return f.GrafanaRuler.ExportRules(ctx)
 }
 
+func (f *RulerApiHandler) handleRoutePostPrometheusRulesImport(ctx *contextmodel.ReqContext, namespace string) response.Response {
+	datasourceUID := ctx.Query("datasourceUid")
+	if datasourceUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("data source UID must be specified"), "")
+	}
+	ds, err := f.DatasourceCache.GetDatasourceByUID(ctx.Req.Context(), datasourceUID, ctx.SignedInUser, ctx.SkipDSCache)
+	if err != nil {
+		return errorToResponse(err)
+	}
+	if ds.Type != datasources.DS_PROMETHEUS && ds.Type != datasources.DS_LOKI {
+		return errorToResponse(unexpectedDatasourceTypeError(ds.Type, "loki, prometheus"))
+	}
+	return f.GrafanaRuler.ImportPrometheusRules(ctx, namespace, ds)
+}
+
 func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
@@ -165,6 +165,34 @@ import (
 //       408: Failure
 //       409: AlertManagerNotReady
 
+// swagger:route GET /alertmanager/grafana/config/api/v1/receivers/deliveries alertmanager RouteGetGrafanaNotificationDeliveries
+//
+// Get the most recent notification attempts of the contact points, most recent first.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: NotificationDeliveries
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: AlertManagerNotReady
+
+// swagger:route POST /alertmanager/grafana/config/api/v1/receivers/deliveries/{DeliveryID}/replay alertmanager RoutePostGrafanaNotificationDeliveryReplay
+//
+// Send the alerts of a failed notification attempt again with the integration that sent them. The new attempt is returned whether it succeeded or not.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: NotificationDelivery
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: AlertManagerNotReady
+
 // swagger:route POST /alertmanager/grafana/config/api/v1/templates/test alertmanager RoutePostTestGrafanaTemplates
 //
 // Test Grafana managed templates without saving them.
@@ -178,6 +206,19 @@ import (
 //       403: PermissionDenied
 //       409: AlertManagerNotReady
 
+// swagger:route POST /alertmanager/grafana/config/api/v1/routes/test alertmanager RoutePostTestGrafanaRoutes
+//
+// Test which notification policies of the current or a proposed configuration match a set of labels.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: TestRoutesResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
 // swagger:route GET /alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -366,6 +407,139 @@ type TestTemplatesErrorResult struct {
 	Message string `json:"message"`
 }
 
+// swagger:parameters RoutePostTestGrafanaRoutes
+type TestRoutesConfigParams struct {
+	// in:body
+	Body TestRoutesConfigBodyParams
+}
+
+type TestRoutesConfigBodyParams struct {
+	// Labels of the alert to route.
+	Labels model.LabelSet `json:"labels"`
+
+	// Time at which the mute and active time intervals are evaluated. Defaults to the current time.
+	Time *time.Time `json:"time,omitempty"`
+
+	// Configuration to route the alert with. Defaults to the current configuration.
+	AlertmanagerConfig *PostableApiAlertingConfig `json:"alertmanager_config,omitempty"`
+}
+
+// swagger:model
+type TestRoutesResult struct {
+	// Time at which the mute and active time intervals were evaluated.
+	Time time.Time `json:"time"`
+
+	// Notification policies that match the labels, in the order in which they are matched.
+	Routes []TestRoutesMatch `json:"routes"`
+}
+
+// TestRoutesMatch is a notification policy that matches the labels of the alert.
+type TestRoutesMatch struct {
+	// Path from the root of the notification policy tree to the matching policy. The last element is the matching policy.
+	Path []TestRoutesPathElement `json:"path"`
+
+	Receiver            string         `json:"receiver"`
+	GroupBy             []string       `json:"group_by"`
+	GroupWait           model.Duration `json:"group_wait"`
+	GroupInterval       model.Duration `json:"group_interval"`
+	RepeatInterval      model.Duration `json:"repeat_interval"`
+	MuteTimeIntervals   []string       `json:"mute_time_intervals,omitempty"`
+	ActiveTimeIntervals []string       `json:"active_time_intervals,omitempty"`
+
+	// Time intervals that mute the notifications of the policy at the time of the test: the mute time intervals
+	// that contain the time and the active time intervals that do not.
+	MutedBy []string `json:"muted_by,omitempty"`
+
+	// Muted is true if the notifications of the policy are muted at the time of the test.
+	Muted bool `json:"muted"`
+}
+
+// TestRoutesPathElement is a notification policy on the path to a matching policy.
+type TestRoutesPathElement struct {
+	// Position of the policy among the child policies of its parent. It is 0 for the root policy.
+	Index    int      `json:"index"`
+	Receiver string   `json:"receiver,omitempty"`
+	Matchers []string `json:"matchers,omitempty"`
+	Continue bool     `json:"continue,omitempty"`
+}
+
+// swagger:parameters RouteGetGrafanaNotificationDeliveries
+type GetNotificationDeliveriesParams struct {
+	// Name of the contact point of the attempts.
+	// in:query
+	// required:false
+	Receiver string `json:"receiver"`
+	// Type of the integration of the attempts.
+	// in:query
+	// required:false
+	Integration string `json:"integration"`
+	// Outcome of the attempts.
+	// in:query
+	// required:false
+	Status NotificationDeliveryStatus `json:"status"`
+	// Only return the attempts made at or after this time.
+	// in:query
+	// required:false
+	From time.Time `json:"from"`
+	// Only return the attempts made at or before this time.
+	// in:query
+	// required:false
+	To time.Time `json:"to"`
+	// Maximum number of attempts to return. Defaults to 100.
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters RoutePostGrafanaNotificationDeliveryReplay
+type NotificationDeliveryReplayParams struct {
+	// ID of the notification attempt to replay.
+	// in:path
+	DeliveryID int64
+}
+
+// swagger:enum NotificationDeliveryStatus
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailure NotificationDeliveryStatus = "failure"
+)
+
+// swagger:model
+type NotificationDeliveries []NotificationDelivery
+
+// NotificationDelivery is an attempt of an integration of a contact point to send a notification for a group of alerts.
+// swagger:model
+type NotificationDelivery struct {
+	ID               int64          `json:"id"`
+	Receiver         string         `json:"receiver"`
+	Integration      string         `json:"integration"`
+	IntegrationIndex int            `json:"integrationIndex"`
+	GroupKey         string         `json:"groupKey"`
+	GroupLabels      model.LabelSet `json:"groupLabels"`
+
+	// Number of alerts in the notification.
+	AlertCount int `json:"alertCount"`
+
+	Status NotificationDeliveryStatus `json:"status"`
+
+	// Status code of the last HTTP response received by the integration. It is omitted if there was none.
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	// Time taken by the attempt.
+	Latency string `json:"latency"`
+
+	// SHA-256 of the payloads sent by the integration. It is omitted if the integration does not send webhooks or emails through Grafana.
+	PayloadHash string `json:"payloadHash,omitempty"`
+
+	// ID of the attempt this attempt is a replay of. It is omitted if the attempt is not a replay.
+	ReplayOf int64 `json:"replayOf,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // swagger:enum TemplateErrorKind
 type TemplateErrorKind string
 
@@ -62,6 +62,23 @@ type GooglechatIntegration struct {
 	Message *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
 }
 
+type JiraIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL       string `json:"api_url" yaml:"api_url" hcl:"api_url"`
+	Project   string `json:"project" yaml:"project" hcl:"project"`
+	IssueType string `json:"issue_type" yaml:"issue_type" hcl:"issue_type"`
+
+	User                     *string   `json:"user,omitempty" yaml:"user,omitempty" hcl:"user"`
+	Password                 *Secret   `json:"password,omitempty" yaml:"password,omitempty" hcl:"password"`
+	AuthorizationCredentials *Secret   `json:"authorization_credentials,omitempty" yaml:"authorization_credentials,omitempty" hcl:"authorization_credentials"`
+	Summary                  *string   `json:"summary,omitempty" yaml:"summary,omitempty" hcl:"summary"`
+	Description              *string   `json:"description,omitempty" yaml:"description,omitempty" hcl:"description"`
+	Labels                   *[]string `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
+	Priority                 *string   `json:"priority,omitempty" yaml:"priority,omitempty" hcl:"priority"`
+	ResolveTransition        *string   `json:"resolve_transition,omitempty" yaml:"resolve_transition,omitempty" hcl:"resolve_transition"`
+}
+
 type KafkaIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -85,6 +102,18 @@ type LineIntegration struct {
 	Description *string `json:"description,omitempty" yaml:"description,omitempty" hcl:"description"`
 }
 
+type MatrixIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	HomeserverURL string `json:"homeserver_url" yaml:"homeserver_url" hcl:"homeserver_url"`
+	RoomID        string `json:"room_id" yaml:"room_id" hcl:"room_id"`
+	AccessToken   Secret `json:"access_token" yaml:"access_token" hcl:"access_token"`
+
+	MessageType *string `json:"msgtype,omitempty" yaml:"msgtype,omitempty" hcl:"message_type"`
+	Title       *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message     *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+}
+
 type TLSConfig struct {
 	InsecureSkipVerify   *bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" hcl:"insecure_skip_verify"`
 	TLSCACertificate     *Secret `json:"caCertificate,omitempty" yaml:"caCertificate,omitempty" hcl:"ca_certificate"`
@@ -178,6 +207,18 @@ type PushoverIntegration struct {
 	UploadImage      *bool   `json:"uploadImage,omitempty" yaml:"uploadImage,omitempty" hcl:"upload_image"`
 }
 
+type RocketchatIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL Secret `json:"url" yaml:"url" hcl:"url"`
+
+	Channel  *string `json:"channel,omitempty" yaml:"channel,omitempty" hcl:"channel"`
+	Username *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"username"`
+	IconURL  *string `json:"icon_url,omitempty" yaml:"icon_url,omitempty" hcl:"icon_url"`
+	Title    *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message  *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+}
+
 type SensugoIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -313,6 +354,18 @@ type WecomIntegration struct {
 	ToUser  *string `json:"touser,omitempty" yaml:"touser,omitempty" hcl:"to_user"`
 }
 
+type ZulipIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL      string `json:"url" yaml:"url" hcl:"url"`
+	BotEmail string `json:"bot_email" yaml:"bot_email" hcl:"bot_email"`
+	APIKey   Secret `json:"api_key" yaml:"api_key" hcl:"api_key"`
+	Stream   string `json:"stream" yaml:"stream" hcl:"stream"`
+
+	Topic   *string `json:"topic,omitempty" yaml:"topic,omitempty" hcl:"topic"`
+	Message *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+}
+
 type ContactPoint struct {
 	Name         string                    `json:"name" yaml:"name" hcl:"name"`
 	Alertmanager []AlertmanagerIntegration `json:"alertmanager" yaml:"alertmanager" hcl:"alertmanager,block"`
@@ -320,13 +373,16 @@ type ContactPoint struct {
 	Discord      []DiscordIntegration      `json:"discord" yaml:"discord" hcl:"discord,block"`
 	Email        []EmailIntegration        `json:"email" yaml:"email" hcl:"email,block"`
 	Googlechat   []GooglechatIntegration   `json:"googlechat" yaml:"googlechat" hcl:"googlechat,block"`
+	Jira         []JiraIntegration         `json:"jira" yaml:"jira" hcl:"jira,block"`
 	Kafka        []KafkaIntegration        `json:"kafka" yaml:"kafka" hcl:"kafka,block"`
 	Line         []LineIntegration         `json:"line" yaml:"line" hcl:"line,block"`
+	Matrix       []MatrixIntegration       `json:"matrix" yaml:"matrix" hcl:"matrix,block"`
 	Mqtt         []MqttIntegration         `json:"mqtt" yaml:"mqtt" hcl:"mqtt,block"`
 	Opsgenie     []OpsgenieIntegration     `json:"opsgenie" yaml:"opsgenie" hcl:"opsgenie,block"`
 	Pagerduty    []PagerdutyIntegration    `json:"pagerduty" yaml:"pagerduty" hcl:"pagerduty,block"`
 	OnCall       []OnCallIntegration       `json:"oncall" yaml:"oncall" hcl:"oncall,block"`
 	Pushover     []PushoverIntegration     `json:"pushover" yaml:"pushover" hcl:"pushover,block"`
+	Rocketchat   []RocketchatIntegration   `json:"rocketchat" yaml:"rocketchat" hcl:"rocketchat,block"`
 	Sensugo      []SensugoIntegration      `json:"sensugo" yaml:"sensugo" hcl:"sensugo,block"`
 	Slack        []SlackIntegration        `json:"slack" yaml:"slack" hcl:"slack,block"`
 	Sns          []SnsIntegration          `json:"sns" yaml:"sns" hcl:"sns,block"`
@@ -337,4 +393,5 @@ type ContactPoint struct {
 	Webhook      []WebhookIntegration      `json:"webhook" yaml:"webhook" hcl:"webhook,block"`
 	Wecom        []WecomIntegration        `json:"wecom" yaml:"wecom" hcl:"wecom,block"`
 	Webex        []WebexIntegration        `json:"webex" yaml:"webex" hcl:"webex,block"`
+	Zulip        []ZulipIntegration        `json:"zulip" yaml:"zulip" hcl:"zulip,block"`
 }
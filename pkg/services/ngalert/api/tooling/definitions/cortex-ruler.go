@@ -92,6 +92,26 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route POST /ruler/grafana/api/v1/rules/{Namespace}/import/prometheus ruler RoutePostPrometheusRulesImport
+//
+// Converts the groups of a Prometheus rule file to Grafana-managed rules and saves them in the folder.
+// Existing groups with the same names are replaced. The request body is the rule file in YAML or JSON format.
+// In dry-run mode, the rules are only converted and validated.
+//
+//     Consumes:
+//     - application/json
+//     - application/yaml
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: PrometheusRulesImportResponse
+//       202: PrometheusRulesImportResponse
+//       400: PrometheusRulesImportResponse
+//       403: ForbiddenError
+//       404: description: Not found.
+
 // swagger:route POST /ruler/{DatasourceUID}/api/v1/rules/{Namespace} ruler RoutePostNameRulesConfig
 //
 // Creates or updates a rule group
@@ -194,6 +214,22 @@ type NamespaceConfig struct {
 	Body PostableRuleGroupConfig
 }
 
+// swagger:parameters RoutePostPrometheusRulesImport
+type PrometheusRulesImportParams struct {
+	// The UID of the rule folder
+	// in:path
+	Namespace string
+	// The UID of the Prometheus or Loki data source that evaluates the expressions of the rules
+	// in:query
+	// required:true
+	DatasourceUID string `json:"datasourceUid"`
+	// Convert and validate the rules without saving them
+	// in:query
+	// required:false
+	// default:false
+	DryRun bool `json:"dryRun"`
+}
+
 // swagger:parameters RouteGetNamespaceRulesConfig RouteDeleteNamespaceRulesConfig RouteGetNamespaceGrafanaRulesConfig RouteDeleteNamespaceGrafanaRulesConfig
 type PathNamespaceConfig struct {
 	// The UID of the rule folder
@@ -284,8 +320,8 @@ func (c *PostableRuleGroupConfig) validate() error {
 		return fmt.Errorf("cannot mix Grafana & Prometheus style rules")
 	}
 
-	if hasGrafRules && (len(c.SourceTenants) > 0 || c.EvaluationDelay != nil || c.QueryOffset != nil || c.AlignEvaluationTimeOnInterval || c.Limit > 0) {
-		return fmt.Errorf("fields source_tenants, evaluation_delay, query_offset, align_evaluation_time_on_interval and limit are not supported for Grafana rules")
+	if hasGrafRules && (len(c.SourceTenants) > 0 || c.EvaluationDelay != nil || c.AlignEvaluationTimeOnInterval || c.Limit > 0) {
+		return fmt.Errorf("fields source_tenants, evaluation_delay, align_evaluation_time_on_interval and limit are not supported for Grafana rules")
 	}
 	return nil
 }
@@ -516,6 +552,35 @@ type Record struct {
 	// required: true
 	// example: A
 	From string `json:"from" yaml:"from"`
+	// Type of the writer that receives the recorded metric. If empty, the default writer is used.
+	// enum: prometheus,sql,otlp,influxdb
+	// example: sql
+	Writer string `json:"writer,omitempty" yaml:"writer,omitempty"`
+}
+
+// swagger:model
+type AlertRuleDependency struct {
+	// UID of the alert rule in the same organization that inhibits the dependent rule while it is firing.
+	// required: true
+	// example: cluster-down
+	RuleUID string `json:"rule_uid" yaml:"rule_uid"`
+	// Labels that must have the same values in the firing instance of the rule and in the instance of the dependent rule.
+	// If empty, any firing instance of the rule inhibits all instances of the dependent rule.
+	// example: ["cluster"]
+	Equal []string `json:"equal,omitempty" yaml:"equal,omitempty"`
+}
+
+// swagger:model
+type AlertRuleEvaluationLimits struct {
+	// Maximum duration of the evaluation. Used only if it is lower than the limit of the organization.
+	// example: 30s
+	MaxDuration model.Duration `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+	// Maximum number of series returned by a query or by the evaluation. Used only if it is lower than the limit of the organization.
+	// example: 1000
+	MaxSeries int `json:"max_series,omitempty" yaml:"max_series,omitempty"`
+	// Maximum number of data source queries of the rule. Used only if it is lower than the limit of the organization.
+	// example: 3
+	MaxQueries int `json:"max_queries,omitempty" yaml:"max_queries,omitempty"`
 }
 
 // swagger:model
@@ -530,6 +595,9 @@ type PostableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Dependencies         []AlertRuleDependency          `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	QueryOffset          *model.Duration                `json:"query_offset,omitempty" yaml:"query_offset,omitempty"`
+	EvaluationLimits     *AlertRuleEvaluationLimits     `json:"evaluation_limits,omitempty" yaml:"evaluation_limits,omitempty"`
 }
 
 // swagger:model
@@ -552,6 +620,9 @@ type GettableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Dependencies         []AlertRuleDependency          `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	QueryOffset          *model.Duration                `json:"query_offset,omitempty" yaml:"query_offset,omitempty"`
+	EvaluationLimits     *AlertRuleEvaluationLimits     `json:"evaluation_limits,omitempty" yaml:"evaluation_limits,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -628,3 +699,26 @@ type UpdateRuleGroupResponse struct {
 	Updated []string `json:"updated,omitempty"`
 	Deleted []string `json:"deleted,omitempty"`
 }
+
+// swagger:model
+type PrometheusRulesImportResponse struct {
+	Message string                            `json:"message"`
+	DryRun  bool                              `json:"dryRun"`
+	Groups  []PrometheusRuleGroupImportResult `json:"groups"`
+}
+
+type PrometheusRuleGroupImportResult struct {
+	Name  string                       `json:"name"`
+	Error string                       `json:"error,omitempty"`
+	Rules []PrometheusRuleImportResult `json:"rules,omitempty"`
+}
+
+type PrometheusRuleImportResult struct {
+	// Name is the name of the alert or of the recorded metric.
+	Name string `json:"name"`
+	// UID of the Grafana rule. It is empty if the rule is not saved yet.
+	UID   string `json:"uid,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Rule is the converted Grafana rule.
+	Rule *AlertRuleExport `json:"rule,omitempty"`
+}
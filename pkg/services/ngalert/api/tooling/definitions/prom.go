@@ -176,6 +176,8 @@ type Rule struct {
 	Type           string    `json:"type"`
 	LastEvaluation time.Time `json:"lastEvaluation"`
 	EvaluationTime float64   `json:"evaluationTime"`
+	// QueryOffset is the offset of the queries of the rule in seconds.
+	QueryOffset float64 `json:"queryOffset,omitempty"`
 }
 
 // Alert has info for an alert.
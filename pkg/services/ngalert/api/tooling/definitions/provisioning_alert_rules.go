@@ -158,6 +158,10 @@ type ProvisionedAlertRule struct {
 	// required: true
 	// swagger:strfmt duration
 	For model.Duration `json:"for"`
+	// swagger:strfmt duration
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty"`
+	// swagger:strfmt duration
+	QueryOffset model.Duration `json:"queryOffset,omitempty"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -170,6 +174,10 @@ type ProvisionedAlertRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
 	//example: {"metric":"grafana_alerts_ratio", "from":"A"}
 	Record *Record `json:"record"`
+	// example: [{"rule_uid":"cluster-down","equal":["cluster"]}]
+	Dependencies []AlertRuleDependency `json:"dependencies,omitempty"`
+	// example: {"max_duration":"30s","max_series":1000}
+	EvaluationLimits *AlertRuleEvaluationLimits `json:"evaluation_limits,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -271,12 +279,20 @@ type AlertRuleExport struct {
 	// ForString is used to:
 	// - Only export the for field for HCL if it is non-zero.
 	// - Format the Prometheus model.Duration type properly for HCL.
-	ForString            *string                              `json:"-" yaml:"-" hcl:"for"`
+	ForString     *string        `json:"-" yaml:"-" hcl:"for"`
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	// KeepFiringForString is used the same way as ForString.
+	KeepFiringForString *string        `json:"-" yaml:"-" hcl:"keep_firing_for"`
+	QueryOffset         model.Duration `json:"queryOffset,omitempty" yaml:"queryOffset,omitempty"`
+	// QueryOffsetString is used the same way as ForString.
+	QueryOffsetString    *string                              `json:"-" yaml:"-" hcl:"query_offset"`
 	Annotations          *map[string]string                   `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
 	Labels               *map[string]string                   `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
 	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record               *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	Dependencies         []AlertRuleDependencyExport          `json:"dependencies,omitempty" yaml:"dependencies,omitempty" hcl:"dependency,block"`
+	EvaluationLimits     *AlertRuleEvaluationLimitsExport     `json:"evaluation_limits,omitempty" yaml:"evaluation_limits,omitempty" hcl:"evaluation_limits,block"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -310,4 +326,18 @@ type AlertRuleNotificationSettingsExport struct {
 type AlertRuleRecordExport struct {
 	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
 	From   string `json:"from" yaml:"from" hcl:"from"`
+	Writer string `json:"writer,omitempty" yaml:"writer,omitempty" hcl:"writer"`
+}
+
+// AlertRuleDependencyExport is the provisioned export of models.AlertRuleDependency.
+type AlertRuleDependencyExport struct {
+	RuleUID string   `json:"rule_uid" yaml:"rule_uid" hcl:"rule_uid"`
+	Equal   []string `json:"equal,omitempty" yaml:"equal,omitempty" hcl:"equal"`
+}
+
+// AlertRuleEvaluationLimitsExport is the provisioned export of models.AlertRuleEvaluationLimits.
+type AlertRuleEvaluationLimitsExport struct {
+	MaxDuration *string `json:"max_duration,omitempty" yaml:"max_duration,omitempty" hcl:"max_duration"`
+	MaxSeries   *int    `json:"max_series,omitempty" yaml:"max_series,omitempty" hcl:"max_series"`
+	MaxQueries  *int    `json:"max_queries,omitempty" yaml:"max_queries,omitempty" hcl:"max_queries"`
 }
@@ -68,6 +68,19 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/backtest/group testing BacktestGroupConfig
+//
+// Test the current and a proposed version of a rule group
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestGroupResult
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -221,9 +234,10 @@ type BacktestConfig struct {
 	To       time.Time      `json:"to"`
 	Interval model.Duration `json:"interval,omitempty"`
 
-	Condition string         `json:"condition"`
-	Data      []AlertQuery   `json:"data"`
-	For       model.Duration `json:"for,omitempty"`
+	Condition   string         `json:"condition"`
+	Data        []AlertQuery   `json:"data"`
+	For         model.Duration `json:"for,omitempty"`
+	QueryOffset model.Duration `json:"query_offset,omitempty"`
 
 	Title       string            `json:"title"`
 	Labels      map[string]string `json:"labels,omitempty"`
@@ -234,3 +248,60 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters BacktestGroupConfig
+type BacktestGroupConfigRequest struct {
+	// in:body
+	Body BacktestGroupConfig
+}
+
+// swagger:model
+type BacktestGroupConfig struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// UID of the folder of the rule group.
+	NamespaceUID string `json:"namespace_uid"`
+	// Name of the rule group. The current version of the group is tested if it exists.
+	RuleGroup string `json:"rule_group"`
+
+	// Proposed version of the rule group. If set, it is tested in addition to the current version of the group.
+	// The name of the proposed group is ignored.
+	Proposed *PostableRuleGroupConfig `json:"proposed,omitempty"`
+}
+
+// swagger:model
+type BacktestGroupResult struct {
+	Current  *BacktestGroupRun `json:"current,omitempty"`
+	Proposed *BacktestGroupRun `json:"proposed,omitempty"`
+}
+
+type BacktestGroupRun struct {
+	Rules []BacktestRuleResult `json:"rules"`
+}
+
+type BacktestRuleResult struct {
+	UID           string                    `json:"uid"`
+	Title         string                    `json:"title"`
+	Transitions   []BacktestStateTransition `json:"transitions"`
+	Notifications []BacktestNotification    `json:"notifications"`
+}
+
+// BacktestStateTransition is a change of the state of an alert instance.
+type BacktestStateTransition struct {
+	Time          time.Time         `json:"time"`
+	Labels        map[string]string `json:"labels"`
+	PreviousState string            `json:"previous_state"`
+	State         string            `json:"state"`
+}
+
+// BacktestNotification is an alert that would have been sent to the receivers of the matching notification policies.
+type BacktestNotification struct {
+	Time time.Time `json:"time"`
+	// Labels of the alert, including the labels added by Grafana.
+	Labels map[string]string `json:"labels"`
+	// Status is either firing or resolved.
+	Status string `json:"status"`
+	// Receivers of the matching notification policies that are not muted by their time intervals at the time of the notification.
+	Receivers []string `json:"receivers"`
+}
@@ -42,11 +42,13 @@ type stateManager interface {
 type Engine struct {
 	evalFactory        eval.EvaluatorFactory
 	createStateManager func() stateManager
+	appURL             *url.URL
 }
 
 func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer) *Engine {
 	return &Engine{
 		evalFactory: evalFactory,
+		appURL:      appUrl,
 		createStateManager: func() stateManager {
 			cfg := state.ManagerCfg{
 				Metrics:       nil,
@@ -67,13 +69,10 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
-	if !from.Before(to) {
-		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
-	}
-	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
-		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+	length, err := evaluationsCount(rule, from, to)
+	if err != nil {
+		return nil, err
 	}
-	length := int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds)
 
 	stateManager := e.createStateManager()
 
@@ -130,6 +129,17 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 	return result, nil
 }
 
+// evaluationsCount returns the number of evaluations of the rule in the time range [from, to).
+func evaluationsCount(rule *models.AlertRule, from, to time.Time) (int, error) {
+	if !from.Before(to) {
+		return 0, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
+		return 0, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+	}
+	return int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds), nil
+}
+
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
 	for _, q := range condition.Data {
 		if q.DatasourceUID == "__data__" || q.QueryType == "__data__" {
@@ -152,7 +162,12 @@ func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFact
 			if model.DataFrame == nil {
 				return nil, errors.New("the data field must not be empty")
 			}
-			return newDataEvaluator(condition.Condition, model.DataFrame)
+			evaluator, err := newDataEvaluator(condition.Condition, model.DataFrame)
+			if err != nil {
+				return nil, err
+			}
+			evaluator.queryOffset = condition.QueryOffset
+			return evaluator, nil
 		}
 	}
 
@@ -18,6 +18,8 @@ type dataEvaluator struct {
 	data               []mathexp.Series
 	downsampleFunction mathexp.ReducerID
 	upsampleFunction   mathexp.Upsampler
+	// queryOffset shifts the data points back from the evaluation time, like the query offset of the rule.
+	queryOffset time.Duration
 }
 
 func newDataEvaluator(refID string, frame *data.Frame) (*dataEvaluator, error) {
@@ -42,7 +44,7 @@ func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Du
 	to := from.Add(time.Duration(evaluations) * interval)
 	for _, s := range d.data {
 		// making sure the input data frame is aligned with the interval
-		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, from, to.Add(-interval)) // we want to query [from,to)
+		r, err := s.Resample(d.refID, interval, d.downsampleFunction, d.upsampleFunction, from.Add(-d.queryOffset), to.Add(-interval-d.queryOffset)) // we want to query [from,to)
 		if err != nil {
 			return err
 		}
@@ -53,7 +55,7 @@ func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Du
 		result := make([]eval.Result, 0, len(resampled))
 		var now time.Time
 		for _, series := range resampled {
-			snow := series.GetTime(i)
+			snow := series.GetTime(i).Add(d.queryOffset)
 			if !now.IsZero() && now != snow { // this should not happen because all series' belong to a single data frame
 				return errors.New("failed to resample input data. timestamps are not aligned")
 			}
@@ -280,6 +280,32 @@ func TestDataEvaluator_Eval(t *testing.T) {
 			}
 		})
 	})
+	t.Run("should use data points shifted back by query offset", func(t *testing.T) {
+		offset := 3 * time.Second
+		shifted := *evaluator
+		shifted.queryOffset = offset
+
+		r := make([]results, 0, frame.Rows())
+		size := int(to.Sub(from).Seconds())
+		err = shifted.Eval(context.Background(), from.Add(offset), time.Second, size, func(idx int, now time.Time, res eval.Results) error {
+			r = append(r, results{
+				now, res,
+			})
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, r, size)
+		for i, current := range r {
+			require.Equal(t, from.Add(offset).Add(time.Duration(i)*time.Second), current.time)
+			for idx, result := range current.results {
+				require.Equal(t, current.time, result.EvaluatedAt)
+				expected, err := frame.Fields[idx+1].FloatAt(i)
+				require.NoError(t, err)
+				require.EqualValues(t, expected, *result.Values[refID].Value)
+			}
+		}
+	})
 	t.Run("should stop if callback error", func(t *testing.T) {
 		expectedError := errors.New("error")
 		err = evaluator.Eval(context.Background(), from, time.Second, 6, func(idx int, now time.Time, res eval.Results) error {
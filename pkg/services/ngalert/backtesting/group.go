@@ -0,0 +1,160 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// NotificationRouter returns the receivers that an alert with the given labels is routed to at the given time.
+// Receivers of notification policies that are muted by their time intervals at that time are not returned.
+type NotificationRouter interface {
+	Receivers(labels data.Labels, at time.Time) ([]string, error)
+}
+
+// GroupResult is the result of backtesting a group of rules. Rules are in the same order as in the tested group.
+type GroupResult struct {
+	Rules []RuleResult
+}
+
+// RuleResult contains the state transitions of the alert instances of a rule
+// and the notifications that they would have produced.
+type RuleResult struct {
+	Rule          *models.AlertRule
+	Transitions   []Transition
+	Notifications []Notification
+}
+
+// Transition is a change of the state of an alert instance.
+type Transition struct {
+	Time          time.Time
+	Labels        data.Labels
+	PreviousState string
+	State         string
+}
+
+// Notification is an alert that would have been sent to the Alertmanager because it started firing or was resolved.
+type Notification struct {
+	Time      time.Time
+	Labels    data.Labels
+	Resolved  bool
+	Receivers []string
+}
+
+// TestGroup runs all rules of a group over the time range [from, to) and returns the timeline of state transitions
+// and the notifications of every rule. Each rule is evaluated with its own state manager,
+// so dependencies between rules are not applied.
+// Alerts that keep firing are re-sent to the Alertmanager but they are deduplicated there, therefore only the alerts
+// that start firing or get resolved are reported as notifications. The receivers of a notification are provided by the router,
+// which applies the time intervals of the notification policies at the time of the notification.
+func (e *Engine) TestGroup(ctx context.Context, user identity.Requester, rules []*models.AlertRule, folderTitle string, includeFolder bool, router NotificationRouter, from, to time.Time) (*GroupResult, error) {
+	logger := logger.FromContext(ctx)
+
+	if len(rules) == 0 {
+		return nil, errors.Join(ErrInvalidInputData, errors.New("rule group must not be empty"))
+	}
+	for _, rule := range rules {
+		if _, err := evaluationsCount(rule, from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("Start testing rule group", "from", from, "to", to, "rules", len(rules))
+	start := time.Now()
+
+	result := &GroupResult{Rules: make([]RuleResult, 0, len(rules))}
+	for _, rule := range rules {
+		extraLabels := state.GetRuleExtraLabels(logger, rule, folderTitle, includeFolder)
+		ruleResult, err := e.testRuleTimeline(ctx, user, rule, extraLabels, router, from, to)
+		if err != nil {
+			return nil, err
+		}
+		result.Rules = append(result.Rules, ruleResult)
+	}
+
+	logger.Info("Rule group testing finished successfully", "duration", time.Since(start))
+	return result, nil
+}
+
+func (e *Engine) testRuleTimeline(ctx context.Context, user identity.Requester, rule *models.AlertRule, extraLabels data.Labels, router NotificationRouter, from, to time.Time) (RuleResult, error) {
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	result := RuleResult{Rule: rule}
+
+	length, err := evaluationsCount(rule, from, to)
+	if err != nil {
+		return result, err
+	}
+
+	stateManager := e.createStateManager()
+
+	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition().WithSource("backtesting"), &schedule.AlertingResultsFromRuleState{
+		Manager: stateManager,
+		Rule:    rule,
+	})
+	if err != nil {
+		return result, errors.Join(ErrInvalidInputData, err)
+	}
+
+	// firing contains the alerts that were notified as firing and not resolved yet.
+	firing := make(map[data.Fingerprint]struct{})
+	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
+		if idx >= length {
+			return nil
+		}
+		var toSend state.StateTransitions
+		transitions := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels, func(_ context.Context, states state.StateTransitions) {
+			toSend = states
+		})
+		for _, t := range transitions {
+			if !t.Changed() {
+				continue
+			}
+			result.Transitions = append(result.Transitions, Transition{
+				Time:          currentTime,
+				Labels:        t.Labels.Copy(),
+				PreviousState: t.PreviousFormatted(),
+				State:         t.Formatted(),
+			})
+		}
+		for _, t := range toSend {
+			alert := state.StateToPostableAlert(t, e.appURL)
+			labels := data.Labels(alert.Labels)
+			fp := labels.Fingerprint()
+			resolved := t.State.State == eval.Normal
+			if _, ok := firing[fp]; ok != resolved {
+				// The alert is either re-sent while firing or resolved without being notified as firing.
+				continue
+			}
+			if resolved {
+				delete(firing, fp)
+			} else {
+				firing[fp] = struct{}{}
+			}
+			var receivers []string
+			if router != nil {
+				var err error
+				receivers, err = router.Receivers(labels, currentTime)
+				if err != nil {
+					return fmt.Errorf("failed to route notification: %w", err)
+				}
+			}
+			result.Notifications = append(result.Notifications, Notification{
+				Time:      currentTime,
+				Labels:    labels,
+				Resolved:  resolved,
+				Receivers: receivers,
+			})
+		}
+		return nil
+	})
+	return result, err
+}
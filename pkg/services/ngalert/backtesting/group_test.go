@@ -0,0 +1,107 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestEngineTestGroup(t *testing.T) {
+	evaluator := &fakeBacktestingEvaluator{}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	engine := NewEngine(nil, nil, tracing.InitializeTracerForTest())
+
+	gen := models.RuleGen
+	rules := gen.With(gen.WithInterval(time.Second), gen.WithFor(0), gen.WithNoNotificationSettings()).GenerateManyRef(2)
+	from := time.Unix(0, 0)
+	to := from.Add(5 * time.Second)
+
+	instance := data.Labels{"instance": "a"}
+	// Normal, Alerting, Alerting, Normal, Alerting
+	alertingAt := map[time.Time]bool{
+		from.Add(1 * time.Second): true,
+		from.Add(2 * time.Second): true,
+		from.Add(4 * time.Second): true,
+	}
+	evaluator.evalCallback = func(now time.Time) (eval.Results, error) {
+		s := eval.Normal
+		if alertingAt[now] {
+			s = eval.Alerting
+		}
+		return eval.Results{{Instance: instance, State: s, EvaluatedAt: now}}, nil
+	}
+
+	router := &fakeNotificationRouter{receivers: []string{"test-receiver"}}
+
+	t.Run("should return transitions and notifications of every rule", func(t *testing.T) {
+		result, err := engine.TestGroup(context.Background(), nil, rules, "folder", true, router, from, to)
+		require.NoError(t, err)
+		require.Len(t, result.Rules, len(rules))
+
+		for i, r := range result.Rules {
+			require.Equal(t, rules[i], r.Rule)
+
+			require.Len(t, r.Transitions, 3)
+			require.Equal(t, from.Add(1*time.Second), r.Transitions[0].Time)
+			require.Equal(t, "Normal", r.Transitions[0].PreviousState)
+			require.Equal(t, "Alerting", r.Transitions[0].State)
+			require.Equal(t, from.Add(3*time.Second), r.Transitions[1].Time)
+			require.Equal(t, "Alerting", r.Transitions[1].PreviousState)
+			require.Equal(t, "Normal", r.Transitions[1].State)
+			require.Equal(t, from.Add(4*time.Second), r.Transitions[2].Time)
+			require.Equal(t, "a", r.Transitions[0].Labels["instance"])
+			require.Equal(t, rules[i].UID, r.Transitions[0].Labels[alertingModels.RuleUIDLabel])
+			require.Equal(t, "folder", r.Transitions[0].Labels[models.FolderTitleLabel])
+
+			// The alert keeps firing at 2s, which does not produce a new notification.
+			require.Len(t, r.Notifications, 3)
+			require.Equal(t, from.Add(1*time.Second), r.Notifications[0].Time)
+			require.False(t, r.Notifications[0].Resolved)
+			require.Equal(t, from.Add(3*time.Second), r.Notifications[1].Time)
+			require.True(t, r.Notifications[1].Resolved)
+			require.Equal(t, from.Add(4*time.Second), r.Notifications[2].Time)
+			require.False(t, r.Notifications[2].Resolved)
+			for _, n := range r.Notifications {
+				require.Equal(t, []string{"test-receiver"}, n.Receivers)
+				require.Equal(t, rules[i].Title, n.Labels["alertname"])
+			}
+		}
+		require.NotEmpty(t, router.routed)
+	})
+
+	t.Run("should fail if time range is shorter than interval of any rule", func(t *testing.T) {
+		long := gen.With(gen.WithInterval(time.Minute)).GenerateRef()
+		_, err := engine.TestGroup(context.Background(), nil, append(rules, long), "folder", true, router, from, to)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+
+	t.Run("should fail if group is empty", func(t *testing.T) {
+		_, err := engine.TestGroup(context.Background(), nil, nil, "folder", true, router, from, to)
+		require.ErrorIs(t, err, ErrInvalidInputData)
+	})
+}
+
+type fakeNotificationRouter struct {
+	receivers []string
+	routed    []data.Labels
+}
+
+func (f *fakeNotificationRouter) Receivers(labels data.Labels, _ time.Time) ([]string, error) {
+	f.routed = append(f.routed, labels)
+	return f.receivers, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 // AlertingResultsReader provides fingerprints of results that are in alerting state.
@@ -14,6 +15,12 @@ type AlertingResultsReader interface {
 	Read() map[data.Fingerprint]struct{}
 }
 
+// ThresholdStateReader provides the results of the previous evaluations of a rule for each dimension.
+// It is used during the evaluation of threshold expressions with several levels or a breach window.
+type ThresholdStateReader interface {
+	ReadThresholdState() expr.ThresholdStates
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx                   context.Context
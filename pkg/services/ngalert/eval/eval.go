@@ -26,6 +26,9 @@ import (
 
 var logger = log.New("ngalert.eval")
 
+// ErrLimitExceeded is the error of a rule evaluation that exceeded the limits of the rule.
+var ErrLimitExceeded = errors.New("evaluation limit exceeded")
+
 type EvaluatorFactory interface {
 	// Create builds an evaluator pipeline ready to evaluate a rule's query
 	Create(ctx EvaluationContext, condition models.Condition) (ConditionEvaluator, error)
@@ -397,6 +400,25 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 					}
 				}
 			}
+
+			isStateful, err := q.IsStatefulThresholdExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build query '%s': %w", q.RefID, err)
+			}
+			if isStateful {
+				// similar to hysteresis, the previous results are tracked only for the alert condition.
+				if q.RefID != condition.Condition {
+					return nil, fmt.Errorf("threshold '%s' with several levels or a breach window is only allowed to be the alert condition", q.RefID)
+				}
+				if stateReader, ok := reader.(ThresholdStateReader); ok {
+					states := stateReader.ReadThresholdState()
+					logger.FromContext(ctx.Ctx).Debug("Detected stateful threshold command. Populating with the previous results", "items", len(states))
+					err = q.PatchStatefulThresholdExpression(states)
+					if err != nil {
+						return nil, fmt.Errorf("failed to amend threshold command '%s': %w", q.RefID, err)
+					}
+				}
+			}
 		}
 
 		model, err := q.GetModel()
@@ -413,8 +435,13 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 			return nil, fmt.Errorf("failed to retrieve maxDatapoints from '%s': %w", q.RefID, err)
 		}
 
+		// the query offset moves the whole time range back, like query_offset in Prometheus.
+		timeRange := q.RelativeTimeRange
+		timeRange.From += models.Duration(condition.QueryOffset)
+		timeRange.To += models.Duration(condition.QueryOffset)
+
 		req.Queries = append(req.Queries, expr.Query{
-			TimeRange:     q.RelativeTimeRange.ToTimeRange(),
+			TimeRange:     timeRange.ToTimeRange(),
 			DataSource:    ds,
 			JSON:          model,
 			Interval:      interval,
@@ -2,6 +2,7 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -578,6 +579,66 @@ func TestValidate(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "pass if forecast command and ML plugin is not installed",
+			error: false,
+			condition: func(services services) models.Condition {
+				dsQuery := models.GenerateAlertQuery()
+				ds := &datasources.DataSource{
+					UID:  dsQuery.DatasourceUID,
+					Type: util.GenerateShortUID(),
+				}
+				services.cache.DataSources = append(services.cache.DataSources, ds)
+				services.pluginsStore.PluginList = append(services.pluginsStore.PluginList, pluginstore.Plugin{
+					JSONData: plugins.JSONData{
+						ID:      ds.Type,
+						Backend: true,
+					},
+				})
+
+				return models.Condition{
+					Condition: "B",
+					Data: []models.AlertQuery{
+						dsQuery,
+						{
+							RefID:         "B",
+							DatasourceUID: expr.MLDatasourceUID,
+							Model:         json.RawMessage(fmt.Sprintf(`{"type": "forecast", "config": {"input": "%s", "horizon": "1h"}}`, dsQuery.RefID)),
+						},
+					},
+				}
+			},
+		},
+		{
+			name:  "fail if outlier command and ML plugin is not installed",
+			error: true,
+			condition: func(services services) models.Condition {
+				dsQuery := models.GenerateAlertQuery()
+				ds := &datasources.DataSource{
+					UID:  dsQuery.DatasourceUID,
+					Type: util.GenerateShortUID(),
+				}
+				services.cache.DataSources = append(services.cache.DataSources, ds)
+				services.pluginsStore.PluginList = append(services.pluginsStore.PluginList, pluginstore.Plugin{
+					JSONData: plugins.JSONData{
+						ID:      ds.Type,
+						Backend: true,
+					},
+				})
+
+				return models.Condition{
+					Condition: "B",
+					Data: []models.AlertQuery{
+						dsQuery,
+						{
+							RefID:         "B",
+							DatasourceUID: expr.MLDatasourceUID,
+							Model:         json.RawMessage(`{"type": "outlier", "config": {"datasource_uid": "a4ce599c-4c93-44b9-be5b-76385b8c01be", "query_params": {"expr": "up"}, "response_type": "binary", "algorithm": {"name": "dbscan", "config": {"epsilon": 7.667}, "sensitivity": 0.83}}}`),
+						},
+					},
+				}
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -1294,6 +1355,35 @@ func TestCreate(t *testing.T) {
 
 		require.Equal(t, expectedHeaders, request.Headers)
 	})
+
+	t.Run("should shift time range of queries by query offset", func(t *testing.T) {
+		q := models.CreateClassicConditionExpression("A", "B", "avg", "gt", 1)
+		q.RelativeTimeRange = models.RelativeTimeRange{From: models.Duration(10 * time.Minute), To: models.Duration(time.Minute)}
+		condition := models.Condition{
+			Condition:   q.RefID,
+			Data:        []models.AlertQuery{q},
+			QueryOffset: 30 * time.Second,
+		}
+
+		var request *expr.Request
+		factory := evaluatorImpl{
+			expressionService: fakeExpressionService{
+				buildHook: func(req *expr.Request) (expr.DataPipeline, error) {
+					request = req
+					return expr.DataPipeline{
+						fakeNode{refID: q.RefID},
+					}, nil
+				},
+			},
+		}
+
+		_, err := factory.Create(NewContext(context.Background(), &user.SignedInUser{}), condition)
+		require.NoError(t, err)
+
+		require.NotNil(t, request)
+		require.Len(t, request.Queries, 1)
+		require.Equal(t, expr.RelativeTimeRange{From: -(10*time.Minute + 30*time.Second), To: -(time.Minute + 30*time.Second)}, request.Queries[0].TimeRange)
+	})
 }
 
 type fakeExpressionService struct {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/ml"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -43,6 +44,9 @@ func (e *ConditionValidator) Validate(ctx EvaluationContext, condition models.Co
 				return fmt.Errorf("datasource refID %s is not a backend datasource", query.RefID)
 			}
 		case expr.TypeMLNode:
+			if ml.IsLocalCommand(query.JSON) { // local commands do not need the ML plugin
+				break
+			}
 			_, found := e.pluginsStore.Plugin(ctx.Ctx, query.DataSource.Type)
 			if !found {
 				return fmt.Errorf("datasource refID %s could not be found: %w", query.RefID, plugins.ErrPluginUnavailable)
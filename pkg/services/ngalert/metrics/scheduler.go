@@ -20,6 +20,7 @@ type Scheduler struct {
 	EvalDuration                        *prometheus.HistogramVec
 	EvalAttemptTotal                    *prometheus.CounterVec
 	EvalAttemptFailures                 *prometheus.CounterVec
+	EvalLimitExceeded                   *prometheus.CounterVec
 	ProcessDuration                     *prometheus.HistogramVec
 	SendDuration                        *prometheus.HistogramVec
 	SimpleNotificationRules             *prometheus.GaugeVec
@@ -93,6 +94,15 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org"},
 		),
+		EvalLimitExceeded: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_limit_exceeded_total",
+				Help:      "The total number of rule evaluations that exceeded the evaluation limits, by limit.",
+			},
+			[]string{"org", "limit"},
+		),
 		ProcessDuration: promauto.With(r).NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: Namespace,
@@ -140,6 +140,42 @@ func (aq *AlertQuery) PatchHysteresisExpression(loadedMetrics map[data.Fingerpri
 	return expr.SetLoadedDimensionsToHysteresisCommand(aq.modelProps, loadedMetrics)
 }
 
+// IsStatefulThresholdExpression returns true if the model describes a threshold expression with several levels or a breach window.
+// Returns error if the Model is not a valid JSON
+func (aq *AlertQuery) IsStatefulThresholdExpression() (bool, error) {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return false, err
+		}
+	}
+	return expr.IsStatefulThresholdExpression(aq.modelProps), nil
+}
+
+// StatefulThresholdHistory returns the reference variable of a threshold expression with several levels or a breach window,
+// and the number of previous evaluations it needs for each dimension. Returns false if the model does not describe such an expression.
+func (aq *AlertQuery) StatefulThresholdHistory() (string, int, bool, error) {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return "", 0, false, err
+		}
+	}
+	refID, size, ok := expr.GetStatefulThresholdHistory(aq.modelProps)
+	return refID, size, ok, nil
+}
+
+// PatchStatefulThresholdExpression updates the AlertQuery to include the results of the previous evaluations into the threshold expression
+func (aq *AlertQuery) PatchStatefulThresholdExpression(states expr.ThresholdStates) error {
+	if aq.modelProps == nil {
+		err := aq.setModelProps()
+		if err != nil {
+			return err
+		}
+	}
+	return expr.SetPreviousStateToStatefulThresholdCommand(aq.modelProps, states)
+}
+
 // setMaxDatapoints sets the model maxDataPoints if it's missing or invalid
 func (aq *AlertQuery) setMaxDatapoints() error {
 	if aq.modelProps == nil {
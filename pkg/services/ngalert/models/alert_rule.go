@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"maps"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -164,6 +165,9 @@ const (
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
 	StateReasonKeepLast      = "KeepLast"
+	StateReasonKeepFiring    = "KeepFiring"
+	StateReasonInhibited     = "Inhibited"
+	StateReasonLimitExceeded = "LimitExceeded"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -264,12 +268,22 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For                  time.Duration
+	For time.Duration
+	// KeepFiringFor is how long alert instances stay in Alerting after the condition is no longer met.
+	// It is the equivalent of keep_firing_for in Prometheus alerting rules.
+	KeepFiringFor time.Duration
+	// QueryOffset shifts the time range of the queries back from the evaluation time.
+	// It is the equivalent of query_offset in Prometheus rule groups.
+	QueryOffset          time.Duration
 	Annotations          map[string]string
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings
 	Metadata             AlertRuleMetadata
+	// Dependencies are the rules that inhibit the alert instances of this rule while they are firing.
+	Dependencies []AlertRuleDependency
+	// EvaluationLimits are the limits of the evaluation of this rule that override the limits of the organization.
+	EvaluationLimits AlertRuleEvaluationLimits
 }
 
 type AlertRuleMetadata struct {
@@ -385,15 +399,17 @@ func (alertRule *AlertRule) GetEvalCondition() Condition {
 	}
 	if alertRule.Type() == RuleTypeRecording {
 		return Condition{
-			Metadata:  meta,
-			Condition: alertRule.Record.From,
-			Data:      alertRule.Data,
+			Metadata:    meta,
+			Condition:   alertRule.Record.From,
+			Data:        alertRule.Data,
+			QueryOffset: alertRule.QueryOffset,
 		}
 	}
 	return Condition{
-		Metadata:  meta,
-		Condition: alertRule.Condition,
-		Data:      alertRule.Data,
+		Metadata:    meta,
+		Condition:   alertRule.Condition,
+		Data:        alertRule.Data,
+		QueryOffset: alertRule.QueryOffset,
 	}
 }
 
@@ -569,7 +585,7 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 
 	var err error
 	if alertRule.Type() == RuleTypeRecording {
-		err = validateRecordingRuleFields(alertRule)
+		err = validateRecordingRuleFields(alertRule, cfg.RecordingRules)
 	} else {
 		err = validateAlertRuleFields(alertRule)
 	}
@@ -581,6 +597,14 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
 	}
 
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ErrAlertRuleFailedValidation)
+	}
+
+	if alertRule.QueryOffset < 0 {
+		return fmt.Errorf("%w: field `query_offset` cannot be negative", ErrAlertRuleFailedValidation)
+	}
+
 	if len(alertRule.Labels) > 0 {
 		for label := range alertRule.Labels {
 			if _, ok := LabelsUserCannotSpecify[label]; ok {
@@ -589,6 +613,14 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		}
 	}
 
+	if err := validateDependencies(alertRule); err != nil {
+		return err
+	}
+
+	if err := alertRule.EvaluationLimits.Validate(); err != nil {
+		return fmt.Errorf("%w: invalid evaluation limits: %s", ErrAlertRuleFailedValidation, err)
+	}
+
 	if len(alertRule.NotificationSettings) > 0 {
 		if len(alertRule.NotificationSettings) != 1 {
 			return fmt.Errorf("%w: only one notification settings entry is allowed", ErrAlertRuleFailedValidation)
@@ -612,7 +644,7 @@ func validateAlertRuleFields(rule *AlertRule) error {
 	return nil
 }
 
-func validateRecordingRuleFields(rule *AlertRule) error {
+func validateRecordingRuleFields(rule *AlertRule, settings setting.RecordingRuleSettings) error {
 	metricName := prommodels.LabelValue(rule.Record.Metric)
 	if !metricName.IsValid() {
 		return fmt.Errorf("%w: %s", ErrAlertRuleFailedValidation, "metric name for recording rule must be a valid utf8 string")
@@ -620,6 +652,9 @@ func validateRecordingRuleFields(rule *AlertRule) error {
 	if !prommodels.IsValidMetricName(metricName) {
 		return fmt.Errorf("%w: %s", ErrAlertRuleFailedValidation, "metric name for recording rule must be a valid Prometheus metric name")
 	}
+	if err := ValidateRecordingWriter(rule.Record.Writer, EnabledRecordingWriterTypes(settings)); err != nil {
+		return err
+	}
 
 	ClearRecordingRuleIgnoredFields(rule)
 
@@ -657,7 +692,10 @@ func ClearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.ExecErrState = ""
 	rule.Condition = ""
 	rule.For = 0
+	rule.KeepFiringFor = 0
 	rule.NotificationSettings = nil
+	rule.Dependencies = nil
+	rule.EvaluationLimits = AlertRuleEvaluationLimits{}
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -742,6 +780,9 @@ type Condition struct {
 
 	// Data is an array of data source queries and/or server side expressions.
 	Data []AlertQuery `json:"data"`
+
+	// QueryOffset shifts the time range of all queries back from the evaluation time.
+	QueryOffset time.Duration `json:"-"`
 }
 
 func (c Condition) withMetadata(key, value string) Condition {
@@ -749,9 +790,10 @@ func (c Condition) withMetadata(key, value string) Condition {
 	maps.Copy(meta, c.Metadata)
 	meta[key] = value
 	return Condition{
-		Metadata:  meta,
-		Condition: c.Condition,
-		Data:      c.Data,
+		Metadata:    meta,
+		Condition:   c.Condition,
+		Data:        c.Data,
+		QueryOffset: c.QueryOffset,
 	}
 }
 
@@ -802,6 +844,12 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
+	if ruleToPatch.QueryOffset == -1 {
+		ruleToPatch.QueryOffset = existingRule.QueryOffset
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
@@ -870,12 +918,64 @@ func GroupByAlertRuleGroupKey(rules []*AlertRule) map[AlertRuleGroupKey]RulesGro
 	return result
 }
 
+// Types of the writers that recording rules can send their results to.
+const (
+	RecordingWriterPrometheus = "prometheus"
+	RecordingWriterSQL        = "sql"
+	RecordingWriterOTLP       = "otlp"
+	RecordingWriterInfluxDB   = "influxdb"
+)
+
+// RecordingWriterTypes contains all types of the writers of recording rules.
+var RecordingWriterTypes = []string{
+	RecordingWriterPrometheus,
+	RecordingWriterSQL,
+	RecordingWriterOTLP,
+	RecordingWriterInfluxDB,
+}
+
+// EnabledRecordingWriterTypes returns the types of the writers of recording rules that are configured in settings.
+// If writing the results of recording rules is disabled, all types are returned because nothing is written anyway.
+func EnabledRecordingWriterTypes(settings setting.RecordingRuleSettings) []string {
+	if !settings.Enabled {
+		return RecordingWriterTypes
+	}
+	var result []string
+	if settings.URL != "" {
+		result = append(result, RecordingWriterPrometheus)
+	}
+	if settings.SQL.Enabled {
+		result = append(result, RecordingWriterSQL)
+	}
+	if settings.OTLP.Enabled {
+		result = append(result, RecordingWriterOTLP)
+	}
+	if settings.InfluxDB.Enabled {
+		result = append(result, RecordingWriterInfluxDB)
+	}
+	return result
+}
+
+// ValidateRecordingWriter checks that the writer selected by a recording rule is one of the enabled types.
+// An empty writer selects the default writer, which is always enabled.
+func ValidateRecordingWriter(writer string, enabled []string) error {
+	if writer == "" || slices.Contains(enabled, writer) {
+		return nil
+	}
+	if !slices.Contains(RecordingWriterTypes, writer) {
+		return fmt.Errorf("%w: unknown writer '%s' of recording rule, must be one of %s", ErrAlertRuleFailedValidation, writer, strings.Join(RecordingWriterTypes, ", "))
+	}
+	return fmt.Errorf("%w: writer '%s' of recording rule is not enabled, enabled writers are: %s", ErrAlertRuleFailedValidation, writer, strings.Join(enabled, ", "))
+}
+
 // Record contains mapping information for Recording Rules.
 type Record struct {
 	// Metric indicates a metric name to send results to.
 	Metric string
 	// From contains a query RefID, indicating which expression node is the output of the recording rule.
 	From string
+	// Writer is the type of the writer that receives the results of the rule. If empty, the default writer is used.
+	Writer string `json:",omitempty"`
 }
 
 func (r *Record) Fingerprint() data.Fingerprint {
@@ -890,6 +990,10 @@ func (r *Record) Fingerprint() data.Fingerprint {
 
 	writeString(r.Metric)
 	writeString(r.From)
+	// the writer is hashed only if set, so that the fingerprint of rules that use the default writer does not change.
+	if r.Writer != "" {
+		writeString(r.Writer)
+	}
 	return data.Fingerprint(h.Sum64())
 }
 
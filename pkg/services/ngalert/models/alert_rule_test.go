@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"reflect"
 	"sort"
@@ -12,11 +13,13 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/cmputil"
 )
@@ -247,6 +250,18 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "KeepFiringFor is -1",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.KeepFiringFor = -1
+				},
+			},
+			{
+				name: "QueryOffset is -1",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.QueryOffset = -1
+				},
+			},
 			{
 				name: "IsPaused did not come in request",
 				mutator: func(r *AlertRuleWithOptionals) {
@@ -263,6 +278,8 @@ func TestPatchPartialAlertRule(t *testing.T) {
 
 		gen := RuleGen.With(
 			RuleMuts.WithFor(time.Duration(rand.Int63n(1000)+1)),
+			RuleMuts.WithKeepFiringFor(time.Duration(rand.Int63n(1000)+1)),
+			RuleMuts.WithQueryOffset(time.Duration(rand.Int63n(1000)+1)),
 			RuleMuts.WithEditorSettingsSimplifiedQueryAndExpressionsSection(true),
 		)
 
@@ -908,3 +925,47 @@ func TestAlertRuleGetKeyWithGroup(t *testing.T) {
 		require.Equal(t, expected, rule.GetKeyWithGroup())
 	})
 }
+
+func TestEnabledRecordingWriterTypes(t *testing.T) {
+	t.Run("should return all types if recording rules are disabled", func(t *testing.T) {
+		require.Equal(t, RecordingWriterTypes, EnabledRecordingWriterTypes(setting.RecordingRuleSettings{}))
+	})
+
+	t.Run("should return only configured writers", func(t *testing.T) {
+		settings := setting.RecordingRuleSettings{
+			Enabled: true,
+			SQL:     setting.RecordingRuleSQLSettings{Enabled: true},
+		}
+		require.Equal(t, []string{RecordingWriterSQL}, EnabledRecordingWriterTypes(settings))
+
+		settings.URL = "http://localhost:9090/api/v1/write"
+		require.Equal(t, []string{RecordingWriterPrometheus, RecordingWriterSQL}, EnabledRecordingWriterTypes(settings))
+	})
+
+	t.Run("should reject rules that select writers that are not enabled", func(t *testing.T) {
+		enabled := []string{RecordingWriterSQL}
+		require.NoError(t, ValidateRecordingWriter("", enabled))
+		require.NoError(t, ValidateRecordingWriter(RecordingWriterSQL, enabled))
+		require.ErrorContains(t, ValidateRecordingWriter(RecordingWriterPrometheus, enabled), "is not enabled")
+		require.ErrorContains(t, ValidateRecordingWriter("unknown", enabled), "unknown writer")
+	})
+}
+
+func TestRecordFingerprint(t *testing.T) {
+	t.Run("should not change for rules without writer", func(t *testing.T) {
+		// fingerprint of the metric and the query before the writer was added to the record.
+		h := fnv.New64()
+		for _, s := range []string{"metric", "A"} {
+			_, _ = h.Write([]byte(s))
+			_, _ = h.Write([]byte{255})
+		}
+		r := Record{Metric: "metric", From: "A"}
+		require.Equal(t, data.Fingerprint(h.Sum64()), r.Fingerprint())
+	})
+
+	t.Run("should change with the writer", func(t *testing.T) {
+		r := Record{Metric: "metric", From: "A"}
+		withWriter := Record{Metric: "metric", From: "A", Writer: RecordingWriterSQL}
+		require.NotEqual(t, r.Fingerprint(), withWriter.Fingerprint())
+	})
+}
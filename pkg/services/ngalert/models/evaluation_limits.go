@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// AlertRuleEvaluationLimits are the limits of the evaluation of a single alert rule. They can only make the limits
+// configured for the organization of the rule stricter. A zero value means that the limit of the organization applies.
+type AlertRuleEvaluationLimits struct {
+	// MaxDuration is how long the evaluation can take.
+	MaxDuration time.Duration `json:"max_duration,omitempty"`
+	// MaxSeries is how many series the queries and the evaluation can return.
+	MaxSeries int `json:"max_series,omitempty"`
+	// MaxQueries is how many data source queries the rule can have.
+	MaxQueries int `json:"max_queries,omitempty"`
+}
+
+// IsZero returns true if the rule does not override any limit.
+func (l AlertRuleEvaluationLimits) IsZero() bool {
+	return l == AlertRuleEvaluationLimits{}
+}
+
+// Validate checks that none of the limits is negative.
+func (l AlertRuleEvaluationLimits) Validate() error {
+	if l.MaxDuration < 0 {
+		return errors.New("max duration cannot be negative")
+	}
+	if l.MaxSeries < 0 {
+		return errors.New("max series cannot be negative")
+	}
+	if l.MaxQueries < 0 {
+		return errors.New("max queries cannot be negative")
+	}
+	return nil
+}
+
+// Apply returns the limits of the organization with the limits of the rule applied.
+// A limit of the rule is used only if it is stricter than the limit of the organization.
+func (l AlertRuleEvaluationLimits) Apply(limits setting.EvaluationLimits) setting.EvaluationLimits {
+	return setting.EvaluationLimits{
+		MaxDuration: stricterLimit(limits.MaxDuration, l.MaxDuration),
+		MaxSeries:   stricterLimit(limits.MaxSeries, l.MaxSeries),
+		MaxQueries:  stricterLimit(limits.MaxQueries, l.MaxQueries),
+	}
+}
+
+// stricterLimit returns the lower of the two limits, where a limit that is not positive means no limit.
+func stricterLimit[T time.Duration | int](limit, override T) T {
+	if override <= 0 {
+		return limit
+	}
+	if limit <= 0 || override < limit {
+		return override
+	}
+	return limit
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAlertRuleEvaluationLimitsApply(t *testing.T) {
+	org := setting.EvaluationLimits{MaxDuration: time.Minute, MaxSeries: 100}
+
+	testCases := []struct {
+		name     string
+		limits   AlertRuleEvaluationLimits
+		expected setting.EvaluationLimits
+	}{
+		{
+			name:     "uses the limits of the organization if the rule does not override them",
+			limits:   AlertRuleEvaluationLimits{},
+			expected: org,
+		},
+		{
+			name:     "uses the limits of the rule if they are stricter",
+			limits:   AlertRuleEvaluationLimits{MaxDuration: 10 * time.Second, MaxSeries: 10},
+			expected: setting.EvaluationLimits{MaxDuration: 10 * time.Second, MaxSeries: 10},
+		},
+		{
+			name:     "ignores the limits of the rule if they are less strict",
+			limits:   AlertRuleEvaluationLimits{MaxDuration: time.Hour, MaxSeries: 1000},
+			expected: org,
+		},
+		{
+			name:     "uses the limits of the rule if the organization has no limit",
+			limits:   AlertRuleEvaluationLimits{MaxQueries: 2},
+			expected: setting.EvaluationLimits{MaxDuration: time.Minute, MaxSeries: 100, MaxQueries: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.limits.Apply(org))
+		})
+	}
+}
+
+func TestAlertRuleEvaluationLimitsValidate(t *testing.T) {
+	require.NoError(t, AlertRuleEvaluationLimits{}.Validate())
+	require.NoError(t, AlertRuleEvaluationLimits{MaxDuration: time.Second, MaxSeries: 1, MaxQueries: 1}.Validate())
+	require.ErrorContains(t, AlertRuleEvaluationLimits{MaxDuration: -time.Second}.Validate(), "max duration")
+	require.ErrorContains(t, AlertRuleEvaluationLimits{MaxSeries: -1}.Validate(), "max series")
+	require.ErrorContains(t, AlertRuleEvaluationLimits{MaxQueries: -1}.Validate(), "max queries")
+}
@@ -1,8 +1,11 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/grafana/grafana/pkg/expr"
 )
 
 // AlertInstance represents a single alert instance.
@@ -16,7 +19,32 @@ type AlertInstance struct {
 	LastEvalTime      time.Time
 	LastSentAt        *time.Time
 	ResolvedAt        *time.Time
+	KeepFiringSince   *time.Time
 	ResultFingerprint string
+	ThresholdHistory  InstanceThresholdHistory
+}
+
+// InstanceThresholdHistory is the history of the condition of a rule that is a threshold expression with several
+// levels or a breach window, for a single instance. It is stored in the database as JSON.
+type InstanceThresholdHistory []expr.ThresholdEvaluation
+
+// FromDB loads the history stored in the database as JSON.
+// FromDB is part of the xorm Conversion interface.
+func (h *InstanceThresholdHistory) FromDB(b []byte) error {
+	if len(b) == 0 {
+		*h = nil
+		return nil
+	}
+	return json.Unmarshal(b, h)
+}
+
+// ToDB serializes the history as JSON, or returns nil if it's empty.
+// ToDB is part of the xorm Conversion interface.
+func (h *InstanceThresholdHistory) ToDB() ([]byte, error) {
+	if h == nil || len(*h) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(*h)
 }
 
 type AlertInstanceKey struct {
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+var (
+	// ErrNotificationDeliveryNotFound is returned when the delivery does not exist.
+	ErrNotificationDeliveryNotFound = errors.New("notification delivery not found")
+)
+
+// NotificationDeliveryStatus is the outcome of a notification attempt.
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryStatusSuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryStatusFailure NotificationDeliveryStatus = "failure"
+)
+
+// NotificationDelivery is a single attempt of an integration of a contact point to send a notification for a group of alerts.
+type NotificationDelivery struct {
+	ID               int64
+	OrgID            int64
+	Receiver         string
+	Integration      string
+	IntegrationIndex int
+	GroupKey         string
+	GroupLabels      model.LabelSet
+	// Alerts are the alerts that were sent. They are kept so that the notification can be replayed.
+	Alerts []model.Alert
+	Status NotificationDeliveryStatus
+	// HTTPStatus is the status code of the last HTTP response received by the integration, or 0 if there was none.
+	HTTPStatus int
+	Error      string
+	Latency    time.Duration
+	// PayloadHash is the SHA-256 of the payloads handed over to the webhook and email senders, or empty if the integration does not use them.
+	PayloadHash string
+	// ReplayOf is the ID of the delivery that this delivery is a replay of, or 0 if it is not a replay.
+	ReplayOf  int64
+	Timestamp time.Time
+}
+
+// ListNotificationDeliveriesQuery is the query for the most recent notification deliveries of an organization.
+type ListNotificationDeliveriesQuery struct {
+	OrgID       int64
+	Receivers   []string
+	Integration string
+	Status      NotificationDeliveryStatus
+	From        time.Time
+	To          time.Time
+	Limit       int
+}
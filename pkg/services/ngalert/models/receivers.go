@@ -14,6 +14,7 @@ import (
 
 	alertingNotify "github.com/grafana/alerting/notify"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
 )
 
@@ -543,6 +544,9 @@ func ValidateIntegration(ctx context.Context, integration alertingNotify.Grafana
 	if integration.Settings == nil {
 		return fmt.Errorf("settings should not be empty")
 	}
+	if channels.IsSupported(integration.Type) {
+		return channels.ValidateIntegration(ctx, &integration, decryptFunc)
+	}
 
 	_, err := alertingNotify.BuildReceiverConfiguration(ctx, &alertingNotify.APIReceiver{
 		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	prommodels "github.com/prometheus/common/model"
+)
+
+// AlertRuleDependency declares that the alert instances of a rule are inhibited while the rule it depends on is firing.
+type AlertRuleDependency struct {
+	// RuleUID is the UID of the rule in the same organization that inhibits the dependent rule.
+	RuleUID string `json:"rule_uid"`
+	// Equal is the list of labels that must have the same values in the firing instance of the rule RuleUID
+	// and in the instance of the dependent rule. If empty, any firing instance inhibits all instances of the dependent rule.
+	Equal []string `json:"equal,omitempty"`
+}
+
+// Validate checks that the dependency refers to a rule and that all labels are valid label names.
+func (d AlertRuleDependency) Validate() error {
+	if d.RuleUID == "" {
+		return errors.New("rule UID cannot be empty")
+	}
+	for _, name := range d.Equal {
+		if !prommodels.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the labels of the instance of the rule RuleUID and the labels of the instance of the dependent rule
+// have the same values for all labels in Equal. A label that is missing in both instances is considered equal.
+func (d AlertRuleDependency) Matches(parent, dependent map[string]string) bool {
+	for _, name := range d.Equal {
+		if parent[name] != dependent[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// CopyAlertRuleDependency creates a deep copy of AlertRuleDependency.
+func CopyAlertRuleDependency(d AlertRuleDependency) AlertRuleDependency {
+	return AlertRuleDependency{
+		RuleUID: d.RuleUID,
+		Equal:   slices.Clone(d.Equal),
+	}
+}
+
+func validateDependencies(rule *AlertRule) error {
+	seen := make(map[string]struct{}, len(rule.Dependencies))
+	for idx, d := range rule.Dependencies {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid dependency at index %d: %s", ErrAlertRuleFailedValidation, idx, err)
+		}
+		if d.RuleUID == rule.UID {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := seen[d.RuleUID]; ok {
+			return fmt.Errorf("%w: rule %s is listed in dependencies more than once", ErrAlertRuleFailedValidation, d.RuleUID)
+		}
+		seen[d.RuleUID] = struct{}{}
+	}
+	return nil
+}
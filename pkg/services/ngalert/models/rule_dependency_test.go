@@ -0,0 +1,105 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleDependencyMatches(t *testing.T) {
+	testCases := []struct {
+		name      string
+		equal     []string
+		parent    map[string]string
+		dependent map[string]string
+		expected  bool
+	}{
+		{
+			name:      "matches any instance if equal is empty",
+			parent:    map[string]string{"cluster": "a"},
+			dependent: map[string]string{"cluster": "b"},
+			expected:  true,
+		},
+		{
+			name:      "matches if all labels are equal",
+			equal:     []string{"cluster", "namespace"},
+			parent:    map[string]string{"cluster": "a", "namespace": "ns", "alertname": "ClusterDown"},
+			dependent: map[string]string{"cluster": "a", "namespace": "ns", "alertname": "HighLatency"},
+			expected:  true,
+		},
+		{
+			name:      "does not match if a label is different",
+			equal:     []string{"cluster", "namespace"},
+			parent:    map[string]string{"cluster": "a", "namespace": "ns"},
+			dependent: map[string]string{"cluster": "b", "namespace": "ns"},
+			expected:  false,
+		},
+		{
+			name:      "does not match if a label is missing in one of the instances",
+			equal:     []string{"cluster"},
+			parent:    map[string]string{"cluster": "a"},
+			dependent: map[string]string{},
+			expected:  false,
+		},
+		{
+			name:      "matches if a label is missing in both instances",
+			equal:     []string{"cluster"},
+			parent:    map[string]string{},
+			dependent: map[string]string{},
+			expected:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := AlertRuleDependency{RuleUID: "parent", Equal: tc.equal}
+			require.Equal(t, tc.expected, d.Matches(tc.parent, tc.dependent))
+		})
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	testCases := []struct {
+		name         string
+		dependencies []AlertRuleDependency
+		expErr       string
+	}{
+		{
+			name: "valid dependencies",
+			dependencies: []AlertRuleDependency{
+				{RuleUID: "parent-1", Equal: []string{"cluster"}},
+				{RuleUID: "parent-2"},
+			},
+		},
+		{
+			name:         "empty rule UID",
+			dependencies: []AlertRuleDependency{{Equal: []string{"cluster"}}},
+			expErr:       "rule UID cannot be empty",
+		},
+		{
+			name:         "invalid label name",
+			dependencies: []AlertRuleDependency{{RuleUID: "parent", Equal: []string{"not a label"}}},
+			expErr:       `invalid label name "not a label"`,
+		},
+		{
+			name:         "depends on itself",
+			dependencies: []AlertRuleDependency{{RuleUID: "rule"}},
+			expErr:       "rule cannot depend on itself",
+		},
+		{
+			name:         "duplicate rule UID",
+			dependencies: []AlertRuleDependency{{RuleUID: "parent"}, {RuleUID: "parent", Equal: []string{"cluster"}}},
+			expErr:       "rule parent is listed in dependencies more than once",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateDependencies(&AlertRule{UID: "rule", Dependencies: tc.dependencies})
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
@@ -314,6 +314,30 @@ func (a *AlertRuleMutators) WithFor(duration time.Duration) AlertRuleMutator {
 	}
 }
 
+func (a *AlertRuleMutators) WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
+func (a *AlertRuleMutators) WithQueryOffset(offset time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.QueryOffset = offset
+	}
+}
+
+func (a *AlertRuleMutators) WithEvaluationLimits(limits AlertRuleEvaluationLimits) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.EvaluationLimits = limits
+	}
+}
+
+func (a *AlertRuleMutators) WithDependencies(dependencies ...AlertRuleDependency) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Dependencies = dependencies
+	}
+}
+
 func (a *AlertRuleMutators) WithForNTimes(timesOfInterval int64) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.For = time.Duration(rule.IntervalSeconds*timesOfInterval) * time.Second
@@ -387,6 +411,12 @@ func (a *AlertRuleMutators) WithDashboardAndPanel(dashboardUID *string, panelID
 //
 //	rule1 := RuleGen.With(WithUniqueUID()).Generate
 //	rule2 := RuleGen.With(WithUniqueUID()).Generate
+func (a *AlertRuleMutators) WithUID(uid string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.UID = uid
+	}
+}
+
 func (a *AlertRuleMutators) WithUniqueUID() AlertRuleMutator {
 	uids := sync.Map{}
 	return func(rule *AlertRule) {
@@ -523,6 +553,15 @@ func (a *AlertRuleMutators) WithMetric(metric string) AlertRuleMutator {
 	}
 }
 
+func (a *AlertRuleMutators) WithRecordingWriter(writer string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		if rule.Record == nil {
+			rule.Record = &Record{}
+		}
+		rule.Record.Writer = writer
+	}
+}
+
 func (a *AlertRuleMutators) WithRecordFrom(from string) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		if rule.Record == nil {
@@ -607,21 +646,24 @@ func GenerateGroupKey(orgID int64) AlertRuleGroupKey {
 // CopyRule creates a deep copy of AlertRule
 func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 	result := AlertRule{
-		ID:              r.ID,
-		OrgID:           r.OrgID,
-		Title:           r.Title,
-		Condition:       r.Condition,
-		Updated:         r.Updated,
-		IntervalSeconds: r.IntervalSeconds,
-		Version:         r.Version,
-		UID:             r.UID,
-		NamespaceUID:    r.NamespaceUID,
-		RuleGroup:       r.RuleGroup,
-		RuleGroupIndex:  r.RuleGroupIndex,
-		NoDataState:     r.NoDataState,
-		ExecErrState:    r.ExecErrState,
-		For:             r.For,
-		Record:          r.Record,
+		ID:               r.ID,
+		OrgID:            r.OrgID,
+		Title:            r.Title,
+		Condition:        r.Condition,
+		Updated:          r.Updated,
+		IntervalSeconds:  r.IntervalSeconds,
+		Version:          r.Version,
+		UID:              r.UID,
+		NamespaceUID:     r.NamespaceUID,
+		RuleGroup:        r.RuleGroup,
+		RuleGroupIndex:   r.RuleGroupIndex,
+		NoDataState:      r.NoDataState,
+		ExecErrState:     r.ExecErrState,
+		For:              r.For,
+		KeepFiringFor:    r.KeepFiringFor,
+		QueryOffset:      r.QueryOffset,
+		EvaluationLimits: r.EvaluationLimits,
+		Record:           r.Record,
 	}
 
 	if r.DashboardUID != nil {
@@ -663,6 +705,7 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		result.Record = &Record{
 			From:   r.Record.From,
 			Metric: r.Record.Metric,
+			Writer: r.Record.Writer,
 		}
 	}
 
@@ -670,6 +713,10 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	for _, d := range r.Dependencies {
+		result.Dependencies = append(result.Dependencies, CopyAlertRuleDependency(d))
+	}
+
 	if len(mutators) > 0 {
 		for _, mutator := range mutators {
 			mutator(&result)
@@ -1337,7 +1384,10 @@ func ConvertToRecordingRule(rule *AlertRule) {
 	rule.NoDataState = ""
 	rule.ExecErrState = ""
 	rule.For = 0
+	rule.KeepFiringFor = 0
 	rule.NotificationSettings = nil
+	rule.Dependencies = nil
+	rule.EvaluationLimits = AlertRuleEvaluationLimits{}
 }
 
 func nameToUid(name string) string { // Avoid legacy_storage.NameToUid import cycle.
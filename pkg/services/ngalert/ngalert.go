@@ -309,6 +309,8 @@ func (ng *AlertNG) init() error {
 		}
 	}
 
+	overrides = append(overrides, notifier.WithDeliveryLog(ng.store))
+
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
 	moa, err := notifier.NewMultiOrgAlertmanager(
@@ -367,7 +369,7 @@ func (ng *AlertNG) init() error {
 		// Force-disable the feature if the feature toggle is not on - sets us up for feature toggle removal.
 		ng.Cfg.UnifiedAlerting.RecordingRules.Enabled = false
 	}
-	recordingWriter, err := createRecordingWriter(ng.FeatureToggles, ng.Cfg.UnifiedAlerting.RecordingRules, ng.httpClientProvider, clk, ng.Metrics.GetRemoteWriterMetrics())
+	recordingWriter, err := createRecordingWriter(ng.FeatureToggles, ng.Cfg.UnifiedAlerting.RecordingRules, ng.httpClientProvider, ng.SQLStore, clk, ng.Metrics.GetRemoteWriterMetrics())
 	if err != nil {
 		return fmt.Errorf("failed to initialize recording writer: %w", err)
 	}
@@ -380,8 +382,10 @@ func (ng *AlertNG) init() error {
 		MinRuleInterval:      ng.Cfg.UnifiedAlerting.MinInterval,
 		DisableGrafanaFolder: ng.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
 		JitterEvaluations:    schedule.JitterStrategyFrom(ng.Cfg.UnifiedAlerting, ng.FeatureToggles),
+		QueryResultCache:     ng.Cfg.UnifiedAlerting.QueryResultCache,
 		AppURL:               appUrl,
 		EvaluatorFactory:     evalFactory,
+		EvaluationLimits:     ng.Cfg.UnifiedAlerting.EvaluationLimits,
 		RuleStore:            ng.store,
 		RecordingRulesCfg:    ng.Cfg.UnifiedAlerting.RecordingRules,
 		Metrics:              ng.Metrics.GetSchedulerMetrics(),
@@ -390,11 +394,21 @@ func (ng *AlertNG) init() error {
 		Log:                  log.New("ngalert.scheduler"),
 		RecordingWriter:      ng.RecordingWriter,
 	}
+	if ng.Cfg.UnifiedAlerting.HASchedulerSharding {
+		if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
+			// the periodic save overwrites the state of all rules, including the ones evaluated by other instances
+			ng.Log.Warn("Scheduler sharding is not supported when the state is saved periodically, every instance evaluates all rules")
+		} else if members := moa.ClusterMembers(); members == nil {
+			ng.Log.Warn("Scheduler sharding requires high availability to be configured, every instance evaluates all rules")
+		} else {
+			schedCfg.ClusterPeer = members
+		}
+	}
 
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
 	// If any are set, override the config accordingly.
 	ApplyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
-	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log, ng.tracer, ac.NewRuleService(ng.accesscontrol))
+	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.SQLStore, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log, ng.tracer, ac.NewRuleService(ng.accesscontrol))
 	if err != nil {
 		return err
 	}
@@ -584,7 +598,7 @@ type Historian interface {
 	state.Historian
 }
 
-func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore, met *metrics.Historian, l log.Logger, tracer tracing.Tracer, ac historian.AccessControl) (Historian, error) {
+func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, sqlStore db.DB, ds dashboards.DashboardService, rs historian.RuleStore, met *metrics.Historian, l log.Logger, tracer tracing.Tracer, ac historian.AccessControl) (Historian, error) {
 	if !cfg.Enabled {
 		met.Info.WithLabelValues("noop").Set(0)
 		return historian.NewNopHistorian(), nil
@@ -599,7 +613,7 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 	if backend == historian.BackendTypeMultiple {
 		primaryCfg := cfg
 		primaryCfg.Backend = cfg.MultiPrimary
-		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, ds, rs, met, l, tracer, ac)
+		primary, err := configureHistorianBackend(ctx, primaryCfg, ar, sqlStore, ds, rs, met, l, tracer, ac)
 		if err != nil {
 			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
 		}
@@ -608,7 +622,7 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		for _, b := range cfg.MultiSecondaries {
 			secCfg := cfg
 			secCfg.Backend = b
-			sec, err := configureHistorianBackend(ctx, secCfg, ar, ds, rs, met, l, tracer, ac)
+			sec, err := configureHistorianBackend(ctx, secCfg, ar, sqlStore, ds, rs, met, l, tracer, ac)
 			if err != nil {
 				return nil, fmt.Errorf("multi-backend target \"%s\" was miconfigured: %w", b, err)
 			}
@@ -639,6 +653,10 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		}
 		return backend, nil
 	}
+	if backend == historian.BackendTypeSQL {
+		sqlBackendLogger := log.New("ngalert.state.historian", "backend", "sql")
+		return historian.NewSQLBackend(sqlBackendLogger, sqlStore, cfg.SQLRetention, met, rs, ac), nil
+	}
 
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
@@ -692,12 +710,56 @@ func createRemoteAlertmanager(cfg remote.AlertmanagerConfig, kvstore kvstore.KVS
 	return remote.NewAlertmanager(cfg, notifier.NewFileStore(cfg.OrgID, kvstore), decryptFn, autogenFn, m, tracer)
 }
 
-func createRecordingWriter(featureToggles featuremgmt.FeatureToggles, settings setting.RecordingRuleSettings, httpClientProvider httpclient.Provider, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
+func createRecordingWriter(featureToggles featuremgmt.FeatureToggles, settings setting.RecordingRuleSettings, httpClientProvider httpclient.Provider, store db.DB, clock clock.Clock, m *metrics.RemoteWriter) (schedule.RecordingWriter, error) {
 	logger := log.New("ngalert.writer")
 
-	if settings.Enabled {
-		return writer.NewPrometheusWriter(settings, httpClientProvider, clock, logger, m)
+	defaultType := settings.DefaultWriter
+	if defaultType == "" {
+		defaultType = models.RecordingWriterPrometheus
+	}
+	if !settings.Enabled {
+		// Rules can select any writer, see models.EnabledRecordingWriterTypes.
+		registry := writer.NewRegistry(defaultType, writer.NoopWriter{})
+		for _, writerType := range models.RecordingWriterTypes {
+			registry.Register(writerType, writer.NoopWriter{})
+		}
+		return registry, nil
 	}
 
-	return writer.NoopWriter{}, nil
+	// Only the configured writers are registered, rules that select other writers are rejected by validation.
+	writers := make(map[string]writer.Writer)
+	if settings.URL != "" {
+		prom, err := writer.NewPrometheusWriter(settings, httpClientProvider, clock, logger.New("writer", models.RecordingWriterPrometheus), m)
+		if err != nil {
+			return nil, err
+		}
+		writers[models.RecordingWriterPrometheus] = prom
+	}
+	if settings.SQL.Enabled {
+		writers[models.RecordingWriterSQL] = writer.NewSQLWriter(store, settings.SQL.Retention, clock, logger.New("writer", models.RecordingWriterSQL), m)
+	}
+	if settings.OTLP.Enabled {
+		otlp, err := writer.NewOTLPWriter(settings.OTLP, httpClientProvider, clock, logger.New("writer", models.RecordingWriterOTLP), m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize OTLP writer: %w", err)
+		}
+		writers[models.RecordingWriterOTLP] = otlp
+	}
+	if settings.InfluxDB.Enabled {
+		influxDB, err := writer.NewInfluxDBWriter(settings.InfluxDB, httpClientProvider, clock, logger.New("writer", models.RecordingWriterInfluxDB), m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize InfluxDB writer: %w", err)
+		}
+		writers[models.RecordingWriterInfluxDB] = influxDB
+	}
+
+	defaultWriter, ok := writers[defaultType]
+	if !ok {
+		return nil, fmt.Errorf("default writer '%s' of recording rules is not enabled", defaultType)
+	}
+	registry := writer.NewRegistry(defaultType, defaultWriter)
+	for writerType, w := range writers {
+		registry.Register(writerType, w)
+	}
+	return registry, nil
 }
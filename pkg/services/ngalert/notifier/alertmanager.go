@@ -20,6 +20,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
@@ -60,6 +61,9 @@ type alertmanager struct {
 	orgID     int64
 
 	withAutogen bool
+
+	// deliveryLog records the notification attempts of the integrations. It is nil when the delivery log is disabled.
+	deliveryLog *deliveryLog
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -167,6 +171,9 @@ func (am *alertmanager) Ready() bool {
 
 func (am *alertmanager) StopAndWait() {
 	am.Base.StopAndWait()
+	if am.deliveryLog != nil {
+		am.deliveryLog.stopAndWait()
+	}
 }
 
 // SaveAndApplyDefaultConfig saves the default configuration to the database and applies it to the Alertmanager.
@@ -342,6 +349,12 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 	}
 
 	am.logger.Info("Applying new configuration to Alertmanager", "configHash", fmt.Sprintf("%x", configHash))
+	buildIntegrations := buildIntegrationsFunc(am.buildReceiverIntegrations)
+	if am.deliveryLog != nil {
+		var done func(applied bool)
+		buildIntegrations, done = am.deliveryLog.instrument(buildIntegrations)
+		defer func() { done(err == nil) }()
+	}
 	err = am.Base.ApplyConfig(AlertingConfiguration{
 		rawAlertmanagerConfig:    rawConfig,
 		configHash:               configHash,
@@ -351,7 +364,7 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 		timeIntervals:            cfg.AlertmanagerConfig.TimeIntervals,
 		templates:                ToTemplateDefinitions(cfg),
 		receivers:                PostableApiAlertingConfigToApiReceivers(cfg.AlertmanagerConfig),
-		receiverIntegrationsFunc: am.buildReceiverIntegrations,
+		receiverIntegrationsFunc: buildIntegrations,
 	})
 	if err != nil {
 		return false, err
@@ -379,17 +392,38 @@ func (am *alertmanager) applyAndMarkConfig(ctx context.Context, hash string, cfg
 	return nil
 }
 
+// ReplayNotificationDelivery sends the alerts of the notification delivery again with the integration that sent them.
+// The new attempt is recorded in the delivery log and returned, whether it succeeded or not.
+func (am *alertmanager) ReplayNotificationDelivery(ctx context.Context, d *ngmodels.NotificationDelivery) (*ngmodels.NotificationDelivery, error) {
+	if am.deliveryLog == nil {
+		return nil, ErrDeliveryLogDisabled
+	}
+	return am.deliveryLog.replay(ctx, d)
+}
+
 func (am *alertmanager) AppURL() string {
 	return am.Settings.AppURL
 }
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
-	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
+	s := &sender{am.NotificationService}
+	// Integrations that are implemented in Grafana are built separately because the alerting library does not know about them.
+	grafanaIntegrations, err := channels.BuildReceiverIntegrations(context.Background(), receiver, tmpl, am.decryptFn, s, LoggerFactory)
+	if err != nil {
+		return nil, err
+	}
+	libraryReceiver := *receiver
+	libraryReceiver.Integrations = make([]*alertingNotify.GrafanaIntegrationConfig, 0, len(receiver.Integrations))
+	for _, integration := range receiver.Integrations {
+		if !channels.IsSupported(integration.Type) {
+			libraryReceiver.Integrations = append(libraryReceiver.Integrations, integration)
+		}
+	}
+	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), &libraryReceiver, am.decryptFn)
 	if err != nil {
 		return nil, err
 	}
-	s := &sender{am.NotificationService}
 	img := newImageProvider(am.Store, log.New("ngalert.notifier.image-provider"))
 	integrations, err := alertingNotify.BuildReceiverIntegrations(
 		receiverCfg,
@@ -408,7 +442,7 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
-	return integrations, nil
+	return append(integrations, grafanaIntegrations...), nil
 }
 
 // PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not
@@ -0,0 +1,155 @@
+// Package channels contains the contact point integrations that are implemented in Grafana rather than in the alerting library.
+package channels
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/jira"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/matrix"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/rocketchat"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/zulip"
+)
+
+type notificationChannel interface {
+	notify.Notifier
+	notify.ResolvedSender
+}
+
+// IsSupported returns true if the integration type is implemented by this package.
+func IsSupported(integrationType string) bool {
+	switch strings.ToLower(integrationType) {
+	case "zulip", "matrix", "rocketchat", "jira":
+		return true
+	}
+	return false
+}
+
+// ValidateIntegration parses, decrypts and validates the settings of the integration.
+func ValidateIntegration(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) error {
+	_, err := newNotifier(ctx, integration, decrypt, nil, nil, &logging.FakeLogger{})
+	if err != nil {
+		return alertingNotify.IntegrationValidationError{
+			Integration: integration,
+			Err:         err,
+		}
+	}
+	return nil
+}
+
+// BuildReceiverIntegrations builds the integrations of the receiver whose type is implemented by this package.
+// Integrations of other types are ignored.
+func BuildReceiverIntegrations(
+	ctx context.Context,
+	receiver *alertingNotify.APIReceiver,
+	tmpl *templates.Template,
+	decrypt alertingNotify.GetDecryptedValueFn,
+	sender receivers.WebhookSender,
+	logger logging.LoggerFactory,
+) ([]*alertingNotify.Integration, error) {
+	var (
+		integrations []*alertingNotify.Integration
+		errs         types.MultiError
+		// Like in the alerting library, integrations are indexed by their position among the integrations of the same type.
+		indexes = make(map[string]int)
+	)
+	for _, cfg := range receiver.Integrations {
+		if !IsSupported(cfg.Type) {
+			continue
+		}
+		n, err := newNotifier(ctx, cfg, decrypt, tmpl, sender, logger("ngalert.notifier."+cfg.Type, "notifierUID", cfg.UID))
+		if err != nil {
+			errs.Add(alertingNotify.IntegrationValidationError{
+				Integration: cfg,
+				Err:         err,
+			})
+			continue
+		}
+		integrations = append(integrations, alertingNotify.NewIntegration(n, n, cfg.Type, indexes[cfg.Type], cfg.Name))
+		indexes[cfg.Type]++
+	}
+	if errs.Len() > 0 {
+		return nil, &errs
+	}
+	return integrations, nil
+}
+
+func newNotifier(
+	ctx context.Context,
+	cfg *alertingNotify.GrafanaIntegrationConfig,
+	decrypt alertingNotify.GetDecryptedValueFn,
+	tmpl *templates.Template,
+	sender receivers.WebhookSender,
+	logger logging.Logger,
+) (notificationChannel, error) {
+	secureSettings := decodeSecureSettings(cfg.SecureSettings)
+	decryptFn := func(key string, fallback string) string {
+		return decrypt(ctx, secureSettings, key, fallback)
+	}
+	meta := receivers.Metadata{
+		UID:                   cfg.UID,
+		Name:                  cfg.Name,
+		Type:                  cfg.Type,
+		DisableResolveMessage: cfg.DisableResolveMessage,
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case "zulip":
+		settings, err := zulip.NewConfig(cfg.Settings, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		return zulip.New(settings, meta, tmpl, sender, logger), nil
+	case "matrix":
+		settings, err := matrix.NewConfig(cfg.Settings, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		return matrix.New(settings, meta, tmpl, sender, logger), nil
+	case "rocketchat":
+		settings, err := rocketchat.NewConfig(cfg.Settings, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		return rocketchat.New(settings, meta, tmpl, sender, logger), nil
+	case "jira":
+		settings, err := jira.NewConfig(cfg.Settings, decryptFn)
+		if err != nil {
+			return nil, err
+		}
+		return jira.New(settings, meta, tmpl, sender, logger), nil
+	}
+	return nil, fmt.Errorf("notifier %s is not supported", cfg.Type)
+}
+
+// decodeSecureSettings decodes the base64-encoded secure settings of the integration.
+// Like in the alerting library, secure settings that are not base64-encoded are used as is.
+func decodeSecureSettings(secrets map[string]string) map[string][]byte {
+	decoded := make(map[string][]byte, len(secrets))
+	for k, v := range secrets {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return rawSecureSettings(secrets)
+		}
+		decoded[k] = d
+	}
+	return decoded
+}
+
+func rawSecureSettings(secrets map[string]string) map[string][]byte {
+	raw := make(map[string][]byte, len(secrets))
+	for k, v := range secrets {
+		raw[k] = []byte(v)
+	}
+	return raw
+}
@@ -0,0 +1,103 @@
+package channels
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/jira"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/matrix"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/rocketchat"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels/zulip"
+)
+
+var allConfigsForTesting = map[string]string{
+	"jira":       jira.FullValidConfigForTesting,
+	"matrix":     matrix.FullValidConfigForTesting,
+	"rocketchat": rocketchat.FullValidConfigForTesting,
+	"zulip":      zulip.FullValidConfigForTesting,
+}
+
+func decryptForTesting(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+	return receiversTesting.DecryptForTesting(sjd)(key, fallback)
+}
+
+func TestValidateIntegration(t *testing.T) {
+	for integrationType, settings := range allConfigsForTesting {
+		t.Run(integrationType, func(t *testing.T) {
+			require.True(t, IsSupported(integrationType))
+			err := ValidateIntegration(context.Background(), &alertingNotify.GrafanaIntegrationConfig{
+				Type:     integrationType,
+				Settings: json.RawMessage(settings),
+			}, decryptForTesting)
+			require.NoError(t, err)
+
+			err = ValidateIntegration(context.Background(), &alertingNotify.GrafanaIntegrationConfig{
+				Type:     integrationType,
+				Settings: json.RawMessage(`{}`),
+			}, decryptForTesting)
+			var validationErr alertingNotify.IntegrationValidationError
+			require.ErrorAs(t, err, &validationErr)
+		})
+	}
+
+	t.Run("should fail if the type is not supported", func(t *testing.T) {
+		require.False(t, IsSupported("slack"))
+		err := ValidateIntegration(context.Background(), &alertingNotify.GrafanaIntegrationConfig{
+			Type:     "slack",
+			Settings: json.RawMessage(`{}`),
+		}, decryptForTesting)
+		require.ErrorContains(t, err, "notifier slack is not supported")
+	})
+
+	t.Run("should use base64-encoded and raw secure settings", func(t *testing.T) {
+		settings := `{"url": "https://example.zulipchat.com", "bot_email": "bot@example.com", "stream": "alerts"}`
+		for _, secret := range []string{base64.StdEncoding.EncodeToString([]byte("key")), "not base64!"} {
+			err := ValidateIntegration(context.Background(), &alertingNotify.GrafanaIntegrationConfig{
+				Type:           "zulip",
+				Settings:       json.RawMessage(settings),
+				SecureSettings: map[string]string{"api_key": secret},
+			}, decryptForTesting)
+			require.NoError(t, err)
+		}
+	})
+}
+
+func TestBuildReceiverIntegrations(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+				{Name: "test", Type: "zulip", Settings: json.RawMessage(zulip.FullValidConfigForTesting)},
+				{Name: "test", Type: "slack", Settings: json.RawMessage(`{}`)},
+				{Name: "test", Type: "matrix", Settings: json.RawMessage(matrix.FullValidConfigForTesting)},
+				{Name: "test", Type: "zulip", Settings: json.RawMessage(zulip.FullValidConfigForTesting)},
+			},
+		},
+	}
+	loggerFactory := func(string, ...interface{}) logging.Logger { return &logging.FakeLogger{} }
+
+	integrations, err := BuildReceiverIntegrations(context.Background(), receiver, templates.ForTests(t), decryptForTesting, receivers.MockNotificationService(), loggerFactory)
+	require.NoError(t, err)
+	require.Len(t, integrations, 3)
+	require.Equal(t, "zulip[0]", integrations[0].String())
+	require.Equal(t, "matrix[0]", integrations[1].String())
+	require.Equal(t, "zulip[1]", integrations[2].String())
+
+	t.Run("should fail if an integration is invalid", func(t *testing.T) {
+		receiver.Integrations = append(receiver.Integrations, &alertingNotify.GrafanaIntegrationConfig{
+			Name:     "test",
+			Type:     "jira",
+			Settings: json.RawMessage(`{}`),
+		})
+		_, err := BuildReceiverIntegrations(context.Background(), receiver, templates.ForTests(t), decryptForTesting, receivers.MockNotificationService(), loggerFactory)
+		require.ErrorContains(t, err, "could not find api_url in settings")
+	})
+}
@@ -0,0 +1,60 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+type Config struct {
+	URL                      string   `json:"api_url" yaml:"api_url"`
+	User                     string   `json:"user,omitempty" yaml:"user,omitempty"`
+	Password                 string   `json:"password,omitempty" yaml:"password,omitempty"`
+	AuthorizationCredentials string   `json:"authorization_credentials,omitempty" yaml:"authorization_credentials,omitempty"`
+	Project                  string   `json:"project" yaml:"project"`
+	IssueType                string   `json:"issue_type" yaml:"issue_type"`
+	Summary                  string   `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description              string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Labels                   []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Priority                 string   `json:"priority,omitempty" yaml:"priority,omitempty"`
+	ResolveTransition        string   `json:"resolve_transition,omitempty" yaml:"resolve_transition,omitempty"`
+}
+
+// NewConfig is the constructor for the Jira notifier.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	var settings Config
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if settings.URL == "" {
+		return Config{}, errors.New("could not find api_url in settings")
+	}
+	if _, err := url.Parse(settings.URL); err != nil {
+		return Config{}, fmt.Errorf("invalid api_url: %w", err)
+	}
+	settings.Password = decryptFn("password", settings.Password)
+	settings.AuthorizationCredentials = decryptFn("authorization_credentials", settings.AuthorizationCredentials)
+	if settings.AuthorizationCredentials != "" && (settings.User != "" || settings.Password != "") {
+		return Config{}, errors.New("both basic authentication and authorization credentials are set, only one of them can be used")
+	}
+	if settings.AuthorizationCredentials == "" && (settings.User == "" || settings.Password == "") {
+		return Config{}, errors.New("either user and password or authorization credentials must be set")
+	}
+	if settings.Project == "" {
+		return Config{}, errors.New("could not find project in settings")
+	}
+	if settings.IssueType == "" {
+		return Config{}, errors.New("could not find issue_type in settings")
+	}
+	if settings.Summary == "" {
+		settings.Summary = templates.DefaultMessageTitleEmbed
+	}
+	if settings.Description == "" {
+		settings.Description = templates.DefaultMessageEmbed
+	}
+	return settings, nil
+}
@@ -0,0 +1,197 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+// Jira limits from https://confluence.atlassian.com/jirakb/jira-cloud-issue-field-limits-1300535785.html
+const (
+	jiraMaxSummaryLenRunes     = 255
+	jiraMaxDescriptionLenRunes = 32767
+)
+
+// Notifier is responsible for creating Jira issues for groups of firing alerts and transitioning them when the alerts are resolved.
+// The issue of a group of alerts is identified by a label that contains the hash of the group key.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	settings Config
+}
+
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+type issue struct {
+	Key         string       `json:"key"`
+	Transitions []transition `json:"transitions,omitempty"`
+}
+
+type transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Notify creates or updates the Jira issue of the group of alerts if they are firing, and transitions it if they are resolved.
+func (jn *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	jn.log.Debug("executing Jira notification", "notification", jn.Name)
+
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	groupLabel := fmt.Sprintf("ALERT{%s}", key.Hash())
+
+	existing, err := jn.searchOpenIssue(ctx, groupLabel)
+	if err != nil {
+		return false, fmt.Errorf("failed to search for the Jira issue of the alerts: %w", err)
+	}
+
+	if types.Alerts(as...).Status() == model.AlertResolved {
+		if existing == nil || jn.settings.ResolveTransition == "" {
+			jn.log.Debug("alerts are resolved, there is no issue to transition", "group_label", groupLabel)
+			return true, nil
+		}
+		if err := jn.transitionIssue(ctx, existing, jn.settings.ResolveTransition); err != nil {
+			return false, fmt.Errorf("failed to transition Jira issue %s: %w", existing.Key, err)
+		}
+		return true, nil
+	}
+
+	fields := jn.buildFields(ctx, as, groupLabel, existing == nil)
+	if existing != nil {
+		if err := jn.send(ctx, http.MethodPut, "/issue/"+existing.Key, map[string]any{"fields": fields}, nil); err != nil {
+			return false, fmt.Errorf("failed to update Jira issue %s: %w", existing.Key, err)
+		}
+		return true, nil
+	}
+
+	var created issue
+	if err := jn.send(ctx, http.MethodPost, "/issue", map[string]any{"fields": fields}, &created); err != nil {
+		return false, fmt.Errorf("failed to create Jira issue: %w", err)
+	}
+	jn.log.Debug("created Jira issue", "issue", created.Key, "group_label", groupLabel)
+	return true, nil
+}
+
+// buildFields returns the fields of the issue. The project, type, labels and priority are only set when the issue is created,
+// so that changes made to them in Jira are not overwritten.
+func (jn *Notifier) buildFields(ctx context.Context, as []*types.Alert, groupLabel string, create bool) map[string]any {
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, jn.tmpl, as, jn.log, &tmplErr)
+
+	summary, truncated := receivers.TruncateInRunes(tmpl(jn.settings.Summary), jiraMaxSummaryLenRunes)
+	if truncated {
+		jn.log.Warn("Truncated summary", "group_label", groupLabel, "max_runes", jiraMaxSummaryLenRunes)
+	}
+	description, truncated := receivers.TruncateInRunes(tmpl(jn.settings.Description), jiraMaxDescriptionLenRunes)
+	if truncated {
+		jn.log.Warn("Truncated description", "group_label", groupLabel, "max_runes", jiraMaxDescriptionLenRunes)
+	}
+
+	fields := map[string]any{
+		"summary":     summary,
+		"description": description,
+	}
+	if create {
+		labels := make([]string, 0, len(jn.settings.Labels)+1)
+		for _, l := range jn.settings.Labels {
+			// Jira labels cannot contain spaces.
+			if l = strings.ReplaceAll(tmpl(l), " ", "_"); l != "" {
+				labels = append(labels, l)
+			}
+		}
+		fields["project"] = map[string]string{"key": jn.settings.Project}
+		fields["issuetype"] = map[string]string{"name": jn.settings.IssueType}
+		fields["labels"] = append(labels, groupLabel)
+		if priority := tmpl(jn.settings.Priority); priority != "" {
+			fields["priority"] = map[string]string{"name": priority}
+		}
+	}
+	if tmplErr != nil {
+		jn.log.Warn("failed to template Jira issue", "error", tmplErr.Error())
+	}
+	return fields
+}
+
+// searchOpenIssue returns the most recent issue of the group of alerts that is not done, or nil if there is none.
+func (jn *Notifier) searchOpenIssue(ctx context.Context, groupLabel string) (*issue, error) {
+	query := map[string]any{
+		"jql":        fmt.Sprintf(`project = %q AND labels = %q AND statusCategory != Done ORDER BY created DESC`, jn.settings.Project, groupLabel),
+		"fields":     []string{"status"},
+		"expand":     []string{"transitions"},
+		"maxResults": 1,
+	}
+	var result struct {
+		Issues []issue `json:"issues"`
+	}
+	if err := jn.send(ctx, http.MethodPost, "/search", query, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Issues) == 0 {
+		return nil, nil
+	}
+	return &result.Issues[0], nil
+}
+
+func (jn *Notifier) transitionIssue(ctx context.Context, i *issue, name string) error {
+	for _, t := range i.Transitions {
+		if strings.EqualFold(t.Name, name) {
+			return jn.send(ctx, http.MethodPost, "/issue/"+i.Key+"/transitions", map[string]any{"transition": map[string]string{"id": t.ID}}, nil)
+		}
+	}
+	return fmt.Errorf("transition %q is not available", name)
+}
+
+// send sends a request to the Jira REST API and decodes the response into result if it is not nil.
+func (jn *Notifier) send(ctx context.Context, method, path string, payload any, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	cmd := &receivers.SendWebhookSettings{
+		URL:        strings.TrimSuffix(jn.settings.URL, "/") + path,
+		HTTPMethod: method,
+		Body:       string(body),
+		User:       jn.settings.User,
+		Password:   jn.settings.Password,
+	}
+	if jn.settings.AuthorizationCredentials != "" {
+		cmd.HTTPHeader = map[string]string{
+			"Authorization": fmt.Sprintf("Bearer %s", jn.settings.AuthorizationCredentials),
+		}
+	}
+	if result != nil {
+		cmd.Validation = func(body []byte, statusCode int) error {
+			if statusCode/100 != 2 {
+				return fmt.Errorf("unexpected status code %d: %s", statusCode, body)
+			}
+			return json.Unmarshal(body, result)
+		}
+	}
+	return jn.ns.SendWebhook(ctx, cmd)
+}
+
+func (jn *Notifier) SendResolved() bool {
+	return !jn.GetDisableResolveMessage()
+}
@@ -0,0 +1,233 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+)
+
+func TestNewConfig(t *testing.T) {
+	cases := []struct {
+		name           string
+		settings       string
+		secrets        map[string][]byte
+		expectedConfig Config
+		expectedErr    string
+	}{
+		{
+			name:        "Error if empty",
+			settings:    "",
+			expectedErr: "failed to unmarshal settings",
+		},
+		{
+			name:        "Error if api_url is missing",
+			settings:    `{}`,
+			expectedErr: "could not find api_url in settings",
+		},
+		{
+			name:        "Error if no authentication",
+			settings:    `{"api_url": "https://example.atlassian.net/rest/api/2", "project": "OPS", "issue_type": "Bug"}`,
+			expectedErr: "either user and password or authorization credentials must be set",
+		},
+		{
+			name:        "Error if both basic authentication and authorization credentials",
+			settings:    `{"api_url": "https://example.atlassian.net/rest/api/2", "user": "user", "password": "password", "authorization_credentials": "token", "project": "OPS", "issue_type": "Bug"}`,
+			expectedErr: "both basic authentication and authorization credentials are set",
+		},
+		{
+			name:        "Error if project is missing",
+			settings:    `{"api_url": "https://example.atlassian.net/rest/api/2", "authorization_credentials": "token", "issue_type": "Bug"}`,
+			expectedErr: "could not find project in settings",
+		},
+		{
+			name:        "Error if issue_type is missing",
+			settings:    `{"api_url": "https://example.atlassian.net/rest/api/2", "authorization_credentials": "token", "project": "OPS"}`,
+			expectedErr: "could not find issue_type in settings",
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"api_url": "https://example.atlassian.net/rest/api/2", "authorization_credentials": "token", "project": "OPS", "issue_type": "Bug"}`,
+			expectedConfig: Config{
+				URL:                      "https://example.atlassian.net/rest/api/2",
+				AuthorizationCredentials: "token",
+				Project:                  "OPS",
+				IssueType:                "Bug",
+				Summary:                  templates.DefaultMessageTitleEmbed,
+				Description:              templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name:     "Extracts all fields and uses secrets",
+			settings: FullValidConfigForTesting,
+			secrets:  receiversTesting.ReadSecretsJSONForTesting(FullValidSecretsForTesting),
+			expectedConfig: Config{
+				URL:               "https://example.atlassian.net/rest/api/2",
+				User:              "alerts@example.com",
+				Password:          "test-secret-password",
+				Project:           "OPS",
+				IssueType:         "Incident",
+				Summary:           "test-summary",
+				Description:       "test-description",
+				Labels:            []string{"grafana", "{{ .CommonLabels.team }}"},
+				Priority:          "High",
+				ResolveTransition: "Done",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secrets))
+			if c.expectedErr != "" {
+				require.ErrorContains(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+// fakeJira records the requests and replies to them with the response registered for their URL.
+type fakeJira struct {
+	calls     []receivers.SendWebhookSettings
+	responses map[string]string
+}
+
+func (f *fakeJira) SendWebhook(_ context.Context, cmd *receivers.SendWebhookSettings) error {
+	f.calls = append(f.calls, *cmd)
+	if cmd.Validation == nil {
+		return nil
+	}
+	return cmd.Validation([]byte(f.responses[cmd.URL]), 200)
+}
+
+func (f *fakeJira) SendEmail(context.Context, *receivers.SendEmailSettings) error {
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	const apiURL = "https://example.atlassian.net/rest/api/2"
+
+	cfg := Config{
+		URL:               apiURL,
+		User:              "user",
+		Password:          "password",
+		Project:           "OPS",
+		IssueType:         "Bug",
+		Summary:           `{{ .CommonLabels.alertname }}`,
+		Description:       `{{ len .Alerts.Firing }} firing`,
+		Labels:            []string{"{{ .CommonLabels.team }}"},
+		Priority:          "High",
+		ResolveTransition: "done",
+	}
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	require.NoError(t, err)
+	groupLabel := "ALERT{" + groupKey.Hash() + "}"
+
+	firing := &types.Alert{
+		Alert: model.Alert{Labels: model.LabelSet{"alertname": "alert1", "team": "sre team"}},
+	}
+	resolved := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "alert1", "team": "sre team"},
+			StartsAt: time.Now().Add(-2 * time.Hour),
+			EndsAt:   time.Now().Add(-time.Hour),
+		},
+	}
+
+	t.Run("should create an issue if there is no open issue for the group", func(t *testing.T) {
+		sender := &fakeJira{responses: map[string]string{
+			apiURL + "/search": `{"issues": []}`,
+			apiURL + "/issue":  `{"key": "OPS-1"}`,
+		}}
+		n := New(cfg, receivers.Metadata{}, templates.ForTests(t), sender, &logging.FakeLogger{})
+
+		ok, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Len(t, sender.calls, 2)
+		require.Equal(t, "POST", sender.calls[0].HTTPMethod)
+		require.Contains(t, sender.calls[0].Body, groupLabel)
+		require.Equal(t, "user", sender.calls[0].User)
+		require.Equal(t, "password", sender.calls[0].Password)
+
+		require.Equal(t, apiURL+"/issue", sender.calls[1].URL)
+		require.Equal(t, "POST", sender.calls[1].HTTPMethod)
+		require.JSONEq(t, `{"fields": {
+			"project": {"key": "OPS"},
+			"issuetype": {"name": "Bug"},
+			"summary": "alert1",
+			"description": "1 firing",
+			"labels": ["sre_team", "`+groupLabel+`"],
+			"priority": {"name": "High"}
+		}}`, sender.calls[1].Body)
+	})
+
+	t.Run("should update the open issue of the group", func(t *testing.T) {
+		sender := &fakeJira{responses: map[string]string{
+			apiURL + "/search": `{"issues": [{"key": "OPS-1"}]}`,
+		}}
+		n := New(cfg, receivers.Metadata{}, templates.ForTests(t), sender, &logging.FakeLogger{})
+
+		ok, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Len(t, sender.calls, 2)
+		require.Equal(t, apiURL+"/issue/OPS-1", sender.calls[1].URL)
+		require.Equal(t, "PUT", sender.calls[1].HTTPMethod)
+		require.JSONEq(t, `{"fields": {"summary": "alert1", "description": "1 firing"}}`, sender.calls[1].Body)
+	})
+
+	t.Run("should transition the open issue when alerts are resolved", func(t *testing.T) {
+		sender := &fakeJira{responses: map[string]string{
+			apiURL + "/search": `{"issues": [{"key": "OPS-1", "transitions": [{"id": "11", "name": "In Progress"}, {"id": "31", "name": "Done"}]}]}`,
+		}}
+		n := New(cfg, receivers.Metadata{}, templates.ForTests(t), sender, &logging.FakeLogger{})
+
+		ok, err := n.Notify(ctx, resolved)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Len(t, sender.calls, 2)
+		require.Equal(t, apiURL+"/issue/OPS-1/transitions", sender.calls[1].URL)
+		require.JSONEq(t, `{"transition": {"id": "31"}}`, sender.calls[1].Body)
+	})
+
+	t.Run("should fail if the resolve transition is not available", func(t *testing.T) {
+		sender := &fakeJira{responses: map[string]string{
+			apiURL + "/search": `{"issues": [{"key": "OPS-1", "transitions": [{"id": "11", "name": "In Progress"}]}]}`,
+		}}
+		n := New(cfg, receivers.Metadata{}, templates.ForTests(t), sender, &logging.FakeLogger{})
+
+		ok, err := n.Notify(ctx, resolved)
+		require.ErrorContains(t, err, `transition "done" is not available`)
+		require.False(t, ok)
+	})
+
+	t.Run("should do nothing when alerts are resolved and there is no open issue", func(t *testing.T) {
+		sender := &fakeJira{responses: map[string]string{
+			apiURL + "/search": `{"issues": []}`,
+		}}
+		n := New(cfg, receivers.Metadata{}, templates.ForTests(t), sender, &logging.FakeLogger{})
+
+		ok, err := n.Notify(ctx, resolved)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Len(t, sender.calls, 1)
+	})
+}
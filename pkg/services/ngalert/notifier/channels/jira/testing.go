@@ -0,0 +1,20 @@
+package jira
+
+// FullValidConfigForTesting is a string representation of a JSON object that contains all fields supported by the notifier Config. It can be used without secrets.
+const FullValidConfigForTesting = `{
+	"api_url": "https://example.atlassian.net/rest/api/2",
+	"user": "alerts@example.com",
+	"password": "test-password",
+	"project": "OPS",
+	"issue_type": "Incident",
+	"summary": "test-summary",
+	"description": "test-description",
+	"labels": ["grafana", "{{ .CommonLabels.team }}"],
+	"priority": "High",
+	"resolve_transition": "Done"
+}`
+
+// FullValidSecretsForTesting is a string representation of JSON object that contains all fields that can be overridden from secrets
+const FullValidSecretsForTesting = `{
+	"password": "test-secret-password"
+}`
@@ -0,0 +1,61 @@
+package matrix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+// Matrix message types supported by the notifier. Notices are meant for bots and do not trigger other bots.
+const (
+	MessageTypeNotice = "m.notice"
+	MessageTypeText   = "m.text"
+)
+
+type Config struct {
+	HomeserverURL string `json:"homeserver_url" yaml:"homeserver_url"`
+	RoomID        string `json:"room_id" yaml:"room_id"`
+	AccessToken   string `json:"access_token" yaml:"access_token"`
+	MessageType   string `json:"msgtype,omitempty" yaml:"msgtype,omitempty"`
+	Title         string `json:"title,omitempty" yaml:"title,omitempty"`
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// NewConfig is the constructor for the Matrix notifier.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	var settings Config
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if settings.HomeserverURL == "" {
+		return Config{}, errors.New("could not find homeserver_url in settings")
+	}
+	if _, err := url.Parse(settings.HomeserverURL); err != nil {
+		return Config{}, fmt.Errorf("invalid homeserver_url: %w", err)
+	}
+	if settings.RoomID == "" {
+		return Config{}, errors.New("could not find room_id in settings")
+	}
+	settings.AccessToken = decryptFn("access_token", settings.AccessToken)
+	if settings.AccessToken == "" {
+		return Config{}, errors.New("could not find access_token in settings")
+	}
+	switch settings.MessageType {
+	case "":
+		settings.MessageType = MessageTypeNotice
+	case MessageTypeNotice, MessageTypeText:
+	default:
+		return Config{}, fmt.Errorf("invalid msgtype %q, must be %s or %s", settings.MessageType, MessageTypeNotice, MessageTypeText)
+	}
+	if settings.Title == "" {
+		settings.Title = templates.DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = templates.DefaultMessageEmbed
+	}
+	return settings, nil
+}
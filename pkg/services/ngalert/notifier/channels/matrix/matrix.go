@@ -0,0 +1,108 @@
+package matrix
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+// Notifier is responsible for sending alert notifications to a Matrix room.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	settings Config
+}
+
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+// matrixMessage is the content of a m.room.message event.
+type matrixMessage struct {
+	MsgType string `json:"msgtype"`
+	Body    string `json:"body"`
+}
+
+// Notify sends an alert notification to Matrix as a message event in the configured room.
+func (mn *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	mn.log.Debug("executing Matrix notification", "notification", mn.Name)
+
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, mn.tmpl, as, mn.log, &tmplErr)
+
+	msg := matrixMessage{
+		MsgType: mn.settings.MessageType,
+		Body:    fmt.Sprintf("%s\n%s", tmpl(mn.settings.Title), tmpl(mn.settings.Message)),
+	}
+	if tmplErr != nil {
+		mn.log.Warn("failed to template Matrix message", "error", tmplErr.Error())
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	txnID, err := mn.transactionID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// The transaction ID makes retries of the same notification idempotent.
+	// See https://spec.matrix.org/latest/client-server-api/#put_matrixclientv3roomsroomidsendeventtypetxnid
+	cmd := &receivers.SendWebhookSettings{
+		URL: fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+			strings.TrimSuffix(mn.settings.HomeserverURL, "/"), url.PathEscape(mn.settings.RoomID), txnID),
+		HTTPMethod: http.MethodPut,
+		HTTPHeader: map[string]string{
+			"Authorization": fmt.Sprintf("Bearer %s", mn.settings.AccessToken),
+		},
+		Body: string(body),
+	}
+
+	if err := mn.ns.SendWebhook(ctx, cmd); err != nil {
+		return false, fmt.Errorf("failed to send notification to Matrix: %w", err)
+	}
+
+	return true, nil
+}
+
+// transactionID returns an identifier of the notification that is the same for all attempts to send it.
+// It is derived from the group of alerts and the time at which the group was flushed.
+func (mn *Notifier) transactionID(ctx context.Context) (string, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = time.Now()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", mn.UID, key.Hash(), now.UnixNano())))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+func (mn *Notifier) SendResolved() bool {
+	return !mn.GetDisableResolveMessage()
+}
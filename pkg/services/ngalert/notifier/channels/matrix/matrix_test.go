@@ -0,0 +1,134 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+)
+
+func TestNewConfig(t *testing.T) {
+	cases := []struct {
+		name           string
+		settings       string
+		secrets        map[string][]byte
+		expectedConfig Config
+		expectedErr    string
+	}{
+		{
+			name:        "Error if empty",
+			settings:    "",
+			expectedErr: "failed to unmarshal settings",
+		},
+		{
+			name:        "Error if homeserver_url is missing",
+			settings:    `{"room_id": "!alerts:example.com", "access_token": "token"}`,
+			expectedErr: "could not find homeserver_url in settings",
+		},
+		{
+			name:        "Error if room_id is missing",
+			settings:    `{"homeserver_url": "https://matrix.example.com", "access_token": "token"}`,
+			expectedErr: "could not find room_id in settings",
+		},
+		{
+			name:        "Error if access_token is missing",
+			settings:    `{"homeserver_url": "https://matrix.example.com", "room_id": "!alerts:example.com"}`,
+			expectedErr: "could not find access_token in settings",
+		},
+		{
+			name:        "Error if msgtype is not supported",
+			settings:    `{"homeserver_url": "https://matrix.example.com", "room_id": "!alerts:example.com", "access_token": "token", "msgtype": "m.image"}`,
+			expectedErr: `invalid msgtype "m.image"`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"homeserver_url": "https://matrix.example.com", "room_id": "!alerts:example.com", "access_token": "token"}`,
+			expectedConfig: Config{
+				HomeserverURL: "https://matrix.example.com",
+				RoomID:        "!alerts:example.com",
+				AccessToken:   "token",
+				MessageType:   MessageTypeNotice,
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name:     "Extracts all fields and uses secrets",
+			settings: FullValidConfigForTesting,
+			secrets:  receiversTesting.ReadSecretsJSONForTesting(FullValidSecretsForTesting),
+			expectedConfig: Config{
+				HomeserverURL: "https://matrix.example.com",
+				RoomID:        "!alerts:example.com",
+				AccessToken:   "test-secret-access-token",
+				MessageType:   MessageTypeText,
+				Title:         "test-title",
+				Message:       "test-message",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secrets))
+			if c.expectedErr != "" {
+				require.ErrorContains(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	webhookSender := receivers.MockNotificationService()
+	n := New(Config{
+		HomeserverURL: "https://matrix.example.com/",
+		RoomID:        "!alerts:example.com",
+		AccessToken:   "token",
+		MessageType:   MessageTypeNotice,
+		Title:         `{{ .CommonLabels.alertname }}`,
+		Message:       `{{ len .Alerts.Firing }} firing`,
+	}, receivers.Metadata{UID: "uid"}, tmpl, webhookSender, &logging.FakeLogger{})
+
+	alert := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "alert1"}}}
+	ctx := notify.WithNow(notify.WithGroupKey(context.Background(), "alertname"), time.Unix(1000, 0))
+
+	ok, err := n.Notify(ctx, alert)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.Equal(t, http.MethodPut, webhookSender.Webhook.HTTPMethod)
+	require.Equal(t, "Bearer token", webhookSender.Webhook.HTTPHeader["Authorization"])
+	require.True(t, strings.HasPrefix(webhookSender.Webhook.URL, "https://matrix.example.com/_matrix/client/v3/rooms/%21alerts:example.com/send/m.room.message/"))
+	require.JSONEq(t, `{"msgtype": "m.notice", "body": "alert1\n1 firing"}`, webhookSender.Webhook.Body)
+
+	t.Run("retries of the same notification use the same transaction", func(t *testing.T) {
+		first := webhookSender.Webhook.URL
+		_, err := n.Notify(ctx, alert)
+		require.NoError(t, err)
+		require.Equal(t, first, webhookSender.Webhook.URL)
+
+		_, err = n.Notify(notify.WithNow(ctx, time.Unix(2000, 0)), alert)
+		require.NoError(t, err)
+		require.NotEqual(t, first, webhookSender.Webhook.URL)
+	})
+}
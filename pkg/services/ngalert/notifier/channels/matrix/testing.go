@@ -0,0 +1,16 @@
+package matrix
+
+// FullValidConfigForTesting is a string representation of a JSON object that contains all fields supported by the notifier Config. It can be used without secrets.
+const FullValidConfigForTesting = `{
+	"homeserver_url": "https://matrix.example.com",
+	"room_id": "!alerts:example.com",
+	"access_token": "test-access-token",
+	"msgtype": "m.text",
+	"title": "test-title",
+	"message": "test-message"
+}`
+
+// FullValidSecretsForTesting is a string representation of JSON object that contains all fields that can be overridden from secrets
+const FullValidSecretsForTesting = `{
+	"access_token": "test-secret-access-token"
+}`
@@ -0,0 +1,42 @@
+package rocketchat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+type Config struct {
+	URL      string `json:"url" yaml:"url"`
+	Channel  string `json:"channel,omitempty" yaml:"channel,omitempty"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	IconURL  string `json:"icon_url,omitempty" yaml:"icon_url,omitempty"`
+	Title    string `json:"title,omitempty" yaml:"title,omitempty"`
+	Message  string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// NewConfig is the constructor for the Rocket.Chat notifier.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	var settings Config
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	settings.URL = decryptFn("url", settings.URL)
+	if settings.URL == "" {
+		return Config{}, errors.New("could not find url in settings")
+	}
+	if _, err := url.Parse(settings.URL); err != nil {
+		return Config{}, fmt.Errorf("invalid url: %w", err)
+	}
+	if settings.Title == "" {
+		settings.Title = templates.DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = templates.DefaultMessageEmbed
+	}
+	return settings, nil
+}
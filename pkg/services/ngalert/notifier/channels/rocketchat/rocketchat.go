@@ -0,0 +1,98 @@
+package rocketchat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+// Notifier is responsible for sending alert notifications to Rocket.Chat using an incoming webhook.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	settings Config
+}
+
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+// rocketchatMessage is the payload of a Rocket.Chat incoming webhook.
+// See https://docs.rocket.chat/use-rocket.chat/workspace-administration/integrations
+type rocketchatMessage struct {
+	Text        string                 `json:"text"`
+	Channel     string                 `json:"channel,omitempty"`
+	Alias       string                 `json:"alias,omitempty"`
+	Avatar      string                 `json:"avatar,omitempty"`
+	Attachments []rocketchatAttachment `json:"attachments"`
+}
+
+type rocketchatAttachment struct {
+	Title     string `json:"title,omitempty"`
+	TitleLink string `json:"title_link,omitempty"`
+	Text      string `json:"text"`
+	Color     string `json:"color"`
+}
+
+// Notify sends an alert notification to Rocket.Chat.
+func (rn *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	rn.log.Debug("executing Rocket.Chat notification", "notification", rn.Name)
+
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, rn.tmpl, as, rn.log, &tmplErr)
+
+	title := tmpl(rn.settings.Title)
+	msg := rocketchatMessage{
+		Text:    title,
+		Channel: tmpl(rn.settings.Channel),
+		Alias:   tmpl(rn.settings.Username),
+		Avatar:  tmpl(rn.settings.IconURL),
+		Attachments: []rocketchatAttachment{
+			{
+				Title:     title,
+				TitleLink: receivers.JoinURLPath(rn.tmpl.ExternalURL.String(), "/alerting/list", rn.log),
+				Text:      tmpl(rn.settings.Message),
+				Color:     receivers.GetAlertStatusColor(types.Alerts(as...).Status()),
+			},
+		},
+	}
+	if tmplErr != nil {
+		rn.log.Warn("failed to template Rocket.Chat message", "error", tmplErr.Error())
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	cmd := &receivers.SendWebhookSettings{
+		URL:        rn.settings.URL,
+		HTTPMethod: http.MethodPost,
+		Body:       string(body),
+	}
+
+	if err := rn.ns.SendWebhook(ctx, cmd); err != nil {
+		return false, fmt.Errorf("failed to send notification to Rocket.Chat: %w", err)
+	}
+
+	return true, nil
+}
+
+func (rn *Notifier) SendResolved() bool {
+	return !rn.GetDisableResolveMessage()
+}
@@ -0,0 +1,109 @@
+package rocketchat
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+)
+
+func TestNewConfig(t *testing.T) {
+	cases := []struct {
+		name           string
+		settings       string
+		secrets        map[string][]byte
+		expectedConfig Config
+		expectedErr    string
+	}{
+		{
+			name:        "Error if empty",
+			settings:    "",
+			expectedErr: "failed to unmarshal settings",
+		},
+		{
+			name:        "Error if url is missing",
+			settings:    `{}`,
+			expectedErr: "could not find url in settings",
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "https://rocketchat.example.com/hooks/token"}`,
+			expectedConfig: Config{
+				URL:     "https://rocketchat.example.com/hooks/token",
+				Title:   templates.DefaultMessageTitleEmbed,
+				Message: templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name:     "Extracts all fields and uses secrets",
+			settings: FullValidConfigForTesting,
+			secrets:  receiversTesting.ReadSecretsJSONForTesting(FullValidSecretsForTesting),
+			expectedConfig: Config{
+				URL:      "https://rocketchat.example.com/hooks/test-secret-token",
+				Channel:  "#alerts",
+				Username: "Grafana",
+				IconURL:  "https://grafana.com/static/assets/img/fav32.png",
+				Title:    "test-title",
+				Message:  "test-message",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secrets))
+			if c.expectedErr != "" {
+				require.ErrorContains(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	webhookSender := receivers.MockNotificationService()
+	n := New(Config{
+		URL:      "https://rocketchat.example.com/hooks/token",
+		Channel:  "#alerts",
+		Username: "Grafana",
+		Title:    `{{ .CommonLabels.alertname }}`,
+		Message:  `{{ len .Alerts.Firing }} firing`,
+	}, receivers.Metadata{}, tmpl, webhookSender, &logging.FakeLogger{})
+
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ok, err := n.Notify(ctx, &types.Alert{
+		Alert: model.Alert{Labels: model.LabelSet{"alertname": "alert1"}},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.Equal(t, "https://rocketchat.example.com/hooks/token", webhookSender.Webhook.URL)
+	require.JSONEq(t, `{
+		"text": "alert1",
+		"channel": "#alerts",
+		"alias": "Grafana",
+		"attachments": [{
+			"title": "alert1",
+			"title_link": "http://localhost/alerting/list",
+			"text": "1 firing",
+			"color": "#D63232"
+		}]
+	}`, webhookSender.Webhook.Body)
+}
@@ -0,0 +1,16 @@
+package rocketchat
+
+// FullValidConfigForTesting is a string representation of a JSON object that contains all fields supported by the notifier Config. It can be used without secrets.
+const FullValidConfigForTesting = `{
+	"url": "https://rocketchat.example.com/hooks/test-token",
+	"channel": "#alerts",
+	"username": "Grafana",
+	"icon_url": "https://grafana.com/static/assets/img/fav32.png",
+	"title": "test-title",
+	"message": "test-message"
+}`
+
+// FullValidSecretsForTesting is a string representation of JSON object that contains all fields that can be overridden from secrets
+const FullValidSecretsForTesting = `{
+	"url": "https://rocketchat.example.com/hooks/test-secret-token"
+}`
@@ -0,0 +1,51 @@
+package zulip
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+type Config struct {
+	URL      string `json:"url" yaml:"url"`
+	BotEmail string `json:"bot_email" yaml:"bot_email"`
+	APIKey   string `json:"api_key" yaml:"api_key"`
+	Stream   string `json:"stream" yaml:"stream"`
+	Topic    string `json:"topic,omitempty" yaml:"topic,omitempty"`
+	Message  string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// NewConfig is the constructor for the Zulip notifier.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	var settings Config
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+	if settings.URL == "" {
+		return Config{}, errors.New("could not find url in settings")
+	}
+	if _, err := url.Parse(settings.URL); err != nil {
+		return Config{}, fmt.Errorf("invalid url: %w", err)
+	}
+	if settings.BotEmail == "" {
+		return Config{}, errors.New("could not find bot_email in settings")
+	}
+	settings.APIKey = decryptFn("api_key", settings.APIKey)
+	if settings.APIKey == "" {
+		return Config{}, errors.New("could not find api_key in settings")
+	}
+	if settings.Stream == "" {
+		return Config{}, errors.New("could not find stream in settings")
+	}
+	if settings.Topic == "" {
+		settings.Topic = templates.DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = templates.DefaultMessageEmbed
+	}
+	return settings, nil
+}
@@ -0,0 +1,16 @@
+package zulip
+
+// FullValidConfigForTesting is a string representation of a JSON object that contains all fields supported by the notifier Config. It can be used without secrets.
+const FullValidConfigForTesting = `{
+	"url": "https://example.zulipchat.com",
+	"bot_email": "alerts-bot@example.zulipchat.com",
+	"api_key": "test-api-key",
+	"stream": "alerts",
+	"topic": "test-topic",
+	"message": "test-message"
+}`
+
+// FullValidSecretsForTesting is a string representation of JSON object that contains all fields that can be overridden from secrets
+const FullValidSecretsForTesting = `{
+	"api_key": "test-secret-api-key"
+}`
@@ -0,0 +1,94 @@
+package zulip
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+// Zulip limits from https://zulip.com/api/send-message
+const (
+	zulipMaxTopicLenRunes   = 60
+	zulipMaxMessageLenBytes = 10000
+)
+
+// Notifier is responsible for sending alert notifications to a Zulip stream.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	settings Config
+}
+
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+// Notify sends an alert notification to Zulip as a message in the topic of the configured stream.
+func (zn *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	zn.log.Debug("executing Zulip notification", "notification", zn.Name)
+
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, zn.tmpl, as, zn.log, &tmplErr)
+
+	topic, truncated := receivers.TruncateInRunes(tmpl(zn.settings.Topic), zulipMaxTopicLenRunes)
+	if truncated {
+		key, err := notify.ExtractGroupKey(ctx)
+		if err != nil {
+			return false, err
+		}
+		zn.log.Warn("Truncated topic", "key", key, "max_runes", zulipMaxTopicLenRunes)
+	}
+	message, truncated := receivers.TruncateInBytes(tmpl(zn.settings.Message), zulipMaxMessageLenBytes)
+	if truncated {
+		key, err := notify.ExtractGroupKey(ctx)
+		if err != nil {
+			return false, err
+		}
+		zn.log.Warn("Truncated message", "key", key, "max_bytes", zulipMaxMessageLenBytes)
+	}
+	if tmplErr != nil {
+		zn.log.Warn("failed to template Zulip message", "error", tmplErr.Error())
+	}
+
+	form := url.Values{}
+	form.Add("type", "stream")
+	form.Add("to", zn.settings.Stream)
+	form.Add("topic", topic)
+	form.Add("content", message)
+
+	cmd := &receivers.SendWebhookSettings{
+		URL:         strings.TrimSuffix(zn.settings.URL, "/") + "/api/v1/messages",
+		User:        zn.settings.BotEmail,
+		Password:    zn.settings.APIKey,
+		HTTPMethod:  http.MethodPost,
+		ContentType: "application/x-www-form-urlencoded",
+		Body:        form.Encode(),
+	}
+
+	if err := zn.ns.SendWebhook(ctx, cmd); err != nil {
+		return false, fmt.Errorf("failed to send notification to Zulip: %w", err)
+	}
+
+	return true, nil
+}
+
+func (zn *Notifier) SendResolved() bool {
+	return !zn.GetDisableResolveMessage()
+}
@@ -0,0 +1,122 @@
+package zulip
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	receiversTesting "github.com/grafana/alerting/receivers/testing"
+	"github.com/grafana/alerting/templates"
+)
+
+func TestNewConfig(t *testing.T) {
+	cases := []struct {
+		name           string
+		settings       string
+		secrets        map[string][]byte
+		expectedConfig Config
+		expectedErr    string
+	}{
+		{
+			name:        "Error if empty",
+			settings:    "",
+			expectedErr: "failed to unmarshal settings",
+		},
+		{
+			name:        "Error if url is missing",
+			settings:    `{"bot_email": "bot@example.com", "api_key": "key", "stream": "alerts"}`,
+			expectedErr: "could not find url in settings",
+		},
+		{
+			name:        "Error if api_key is missing",
+			settings:    `{"url": "https://example.zulipchat.com", "bot_email": "bot@example.com", "stream": "alerts"}`,
+			expectedErr: "could not find api_key in settings",
+		},
+		{
+			name:        "Error if stream is missing",
+			settings:    `{"url": "https://example.zulipchat.com", "bot_email": "bot@example.com", "api_key": "key"}`,
+			expectedErr: "could not find stream in settings",
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "https://example.zulipchat.com", "bot_email": "bot@example.com", "api_key": "key", "stream": "alerts"}`,
+			expectedConfig: Config{
+				URL:      "https://example.zulipchat.com",
+				BotEmail: "bot@example.com",
+				APIKey:   "key",
+				Stream:   "alerts",
+				Topic:    templates.DefaultMessageTitleEmbed,
+				Message:  templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name:     "Extracts all fields and uses secrets",
+			settings: FullValidConfigForTesting,
+			secrets:  receiversTesting.ReadSecretsJSONForTesting(FullValidSecretsForTesting),
+			expectedConfig: Config{
+				URL:      "https://example.zulipchat.com",
+				BotEmail: "alerts-bot@example.zulipchat.com",
+				APIKey:   "test-secret-api-key",
+				Stream:   "alerts",
+				Topic:    "test-topic",
+				Message:  "test-message",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewConfig(json.RawMessage(c.settings), receiversTesting.DecryptForTesting(c.secrets))
+			if c.expectedErr != "" {
+				require.ErrorContains(t, err, c.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	webhookSender := receivers.MockNotificationService()
+	n := New(Config{
+		URL:      "https://example.zulipchat.com/",
+		BotEmail: "bot@example.com",
+		APIKey:   "key",
+		Stream:   "alerts",
+		Topic:    `{{ .CommonLabels.alertname }}`,
+		Message:  `{{ len .Alerts.Firing }} firing`,
+	}, receivers.Metadata{}, tmpl, webhookSender, &logging.FakeLogger{})
+
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ok, err := n.Notify(ctx, &types.Alert{
+		Alert: model.Alert{Labels: model.LabelSet{"alertname": "alert1"}},
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.Equal(t, "https://example.zulipchat.com/api/v1/messages", webhookSender.Webhook.URL)
+	require.Equal(t, "bot@example.com", webhookSender.Webhook.User)
+	require.Equal(t, "key", webhookSender.Webhook.Password)
+	form, err := url.ParseQuery(webhookSender.Webhook.Body)
+	require.NoError(t, err)
+	require.Equal(t, url.Values{
+		"type":    {"stream"},
+		"to":      {"alerts"},
+		"topic":   {"alert1"},
+		"content": {"1 firing"},
+	}, form)
+}
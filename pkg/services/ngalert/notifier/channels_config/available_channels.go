@@ -1699,6 +1699,280 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "zulip",
+			Name:        "Zulip",
+			Description: "Sends notifications to a Zulip stream",
+			Heading:     "Zulip settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Zulip URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://example.zulipchat.com",
+					Description:  "The URL of the Zulip server.",
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:        "Bot email",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The email address of the bot that sends the messages.",
+					PropertyName: "bot_email",
+					Required:     true,
+				},
+				{
+					Label:        "API key",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The API key of the bot.",
+					PropertyName: "api_key",
+					Secure:       true,
+					Required:     true,
+				},
+				{
+					Label:        "Stream",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "alerts",
+					Description:  "The stream to send messages to.",
+					PropertyName: "stream",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+					Description:  "Templated topic of the message. Topics longer than 60 characters are truncated.",
+					PropertyName: "topic",
+				},
+				{
+					Label:        "Message",
+					Element:      ElementTypeTextArea,
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+					Description:  "Templated content of the message. Markdown is supported.",
+					PropertyName: "message",
+				},
+			},
+		},
+		{
+			Type:        "matrix",
+			Name:        "Matrix",
+			Description: "Sends notifications to a Matrix room",
+			Heading:     "Matrix settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Homeserver URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://matrix.example.com",
+					Description:  "The URL of the homeserver of the bot.",
+					PropertyName: "homeserver_url",
+					Required:     true,
+				},
+				{
+					Label:        "Room ID",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "!alerts:example.com",
+					Description:  "The ID of the room to send messages to. The bot must have joined the room.",
+					PropertyName: "room_id",
+					Required:     true,
+				},
+				{
+					Label:        "Access token",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The access token of the bot.",
+					PropertyName: "access_token",
+					Secure:       true,
+					Required:     true,
+				},
+				{
+					Label:   "Message type",
+					Element: ElementTypeSelect,
+					SelectOptions: []SelectOption{
+						{
+							Value: "m.notice",
+							Label: "Notice",
+						},
+						{
+							Value: "m.text",
+							Label: "Text",
+						},
+					},
+					Description:  "Notices are not expected to be answered by bots.",
+					PropertyName: "msgtype",
+				},
+				{
+					Label:        "Title",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+					Description:  "Templated title of the message.",
+					PropertyName: "title",
+				},
+				{
+					Label:        "Message",
+					Element:      ElementTypeTextArea,
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+					Description:  "Templated content of the message.",
+					PropertyName: "message",
+				},
+			},
+		},
+		{
+			Type:        "rocketchat",
+			Name:        "Rocket.Chat",
+			Description: "Sends notifications to Rocket.Chat via an incoming webhook",
+			Heading:     "Rocket.Chat settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Webhook URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://rocketchat.example.com/hooks/xxx",
+					Description:  "The URL of the incoming webhook integration.",
+					PropertyName: "url",
+					Secure:       true,
+					Required:     true,
+				},
+				{
+					Label:        "Channel",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "#alerts",
+					Description:  "Optional channel or user to send messages to. Overrides the channel of the webhook integration.",
+					PropertyName: "channel",
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Optional name to display as the sender of the messages.",
+					PropertyName: "username",
+				},
+				{
+					Label:        "Icon URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Optional URL of the image to display as the avatar of the sender.",
+					PropertyName: "icon_url",
+				},
+				{
+					Label:        "Title",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+					Description:  "Templated title of the message.",
+					PropertyName: "title",
+				},
+				{
+					Label:        "Message",
+					Element:      ElementTypeTextArea,
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+					Description:  "Templated content of the message.",
+					PropertyName: "message",
+				},
+			},
+		},
+		{
+			Type:        "jira",
+			Name:        "Jira",
+			Description: "Creates Jira issues for firing alerts and transitions them when the alerts are resolved",
+			Heading:     "Jira settings",
+			Options: []NotifierOption{
+				{
+					Label:        "API URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://example.atlassian.net/rest/api/2",
+					Description:  "The URL of the Jira REST API.",
+					PropertyName: "api_url",
+					Required:     true,
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The user to authenticate with. Required together with the password if no authorization credentials are set.",
+					PropertyName: "user",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					Description:  "The password or API token of the user.",
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Authorization Header - Credentials",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "A personal access token that is sent as a bearer token. Cannot be used together with the username and password.",
+					PropertyName: "authorization_credentials",
+					Secure:       true,
+				},
+				{
+					Label:        "Project key",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "OPS",
+					Description:  "The key of the project in which issues are created.",
+					PropertyName: "project",
+					Required:     true,
+				},
+				{
+					Label:        "Issue type",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "Bug",
+					Description:  "The type of the issues that are created.",
+					PropertyName: "issue_type",
+					Required:     true,
+				},
+				{
+					Label:        "Summary",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+					Description:  "Templated summary of the issue. Summaries longer than 255 characters are truncated.",
+					PropertyName: "summary",
+				},
+				{
+					Label:        "Description",
+					Element:      ElementTypeTextArea,
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+					Description:  "Templated description of the issue.",
+					PropertyName: "description",
+				},
+				{
+					Label:        "Labels",
+					Element:      ElementStringArray,
+					Description:  "Templated labels that are added to the issues that are created. Spaces are replaced with underscores.",
+					PropertyName: "labels",
+				},
+				{
+					Label:        "Priority",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "High",
+					Description:  "Templated name of the priority of the issues that are created.",
+					PropertyName: "priority",
+				},
+				{
+					Label:        "Resolve transition",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "Done",
+					Description:  "The name of the transition to apply to the issue when the alerts are resolved. If empty, the issue is left as is.",
+					PropertyName: "resolve_transition",
+				},
+			},
+		},
 	}
 }
 
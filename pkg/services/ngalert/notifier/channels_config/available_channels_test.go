@@ -32,6 +32,10 @@ func TestGetSecretKeysForContactPointType(t *testing.T) {
 		{receiverType: "opsgenie", expectedSecretFields: []string{"apiKey"}},
 		{receiverType: "webex", expectedSecretFields: []string{"bot_token"}},
 		{receiverType: "sns", expectedSecretFields: []string{"sigv4.access_key", "sigv4.secret_key"}},
+		{receiverType: "zulip", expectedSecretFields: []string{"api_key"}},
+		{receiverType: "matrix", expectedSecretFields: []string{"access_token"}},
+		{receiverType: "rocketchat", expectedSecretFields: []string{"url"}},
+		{receiverType: "jira", expectedSecretFields: []string{"password", "authorization_credentials"}},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.receiverType, func(t *testing.T) {
@@ -0,0 +1,366 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// deliveryWriteTimeout is the maximum time spent saving a notification delivery.
+	deliveryWriteTimeout = 5 * time.Second
+	// deliveryCleanupInterval is how often the deliveries that are older than the retention are deleted.
+	deliveryCleanupInterval = 10 * time.Minute
+	// deliveryQueueSize is the maximum number of deliveries waiting to be saved.
+	// Deliveries are dropped when the queue is full, so that a slow database does not delay notifications.
+	deliveryQueueSize = 1000
+)
+
+var (
+	ErrDeliveryLogDisabled         = errors.New("notification delivery log is disabled")
+	ErrDeliveryReplayNotSupported  = errors.New("the Alertmanager of the organization does not support replaying notifications")
+	ErrDeliveryIntegrationNotFound = errors.New("the integration of the notification delivery does not exist in the current configuration")
+	ErrDeliveryNotFailed           = errors.New("only failed notification deliveries can be replayed")
+)
+
+// DeliveryStore persists the notification deliveries.
+type DeliveryStore interface {
+	SaveNotificationDelivery(ctx context.Context, d *ngmodels.NotificationDelivery) error
+	GetNotificationDelivery(ctx context.Context, orgID int64, id int64) (*ngmodels.NotificationDelivery, error)
+	ListNotificationDeliveries(ctx context.Context, query ngmodels.ListNotificationDeliveriesQuery) ([]*ngmodels.NotificationDelivery, error)
+	DeleteNotificationDeliveriesBefore(ctx context.Context, orgID int64, before time.Time) (int64, error)
+}
+
+// DeliveryReplayer is implemented by the Alertmanagers that can send a notification of the delivery log again.
+type DeliveryReplayer interface {
+	ReplayNotificationDelivery(ctx context.Context, d *ngmodels.NotificationDelivery) (*ngmodels.NotificationDelivery, error)
+}
+
+type buildIntegrationsFunc func(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error)
+
+type integrationKey struct {
+	receiver    string
+	integration string
+	index       int
+}
+
+// deliveryLog records the notification attempts of the integrations of an Alertmanager.
+// The attempts are saved in the background, see run.
+type deliveryLog struct {
+	store     DeliveryStore
+	orgID     int64
+	retention time.Duration
+	logger    log.Logger
+
+	queue    chan *ngmodels.NotificationDelivery
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	stopped  chan struct{}
+
+	mtx sync.Mutex
+	// recorders are the recorders of the integrations of the applied configuration.
+	recorders map[integrationKey]*deliveryRecorder
+}
+
+// newDeliveryLog creates a delivery log and starts saving the notification attempts in the background.
+// Call stopAndWait to stop it.
+func newDeliveryLog(orgID int64, store DeliveryStore, retention time.Duration) *deliveryLog {
+	l := &deliveryLog{
+		store:     store,
+		orgID:     orgID,
+		retention: retention,
+		logger:    log.New("ngalert.notifier.delivery-log", "org", orgID),
+		queue:     make(chan *ngmodels.NotificationDelivery, deliveryQueueSize),
+		stopCh:    make(chan struct{}),
+		stopped:   make(chan struct{}),
+		recorders: make(map[integrationKey]*deliveryRecorder),
+	}
+	go l.run()
+	return l
+}
+
+// run saves the queued deliveries and periodically deletes the deliveries that are older than the retention,
+// until stopAndWait is called. The deliveries that are still queued when it is called are saved before returning.
+func (l *deliveryLog) run() {
+	defer close(l.stopped)
+	ticker := time.NewTicker(deliveryCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case d := <-l.queue:
+			l.save(d)
+		case <-ticker.C:
+			l.deleteExpired()
+		case <-l.stopCh:
+			for {
+				select {
+				case d := <-l.queue:
+					l.save(d)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// stopAndWait stops saving deliveries and waits until the queued deliveries are saved.
+// Deliveries that are recorded afterwards are dropped.
+func (l *deliveryLog) stopAndWait() {
+	l.stopOnce.Do(func() { close(l.stopCh) })
+	<-l.stopped
+}
+
+// instrument returns a function that builds the integrations with build and records their notification attempts.
+// The integrations built before done is called with true replace the integrations whose notifications can be replayed.
+// Integrations built after done is called, for example to test receivers, are returned as built and not recorded.
+func (l *deliveryLog) instrument(build buildIntegrationsFunc) (buildIntegrationsFunc, func(applied bool)) {
+	var (
+		mtx       sync.Mutex
+		sealed    bool
+		recorders = make(map[integrationKey]*deliveryRecorder)
+	)
+	instrumented := func(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+		integrations, err := build(receiver, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		if sealed {
+			return integrations, nil
+		}
+		result := make([]*alertingNotify.Integration, 0, len(integrations))
+		for _, i := range integrations {
+			r := &deliveryRecorder{
+				upstream:    i,
+				log:         l,
+				receiver:    receiver.Name,
+				integration: i.Name(),
+				index:       i.Index(),
+			}
+			result = append(result, alertingNotify.NewIntegration(r, i, i.Name(), i.Index(), receiver.Name))
+			recorders[integrationKey{receiver: receiver.Name, integration: i.Name(), index: i.Index()}] = r
+		}
+		return result, nil
+	}
+	done := func(applied bool) {
+		mtx.Lock()
+		sealed = true
+		mtx.Unlock()
+		if !applied {
+			return
+		}
+		l.mtx.Lock()
+		l.recorders = recorders
+		l.mtx.Unlock()
+	}
+	return instrumented, done
+}
+
+func (l *deliveryLog) recorder(receiver, integration string, index int) *deliveryRecorder {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.recorders[integrationKey{receiver: receiver, integration: integration, index: index}]
+}
+
+// enqueue queues the delivery to be saved in the background. The delivery is dropped if the queue is full.
+func (l *deliveryLog) enqueue(d *ngmodels.NotificationDelivery) {
+	select {
+	case l.queue <- d:
+	default:
+		l.logger.Warn("Dropped notification delivery because too many deliveries are waiting to be saved", "receiver", d.Receiver, "integration", d.Integration)
+	}
+}
+
+// save saves the delivery. Errors are logged because they must not fail the notification.
+func (l *deliveryLog) save(d *ngmodels.NotificationDelivery) {
+	// Detached context here is to make sure that the delivery is saved even if the notification context is canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryWriteTimeout)
+	defer cancel()
+	if err := l.store.SaveNotificationDelivery(ctx, d); err != nil {
+		l.logger.Error("Failed to save notification delivery", "receiver", d.Receiver, "integration", d.Integration, "error", err)
+	}
+}
+
+// deleteExpired deletes the deliveries that are older than the retention.
+func (l *deliveryLog) deleteExpired() {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryWriteTimeout)
+	defer cancel()
+	deleted, err := l.store.DeleteNotificationDeliveriesBefore(ctx, l.orgID, time.Now().Add(-l.retention))
+	if err != nil {
+		l.logger.Error("Failed to delete expired notification deliveries", "error", err)
+		return
+	}
+	l.logger.Debug("Deleted expired notification deliveries", "count", deleted)
+}
+
+// replay sends the alerts of the failed delivery again with the integration that sent them, and records the new attempt.
+// Unlike the attempts of the notification pipeline, the new attempt is saved before returning, so that it has an ID.
+func (l *deliveryLog) replay(ctx context.Context, d *ngmodels.NotificationDelivery) (*ngmodels.NotificationDelivery, error) {
+	if d.Status != ngmodels.NotificationDeliveryStatusFailure {
+		return nil, ErrDeliveryNotFailed
+	}
+	r := l.recorder(d.Receiver, d.Integration, d.IntegrationIndex)
+	if r == nil {
+		return nil, ErrDeliveryIntegrationNotFound
+	}
+
+	now := time.Now()
+	alerts := make([]*types.Alert, 0, len(d.Alerts))
+	for _, a := range d.Alerts {
+		alerts = append(alerts, &types.Alert{Alert: a, UpdatedAt: now})
+	}
+	ctx = notify.WithGroupKey(ctx, d.GroupKey)
+	ctx = notify.WithGroupLabels(ctx, d.GroupLabels)
+	ctx = notify.WithReceiverName(ctx, d.Receiver)
+	ctx = notify.WithNow(ctx, now)
+
+	_, replayed, _ := r.notify(ctx, d.ID, alerts...)
+	l.save(replayed)
+	return replayed, nil
+}
+
+// deliveryRecorder records the notification attempts of an integration in the delivery log.
+type deliveryRecorder struct {
+	upstream    notify.Notifier
+	log         *deliveryLog
+	receiver    string
+	integration string
+	index       int
+}
+
+// Notify implements the Notifier interface.
+func (r *deliveryRecorder) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	retry, d, err := r.notify(ctx, 0, alerts...)
+	r.log.enqueue(d)
+	return retry, err
+}
+
+// notify sends the alerts with the upstream integration and returns the attempt. The attempt is not saved.
+func (r *deliveryRecorder) notify(ctx context.Context, replayOf int64, alerts ...*types.Alert) (bool, *ngmodels.NotificationDelivery, error) {
+	attempt := newDeliveryAttempt()
+	start := time.Now()
+	retry, err := r.upstream.Notify(withDeliveryAttempt(ctx, attempt), alerts...)
+
+	groupKey, _ := notify.GroupKey(ctx)
+	groupLabels, _ := notify.GroupLabels(ctx)
+	d := &ngmodels.NotificationDelivery{
+		OrgID:            r.log.orgID,
+		Receiver:         r.receiver,
+		Integration:      r.integration,
+		IntegrationIndex: r.index,
+		GroupKey:         groupKey,
+		GroupLabels:      groupLabels,
+		Alerts:           make([]model.Alert, 0, len(alerts)),
+		Status:           ngmodels.NotificationDeliveryStatusSuccess,
+		HTTPStatus:       attempt.httpStatus(),
+		Latency:          time.Since(start),
+		PayloadHash:      attempt.payloadHash(),
+		ReplayOf:         replayOf,
+		Timestamp:        start,
+	}
+	for _, a := range alerts {
+		d.Alerts = append(d.Alerts, a.Alert)
+	}
+	if err != nil {
+		d.Status = ngmodels.NotificationDeliveryStatusFailure
+		d.Error = err.Error()
+	}
+	return retry, d, err
+}
+
+// deliveryAttempt collects the payloads and the HTTP status of a notification attempt as the integration hands them over to the sender.
+type deliveryAttempt struct {
+	mtx      sync.Mutex
+	payloads hash.Hash
+	sent     bool
+	status   int
+}
+
+func newDeliveryAttempt() *deliveryAttempt {
+	return &deliveryAttempt{payloads: sha256.New()}
+}
+
+func (a *deliveryAttempt) addPayload(payload []byte) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.payloads.Write(payload)
+	a.sent = true
+}
+
+func (a *deliveryAttempt) setHTTPStatus(status int) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.status = status
+}
+
+func (a *deliveryAttempt) httpStatus() int {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.status
+}
+
+// payloadHash returns the hex-encoded SHA-256 of the payloads, or an empty string if there were none.
+func (a *deliveryAttempt) payloadHash() string {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if !a.sent {
+		return ""
+	}
+	return hex.EncodeToString(a.payloads.Sum(nil))
+}
+
+type deliveryAttemptKey struct{}
+
+func withDeliveryAttempt(ctx context.Context, attempt *deliveryAttempt) context.Context {
+	return context.WithValue(ctx, deliveryAttemptKey{}, attempt)
+}
+
+func deliveryAttemptFromContext(ctx context.Context) (*deliveryAttempt, bool) {
+	attempt, ok := ctx.Value(deliveryAttemptKey{}).(*deliveryAttempt)
+	return attempt, ok
+}
+
+// ListNotificationDeliveries returns the most recent notification deliveries of the organization that match the query.
+func (moa *MultiOrgAlertmanager) ListNotificationDeliveries(ctx context.Context, query ngmodels.ListNotificationDeliveriesQuery) ([]*ngmodels.NotificationDelivery, error) {
+	if moa.deliveryStore == nil {
+		return nil, ErrDeliveryLogDisabled
+	}
+	return moa.deliveryStore.ListNotificationDeliveries(ctx, query)
+}
+
+// GetNotificationDelivery returns the notification delivery of the organization with the ID.
+// It returns ngmodels.ErrNotificationDeliveryNotFound if the delivery does not exist.
+func (moa *MultiOrgAlertmanager) GetNotificationDelivery(ctx context.Context, orgID int64, id int64) (*ngmodels.NotificationDelivery, error) {
+	if moa.deliveryStore == nil {
+		return nil, ErrDeliveryLogDisabled
+	}
+	return moa.deliveryStore.GetNotificationDelivery(ctx, orgID, id)
+}
+
+// ReplayNotificationDelivery sends the alerts of the notification delivery again with the Alertmanager of its organization.
+func (moa *MultiOrgAlertmanager) ReplayNotificationDelivery(ctx context.Context, d *ngmodels.NotificationDelivery) (*ngmodels.NotificationDelivery, error) {
+	am, err := moa.AlertmanagerFor(d.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	replayer, ok := am.(DeliveryReplayer)
+	if !ok {
+		return nil, ErrDeliveryReplayNotSupported
+	}
+	return replayer.ReplayNotificationDelivery(ctx, d)
+}
@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+type fakeDeliveryStore struct {
+	mtx     sync.Mutex
+	saved   []*ngmodels.NotificationDelivery
+	deletes []time.Time
+}
+
+func (f *fakeDeliveryStore) SaveNotificationDelivery(_ context.Context, d *ngmodels.NotificationDelivery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	d.ID = int64(len(f.saved) + 1)
+	f.saved = append(f.saved, d)
+	return nil
+}
+
+func (f *fakeDeliveryStore) GetNotificationDelivery(_ context.Context, _ int64, id int64) (*ngmodels.NotificationDelivery, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if id < 1 || int(id) > len(f.saved) {
+		return nil, ngmodels.ErrNotificationDeliveryNotFound
+	}
+	return f.saved[id-1], nil
+}
+
+func (f *fakeDeliveryStore) ListNotificationDeliveries(context.Context, ngmodels.ListNotificationDeliveriesQuery) ([]*ngmodels.NotificationDelivery, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return slices.Clone(f.saved), nil
+}
+
+func (f *fakeDeliveryStore) DeleteNotificationDeliveriesBefore(_ context.Context, _ int64, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.deletes = append(f.deletes, before)
+	return 0, nil
+}
+
+func (f *fakeDeliveryStore) savedDeliveries() []*ngmodels.NotificationDelivery {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return slices.Clone(f.saved)
+}
+
+// webhookNotifier sends a fixed payload with the sender, like the webhook integrations do.
+type webhookNotifier struct {
+	sender  sender
+	payload string
+}
+
+func (n webhookNotifier) Notify(ctx context.Context, _ ...*types.Alert) (bool, error) {
+	err := n.sender.SendWebhook(ctx, &receivers.SendWebhookSettings{URL: "http://localhost/hook", Body: n.payload})
+	return err != nil, err
+}
+
+func (n webhookNotifier) SendResolved() bool {
+	return true
+}
+
+func TestDeliveryLog(t *testing.T) {
+	const payload = `{"status": "firing"}`
+	payloadHash := sha256.Sum256([]byte(payload))
+
+	statusCode := 502
+	ns := notifications.MockNotificationService()
+	ns.WebhookHandler = func(_ context.Context, cmd *notifications.SendWebhookSync) error {
+		if err := cmd.Validation(nil, statusCode); err != nil {
+			return err
+		}
+		if statusCode/100 != 2 {
+			return errors.New("unexpected status code")
+		}
+		return nil
+	}
+	n := webhookNotifier{sender: sender{ns}, payload: payload}
+
+	store := &fakeDeliveryStore{}
+	deliveryLog := newDeliveryLog(1, store, time.Hour)
+	t.Cleanup(deliveryLog.stopAndWait)
+	build, done := deliveryLog.instrument(func(receiver *alertingNotify.APIReceiver, _ *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+		return []*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "webhook", 0, receiver.Name)}, nil
+	})
+	integrations, err := build(&alertingNotify.APIReceiver{ConfigReceiver: config.Receiver{Name: "team-a"}}, nil)
+	require.NoError(t, err)
+	require.Len(t, integrations, 1)
+	require.Equal(t, "webhook[0]", integrations[0].String())
+	done(true)
+
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}
+	ctx := notify.WithGroupKey(context.Background(), "group-key")
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+
+	t.Run("should record a failed notification", func(t *testing.T) {
+		retry, err := integrations[0].Notify(ctx, alert)
+		require.Error(t, err)
+		require.True(t, retry)
+
+		require.Eventually(t, func() bool { return len(store.savedDeliveries()) == 1 }, time.Second, 10*time.Millisecond)
+		d := store.savedDeliveries()[0]
+		require.Equal(t, int64(1), d.OrgID)
+		require.Equal(t, "team-a", d.Receiver)
+		require.Equal(t, "webhook", d.Integration)
+		require.Equal(t, "group-key", d.GroupKey)
+		require.Equal(t, model.LabelSet{"alertname": "test"}, d.GroupLabels)
+		require.Equal(t, []model.Alert{alert.Alert}, d.Alerts)
+		require.Equal(t, ngmodels.NotificationDeliveryStatusFailure, d.Status)
+		require.Equal(t, 502, d.HTTPStatus)
+		require.Contains(t, d.Error, "unexpected status code")
+		require.Equal(t, hex.EncodeToString(payloadHash[:]), d.PayloadHash)
+		require.Zero(t, d.ReplayOf)
+	})
+
+	t.Run("should delete deliveries older than the retention", func(t *testing.T) {
+		deliveryLog.deleteExpired()
+		require.Len(t, store.deletes, 1)
+		require.WithinDuration(t, time.Now().Add(-time.Hour), store.deletes[0], time.Minute)
+	})
+
+	t.Run("should replay a failed notification with the integration that sent it", func(t *testing.T) {
+		statusCode = 200
+		failed := store.savedDeliveries()[0]
+		replayed, err := deliveryLog.replay(context.Background(), failed)
+		require.NoError(t, err)
+
+		saved := store.savedDeliveries()
+		require.Equal(t, saved[len(saved)-1], replayed)
+		require.NotZero(t, replayed.ID)
+		require.Equal(t, failed.ID, replayed.ReplayOf)
+		require.Equal(t, ngmodels.NotificationDeliveryStatusSuccess, replayed.Status)
+		require.Equal(t, 200, replayed.HTTPStatus)
+		require.Empty(t, replayed.Error)
+		require.Equal(t, "group-key", replayed.GroupKey)
+		require.Equal(t, failed.Alerts, replayed.Alerts)
+	})
+
+	t.Run("should not replay a successful notification", func(t *testing.T) {
+		saved := store.savedDeliveries()
+		_, err := deliveryLog.replay(context.Background(), saved[len(saved)-1])
+		require.ErrorIs(t, err, ErrDeliveryNotFailed)
+		require.Len(t, store.savedDeliveries(), len(saved))
+	})
+
+	t.Run("should not record nor replay with integrations built after the configuration is applied", func(t *testing.T) {
+		testIntegrations, err := build(&alertingNotify.APIReceiver{ConfigReceiver: config.Receiver{Name: "team-b"}}, nil)
+		require.NoError(t, err)
+		require.Len(t, testIntegrations, 1)
+
+		saved := store.savedDeliveries()
+		_, err = testIntegrations[0].Notify(ctx, alert)
+		require.NoError(t, err)
+
+		d := *saved[0]
+		d.Receiver = "team-b"
+		_, err = deliveryLog.replay(context.Background(), &d)
+		require.ErrorIs(t, err, ErrDeliveryIntegrationNotFound)
+
+		// Saving is asynchronous, so wait for the queue to be drained before checking that nothing was recorded.
+		deliveryLog.stopAndWait()
+		require.Len(t, store.savedDeliveries(), len(saved))
+	})
+}
@@ -103,6 +103,9 @@ type MultiOrgAlertmanager struct {
 	ns      notifications.Service
 
 	receiverResourcePermissions ac.ReceiverPermissionsService
+
+	// deliveryStore persists the notification deliveries. The delivery log is disabled when it is nil.
+	deliveryStore DeliveryStore
 }
 
 type OrgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
@@ -115,6 +118,16 @@ func WithAlertmanagerOverride(f func(OrgAlertmanagerFactory) OrgAlertmanagerFact
 	}
 }
 
+// WithDeliveryLog enables the notification delivery log of the Alertmanagers of the organizations.
+// The delivery log stays disabled if its retention is not positive.
+func WithDeliveryLog(store DeliveryStore) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		if moa.settings.UnifiedAlerting.NotificationDeliveryLogRetention > 0 {
+			moa.deliveryStore = store
+		}
+	}
+}
+
 func NewMultiOrgAlertmanager(
 	cfg *setting.Cfg,
 	configStore AlertingStore,
@@ -160,7 +173,14 @@ func NewMultiOrgAlertmanager(
 	moa.factory = func(ctx context.Context, orgID int64) (Alertmanager, error) {
 		m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID), l)
 		stateStore := NewFileStore(orgID, kvStore)
-		return NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager.IsEnabled(ctx, featuremgmt.FlagAlertingSimplifiedRouting))
+		am, err := NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager.IsEnabled(ctx, featuremgmt.FlagAlertingSimplifiedRouting))
+		if err != nil {
+			return nil, err
+		}
+		if moa.deliveryStore != nil {
+			am.deliveryLog = newDeliveryLog(orgID, moa.deliveryStore, moa.settings.UnifiedAlerting.NotificationDeliveryLogRetention)
+		}
+		return am, nil
 	}
 
 	for _, opt := range opts {
@@ -406,6 +426,44 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterMembers provides the members of the high availability cluster. The scheduler uses it to shard the evaluation of rules.
+type ClusterMembers interface {
+	// Self returns the name of this instance in the cluster.
+	Self() string
+	// Members returns the names of the healthy members of the cluster, including this instance.
+	Members() []string
+}
+
+// ClusterMembers returns the members of the high availability cluster, or nil if high availability is not configured.
+func (moa *MultiOrgAlertmanager) ClusterMembers() ClusterMembers {
+	switch p := moa.peer.(type) {
+	case *redisPeer:
+		return p
+	case *alertingCluster.Peer:
+		return memberlistMembers{peer: p}
+	default:
+		return nil
+	}
+}
+
+// memberlistMembers provides the members of the gossip mesh.
+type memberlistMembers struct {
+	peer *alertingCluster.Peer
+}
+
+func (m memberlistMembers) Self() string {
+	return m.peer.Name()
+}
+
+func (m memberlistMembers) Members() []string {
+	nodes := m.peer.Peers()
+	members := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		members = append(members, node.Name)
+	}
+	return members
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
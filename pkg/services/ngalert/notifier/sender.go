@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/grafana/alerting/receivers"
 
@@ -13,6 +14,18 @@ type sender struct {
 }
 
 func (s sender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSettings) error {
+	validation := cmd.Validation
+	if attempt, ok := deliveryAttemptFromContext(ctx); ok {
+		attempt.addPayload([]byte(cmd.Body))
+		// The status code is only exposed to the validation callback, which is called for every response.
+		validation = func(body []byte, statusCode int) error {
+			attempt.setHTTPStatus(statusCode)
+			if cmd.Validation == nil {
+				return nil
+			}
+			return cmd.Validation(body, statusCode)
+		}
+	}
 	return s.ns.SendWebhookSync(ctx, &notifications.SendWebhookSync{
 		Url:         cmd.URL,
 		User:        cmd.User,
@@ -21,12 +34,18 @@ func (s sender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSetti
 		HttpMethod:  cmd.HTTPMethod,
 		HttpHeader:  cmd.HTTPHeader,
 		ContentType: cmd.ContentType,
-		Validation:  cmd.Validation,
+		Validation:  validation,
 		TLSConfig:   cmd.TLSConfig,
 	})
 }
 
 func (s sender) SendEmail(ctx context.Context, cmd *receivers.SendEmailSettings) error {
+	if attempt, ok := deliveryAttemptFromContext(ctx); ok {
+		// Emails are rendered by the notification service, so the command is the closest thing to the payload.
+		if payload, err := json.Marshal(cmd); err == nil {
+			attempt.addPayload(payload)
+		}
+	}
 	return s.ns.SendEmailCommandHandlerSync(ctx, &notifications.SendEmailCommandSync{
 		SendEmailCommand: notifications.SendEmailCommand{
 			To:            cmd.To,
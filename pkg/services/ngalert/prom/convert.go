@@ -0,0 +1,331 @@
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// queryRefID is the RefID of the data source query of converted rules.
+	queryRefID = "A"
+	// conditionRefID is the RefID of the expression that is the condition of converted alerting rules.
+	conditionRefID = "B"
+
+	// defaultFromTimeRange is how far back the data source queries look by default.
+	// The queries are instant queries so only the end of the range matters, but the range must not be empty.
+	defaultFromTimeRange = 10 * time.Minute
+)
+
+// firingExpression makes every series returned by the query fire, which is how Prometheus evaluates alerting rules.
+var firingExpression = fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", queryRefID)
+
+var (
+	// templateActionRegexp matches the actions of a template, e.g. "{{ $value | humanize }}".
+	templateActionRegexp = regexp.MustCompile(`(?s){{.*?}}`)
+	// templateValueRegexp matches the references to the value of the alert in an action of a Prometheus template,
+	// which are $value and .Value. The character before the reference is captured so that it can be kept.
+	templateValueRegexp = regexp.MustCompile(`(^|[^\w$.)\]])(\$value|\.Value)\b`)
+	// templateValueReplacement replaces the references with the Grafana template expression of the value of the query
+	// of converted rules. In Grafana templates, $value and .Value are a string that describes the values of all queries
+	// and expressions.
+	templateValueReplacement = fmt.Sprintf("${1}$$values.%s.Value", queryRefID)
+)
+
+var (
+	ErrUnsupportedDatasource = errors.New("rules can only be converted to queries of Prometheus or Loki data sources")
+	ErrInvalidRule           = errors.New("invalid rule")
+	ErrInvalidRuleGroup      = errors.New("invalid rule group")
+	ErrUnsupportedFeature    = errors.New("not supported by Grafana-managed rules")
+)
+
+// Config defines how Prometheus rules are converted to Grafana alert rules.
+type Config struct {
+	// DatasourceUID and DatasourceType define the data source that executes the expressions of the rules.
+	DatasourceUID  string
+	DatasourceType string
+	// DefaultInterval is the evaluation interval of groups that do not specify one.
+	DefaultInterval time.Duration
+	// FromTimeRange is the start of the relative time range of the queries. Defaults to 10 minutes.
+	FromTimeRange time.Duration
+}
+
+// Converter converts Prometheus rules to Grafana alert rules.
+type Converter struct {
+	cfg Config
+}
+
+// GroupResult is the result of converting a Prometheus rule group.
+// Err is set if the group itself cannot be converted, in which case none of its rules are converted.
+type GroupResult struct {
+	Group RuleGroup
+	Rules []RuleResult
+	Err   error
+}
+
+// RuleResult is the result of converting a single Prometheus rule. AlertRule is only valid if Err is nil.
+type RuleResult struct {
+	Rule      Rule
+	AlertRule models.AlertRule
+	Err       error
+}
+
+// HasErrors returns true if the group or any of its rules failed to convert.
+func (g GroupResult) HasErrors() bool {
+	if g.Err != nil {
+		return true
+	}
+	for _, r := range g.Rules {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func NewConverter(cfg Config) (*Converter, error) {
+	if cfg.DatasourceUID == "" {
+		return nil, errors.New("data source UID must be specified")
+	}
+	if cfg.DatasourceType != datasources.DS_PROMETHEUS && cfg.DatasourceType != datasources.DS_LOKI {
+		return nil, fmt.Errorf("%w: data source %s has type %s", ErrUnsupportedDatasource, cfg.DatasourceUID, cfg.DatasourceType)
+	}
+	if cfg.DefaultInterval <= 0 {
+		return nil, errors.New("default interval must be positive")
+	}
+	if cfg.FromTimeRange == 0 {
+		cfg.FromTimeRange = defaultFromTimeRange
+	}
+	if cfg.FromTimeRange < 0 {
+		return nil, errors.New("query time range must be positive")
+	}
+	return &Converter{cfg: cfg}, nil
+}
+
+// Convert converts all groups of the rule file to Grafana alert rules in the namespace.
+// It does not stop on the first failure but reports errors for every group and rule that cannot be converted.
+// Titles of Grafana alert rules must be unique in a namespace, so if several rules have the same name,
+// all but the first one get a numeric suffix, e.g. "HighLatency (2)".
+func (c *Converter) Convert(orgID int64, namespaceUID string, file RulesFile) []GroupResult {
+	result := make([]GroupResult, 0, len(file.Groups))
+	groupNames := make(map[string]struct{}, len(file.Groups))
+	titles := make(map[string]int)
+	for _, group := range file.Groups {
+		groupResult := GroupResult{Group: group}
+		if _, ok := groupNames[group.Name]; ok {
+			groupResult.Err = fmt.Errorf("%w: group name %q is not unique", ErrInvalidRuleGroup, group.Name)
+			result = append(result, groupResult)
+			continue
+		}
+		groupNames[group.Name] = struct{}{}
+
+		groupResult.Rules, groupResult.Err = c.convertGroup(orgID, namespaceUID, group)
+		for i := range groupResult.Rules {
+			rule := &groupResult.Rules[i]
+			if rule.Err != nil {
+				continue
+			}
+			titles[rule.AlertRule.Title]++
+			if n := titles[rule.AlertRule.Title]; n > 1 {
+				rule.AlertRule.Title = fmt.Sprintf("%s (%d)", rule.AlertRule.Title, n)
+			}
+		}
+		result = append(result, groupResult)
+	}
+	return result
+}
+
+func (c *Converter) convertGroup(orgID int64, namespaceUID string, group RuleGroup) ([]RuleResult, error) {
+	if group.Name == "" {
+		return nil, fmt.Errorf("%w: group name is empty", ErrInvalidRuleGroup)
+	}
+	if len(group.Rules) == 0 {
+		return nil, fmt.Errorf("%w: group has no rules", ErrInvalidRuleGroup)
+	}
+	if group.Limit > 0 {
+		return nil, fmt.Errorf("%w: limit is %w", ErrInvalidRuleGroup, ErrUnsupportedFeature)
+	}
+	if len(group.SourceTenants) > 0 {
+		return nil, fmt.Errorf("%w: source_tenants is %w", ErrInvalidRuleGroup, ErrUnsupportedFeature)
+	}
+	if group.AlignEvaluationTimeOnInterval {
+		return nil, fmt.Errorf("%w: align_evaluation_time_on_interval is %w", ErrInvalidRuleGroup, ErrUnsupportedFeature)
+	}
+
+	interval := time.Duration(group.Interval)
+	if interval == 0 {
+		interval = c.cfg.DefaultInterval
+	}
+	if interval < 0 {
+		return nil, fmt.Errorf("%w: interval must be positive", ErrInvalidRuleGroup)
+	}
+
+	// evaluation_delay is the deprecated name of query_offset in Mimir
+	var offset time.Duration
+	if group.QueryOffset != nil {
+		offset = time.Duration(*group.QueryOffset)
+	} else if group.EvaluationDelay != nil {
+		offset = time.Duration(*group.EvaluationDelay)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: query_offset must not be negative", ErrInvalidRuleGroup)
+	}
+
+	result := make([]RuleResult, 0, len(group.Rules))
+	for idx, rule := range group.Rules {
+		alertRule, err := c.convertRule(orgID, namespaceUID, group.Name, interval, offset, rule)
+		if err != nil {
+			err = fmt.Errorf("rule %d (%s): %w", idx+1, rule.Name(), err)
+		}
+		alertRule.RuleGroupIndex = idx + 1
+		result = append(result, RuleResult{Rule: rule, AlertRule: alertRule, Err: err})
+	}
+	return result, nil
+}
+
+func (c *Converter) convertRule(orgID int64, namespaceUID, group string, interval, offset time.Duration, rule Rule) (models.AlertRule, error) {
+	if rule.Alert != "" && rule.Record != "" {
+		return models.AlertRule{}, fmt.Errorf("%w: only one of alert and record can be set", ErrInvalidRule)
+	}
+	if rule.Alert == "" && rule.Record == "" {
+		return models.AlertRule{}, fmt.Errorf("%w: one of alert and record must be set", ErrInvalidRule)
+	}
+	if strings.TrimSpace(rule.Expr) == "" {
+		return models.AlertRule{}, fmt.Errorf("%w: expr is empty", ErrInvalidRule)
+	}
+	if c.cfg.DatasourceType == datasources.DS_PROMETHEUS {
+		if _, err := parser.ParseExpr(rule.Expr); err != nil {
+			return models.AlertRule{}, fmt.Errorf("%w: cannot parse expr: %w", ErrInvalidRule, err)
+		}
+	}
+
+	query, err := c.query(rule.Expr)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	result := models.AlertRule{
+		OrgID:           orgID,
+		NamespaceUID:    namespaceUID,
+		RuleGroup:       group,
+		IntervalSeconds: int64(interval.Seconds()),
+		QueryOffset:     offset,
+	}
+
+	if rule.Record != "" {
+		if rule.For != 0 {
+			return models.AlertRule{}, fmt.Errorf("%w: recording rules cannot have for", ErrInvalidRule)
+		}
+		if rule.KeepFiringFor != 0 {
+			return models.AlertRule{}, fmt.Errorf("%w: recording rules cannot have keep_firing_for", ErrInvalidRule)
+		}
+		if len(rule.Annotations) > 0 {
+			return models.AlertRule{}, fmt.Errorf("%w: recording rules cannot have annotations", ErrInvalidRule)
+		}
+		result.Title = rule.Record
+		result.Labels = maps.Clone(rule.Labels)
+		result.Data = []models.AlertQuery{query}
+		result.Record = &models.Record{
+			Metric: rule.Record,
+			From:   queryRefID,
+		}
+		return result, nil
+	}
+
+	condition, err := firingCondition()
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	result.Title = rule.Alert
+	result.Condition = condition.RefID
+	result.Data = []models.AlertQuery{query, condition}
+	result.For = time.Duration(rule.For)
+	result.KeepFiringFor = time.Duration(rule.KeepFiringFor)
+	result.Labels = translateTemplates(rule.Labels)
+	result.Annotations = translateTemplates(rule.Annotations)
+	// Prometheus does not fire alerts when the query returns nothing or fails.
+	result.NoDataState = models.OK
+	result.ExecErrState = models.ErrorErrState
+	return result, nil
+}
+
+// query creates an instant query of the data source. The query offset of the group is set on the rule, so the time range is not shifted.
+func (c *Converter) query(expression string) (models.AlertQuery, error) {
+	model := map[string]any{
+		"refId": queryRefID,
+		"datasource": map[string]string{
+			"type": c.cfg.DatasourceType,
+			"uid":  c.cfg.DatasourceUID,
+		},
+		"expr":    expression,
+		"instant": true,
+		"range":   false,
+	}
+	if c.cfg.DatasourceType == datasources.DS_LOKI {
+		model["queryType"] = "instant"
+	}
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return models.AlertQuery{}, fmt.Errorf("failed to create query model: %w", err)
+	}
+	return models.AlertQuery{
+		RefID:         queryRefID,
+		DatasourceUID: c.cfg.DatasourceUID,
+		RelativeTimeRange: models.RelativeTimeRange{
+			From: models.Duration(c.cfg.FromTimeRange),
+		},
+		Model: raw,
+	}, nil
+}
+
+// firingCondition creates a math expression that is true for every series returned by the query.
+func firingCondition() (models.AlertQuery, error) {
+	raw, err := json.Marshal(map[string]any{
+		"refId": conditionRefID,
+		"type":  "math",
+		"datasource": map[string]string{
+			"type": expr.DatasourceType,
+			"uid":  expr.DatasourceUID,
+		},
+		"expression": firingExpression,
+	})
+	if err != nil {
+		return models.AlertQuery{}, fmt.Errorf("failed to create condition model: %w", err)
+	}
+	return models.AlertQuery{
+		RefID:         conditionRefID,
+		QueryType:     expr.DatasourceType,
+		DatasourceUID: expr.DatasourceUID,
+		Model:         raw,
+	}, nil
+}
+
+// translateTemplates returns a copy of the labels or annotations of an alerting rule in which the references to the
+// value of the alert are replaced with the value of the query of the converted rule.
+func translateTemplates(templates map[string]string) map[string]string {
+	if templates == nil {
+		return nil
+	}
+	result := make(map[string]string, len(templates))
+	for k, v := range templates {
+		result[k] = translateTemplate(v)
+	}
+	return result
+}
+
+// translateTemplate replaces $value and .Value in the actions of the Prometheus template with the value of the query.
+func translateTemplate(text string) string {
+	return templateActionRegexp.ReplaceAllStringFunc(text, func(action string) string {
+		return templateValueRegexp.ReplaceAllString(action, templateValueReplacement)
+	})
+}
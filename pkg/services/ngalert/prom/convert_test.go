@@ -0,0 +1,260 @@
+package prom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestNewConverter(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cfg    Config
+		expErr string
+	}{
+		{
+			name:   "data source UID is required",
+			cfg:    Config{DatasourceType: datasources.DS_PROMETHEUS, DefaultInterval: time.Minute},
+			expErr: "data source UID must be specified",
+		},
+		{
+			name:   "data source must be Prometheus or Loki",
+			cfg:    Config{DatasourceUID: "uid", DatasourceType: "graphite", DefaultInterval: time.Minute},
+			expErr: ErrUnsupportedDatasource.Error(),
+		},
+		{
+			name:   "default interval must be positive",
+			cfg:    Config{DatasourceUID: "uid", DatasourceType: datasources.DS_LOKI},
+			expErr: "default interval must be positive",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewConverter(tc.cfg)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+
+	c, err := NewConverter(Config{DatasourceUID: "uid", DatasourceType: datasources.DS_PROMETHEUS, DefaultInterval: time.Minute})
+	require.NoError(t, err)
+	require.Equal(t, defaultFromTimeRange, c.cfg.FromTimeRange)
+}
+
+func TestConvert(t *testing.T) {
+	c, err := NewConverter(Config{DatasourceUID: "prom", DatasourceType: datasources.DS_PROMETHEUS, DefaultInterval: 30 * time.Second})
+	require.NoError(t, err)
+
+	t.Run("converts alerting rule", func(t *testing.T) {
+		result := c.Convert(1, "folder", RulesFile{Groups: []RuleGroup{{
+			Name:     "group",
+			Interval: model.Duration(time.Minute),
+			Rules: []Rule{{
+				Alert:         "HighLatency",
+				Expr:          "histogram_quantile(0.99, rate(latency_bucket[5m])) > 1",
+				For:           model.Duration(5 * time.Minute),
+				KeepFiringFor: model.Duration(10 * time.Minute),
+				Labels:        map[string]string{"severity": "page"},
+				Annotations:   map[string]string{"summary": "{{ $labels.job }} is slow", "description": "latency is {{ $value }}s"},
+			}},
+		}}})
+
+		require.Len(t, result, 1)
+		require.False(t, result[0].HasErrors())
+		require.Len(t, result[0].Rules, 1)
+		rule := result[0].Rules[0].AlertRule
+		require.Equal(t, "HighLatency", rule.Title)
+		require.Equal(t, int64(1), rule.OrgID)
+		require.Equal(t, "folder", rule.NamespaceUID)
+		require.Equal(t, "group", rule.RuleGroup)
+		require.Equal(t, 1, rule.RuleGroupIndex)
+		require.Equal(t, int64(60), rule.IntervalSeconds)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, 10*time.Minute, rule.KeepFiringFor)
+		require.Equal(t, map[string]string{"severity": "page"}, rule.Labels)
+		require.Equal(t, map[string]string{"summary": "{{ $labels.job }} is slow", "description": "latency is {{ $values.A.Value }}s"}, rule.Annotations)
+		require.Equal(t, models.OK, rule.NoDataState)
+		require.Equal(t, models.ErrorErrState, rule.ExecErrState)
+		require.Nil(t, rule.Record)
+
+		require.Equal(t, conditionRefID, rule.Condition)
+		require.Len(t, rule.Data, 2)
+		query := rule.Data[0]
+		require.Equal(t, queryRefID, query.RefID)
+		require.Equal(t, "prom", query.DatasourceUID)
+		require.Equal(t, models.RelativeTimeRange{From: models.Duration(defaultFromTimeRange)}, query.RelativeTimeRange)
+		var queryModel map[string]any
+		require.NoError(t, json.Unmarshal(query.Model, &queryModel))
+		require.Equal(t, "histogram_quantile(0.99, rate(latency_bucket[5m])) > 1", queryModel["expr"])
+		require.Equal(t, true, queryModel["instant"])
+
+		condition := rule.Data[1]
+		require.Equal(t, conditionRefID, condition.RefID)
+		isExpression, err := condition.IsExpression()
+		require.NoError(t, err)
+		require.True(t, isExpression)
+		var conditionModel map[string]any
+		require.NoError(t, json.Unmarshal(condition.Model, &conditionModel))
+		require.Equal(t, firingExpression, conditionModel["expression"])
+	})
+
+	t.Run("converts recording rule", func(t *testing.T) {
+		result := c.Convert(1, "folder", RulesFile{Groups: []RuleGroup{{
+			Name: "group",
+			Rules: []Rule{{
+				Record: "job:requests:rate5m",
+				Expr:   "sum by (job) (rate(requests_total[5m]))",
+				Labels: map[string]string{"team": "a"},
+			}},
+		}}})
+
+		require.False(t, result[0].HasErrors())
+		rule := result[0].Rules[0].AlertRule
+		require.Equal(t, "job:requests:rate5m", rule.Title)
+		require.Equal(t, int64(30), rule.IntervalSeconds)
+		require.Equal(t, &models.Record{Metric: "job:requests:rate5m", From: queryRefID}, rule.Record)
+		require.Equal(t, map[string]string{"team": "a"}, rule.Labels)
+		require.Empty(t, rule.Condition)
+		require.Len(t, rule.Data, 1)
+	})
+
+	t.Run("query offset is set on the rule", func(t *testing.T) {
+		offset := model.Duration(time.Minute)
+		result := c.Convert(1, "folder", RulesFile{Groups: []RuleGroup{{
+			Name:        "group",
+			QueryOffset: &offset,
+			Rules:       []Rule{{Alert: "A", Expr: "up == 0"}},
+		}}})
+
+		require.False(t, result[0].HasErrors())
+		require.Equal(t, time.Minute, result[0].Rules[0].AlertRule.QueryOffset)
+		require.Equal(t, models.RelativeTimeRange{
+			From: models.Duration(defaultFromTimeRange),
+		}, result[0].Rules[0].AlertRule.Data[0].RelativeTimeRange)
+	})
+
+	t.Run("rules with the same name get unique titles", func(t *testing.T) {
+		result := c.Convert(1, "folder", RulesFile{Groups: []RuleGroup{
+			{
+				Name: "group1",
+				Rules: []Rule{
+					{Alert: "HighLatency", Expr: "latency > 1", Labels: map[string]string{"severity": "warning"}},
+					{Alert: "HighLatency", Expr: "latency > 5", Labels: map[string]string{"severity": "page"}},
+				},
+			},
+			{
+				Name:  "group2",
+				Rules: []Rule{{Alert: "HighLatency", Expr: "latency > 10"}},
+			},
+		}})
+
+		require.Equal(t, "HighLatency", result[0].Rules[0].AlertRule.Title)
+		require.Equal(t, "HighLatency (2)", result[0].Rules[1].AlertRule.Title)
+		require.Equal(t, "HighLatency (3)", result[1].Rules[0].AlertRule.Title)
+	})
+
+	t.Run("reports every invalid rule", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			rule   Rule
+			expErr string
+		}{
+			{name: "alert and record", rule: Rule{Alert: "A", Record: "a", Expr: "up"}, expErr: "only one of alert and record can be set"},
+			{name: "no name", rule: Rule{Expr: "up"}, expErr: "one of alert and record must be set"},
+			{name: "empty expr", rule: Rule{Alert: "A"}, expErr: "expr is empty"},
+			{name: "invalid expr", rule: Rule{Alert: "A", Expr: "sum(up"}, expErr: "cannot parse expr"},
+			{name: "recording rule with for", rule: Rule{Record: "a", Expr: "up", For: model.Duration(time.Minute)}, expErr: "recording rules cannot have for"},
+			{name: "recording rule with keep_firing_for", rule: Rule{Record: "a", Expr: "up", KeepFiringFor: model.Duration(time.Minute)}, expErr: "recording rules cannot have keep_firing_for"},
+			{name: "recording rule with annotations", rule: Rule{Record: "a", Expr: "up", Annotations: map[string]string{"a": "b"}}, expErr: "recording rules cannot have annotations"},
+		}
+		group := RuleGroup{Name: "group", Rules: []Rule{{Alert: "Valid", Expr: "up == 0"}}}
+		for _, tc := range testCases {
+			group.Rules = append(group.Rules, tc.rule)
+		}
+
+		result := c.Convert(1, "folder", RulesFile{Groups: []RuleGroup{group}})
+
+		require.True(t, result[0].HasErrors())
+		require.NoError(t, result[0].Err)
+		require.NoError(t, result[0].Rules[0].Err)
+		for i, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.ErrorContains(t, result[0].Rules[i+1].Err, tc.expErr)
+			})
+		}
+	})
+
+	t.Run("reports invalid groups", func(t *testing.T) {
+		rules := []Rule{{Alert: "A", Expr: "up == 0"}}
+		testCases := []struct {
+			name   string
+			group  RuleGroup
+			expErr string
+		}{
+			{name: "no name", group: RuleGroup{Rules: rules}, expErr: "group name is empty"},
+			{name: "no rules", group: RuleGroup{Name: "no rules"}, expErr: "group has no rules"},
+			{name: "limit", group: RuleGroup{Name: "limit", Limit: 10, Rules: rules}, expErr: "limit is not supported"},
+			{name: "source tenants", group: RuleGroup{Name: "tenants", SourceTenants: []string{"a"}, Rules: rules}, expErr: "source_tenants is not supported"},
+			{name: "duplicate name", group: RuleGroup{Name: "limit", Rules: rules}, expErr: `group name "limit" is not unique`},
+		}
+		file := RulesFile{}
+		for _, tc := range testCases {
+			file.Groups = append(file.Groups, tc.group)
+		}
+
+		result := c.Convert(1, "folder", file)
+
+		for i, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.ErrorIs(t, result[i].Err, ErrInvalidRuleGroup)
+				require.ErrorContains(t, result[i].Err, tc.expErr)
+				require.Empty(t, result[i].Rules)
+			})
+		}
+	})
+}
+
+func TestTranslateTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "without value", template: "{{ $labels.job }} is down", expected: "{{ $labels.job }} is down"},
+		{name: "value variable", template: "value is {{ $value }}", expected: "value is {{ $values.A.Value }}"},
+		{name: "value field", template: "value is {{.Value}}", expected: "value is {{$values.A.Value}}"},
+		{name: "value in a pipeline", template: "{{ $value | humanizePercentage }} of errors", expected: "{{ $values.A.Value | humanizePercentage }} of errors"},
+		{name: "value as an argument", template: `{{ printf "%.2f" $value }}`, expected: `{{ printf "%.2f" $values.A.Value }}`},
+		{name: "value in parentheses", template: "{{ humanize (.Value) }}", expected: "{{ humanize ($values.A.Value) }}"},
+		{name: "several values", template: "{{ $value }} > {{ .Value }}", expected: "{{ $values.A.Value }} > {{ $values.A.Value }}"},
+		{name: "values of Grafana", template: "{{ $values.A.Value }}", expected: "{{ $values.A.Value }}"},
+		{name: "label named Value", template: "{{ $labels.Value }} {{ .Labels.Value }}", expected: "{{ $labels.Value }} {{ .Labels.Value }}"},
+		{name: "text outside of actions", template: "$value is {{ $value }}", expected: "$value is {{ $values.A.Value }}"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, translateTemplate(tc.template))
+		})
+	}
+}
+
+func TestConvertLokiRules(t *testing.T) {
+	c, err := NewConverter(Config{DatasourceUID: "loki", DatasourceType: datasources.DS_LOKI, DefaultInterval: time.Minute})
+	require.NoError(t, err)
+
+	result := c.Convert(1, "folder", RulesFile{Groups: []RuleGroup{{
+		Name:  "group",
+		Rules: []Rule{{Alert: "Errors", Expr: `sum(rate({app="api"} |= "error" [5m])) > 10`}},
+	}}})
+
+	require.False(t, result[0].HasErrors())
+	var queryModel map[string]any
+	require.NoError(t, json.Unmarshal(result[0].Rules[0].AlertRule.Data[0].Model, &queryModel))
+	require.Equal(t, "instant", queryModel["queryType"])
+	require.Equal(t, map[string]any{"type": datasources.DS_LOKI, "uid": "loki"}, queryModel["datasource"])
+}
@@ -0,0 +1,64 @@
+package prom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// RulesFile is a rule file in the format used by Prometheus, Mimir and Loki rulers.
+type RulesFile struct {
+	Groups []RuleGroup `yaml:"groups"`
+}
+
+// RuleGroup is a group of Prometheus alerting and recording rules.
+type RuleGroup struct {
+	Name        string          `yaml:"name"`
+	Interval    model.Duration  `yaml:"interval,omitempty"`
+	QueryOffset *model.Duration `yaml:"query_offset,omitempty"`
+	Limit       int             `yaml:"limit,omitempty"`
+	Rules       []Rule          `yaml:"rules"`
+
+	// fields below are used by Mimir/Loki rulers
+
+	SourceTenants                 []string        `yaml:"source_tenants,omitempty"`
+	EvaluationDelay               *model.Duration `yaml:"evaluation_delay,omitempty"`
+	AlignEvaluationTimeOnInterval bool            `yaml:"align_evaluation_time_on_interval,omitempty"`
+}
+
+// Rule is a Prometheus alerting rule if Alert is set, or a recording rule if Record is set.
+type Rule struct {
+	Alert         string            `yaml:"alert,omitempty"`
+	Record        string            `yaml:"record,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           model.Duration    `yaml:"for,omitempty"`
+	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Name returns the name of the alert or of the recorded metric.
+func (r Rule) Name() string {
+	if r.Record != "" {
+		return r.Record
+	}
+	return r.Alert
+}
+
+// ParseRulesFile parses a rule file in YAML or JSON format. Unknown fields are rejected, the same as Prometheus does.
+func ParseRulesFile(b []byte) (RulesFile, error) {
+	var file RulesFile
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return RulesFile{}, fmt.Errorf("failed to parse rule file: %w", err)
+	}
+	if len(file.Groups) == 0 {
+		return RulesFile{}, errors.New("rule file does not contain any rule groups")
+	}
+	return file, nil
+}
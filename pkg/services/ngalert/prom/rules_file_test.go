@@ -0,0 +1,68 @@
+package prom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRulesFile(t *testing.T) {
+	t.Run("parses YAML", func(t *testing.T) {
+		file, err := ParseRulesFile([]byte(`
+groups:
+  - name: group
+    interval: 1m
+    query_offset: 30s
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: page
+        annotations:
+          summary: "{{ $labels.instance }} is down"
+      - record: job:up:sum
+        expr: sum by (job) (up)
+`))
+		require.NoError(t, err)
+
+		offset := model.Duration(30 * time.Second)
+		require.Equal(t, RulesFile{Groups: []RuleGroup{{
+			Name:        "group",
+			Interval:    model.Duration(time.Minute),
+			QueryOffset: &offset,
+			Rules: []Rule{
+				{
+					Alert:       "InstanceDown",
+					Expr:        "up == 0",
+					For:         model.Duration(5 * time.Minute),
+					Labels:      map[string]string{"severity": "page"},
+					Annotations: map[string]string{"summary": "{{ $labels.instance }} is down"},
+				},
+				{
+					Record: "job:up:sum",
+					Expr:   "sum by (job) (up)",
+				},
+			},
+		}}}, file)
+	})
+
+	t.Run("parses JSON", func(t *testing.T) {
+		file, err := ParseRulesFile([]byte(`{"groups":[{"name":"group","rules":[{"alert":"InstanceDown","expr":"up == 0","for":"5m"}]}]}`))
+		require.NoError(t, err)
+		require.Len(t, file.Groups, 1)
+		require.Equal(t, model.Duration(5*time.Minute), file.Groups[0].Rules[0].For)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		_, err := ParseRulesFile([]byte(`{"groups":[{"name":"group","rules":[{"alert":"A","expr":"up","severity":"page"}]}]}`))
+		require.ErrorContains(t, err, "field severity not found")
+	})
+
+	t.Run("rejects files without groups", func(t *testing.T) {
+		_, err := ParseRulesFile([]byte(""))
+		require.ErrorContains(t, err, "does not contain any rule groups")
+	})
+}
@@ -163,6 +163,15 @@ func (fam *RemoteSecondaryForkedAlertmanager) TestReceivers(ctx context.Context,
 	return fam.internal.TestReceivers(ctx, c)
 }
 
+// ReplayNotificationDelivery replays the notification with the internal Alertmanager, which is the one sending notifications.
+func (fam *RemoteSecondaryForkedAlertmanager) ReplayNotificationDelivery(ctx context.Context, d *models.NotificationDelivery) (*models.NotificationDelivery, error) {
+	replayer, ok := fam.internal.(notifier.DeliveryReplayer)
+	if !ok {
+		return nil, notifier.ErrDeliveryReplayNotSupported
+	}
+	return replayer.ReplayNotificationDelivery(ctx, d)
+}
+
 func (fam *RemoteSecondaryForkedAlertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*notifier.TestTemplatesResults, error) {
 	return fam.internal.TestTemplate(ctx, c)
 }
@@ -0,0 +1,92 @@
+// Package retention deletes rows that are older than a retention period from the tables of alerting in the Grafana database.
+// It has few dependencies, so that both the SQL writer of recording rules and the SQL state historian can use it.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+const (
+	// Interval is how often expired rows are deleted.
+	Interval = 10 * time.Minute
+	// BatchSize is the number of rows deleted at once. It is below the SQLite limit of 999 parameters.
+	BatchSize = 500
+)
+
+// Throttle limits how often expired rows are deleted. The zero value is ready to use.
+type Throttle struct {
+	mtx  sync.Mutex
+	last time.Time
+}
+
+// Due returns true if the last cleanup happened more than Interval before now, and records now as the last cleanup.
+func (t *Throttle) Due(now time.Time) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if now.Sub(t.last) < Interval {
+		return false
+	}
+	t.last = now
+	return true
+}
+
+// Reference is a column of another table that holds the IDs of the rows being deleted.
+// Rows of that table are deleted along with the rows they reference.
+type Reference struct {
+	Table  string
+	Column string
+}
+
+// DeleteBefore deletes the rows of the table whose epoch is before cutoff, along with the rows that reference them, and returns the number of rows deleted from the table.
+// IDs are loaded into memory first and deleted in batches to avoid deadlocks with concurrent inserts on MySQL.
+// Each batch is deleted in a transaction, so that a row is never deleted without the rows that reference it.
+func DeleteBefore(ctx context.Context, store db.DB, table string, cutoff int64, refs ...Reference) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		var ids []int64
+		err := store.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.Table(table).Cols("id").Where("epoch < ?", cutoff).Asc("id").Limit(BatchSize).Find(&ids)
+		})
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		placeholders := "?" + strings.Repeat(",?", len(ids)-1)
+		args := make([]any, 0, len(ids)+1)
+		args = append(args, "")
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		err = store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			for _, ref := range refs {
+				args[0] = fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", ref.Table, ref.Column, placeholders)
+				if _, err := sess.Exec(args...); err != nil {
+					return err
+				}
+			}
+			args[0] = fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", table, placeholders)
+			res, err := sess.Exec(args...)
+			if err != nil {
+				return err
+			}
+			affected, err := res.RowsAffected()
+			total += affected
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottle(t *testing.T) {
+	var throttle Throttle
+	now := time.Now()
+
+	require.True(t, throttle.Due(now), "first cleanup should be due")
+	require.False(t, throttle.Due(now.Add(Interval-time.Second)), "cleanup should not be due before the interval")
+	require.True(t, throttle.Due(now.Add(Interval)), "cleanup should be due after the interval")
+	require.False(t, throttle.Due(now.Add(Interval+time.Second)), "interval should start from the last cleanup")
+}
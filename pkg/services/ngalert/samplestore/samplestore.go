@@ -0,0 +1,123 @@
+// Package samplestore reads the samples of recording rules stored in the Grafana database.
+// It has few dependencies, so that both the SQL writer of recording rules and the built-in Grafana datasource can use it.
+package samplestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+const (
+	// TableName is the name of the table that stores the samples.
+	TableName = "recording_rule_sample"
+	// maxQuerySamples is the maximum number of samples returned by a query. The oldest samples are dropped first.
+	maxQuerySamples = 100000
+)
+
+// Sample is a single sample of a series stored in the recording_rule_sample table.
+type Sample struct {
+	ID         int64   `xorm:"pk autoincr 'id'"`
+	OrgID      int64   `xorm:"org_id"`
+	Metric     string  `xorm:"metric"`
+	Labels     string  `xorm:"labels"`
+	LabelsHash string  `xorm:"labels_hash"`
+	Epoch      int64   `xorm:"epoch"`
+	Value      float64 `xorm:"value"`
+}
+
+func (Sample) TableName() string {
+	return TableName
+}
+
+// Query selects the samples of a metric.
+type Query struct {
+	OrgID  int64
+	Metric string
+	// Labels, if not empty, selects only the series that have all of these labels.
+	Labels map[string]string
+	From   time.Time
+	To     time.Time
+}
+
+// Find returns the samples that match the query, as one time series frame per series.
+// Frames are sorted by labels and samples within a frame are sorted by time.
+func Find(ctx context.Context, store db.DB, query Query) (data.Frames, error) {
+	if query.Metric == "" {
+		return nil, errors.New("metric must not be empty")
+	}
+
+	var samples []Sample
+	err := store.WithDbSession(ctx, func(sess *db.Session) error {
+		// Take the latest samples first, so the limit drops the oldest ones.
+		return sess.Table(TableName).
+			Where("org_id = ?", query.OrgID).
+			And("metric = ?", query.Metric).
+			And("epoch >= ?", query.From.UnixMilli()).
+			And("epoch <= ?", query.To.UnixMilli()).
+			Desc("epoch", "id").
+			Limit(maxQuerySamples).
+			Find(&samples)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recording rule samples: %w", err)
+	}
+	return samplesToFrames(query, samples)
+}
+
+// samplesToFrames groups samples sorted from the newest to the oldest into time series frames.
+func samplesToFrames(query Query, samples []Sample) (data.Frames, error) {
+	type series struct {
+		labels data.Labels
+		times  []time.Time
+		values []float64
+	}
+	byHash := make(map[string]*series)
+	for i := len(samples) - 1; i >= 0; i-- {
+		sample := samples[i]
+		s, ok := byHash[sample.LabelsHash]
+		if !ok {
+			var labels data.Labels
+			if err := json.Unmarshal([]byte(sample.Labels), &labels); err != nil {
+				return nil, fmt.Errorf("failed to read labels of recording rule sample: %w", err)
+			}
+			s = &series{labels: labels}
+			byHash[sample.LabelsHash] = s
+		}
+		s.times = append(s.times, time.UnixMilli(sample.Epoch).UTC())
+		s.values = append(s.values, sample.Value)
+	}
+
+	frames := make(data.Frames, 0, len(byHash))
+	for _, s := range byHash {
+		if !matchLabels(s.labels, query.Labels) {
+			continue
+		}
+		frame := data.NewFrame(query.Metric,
+			data.NewField(data.TimeSeriesTimeFieldName, nil, s.times),
+			data.NewField(data.TimeSeriesValueFieldName, s.labels, s.values),
+		)
+		frame.SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti, TypeVersion: data.FrameTypeVersion{0, 1}})
+		frames = append(frames, frame)
+	}
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].Fields[1].Labels.String() < frames[j].Fields[1].Labels.String()
+	})
+	return frames, nil
+}
+
+func matchLabels(labels data.Labels, matchers map[string]string) bool {
+	for k, v := range matchers {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
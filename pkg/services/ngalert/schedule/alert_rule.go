@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -57,6 +58,7 @@ func newRuleFactory(
 	sender AlertsSender,
 	stateManager *state.Manager,
 	evalFactory eval.EvaluatorFactory,
+	evalLimits setting.UnifiedAlertingEvaluationLimitsSettings,
 	ruleProvider ruleProvider,
 	clock clock.Clock,
 	rrCfg setting.RecordingRuleSettings,
@@ -93,6 +95,7 @@ func newRuleFactory(
 			sender,
 			stateManager,
 			evalFactory,
+			evalLimits,
 			ruleProvider,
 			clock,
 			met,
@@ -129,6 +132,9 @@ type alertRule struct {
 	evalFactory  eval.EvaluatorFactory
 	ruleProvider ruleProvider
 
+	// evalLimits are the limits that the evaluation of the rule must not exceed.
+	evalLimits setting.UnifiedAlertingEvaluationLimitsSettings
+
 	// Event hooks that are only used in tests.
 	evalAppliedHook evalAppliedFunc
 	stopAppliedHook stopAppliedFunc
@@ -147,6 +153,7 @@ func newAlertRule(
 	sender AlertsSender,
 	stateManager *state.Manager,
 	evalFactory eval.EvaluatorFactory,
+	evalLimits setting.UnifiedAlertingEvaluationLimitsSettings,
 	ruleProvider ruleProvider,
 	clock clock.Clock,
 	met *metrics.Scheduler,
@@ -170,6 +177,7 @@ func newAlertRule(
 		stateManager:         stateManager,
 		evalFactory:          evalFactory,
 		ruleProvider:         ruleProvider,
+		evalLimits:           evalLimits,
 		evalAppliedHook:      evalAppliedHook,
 		stopAppliedHook:      stopAppliedHook,
 		metrics:              met,
@@ -369,20 +377,32 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 	processDuration := a.metrics.ProcessDuration.WithLabelValues(orgID)
 	sendDuration := a.metrics.SendDuration.WithLabelValues(orgID)
 
+	limits := e.rule.EvaluationLimits.Apply(a.evalLimits.For(e.rule.OrgID))
 	start := a.clock.Now()
 
-	evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), a.newLoadedMetricsReader(e.rule))
-	ruleEval, err := a.evalFactory.Create(evalCtx, e.rule.GetEvalCondition().WithSource("scheduler").WithFolder(e.folderTitle))
 	var results eval.Results
 	var dur time.Duration
-	if err != nil {
-		dur = a.clock.Now().Sub(start)
-		logger.Error("Failed to build rule evaluator", "error", err)
-	} else {
-		results, err = ruleEval.Evaluate(ctx, e.scheduledAt)
-		dur = a.clock.Now().Sub(start)
+	var err error
+	limitErr := checkQueriesLimit(e.rule, limits)
+	if limitErr == nil {
+		evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), a.newLoadedMetricsReader(e.rule))
+		var ruleEval eval.ConditionEvaluator
+		ruleEval, err = a.evalFactory.Create(evalCtx, e.rule.GetEvalCondition().WithSource("scheduler").WithFolder(e.folderTitle))
 		if err != nil {
-			logger.Error("Failed to evaluate rule", "error", err, "duration", dur)
+			dur = a.clock.Now().Sub(start)
+			logger.Error("Failed to build rule evaluator", "error", err)
+		} else {
+			limitCtx, cancel := withDurationLimit(ctx, limits)
+			results, err = ruleEval.Evaluate(expr.WithResultCache(withSeriesLimit(limitCtx, limits), e.resultCache), e.scheduledAt)
+			limitErr = durationLimitExceeded(limitCtx)
+			cancel()
+			dur = a.clock.Now().Sub(start)
+			if limitErr == nil {
+				limitErr = checkSeriesLimit(err, results, limits)
+			}
+			if err != nil && limitErr == nil {
+				logger.Error("Failed to evaluate rule", "error", err, "duration", dur)
+			}
 		}
 	}
 
@@ -394,7 +414,16 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 		return nil
 	}
 
-	if err != nil || results.HasErrors() {
+	if limitErr != nil {
+		// Exceeding the limits is not retried, the evaluation would most likely exceed them again.
+		evalAttemptFailures.Inc()
+		evalTotalFailures.Inc()
+		a.metrics.EvalLimitExceeded.WithLabelValues(orgID, limitErr.limit).Inc()
+		logger.Warn("Alert rule exceeded the evaluation limits", "limit", limitErr.limit, "error", limitErr, "duration", dur)
+		results = eval.Results{eval.NewResultFromError(limitErr, e.scheduledAt, dur)}
+		span.SetStatus(codes.Error, "rule evaluation limit exceeded")
+		span.RecordError(limitErr)
+	} else if err != nil || results.HasErrors() {
 		evalAttemptFailures.Inc()
 
 		// Only retry (return errors) if this isn't the last attempt, otherwise skip these return operations.
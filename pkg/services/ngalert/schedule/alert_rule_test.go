@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -267,7 +268,7 @@ func TestAlertRule(t *testing.T) {
 }
 
 func blankRuleForTests(ctx context.Context, key models.AlertRuleKeyWithGroup) *alertRule {
-	return newAlertRule(ctx, key, nil, false, 0, nil, nil, nil, nil, nil, nil, log.NewNopLogger(), nil, nil, nil)
+	return newAlertRule(ctx, key, nil, false, 0, nil, nil, nil, setting.UnifiedAlertingEvaluationLimitsSettings{}, nil, nil, nil, log.NewNopLogger(), nil, nil, nil)
 }
 
 func TestRuleRoutine(t *testing.T) {
@@ -722,6 +723,126 @@ func TestRuleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("when evaluation exceeds the limits", func(t *testing.T) {
+		rule := gen.With(withQueryForState(t, eval.Alerting)).GenerateRef()
+		rule.ExecErrState = models.AlertingErrState
+		for _, refID := range []string{"B", "C"} {
+			q := gen.GenerateQuery()
+			q.RefID = refID
+			rule.Data = append(rule.Data, q)
+		}
+
+		evalAppliedChan := make(chan time.Time)
+
+		sender := NewSyncAlertsSenderMock()
+		sender.EXPECT().Send(mock.Anything, rule.GetKey(), mock.Anything).Return()
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, sender)
+		sch.maxAttempts = 3
+		sch.evalLimits = setting.UnifiedAlertingEvaluationLimitsSettings{
+			Default: setting.EvaluationLimits{MaxQueries: 1},
+		}
+		ruleStore.PutRule(context.Background(), rule)
+		factory := ruleFactoryFromScheduler(sch)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		ruleInfo := factory.new(ctx, rule)
+
+		go func() {
+			_ = ruleInfo.Run()
+		}()
+
+		ruleInfo.Eval(&Evaluation{
+			scheduledAt: sch.clock.Now(),
+			rule:        rule,
+		})
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should not retry and count the exceeded limit", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluation_attempts_total The total number of rule evaluation attempts.
+				# TYPE grafana_alerting_rule_evaluation_attempts_total counter
+				grafana_alerting_rule_evaluation_attempts_total{org="%[1]d"} 1
+				# HELP grafana_alerting_rule_evaluation_limit_exceeded_total The total number of rule evaluations that exceeded the evaluation limits, by limit.
+				# TYPE grafana_alerting_rule_evaluation_limit_exceeded_total counter
+				grafana_alerting_rule_evaluation_limit_exceeded_total{limit="queries",org="%[1]d"} 1
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric),
+				"grafana_alerting_rule_evaluation_attempts_total",
+				"grafana_alerting_rule_evaluation_limit_exceeded_total")
+			require.NoError(t, err)
+		})
+
+		t.Run("it should move the rule to Error state regardless of the execution error state", func(t *testing.T) {
+			states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+			require.Len(t, states, 1)
+			assert.Equal(t, eval.Error, states[0].State)
+			assert.Equal(t, models.StateReasonLimitExceeded, states[0].StateReason)
+			assert.ErrorIs(t, states[0].Error, eval.ErrLimitExceeded)
+		})
+
+		t.Run("it should send special alert DatasourceError", func(t *testing.T) {
+			sender.AssertNumberOfCalls(t, "Send", 1)
+			args, ok := sender.Calls()[0].Arguments[2].(definitions.PostableAlerts)
+			require.Truef(t, ok, fmt.Sprintf("expected argument of function was supposed to be 'definitions.PostableAlerts' but got %T", sender.Calls()[0].Arguments[2]))
+			assert.Len(t, args.PostableAlerts, 1)
+			assert.Equal(t, state.ErrorAlertName, args.PostableAlerts[0].Labels[prometheusModel.AlertNameLabel])
+		})
+	})
+
+	t.Run("when evaluation exceeds the limits of the rule", func(t *testing.T) {
+		rule := gen.With(withQueryForState(t, eval.Alerting)).GenerateRef()
+		rule.EvaluationLimits = models.AlertRuleEvaluationLimits{MaxQueries: 1}
+		q := gen.GenerateQuery()
+		q.RefID = "B"
+		rule.Data = append(rule.Data, q)
+
+		evalAppliedChan := make(chan time.Time)
+
+		sender := NewSyncAlertsSenderMock()
+		sender.EXPECT().Send(mock.Anything, rule.GetKey(), mock.Anything).Return()
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, sender)
+		sch.evalLimits = setting.UnifiedAlertingEvaluationLimitsSettings{
+			Default: setting.EvaluationLimits{MaxQueries: 10},
+		}
+		ruleStore.PutRule(context.Background(), rule)
+		factory := ruleFactoryFromScheduler(sch)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		ruleInfo := factory.new(ctx, rule)
+
+		go func() {
+			_ = ruleInfo.Run()
+		}()
+
+		ruleInfo.Eval(&Evaluation{
+			scheduledAt: sch.clock.Now(),
+			rule:        rule,
+		})
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should apply the stricter limit of the rule", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluation_limit_exceeded_total The total number of rule evaluations that exceeded the evaluation limits, by limit.
+				# TYPE grafana_alerting_rule_evaluation_limit_exceeded_total counter
+				grafana_alerting_rule_evaluation_limit_exceeded_total{limit="queries",org="%[1]d"} 1
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric),
+				"grafana_alerting_rule_evaluation_limit_exceeded_total")
+			require.NoError(t, err)
+
+			states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+			require.Len(t, states, 1)
+			assert.Equal(t, eval.Error, states[0].State)
+			assert.ErrorIs(t, states[0].Error, eval.ErrLimitExceeded)
+		})
+	})
+
 	t.Run("when there are alerts that should be firing", func(t *testing.T) {
 		t.Run("it should call sender", func(t *testing.T) {
 			// eval.Alerting makes state manager to create notifications for alertmanagers
@@ -847,7 +968,7 @@ func TestRuleRoutine(t *testing.T) {
 }
 
 func ruleFactoryFromScheduler(sch *schedule) ruleFactory {
-	return newRuleFactory(sch.appURL, sch.disableGrafanaFolder, sch.maxAttempts, sch.alertsSender, sch.stateManager, sch.evaluatorFactory, &sch.schedulableAlertRules, sch.clock, sch.rrCfg, sch.metrics, sch.log, sch.tracer, sch.recordingWriter, sch.evalAppliedFunc, sch.stopAppliedFunc)
+	return newRuleFactory(sch.appURL, sch.disableGrafanaFolder, sch.maxAttempts, sch.alertsSender, sch.stateManager, sch.evaluatorFactory, sch.evalLimits, &sch.schedulableAlertRules, sch.clock, sch.rrCfg, sch.metrics, sch.log, sch.tracer, sch.recordingWriter, sch.evalAppliedFunc, sch.stopAppliedFunc)
 }
 
 func stateForRule(rule *models.AlertRule, ts time.Time, evalState eval.State) *state.State {
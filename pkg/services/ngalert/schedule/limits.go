@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Names of the evaluation limits, as they appear in logs and metrics.
+const (
+	limitDuration = "duration"
+	limitSeries   = "series"
+	limitQueries  = "queries"
+)
+
+// limitExceededError is the error of a rule evaluation that exceeded one of the limits of the rule.
+type limitExceededError struct {
+	limit  string
+	reason string
+}
+
+func (e *limitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s", eval.ErrLimitExceeded, e.reason)
+}
+
+func (e *limitExceededError) Unwrap() error {
+	return eval.ErrLimitExceeded
+}
+
+// checkQueriesLimit returns an error if the rule has more data source queries than allowed.
+// Expressions are not counted because they do not query data sources.
+func checkQueriesLimit(rule *ngmodels.AlertRule, limits setting.EvaluationLimits) *limitExceededError {
+	if limits.MaxQueries <= 0 {
+		return nil
+	}
+	queries := 0
+	for _, q := range rule.Data {
+		if isExpr, _ := q.IsExpression(); !isExpr {
+			queries++
+		}
+	}
+	if queries > limits.MaxQueries {
+		return &limitExceededError{limit: limitQueries, reason: fmt.Sprintf("rule has %d data source queries (limit: %d)", queries, limits.MaxQueries)}
+	}
+	return nil
+}
+
+// withDurationLimit returns a context that is canceled when the evaluation takes longer than allowed.
+// Use durationLimitExceeded to check whether the context was canceled because of the limit.
+func withDurationLimit(ctx context.Context, limits setting.EvaluationLimits) (context.Context, context.CancelFunc) {
+	if limits.MaxDuration <= 0 {
+		return context.WithCancel(ctx)
+	}
+	cause := &limitExceededError{limit: limitDuration, reason: fmt.Sprintf("evaluation took longer than %s", limits.MaxDuration)}
+	return context.WithTimeoutCause(ctx, limits.MaxDuration, cause)
+}
+
+// durationLimitExceeded returns an error if the context created by withDurationLimit was canceled because of the limit.
+func durationLimitExceeded(ctx context.Context) *limitExceededError {
+	var err *limitExceededError
+	if errors.As(context.Cause(ctx), &err) {
+		return err
+	}
+	return nil
+}
+
+// withSeriesLimit returns a context that makes the expression pipeline stop as soon as a data source query
+// returns more series than allowed, instead of evaluating the expressions on all of them.
+func withSeriesLimit(ctx context.Context, limits setting.EvaluationLimits) context.Context {
+	return expr.WithSeriesLimit(ctx, limits.MaxSeries)
+}
+
+// checkSeriesLimit returns an error if the evaluation was stopped because a query returned more series than
+// allowed, or if the evaluation returned more series than allowed.
+func checkSeriesLimit(err error, results eval.Results, limits setting.EvaluationLimits) *limitExceededError {
+	if limits.MaxSeries <= 0 {
+		return nil
+	}
+	if errors.Is(err, expr.ErrSeriesLimitExceeded) {
+		return &limitExceededError{limit: limitSeries, reason: err.Error()}
+	}
+	if len(results) <= limits.MaxSeries {
+		return nil
+	}
+	return &limitExceededError{limit: limitSeries, reason: fmt.Sprintf("evaluation returned %d series (limit: %d)", len(results), limits.MaxSeries)}
+}
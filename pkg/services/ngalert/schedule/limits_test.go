@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCheckQueriesLimit(t *testing.T) {
+	gen := models.RuleGen
+	rule := gen.With(gen.WithQuery(gen.GenerateQuery(), gen.GenerateQuery())).GenerateRef()
+	rule.Data = append(rule.Data, models.AlertQuery{RefID: "expr", DatasourceUID: expr.DatasourceUID})
+
+	t.Run("expressions are not counted", func(t *testing.T) {
+		require.Nil(t, checkQueriesLimit(rule, setting.EvaluationLimits{MaxQueries: 2}))
+	})
+
+	t.Run("fails if rule has more data source queries than the limit", func(t *testing.T) {
+		err := checkQueriesLimit(rule, setting.EvaluationLimits{MaxQueries: 1})
+		require.NotNil(t, err)
+		require.Equal(t, limitQueries, err.limit)
+		require.ErrorIs(t, err, eval.ErrLimitExceeded)
+	})
+
+	t.Run("no limit if zero", func(t *testing.T) {
+		require.Nil(t, checkQueriesLimit(rule, setting.EvaluationLimits{}))
+	})
+}
+
+func TestDurationLimit(t *testing.T) {
+	t.Run("context is canceled when evaluation takes longer than the limit", func(t *testing.T) {
+		ctx, cancel := withDurationLimit(context.Background(), setting.EvaluationLimits{MaxDuration: time.Millisecond})
+		defer cancel()
+		<-ctx.Done()
+		err := durationLimitExceeded(ctx)
+		require.NotNil(t, err)
+		require.Equal(t, limitDuration, err.limit)
+		require.ErrorIs(t, err, eval.ErrLimitExceeded)
+	})
+
+	t.Run("limit is not exceeded if parent context is canceled", func(t *testing.T) {
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := withDurationLimit(parent, setting.EvaluationLimits{MaxDuration: time.Hour})
+		defer cancel()
+		cancelParent()
+		<-ctx.Done()
+		require.Nil(t, durationLimitExceeded(ctx))
+	})
+
+	t.Run("no limit if zero", func(t *testing.T) {
+		ctx, cancel := withDurationLimit(context.Background(), setting.EvaluationLimits{})
+		defer cancel()
+		_, ok := ctx.Deadline()
+		require.False(t, ok)
+		require.Nil(t, durationLimitExceeded(ctx))
+	})
+}
+
+func TestCheckSeriesLimit(t *testing.T) {
+	results := eval.Results{{State: eval.Normal}, {State: eval.Alerting}, {State: eval.Alerting}}
+
+	t.Run("fails if evaluation returned more series than the limit", func(t *testing.T) {
+		err := checkSeriesLimit(nil, results, setting.EvaluationLimits{MaxSeries: 2})
+		require.NotNil(t, err)
+		require.Equal(t, limitSeries, err.limit)
+		require.ErrorIs(t, err, eval.ErrLimitExceeded)
+	})
+
+	t.Run("fails if a query returned more series than the limit", func(t *testing.T) {
+		queryErr := fmt.Errorf("%w: query A returned 3 series (limit: 2)", expr.ErrSeriesLimitExceeded)
+		err := checkSeriesLimit(queryErr, nil, setting.EvaluationLimits{MaxSeries: 2})
+		require.NotNil(t, err)
+		require.Equal(t, limitSeries, err.limit)
+		require.ErrorIs(t, err, eval.ErrLimitExceeded)
+		require.ErrorContains(t, err, "query A returned 3 series (limit: 2)")
+	})
+
+	t.Run("succeeds if evaluation returned as many series as the limit", func(t *testing.T) {
+		require.Nil(t, checkSeriesLimit(nil, results, setting.EvaluationLimits{MaxSeries: 3}))
+	})
+
+	t.Run("no limit if zero", func(t *testing.T) {
+		require.Nil(t, checkSeriesLimit(nil, results, setting.EvaluationLimits{}))
+	})
+}
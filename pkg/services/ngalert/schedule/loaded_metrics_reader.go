@@ -3,6 +3,7 @@ package schedule
 import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -42,3 +43,19 @@ func (n AlertingResultsFromRuleState) Read() map[data.Fingerprint]struct{} {
 	}
 	return active
 }
+
+var _ eval.ThresholdStateReader = AlertingResultsFromRuleState{}
+
+// ReadThresholdState returns the history of the threshold condition of the rule that is kept in the states of its
+// dimensions, if the condition is a threshold expression with several levels or a breach window.
+func (n AlertingResultsFromRuleState) ReadThresholdState() expr.ThresholdStates {
+	states := n.Manager.GetStatesForRuleUID(n.Rule.OrgID, n.Rule.UID)
+
+	history := make(expr.ThresholdStates, len(states))
+	for _, st := range states {
+		if len(st.ThresholdHistory) > 0 {
+			history[st.ResultFingerprint] = st.ThresholdHistory
+		}
+	}
+	return history
+}
@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -264,8 +265,9 @@ func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logge
 		return nil
 	}
 
+	// The queries are shifted back by the query offset, and so is the timestamp of the samples, like in Prometheus.
 	writeStart := r.clock.Now()
-	err = r.writer.Write(ctx, ev.rule.Record.Metric, ev.scheduledAt, frames, ev.rule.OrgID, ev.rule.Labels)
+	err = r.writer.Write(ctx, ev.rule.Record.Writer, ev.rule.Record.Metric, ev.scheduledAt.Add(-ev.rule.QueryOffset), frames, ev.rule.OrgID, ev.rule.Labels)
 	writeDur := r.clock.Now().Sub(writeStart)
 
 	if err != nil {
@@ -289,7 +291,7 @@ func (r *recordingRule) buildAndExecutePipeline(ctx context.Context, evalCtx eva
 		logger.Error("Failed to build rule evaluator", "error", err)
 		return nil, err
 	}
-	results, err := evaluator.EvaluateRaw(ctx, ev.scheduledAt)
+	results, err := evaluator.EvaluateRaw(expr.WithResultCache(ctx, ev.resultCache), ev.scheduledAt)
 	if err != nil {
 		logger.Error("Failed to evaluate rule", "error", err, "duration", r.clock.Now().Sub(start))
 	}
@@ -156,7 +156,7 @@ func blankRecordingRuleForTests(ctx context.Context) *recordingRule {
 	st := setting.RecordingRuleSettings{
 		Enabled: true,
 	}
-	return newRecordingRule(context.Background(), models.AlertRuleKey{}, 0, nil, nil, st, log.NewNopLogger(), nil, nil, writer.FakeWriter{}, nil, nil)
+	return newRecordingRule(context.Background(), models.AlertRuleKey{}, 0, nil, nil, st, log.NewNopLogger(), nil, nil, writer.NewRegistry(models.RecordingWriterPrometheus, writer.FakeWriter{}), nil, nil)
 }
 
 func TestRecordingRule_Integration(t *testing.T) {
@@ -167,7 +167,7 @@ func TestRecordingRule_Integration(t *testing.T) {
 	writeTarget := writer.NewTestRemoteWriteTarget(t)
 	defer writeTarget.Close()
 	writerReg := prometheus.NewPedanticRegistry()
-	sch.recordingWriter = setupWriter(t, writeTarget, writerReg)
+	sch.recordingWriter = writer.NewRegistry(models.RecordingWriterPrometheus, setupWriter(t, writeTarget, writerReg))
 
 	t.Run("rule that succeeds", func(t *testing.T) {
 		writeTarget.Reset()
@@ -12,12 +12,15 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 var (
 	errRuleDeleted   = errors.New("rule deleted")
 	errRuleRestarted = errors.New("rule restarted")
+	// errRuleHandedOver is the reason to stop the evaluation of a rule that is evaluated by another instance of the cluster.
+	errRuleHandedOver = errors.New("rule handed over to another instance")
 )
 
 type ruleFactory interface {
@@ -96,6 +99,8 @@ type Evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// resultCache is shared by the evaluations of the same tick. It is nil if sharing of query results is disabled.
+	resultCache *expr.ResultCache
 }
 
 func (e *Evaluation) Fingerprint() fingerprint {
@@ -300,6 +305,10 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeLabels(rule.Labels)
 	writeString(rule.Condition)
 	writeQuery()
+	writeInt(int64(rule.QueryOffset))
+	writeInt(int64(rule.EvaluationLimits.MaxDuration))
+	writeInt(int64(rule.EvaluationLimits.MaxSeries))
+	writeInt(int64(rule.EvaluationLimits.MaxQueries))
 
 	if rule.IsPaused {
 		writeInt(1)
@@ -317,6 +326,13 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.ID)
 	writeInt(rule.OrgID)
 	writeInt(int64(rule.For))
+	writeInt(int64(rule.KeepFiringFor))
+	for _, d := range rule.Dependencies {
+		writeString(d.RuleUID)
+		for _, name := range d.Equal {
+			writeString(name)
+		}
+	}
 	if rule.DashboardUID != nil {
 		writeString(*rule.DashboardUID)
 	}
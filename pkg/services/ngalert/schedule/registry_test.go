@@ -192,8 +192,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			RuleGroupIndex:  1,
 			NoDataState:     "test-nodata",
 			ExecErrState:    "test-err",
-			Record:          &models.Record{Metric: "my_metric", From: "A"},
+			Record:          &models.Record{Metric: "my_metric", From: "A", Writer: "sql"},
 			For:             12,
+			KeepFiringFor:   13,
+			QueryOffset:     14,
 			Annotations: map[string]string{
 				"key-annotation": "value-annotation",
 			},
@@ -209,6 +211,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 					SimplifiedQueryAndExpressionsSection: false,
 				},
 			},
+			Dependencies: []models.AlertRuleDependency{
+				{RuleUID: "parent", Equal: []string{"cluster"}},
+			},
+			EvaluationLimits: models.AlertRuleEvaluationLimits{MaxDuration: 15, MaxSeries: 16, MaxQueries: 17},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -236,8 +242,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			RuleGroupIndex:  22,
 			NoDataState:     "test-nodata2",
 			ExecErrState:    "test-err2",
-			Record:          &models.Record{Metric: "my_metric2", From: "B"},
+			Record:          &models.Record{Metric: "my_metric2", From: "B", Writer: "otlp"},
 			For:             1141,
+			KeepFiringFor:   1142,
+			QueryOffset:     1143,
 			Annotations: map[string]string{
 				"key-annotation2": "value-annotation",
 			},
@@ -253,6 +261,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 					SimplifiedQueryAndExpressionsSection: true,
 				},
 			},
+			Dependencies: []models.AlertRuleDependency{
+				{RuleUID: "parent-2"},
+			},
+			EvaluationLimits: models.AlertRuleEvaluationLimits{MaxDuration: 1144, MaxSeries: 1145, MaxQueries: 1146},
 		}
 
 		excludedFields := map[string]struct{}{
@@ -13,12 +13,14 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/ticker"
@@ -48,8 +50,10 @@ type RulesStore interface {
 	GetAlertRulesForScheduling(ctx context.Context, query *ngmodels.GetAlertRulesForSchedulingQuery) error
 }
 
+// RecordingWriter writes the results of recording rules with the writer of the given type.
+// If the type is empty, the default writer is used.
 type RecordingWriter interface {
-	Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+	Write(ctx context.Context, writerType string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
 }
 
 type schedule struct {
@@ -76,6 +80,7 @@ type schedule struct {
 	log log.Logger
 
 	evaluatorFactory eval.EvaluatorFactory
+	evalLimits       setting.UnifiedAlertingEvaluationLimitsSettings
 
 	ruleStore RulesStore
 
@@ -84,6 +89,7 @@ type schedule struct {
 	appURL               *url.URL
 	disableGrafanaFolder bool
 	jitterEvaluations    JitterStrategy
+	queryResultCache     bool
 	rrCfg                setting.RecordingRuleSettings
 
 	metrics *metrics.Scheduler
@@ -100,6 +106,12 @@ type schedule struct {
 	tracer tracing.Tracer
 
 	recordingWriter RecordingWriter
+
+	// clusterPeer is used to shard the evaluation of rules across the instances of the cluster.
+	// If it is nil, this instance evaluates all rules.
+	clusterPeer notifier.ClusterMembers
+	// rulesOwnedByPeers contains the rules that were evaluated by other instances of the cluster in the last tick.
+	rulesOwnedByPeers map[ngmodels.AlertRuleKey]struct{}
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -112,13 +124,16 @@ type SchedulerCfg struct {
 	RecordingRulesCfg    setting.RecordingRuleSettings
 	AppURL               *url.URL
 	JitterEvaluations    JitterStrategy
+	QueryResultCache     bool
 	EvaluatorFactory     eval.EvaluatorFactory
+	EvaluationLimits     setting.UnifiedAlertingEvaluationLimitsSettings
 	RuleStore            RulesStore
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 	RecordingWriter      RecordingWriter
+	ClusterPeer          notifier.ClusterMembers
 }
 
 // NewScheduler returns a new scheduler.
@@ -136,11 +151,13 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		baseInterval:          cfg.BaseInterval,
 		log:                   cfg.Log,
 		evaluatorFactory:      cfg.EvaluatorFactory,
+		evalLimits:            cfg.EvaluationLimits,
 		ruleStore:             cfg.RuleStore,
 		metrics:               cfg.Metrics,
 		appURL:                cfg.AppURL,
 		disableGrafanaFolder:  cfg.DisableGrafanaFolder,
 		jitterEvaluations:     cfg.JitterEvaluations,
+		queryResultCache:      cfg.QueryResultCache,
 		rrCfg:                 cfg.RecordingRulesCfg,
 		stateManager:          stateManager,
 		minRuleInterval:       cfg.MinRuleInterval,
@@ -148,13 +165,15 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
 		recordingWriter:       cfg.RecordingWriter,
+		clusterPeer:           cfg.ClusterPeer,
+		rulesOwnedByPeers:     make(map[ngmodels.AlertRuleKey]struct{}),
 	}
 
 	return &sch
 }
 
 func (sch *schedule) Run(ctx context.Context) error {
-	sch.log.Info("Starting scheduler", "tickInterval", sch.baseInterval, "maxAttempts", sch.maxAttempts)
+	sch.log.Info("Starting scheduler", "tickInterval", sch.baseInterval, "maxAttempts", sch.maxAttempts, "sharding", sch.clusterPeer != nil)
 	t := ticker.New(sch.clock, sch.baseInterval, sch.metrics.Ticker)
 	defer t.Stop()
 
@@ -257,6 +276,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	sch.updateRulesMetrics(alertRules)
 
 	readyToRun := make([]readyToRunItem, 0)
+	// rules evaluated in this tick share the results of identical queries
+	var resultCache *expr.ResultCache
+	if sch.queryResultCache {
+		resultCache = expr.NewResultCache()
+	}
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	restartedRules := make([]Rule, 0)
 	missingFolder := make(map[string][]string)
@@ -267,6 +291,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.alertsSender,
 		sch.stateManager,
 		sch.evaluatorFactory,
+		sch.evalLimits,
 		&sch.schedulableAlertRules,
 		sch.clock,
 		sch.rrCfg,
@@ -277,8 +302,10 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.evalAppliedFunc,
 		sch.stopAppliedFunc,
 	)
+	// owns tells whether the rule is evaluated by this instance or by another instance of the cluster
+	owns := sch.currentShard()
+	rulesOwnedByPeers := make(map[ngmodels.AlertRuleKey]struct{})
 	for _, item := range alertRules {
-		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
 		key := item.GetKey()
 		logger := sch.log.FromContext(ctx).New(key.LogContext()...)
 
@@ -290,6 +317,25 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 		invalidInterval := item.IntervalSeconds%int64(sch.baseInterval.Seconds()) != 0
 
+		if !owns(key) {
+			// the instance that evaluates the rule now picks up its state from the database
+			if ruleRoutine, ok := sch.registry.del(key); ok {
+				logger.Info("Rule is evaluated by another instance of the cluster")
+				ruleRoutine.Stop(errRuleHandedOver)
+			}
+			if _, ok := sch.rulesOwnedByPeers[key]; !ok {
+				// the state in the cache becomes stale, it is reloaded if the rule is handed back to this instance.
+				// No resolved alerts are sent: the firing alerts stay in the Alertmanager of this instance until they expire,
+				// while the new owner sends them to its Alertmanager on its first evaluation.
+				sch.stateManager.ForgetRuleStates(key)
+			}
+			rulesOwnedByPeers[key] = struct{}{}
+			delete(registeredDefinitions, key)
+			continue
+		}
+
+		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
+
 		if item.Type() != ruleRoutine.Type() {
 			// Restart rules that need it. For now we just replace them, we'll shut them down at the end of the tick.
 			logger.Debug("Rule restarted because type changed", "old", ruleRoutine.Type(), "new", item.Type())
@@ -299,7 +345,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		if newRoutine && !invalidInterval {
+			_, handedOver := sch.rulesOwnedByPeers[key]
 			dispatcherGroup.Go(func() error {
+				if handedOver {
+					// pick up the state written by the instance that evaluated the rule before
+					sch.stateManager.ReloadRuleStates(ctx, item)
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -313,7 +364,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
 		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
-		isReadyToRun := item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
+		isReadyToRun := readyToRunOnTick(item, tickNum, sch.baseInterval, sch.jitterEvaluations)
 
 		var folderTitle string
 		if !sch.disableGrafanaFolder {
@@ -331,6 +382,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 				scheduledAt: tick,
 				rule:        item,
 				folderTitle: folderTitle,
+				resultCache: resultCache,
 			}})
 		}
 		if _, isUpdated := updated[key]; isUpdated && !isReadyToRun {
@@ -352,6 +404,8 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		delete(registeredDefinitions, key)
 	}
 
+	sch.rulesOwnedByPeers = rulesOwnedByPeers
+
 	if len(missingFolder) > 0 { // if this happens then there can be problems with fetching folders from the database.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
@@ -395,3 +449,10 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	sch.deleteAlertRule(toDelete...)
 	return readyToRun, registeredDefinitions, updatedRules
 }
+
+// readyToRunOnTick returns true if the rule should be evaluated on the given tick.
+func readyToRunOnTick(item *ngmodels.AlertRule, tickNum int64, baseInterval time.Duration, jitter JitterStrategy) bool {
+	itemFrequency := item.IntervalSeconds / int64(baseInterval.Seconds())
+	offset := jitterOffsetInTicks(item, baseInterval, jitter)
+	return item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
+}
@@ -977,7 +977,7 @@ func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStor
 		},
 	}
 
-	fakeRecordingWriter := writer.FakeWriter{}
+	fakeRecordingWriter := writer.NewRegistry(models.RecordingWriterPrometheus, writer.FakeWriter{})
 
 	schedCfg := SchedulerCfg{
 		BaseInterval:      cfg.BaseInterval,
@@ -0,0 +1,64 @@
+package schedule
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleShard tells whether this instance evaluates the rule.
+type ruleShard func(key ngmodels.AlertRuleKey) bool
+
+func ownsAllRules(ngmodels.AlertRuleKey) bool {
+	return true
+}
+
+// currentShard returns the rules that are evaluated by this instance with the current members of the cluster.
+// If sharding is disabled, or this instance is not a healthy member of the cluster yet, it evaluates all rules.
+func (sch *schedule) currentShard() ruleShard {
+	if sch.clusterPeer == nil {
+		return ownsAllRules
+	}
+	self := sch.clusterPeer.Self()
+	members := sch.clusterPeer.Members()
+	if !slices.Contains(members, self) {
+		sch.log.Debug("Instance is not a member of the cluster, evaluating all rules", "self", self, "members", members)
+		return ownsAllRules
+	}
+	return func(key ngmodels.AlertRuleKey) bool {
+		return shardOwner(members, key) == self
+	}
+}
+
+// shardOwner returns the member of the cluster that evaluates the rule.
+// It uses rendezvous hashing: the owner is the member with the highest hash of the member name and the rule key.
+// Therefore, when a member joins or leaves the cluster, only the rules it owns, or is going to own, move to other members.
+func shardOwner(members []string, key ngmodels.AlertRuleKey) string {
+	var owner string
+	var maxScore uint64
+	for _, member := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(member))
+		_, _ = h.Write([]byte{255})
+		_, _ = h.Write([]byte(strconv.FormatInt(key.OrgID, 10)))
+		_, _ = h.Write([]byte{255})
+		_, _ = h.Write([]byte(key.UID))
+		score := mix64(h.Sum64())
+		if owner == "" || score > maxScore || (score == maxScore && member < owner) {
+			owner, maxScore = member, score
+		}
+	}
+	return owner
+}
+
+// mix64 spreads the bits of the hash, so that the owners are evenly distributed across members with similar names.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
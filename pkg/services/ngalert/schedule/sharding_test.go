@@ -0,0 +1,160 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeClusterPeer struct {
+	self    string
+	members []string
+}
+
+func (p *fakeClusterPeer) Self() string {
+	return p.self
+}
+
+func (p *fakeClusterPeer) Members() []string {
+	return p.members
+}
+
+func TestShardOwner(t *testing.T) {
+	members := []string{"grafana-0", "grafana-1", "grafana-2"}
+	keys := make([]models.AlertRuleKey, 0, 3000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, models.AlertRuleKey{OrgID: int64(i%3 + 1), UID: fmt.Sprintf("rule-%d", i)})
+	}
+
+	t.Run("owner does not depend on the order of members", func(t *testing.T) {
+		reversed := []string{"grafana-2", "grafana-1", "grafana-0"}
+		for _, key := range keys {
+			require.Equal(t, shardOwner(members, key), shardOwner(reversed, key))
+		}
+	})
+
+	t.Run("rules are distributed across all members", func(t *testing.T) {
+		owned := make(map[string]int)
+		for _, key := range keys {
+			owned[shardOwner(members, key)]++
+		}
+		for _, member := range members {
+			require.InDelta(t, len(keys)/len(members), owned[member], float64(len(keys))/10, "member %s owns too few or too many rules", member)
+		}
+	})
+
+	t.Run("only rules of the member that leaves are moved", func(t *testing.T) {
+		remaining := []string{"grafana-0", "grafana-2"}
+		for _, key := range keys {
+			before := shardOwner(members, key)
+			after := shardOwner(remaining, key)
+			if before != "grafana-1" {
+				require.Equal(t, before, after)
+			} else {
+				require.Contains(t, remaining, after)
+			}
+		}
+	})
+}
+
+func TestSchedule_currentShard(t *testing.T) {
+	key := models.GenerateRuleKey(1)
+
+	t.Run("owns all rules if there is no cluster peer", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		require.True(t, sch.currentShard()(key))
+	})
+
+	t.Run("owns all rules if instance is not a member of the cluster", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		sch.clusterPeer = &fakeClusterPeer{self: "grafana-0", members: []string{"grafana-1", "grafana-2"}}
+		require.True(t, sch.currentShard()(key))
+	})
+
+	t.Run("owns rules assigned to the instance", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		members := []string{"grafana-0", "grafana-1"}
+		sch.clusterPeer = &fakeClusterPeer{self: "grafana-0", members: members}
+		require.Equal(t, shardOwner(members, key) == "grafana-0", sch.currentShard()(key))
+	})
+}
+
+func TestProcessTicks_Sharding(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	peer := &fakeClusterPeer{self: "grafana-0", members: []string{"grafana-0", "grafana-1"}}
+	sch.clusterPeer = peer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	gen := models.RuleGen
+	rules := gen.With(gen.WithOrgID(1), gen.WithInterval(time.Second)).GenerateManyRef(20)
+	ruleStore.PutRule(ctx, rules...)
+
+	owned := make(map[models.AlertRuleKey]bool, len(rules))
+	for _, rule := range rules {
+		owned[rule.GetKey()] = shardOwner(peer.members, rule.GetKey()) == peer.self
+	}
+
+	tick := time.Time{}
+
+	t.Run("evaluates only rules owned by the instance", func(t *testing.T) {
+		tick = tick.Add(sch.baseInterval)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped)
+		for _, item := range scheduled {
+			require.True(t, owned[item.rule.GetKey()])
+		}
+		for key, isOwned := range owned {
+			require.Equal(t, isOwned, sch.registry.exists(key))
+			_, ownedByPeer := sch.rulesOwnedByPeers[key]
+			require.Equal(t, !isOwned, ownedByPeer)
+		}
+	})
+
+	t.Run("takes over rules when a member leaves the cluster", func(t *testing.T) {
+		peer.members = []string{"grafana-0"}
+		tick = tick.Add(sch.baseInterval)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped)
+		require.Len(t, scheduled, len(rules))
+		require.Empty(t, sch.rulesOwnedByPeers)
+	})
+
+	t.Run("hands over rules without deleting them when a member joins the cluster", func(t *testing.T) {
+		routines := make(map[models.AlertRuleKey]Rule, len(rules))
+		for key := range owned {
+			routines[key], _ = sch.registry.get(key)
+		}
+
+		peer.members = []string{"grafana-0", "grafana-1"}
+		tick = tick.Add(sch.baseInterval)
+		_, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped, "rules evaluated by other instances must not be deleted")
+		for key, isOwned := range owned {
+			require.Equal(t, isOwned, sch.registry.exists(key))
+			if ar, ok := routines[key].(*alertRule); ok && !isOwned {
+				require.ErrorIs(t, ar.ctx.Err(), errRuleHandedOver)
+			}
+		}
+	})
+
+	t.Run("forgets rules owned by other instances when they are deleted", func(t *testing.T) {
+		ruleStore.DeleteRule(rules...)
+		tick = tick.Add(sch.baseInterval)
+		_, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		for key, isOwned := range owned {
+			_, ok := stopped[key]
+			require.Equal(t, isOwned, ok)
+		}
+		require.Empty(t, sch.rulesOwnedByPeers)
+	})
+}
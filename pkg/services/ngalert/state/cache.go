@@ -261,6 +261,16 @@ func (c *cache) setAllStates(newStates map[int64]map[string]*ruleStates) {
 	c.states = newStates
 }
 
+// setRuleStates replaces all states of the rule.
+func (c *cache) setRuleStates(orgID int64, ruleUID string, states *ruleStates) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[orgID]; !ok {
+		c.states[orgID] = make(map[string]*ruleStates)
+	}
+	c.states[orgID][ruleUID] = states
+}
+
 func (c *cache) set(entry *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -354,7 +364,7 @@ func (c *cache) GetAlertInstances(skipNormalState bool) []ngModels.AlertInstance
 	for _, orgStates := range c.states {
 		for _, v1 := range orgStates {
 			for _, v2 := range v1.states {
-				if skipNormalState && IsNormalStateWithNoReason(v2) {
+				if skipNormalState && IsNormalStateWithNoReason(v2) && len(v2.ThresholdHistory) == 0 {
 					continue
 				}
 				key, err := v2.GetAlertInstanceKey()
@@ -371,7 +381,9 @@ func (c *cache) GetAlertInstances(skipNormalState bool) []ngModels.AlertInstance
 					CurrentStateEnd:   v2.EndsAt,
 					ResolvedAt:        v2.ResolvedAt,
 					LastSentAt:        v2.LastSentAt,
+					KeepFiringSince:   v2.KeepFiringSince,
 					ResultFingerprint: v2.ResultFingerprint.String(),
+					ThresholdHistory:  v2.ThresholdHistory,
 				})
 			}
 		}
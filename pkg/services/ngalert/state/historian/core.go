@@ -47,6 +47,12 @@ func ShouldRecordAnnotation(t state.StateTransition) bool {
 		return false
 	}
 
+	// Do not log transitions of Alerting states that start or stop being kept firing
+	if t.State.State == eval.Alerting && t.PreviousState == eval.Alerting &&
+		(t.StateReason == models.StateReasonKeepFiring || t.PreviousStateReason == models.StateReasonKeepFiring) {
+		return false
+	}
+
 	// Do not record transitions between Normal and Normal (NoData)
 	if t.State.State == eval.Normal && t.PreviousState == eval.Normal {
 		if (t.State.StateReason == "" && t.PreviousStateReason == models.StateReasonNoData) ||
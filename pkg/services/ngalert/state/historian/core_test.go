@@ -128,6 +128,16 @@ func TestShouldRecordAnnotation(t *testing.T) {
 		require.True(t, ShouldRecordAnnotation(missingSeriesBackward), "Normal(MissingSeries) -> Normal(NoData) should be true")
 	})
 
+	t.Run("transitions between Alerting and Alerting(KeepFiring) not recorded", func(t *testing.T) {
+		forward := transition(eval.Alerting, "", eval.Alerting, models.StateReasonKeepFiring)
+		backward := transition(eval.Alerting, models.StateReasonKeepFiring, eval.Alerting, "")
+		resolve := transition(eval.Alerting, models.StateReasonKeepFiring, eval.Normal, "")
+
+		require.False(t, ShouldRecordAnnotation(forward), "Alerting -> Alerting(KeepFiring) should be false")
+		require.False(t, ShouldRecordAnnotation(backward), "Alerting(KeepFiring) -> Alerting should be false")
+		require.True(t, ShouldRecordAnnotation(resolve), "Alerting(KeepFiring) -> Normal should be true")
+	})
+
 	t.Run("respects filters in shouldRecord()", func(t *testing.T) {
 		missingSeries := transition(eval.Normal, "", eval.Normal, models.StateReasonMissingSeries)
 		unpause := transition(eval.Normal, models.StateReasonPaused, eval.Normal, "")
@@ -0,0 +1,303 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/retention"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+const (
+	sqlHistoryTable      = "alert_state_history"
+	sqlHistoryLabelTable = "alert_state_history_label"
+)
+
+// sqlHistoryEntry is a state transition stored in the alert_state_history table.
+type sqlHistoryEntry struct {
+	ID                int64  `xorm:"pk autoincr 'id'"`
+	OrgID             int64  `xorm:"org_id"`
+	RuleUID           string `xorm:"rule_uid"`
+	FolderUID         string `xorm:"folder_uid"`
+	RuleGroup         string `xorm:"rule_group"`
+	DashboardUID      string `xorm:"dashboard_uid"`
+	PanelID           int64  `xorm:"panel_id"`
+	LabelsFingerprint string `xorm:"labels_fingerprint"`
+	State             string `xorm:"state"`
+	Line              string `xorm:"line"`
+	Epoch             int64  `xorm:"epoch"`
+}
+
+func (sqlHistoryEntry) TableName() string {
+	return sqlHistoryTable
+}
+
+// sqlHistoryLabel is a hash of a single instance label of a state transition stored in the alert_state_history_label table.
+type sqlHistoryLabel struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	HistoryID int64  `xorm:"history_id"`
+	LabelHash string `xorm:"label_hash"`
+}
+
+func (sqlHistoryLabel) TableName() string {
+	return sqlHistoryLabelTable
+}
+
+// SQLBackend is a state.Historian that records state history to dedicated tables in the Grafana database.
+type SQLBackend struct {
+	db        db.DB
+	retention time.Duration
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+	ac        AccessControl
+	ruleStore RuleStore
+
+	cleanup retention.Throttle
+}
+
+// NewSQLBackend creates a new SQLBackend. Entries older than retention are periodically deleted. If retention is 0, entries are kept forever.
+func NewSQLBackend(logger log.Logger, store db.DB, retention time.Duration, metrics *metrics.Historian, ruleStore RuleStore, ac AccessControl) *SQLBackend {
+	return &SQLBackend{
+		db:        store,
+		retention: retention,
+		clock:     clock.New(),
+		metrics:   metrics,
+		log:       logger,
+		ac:        ac,
+		ruleStore: ruleStore,
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	// Build entries before starting goroutine, to make sure all data is copied and won't mutate underneath us.
+	entries, labels := statesToSQLEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	// This also prevents timeouts or other lingering objects (like transactions) from being
+	// incorrectly propagated here from other areas.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		logger.Debug("Saving state history batch", "samples", len(entries))
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(entries)))
+
+		if err := h.save(ctx, entries, labels); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(entries)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "samples", len(entries))
+
+		if h.shouldDeleteExpired() {
+			deleted, err := h.deleteExpired(ctx)
+			if err != nil {
+				logger.Error("Failed to delete expired alert state history", "error", err)
+				return
+			}
+			logger.Debug("Deleted expired alert state history", "deleted", deleted)
+		}
+	}(writeCtx)
+	return errCh
+}
+
+func (h *SQLBackend) save(ctx context.Context, entries []sqlHistoryEntry, labels [][]string) error {
+	return h.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for i := range entries {
+			if _, err := sess.Insert(&entries[i]); err != nil {
+				return err
+			}
+			if len(labels[i]) == 0 {
+				continue
+			}
+			rows := make([]sqlHistoryLabel, 0, len(labels[i]))
+			for _, hash := range labels[i] {
+				rows = append(rows, sqlHistoryLabel{HistoryID: entries[i].ID, LabelHash: hash})
+			}
+			if _, err := sess.Insert(&rows); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query retrieves state history entries from the database and formats the results into a dataframe.
+// The dataframe has the same format as the one returned by the Loki backend.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := getFolderUIDsForFilter(ctx, h.ac, h.ruleStore, query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maximumPageSize {
+		limit = maximumPageSize
+	}
+
+	var entries []sqlHistoryEntry
+	err = h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(sqlHistoryTable).
+			Where("org_id = ?", query.OrgID).
+			And("epoch >= ?", query.From.UnixMilli()).
+			And("epoch <= ?", query.To.UnixMilli())
+		if query.RuleUID != "" {
+			q = q.And("rule_uid = ?", query.RuleUID)
+		}
+		if query.DashboardUID != "" {
+			q = q.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.PanelID != 0 {
+			q = q.And("panel_id = ?", query.PanelID)
+		}
+		if len(uids) > 0 {
+			q = q.In("folder_uid", uids)
+		}
+		// Ensure that all queries we build are deterministic.
+		labelKeys := make([]string, 0, len(query.Labels))
+		for k := range query.Labels {
+			labelKeys = append(labelKeys, k)
+		}
+		sort.Strings(labelKeys)
+		for _, k := range labelKeys {
+			q = q.And(fmt.Sprintf("EXISTS (SELECT 1 FROM %s l WHERE l.history_id = %s.id AND l.label_hash = ?)", sqlHistoryLabelTable, sqlHistoryTable), labelHash(k, query.Labels[k]))
+		}
+		// Take the latest entries first, so the limit drops the oldest ones.
+		return q.Desc("epoch", "id").Limit(limit).Find(&entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state history: %w", err)
+	}
+	return sqlEntriesToFrame(entries)
+}
+
+// sqlEntriesToFrame converts entries sorted from the newest to the oldest to a dataframe sorted by time.
+func sqlEntriesToFrame(entries []sqlHistoryEntry) (*data.Frame, error) {
+	frame := data.NewFrame("states")
+	lbls := data.Labels(map[string]string{})
+
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		line, err := jsonifyRow(entry.Line)
+		if err != nil {
+			return nil, fmt.Errorf("a line was in an invalid format: %w", err)
+		}
+		lblsJson, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(entry.OrgID),
+			GroupLabel:           entry.RuleGroup,
+			FolderUIDLabel:       entry.FolderUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize entry labels: %w", err)
+		}
+		times = append(times, time.UnixMilli(entry.Epoch))
+		lines = append(lines, line)
+		labels = append(labels, lblsJson)
+	}
+
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+	return frame, nil
+}
+
+// statesToSQLEntries builds an entry for every state transition that should be recorded, and the hashes of its instance labels.
+func statesToSQLEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) ([]sqlHistoryEntry, [][]string) {
+	entries := make([]sqlHistoryEntry, 0, len(states))
+	labels := make([][]string, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		entry := newLokiEntry(rule, state)
+		jsn, err := json.Marshal(entry)
+		if err != nil {
+			logger.Error("Failed to construct history record for state, skipping", "error", err)
+			continue
+		}
+
+		hashes := make([]string, 0, len(entry.InstanceLabels))
+		for k, v := range entry.InstanceLabels {
+			hashes = append(hashes, labelHash(k, v))
+		}
+		sort.Strings(hashes)
+
+		entries = append(entries, sqlHistoryEntry{
+			OrgID:             rule.OrgID,
+			RuleUID:           rule.UID,
+			FolderUID:         rule.NamespaceUID,
+			RuleGroup:         rule.Group,
+			DashboardUID:      rule.DashboardUID,
+			PanelID:           rule.PanelID,
+			LabelsFingerprint: entry.Fingerprint,
+			State:             state.State.State.String(),
+			Line:              string(jsn),
+			Epoch:             state.State.LastEvaluationTime.UnixMilli(),
+		})
+		labels = append(labels, hashes)
+	}
+	return entries, labels
+}
+
+// labelHash calculates a stable hash of a single label, which is used to filter entries by labels.
+func labelHash(name, value string) string {
+	return labelFingerprint(data.Labels{name: value})
+}
+
+// shouldDeleteExpired returns true if retention is set and the last cleanup happened more than retention.Interval ago.
+func (h *SQLBackend) shouldDeleteExpired() bool {
+	return h.retention > 0 && h.cleanup.Due(h.clock.Now())
+}
+
+// deleteExpired deletes entries that are older than the retention period, along with their labels.
+func (h *SQLBackend) deleteExpired(ctx context.Context) (int64, error) {
+	return retention.DeleteBefore(ctx, h.db, sqlHistoryTable, h.clock.Now().Add(-h.retention).UnixMilli(),
+		retention.Reference{Table: sqlHistoryLabelTable, Column: "history_id"})
+}
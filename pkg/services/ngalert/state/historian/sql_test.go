@@ -0,0 +1,217 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestStatesToSQLEntries(t *testing.T) {
+	logger := log.NewNopLogger()
+	rule := createTestRule()
+	now := time.Now()
+
+	t.Run("skips transitions that should not be recorded", func(t *testing.T) {
+		states := []state.StateTransition{
+			{
+				PreviousState: eval.Normal,
+				State:         &state.State{State: eval.Normal, Labels: data.Labels{"a": "b"}, LastEvaluationTime: now},
+			},
+		}
+
+		entries, labels := statesToSQLEntries(rule, states, logger)
+
+		require.Empty(t, entries)
+		require.Empty(t, labels)
+	})
+
+	t.Run("builds entries from transitions", func(t *testing.T) {
+		states := singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"a": "b", "__private__": "c"},
+			LastEvaluationTime: now,
+		})
+
+		entries, labels := statesToSQLEntries(rule, states, logger)
+
+		require.Len(t, entries, 1)
+		entry := entries[0]
+		require.Equal(t, rule.OrgID, entry.OrgID)
+		require.Equal(t, rule.UID, entry.RuleUID)
+		require.Equal(t, rule.NamespaceUID, entry.FolderUID)
+		require.Equal(t, rule.Group, entry.RuleGroup)
+		require.Equal(t, rule.DashboardUID, entry.DashboardUID)
+		require.Equal(t, rule.PanelID, entry.PanelID)
+		require.Equal(t, eval.Alerting.String(), entry.State)
+		require.Equal(t, now.UnixMilli(), entry.Epoch)
+		require.Equal(t, labelFingerprint(data.Labels{"a": "b"}), entry.LabelsFingerprint)
+
+		var line LokiEntry
+		require.NoError(t, json.Unmarshal([]byte(entry.Line), &line))
+		require.Equal(t, "Normal", line.Previous)
+		require.Equal(t, "Alerting", line.Current)
+		require.Equal(t, map[string]string{"a": "b"}, line.InstanceLabels)
+
+		require.Equal(t, [][]string{{labelHash("a", "b")}}, labels)
+	})
+}
+
+func TestIntegrationSQLBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	start := clk.Now()
+
+	rule := createTestRule()
+	otherRule := createTestRule()
+	otherRule.UID = "other-rule-uid"
+	otherRule.NamespaceUID = "other-folder"
+
+	ac := &acfakes.FakeRuleService{
+		CanReadAllRulesFunc: func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		},
+	}
+	backend := createTestSQLBackend(t, sqlStore, clk, fakes.NewRuleStore(t), ac)
+
+	transition := func(rule string, cluster string, st eval.State, at time.Time) []state.StateTransition {
+		return []state.StateTransition{
+			{
+				PreviousState: eval.Normal,
+				State: &state.State{
+					AlertRuleUID:       rule,
+					State:              st,
+					Labels:             data.Labels{"alertname": rule, "cluster": cluster},
+					LastEvaluationTime: at,
+				},
+			},
+		}
+	}
+	require.NoError(t, <-backend.Record(context.Background(), rule, transition(rule.UID, "a", eval.Alerting, start)))
+	require.NoError(t, <-backend.Record(context.Background(), rule, transition(rule.UID, "b", eval.Pending, start.Add(time.Minute))))
+	require.NoError(t, <-backend.Record(context.Background(), otherRule, transition(otherRule.UID, "a", eval.Alerting, start.Add(2*time.Minute))))
+
+	query := func(t *testing.T, q models.HistoryQuery) []LokiEntry {
+		t.Helper()
+		q.OrgID = rule.OrgID
+		q.From = start.Add(-time.Hour)
+		q.To = start.Add(time.Hour)
+		frame, err := backend.Query(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+		result := make([]LokiEntry, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	t.Run("returns all entries sorted by time", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{})
+		require.Len(t, entries, 3)
+		require.Equal(t, rule.UID, entries[0].RuleUID)
+		require.Equal(t, "a", entries[0].InstanceLabels["cluster"])
+		require.Equal(t, rule.UID, entries[1].RuleUID)
+		require.Equal(t, "b", entries[1].InstanceLabels["cluster"])
+		require.Equal(t, otherRule.UID, entries[2].RuleUID)
+	})
+
+	t.Run("filters by rule UID", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{RuleUID: otherRule.UID})
+		require.Len(t, entries, 1)
+		require.Equal(t, otherRule.UID, entries[0].RuleUID)
+	})
+
+	t.Run("filters by labels", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{Labels: map[string]string{"cluster": "a"}})
+		require.Len(t, entries, 2)
+		for _, entry := range entries {
+			require.Equal(t, "a", entry.InstanceLabels["cluster"])
+		}
+
+		entries = query(t, models.HistoryQuery{Labels: map[string]string{"cluster": "a", "alertname": rule.UID}})
+		require.Len(t, entries, 1)
+		require.Equal(t, rule.UID, entries[0].RuleUID)
+
+		entries = query(t, models.HistoryQuery{Labels: map[string]string{"cluster": "c"}})
+		require.Empty(t, entries)
+	})
+
+	t.Run("limit keeps the latest entries", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{Limit: 2})
+		require.Len(t, entries, 2)
+		require.Equal(t, "b", entries[0].InstanceLabels["cluster"])
+		require.Equal(t, otherRule.UID, entries[1].RuleUID)
+	})
+
+	t.Run("filters by folders the user can access", func(t *testing.T) {
+		rules := fakes.NewRuleStore(t)
+		rules.Rules = map[int64][]*models.AlertRule{
+			rule.OrgID: {models.RuleGen.With(models.RuleMuts.WithNamespaceUID(otherRule.NamespaceUID)).GenerateRef()},
+		}
+		rules.Folders = map[int64][]*folder.Folder{
+			rule.OrgID: {{UID: otherRule.NamespaceUID, OrgID: rule.OrgID}},
+		}
+		restricted := createTestSQLBackend(t, sqlStore, clk, rules, &acfakes.FakeRuleService{
+			HasAccessInFolderFunc: func(ctx context.Context, user identity.Requester, namespaced models.Namespaced) (bool, error) {
+				return true, nil
+			},
+		})
+
+		frame, err := restricted.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, From: start.Add(-time.Hour), To: start.Add(time.Hour)})
+		require.NoError(t, err)
+		require.Equal(t, 1, frame.Rows())
+	})
+
+	t.Run("deletes entries older than retention", func(t *testing.T) {
+		clk.Add(sqlRetentionForTest + 90*time.Second)
+		// Entries of the first rule are now older than the retention period.
+		require.NoError(t, <-backend.Record(context.Background(), rule, transition(rule.UID, "c", eval.Alerting, clk.Now())))
+
+		q := models.HistoryQuery{OrgID: rule.OrgID, From: start.Add(-time.Hour), To: clk.Now().Add(time.Hour)}
+		frame, err := backend.Query(context.Background(), q)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+
+		var labelsCount int64
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			labelsCount, err = sess.Table(sqlHistoryLabelTable).Count()
+			return err
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 4, labelsCount)
+	})
+}
+
+const sqlRetentionForTest = time.Hour
+
+func createTestSQLBackend(t *testing.T, store db.DB, clk clock.Clock, rules RuleStore, ac AccessControl) *SQLBackend {
+	t.Helper()
+	logger := log.New("ngalert.state.historian", "backend", "sql")
+	backend := NewSQLBackend(logger, store, sqlRetentionForTest, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem), rules, ac)
+	backend.clock = clk
+	return backend
+}
@@ -187,44 +187,14 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader, instanceRea
 				continue
 			}
 
-			// nil safety.
-			annotations := ruleForEntry.Annotations
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
-
 			rulesStates, ok := orgStates[entry.RuleUID]
 			if !ok {
 				rulesStates = &ruleStates{states: make(map[data.Fingerprint]*State)}
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
+			state := st.stateFromInstance(entry, ruleForEntry)
+			rulesStates.states[state.CacheID] = state
 			statesCount++
 		}
 	}
@@ -233,6 +203,75 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader, instanceRea
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// ReloadRuleStates replaces the states of the rule in the cache with its alert instances in the instance store.
+// It is used when the rule is handed over by another instance of the HA cluster, which persists the states on every evaluation.
+// The states are sent on the next evaluation regardless of when the other instance last sent them, because the Alertmanager of this instance has not received them yet.
+func (st *Manager) ReloadRuleStates(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		st.log.Error("Unable to fetch the state of the rule", "error", err, "orgID", rule.OrgID, "ruleUID", rule.UID)
+		return
+	}
+
+	rs := &ruleStates{states: make(map[data.Fingerprint]*State, len(alertInstances))}
+	for _, entry := range alertInstances {
+		state := st.stateFromInstance(entry, rule)
+		state.LastSentAt = nil
+		rs.states[state.CacheID] = state
+	}
+	st.cache.setRuleStates(rule.OrgID, rule.UID, rs)
+}
+
+// stateFromInstance converts an alert instance loaded from the instance store to the state of the given rule.
+func (st *Manager) stateFromInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule) *State {
+	// nil safety.
+	annotations := rule.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	lbs := map[string]string(entry.Labels)
+	cacheID := entry.Labels.Fingerprint()
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
+		KeepFiringSince:      entry.KeepFiringSince,
+		ThresholdHistory:     entry.ThresholdHistory,
+	}
+}
+
+// ForgetRuleStates removes the states of the rule from the cache without deleting them from the instance store.
+func (st *Manager) ForgetRuleStates(key ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(key.OrgID, key.UID)
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -417,9 +456,16 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
 	}
+	history, hasHistory := newThresholdHistory(alertRule)
 	transitions := make([]StateTransition, 0, len(results))
 	for _, result := range results {
 		currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL)
+		if !hasHistory {
+			currentState.ThresholdHistory = nil
+		} else if !results.HasErrors() {
+			// Like for the previous results of hysteresis, results with errors are not recorded.
+			currentState.ThresholdHistory = history.next(alertRule.Condition, result, currentState.ThresholdHistory)
+		}
 		s := st.setNextState(ctx, alertRule, currentState, result, logger)
 		transitions = append(transitions, s)
 	}
@@ -503,6 +549,26 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		currentState.StateReason = resultStateReason(result, alertRule)
 	}
 
+	// Keep track of the Alerting states that are kept firing because of KeepFiringFor.
+	// The timer restarts every time the condition is met again.
+	if currentState.State != eval.Alerting || result.State == eval.Alerting {
+		currentState.KeepFiringSince = nil
+	} else if currentState.KeepFiringSince != nil && result.State == eval.Normal {
+		currentState.StateReason = ngModels.StateReasonKeepFiring
+	}
+
+	// Alerting states are inhibited while a firing instance of a rule they depend on matches them.
+	if currentState.State == eval.Alerting {
+		if ruleUID, ok := st.inhibitingRuleUID(alertRule, currentState); ok {
+			logger.Debug("Alert instance is inhibited", "inhibited_by", ruleUID)
+			if currentState.StateReason == "" {
+				currentState.StateReason = ngModels.StateReasonInhibited
+			} else {
+				currentState.StateReason = ngModels.ConcatReasons(currentState.StateReason, ngModels.StateReasonInhibited)
+			}
+		}
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
 	newlyResolved := false
@@ -584,6 +650,19 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 	}
 }
 
+// inhibitingRuleUID returns the UID of the first rule in the dependencies of alertRule that has an Alerting state
+// that matches the state s. The states of the other rules are the ones of their latest evaluation.
+func (st *Manager) inhibitingRuleUID(alertRule *ngModels.AlertRule, s *State) (string, bool) {
+	for _, dependency := range alertRule.Dependencies {
+		for _, parent := range st.cache.getStatesForRuleUID(alertRule.OrgID, dependency.RuleUID, true) {
+			if parent.State == eval.Alerting && dependency.Matches(parent.Labels, s.Labels) {
+				return dependency.RuleUID, true
+			}
+		}
+	}
+	return "", false
+}
+
 func (st *Manager) deleteStaleStatesFromCache(ctx context.Context, logger log.Logger, evaluatedAt time.Time, alertRule *ngModels.AlertRule) []StateTransition {
 	// If we are removing two or more stale series it makes sense to share the resolved image as the alert rule is the same.
 	// TODO: We will need to change this when we support images without screenshots as each series will have a different image
@@ -568,6 +568,110 @@ func TestProcessEvalResults(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:      "alerting -> normal keeps alerting when KeepFiringFor is set",
+			alertRule: baseRuleWith(m.WithKeepFiringFor(3 * evaluationInterval)),
+			evalResults: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+			},
+			expectedAnnotations: 1,
+			expectedStates: []*state.State{
+				{
+					Labels:             labels["system + rule + labels1"],
+					ResultFingerprint:  labels1.Fingerprint(),
+					State:              eval.Alerting,
+					StateReason:        models.StateReasonKeepFiring,
+					LatestResult:       newEvaluation(t3, eval.Normal),
+					StartsAt:           t1,
+					EndsAt:             t3.Add(state.ResendDelay * 4),
+					LastEvaluationTime: t3,
+					KeepFiringSince:    &t2,
+					LastSentAt:         &t1,
+				},
+			},
+		},
+		{
+			desc:      "alerting -> normal resolves when KeepFiringFor has passed",
+			alertRule: baseRuleWith(m.WithKeepFiringFor(3 * evaluationInterval)),
+			evalResults: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				tn(4): {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				tn(5): {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+			},
+			expectedAnnotations: 2,
+			expectedStates: []*state.State{
+				{
+					Labels:             labels["system + rule + labels1"],
+					ResultFingerprint:  labels1.Fingerprint(),
+					State:              eval.Normal,
+					LatestResult:       newEvaluation(tn(5), eval.Normal),
+					StartsAt:           tn(5),
+					EndsAt:             tn(5),
+					LastEvaluationTime: tn(5),
+					ResolvedAt:         util.Pointer(tn(5)),
+					LastSentAt:         util.Pointer(tn(5)),
+				},
+			},
+		},
+		{
+			desc:      "alerting -> normal -> alerting restarts KeepFiringFor",
+			alertRule: baseRuleWith(m.WithKeepFiringFor(3 * evaluationInterval)),
+			evalResults: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				tn(4): {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				tn(5): {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				tn(6): {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+			},
+			expectedAnnotations: 1,
+			expectedStates: []*state.State{
+				{
+					Labels:             labels["system + rule + labels1"],
+					ResultFingerprint:  labels1.Fingerprint(),
+					State:              eval.Alerting,
+					StateReason:        models.StateReasonKeepFiring,
+					LatestResult:       newEvaluation(tn(6), eval.Normal),
+					StartsAt:           t1,
+					EndsAt:             tn(6).Add(state.ResendDelay * 4),
+					LastEvaluationTime: tn(6),
+					KeepFiringSince:    util.Pointer(tn(4)),
+					LastSentAt:         util.Pointer(tn(4)),
+				},
+			},
+		},
 		{
 			desc:      "alerting -> normal -> normal resolves and maintains ResolvedAt",
 			alertRule: baseRule,
@@ -1671,6 +1775,76 @@ func TestProcessEvalResults(t *testing.T) {
 			require.Contains(t, savedStates, s.CacheID)
 		}
 	})
+
+	t.Run("inhibits alerting states while a rule they depend on is firing", func(t *testing.T) {
+		cfg := state.ManagerCfg{
+			Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore: &state.FakeInstanceStore{},
+			Images:        &state.NotAvailableImageService{},
+			Clock:         clock.NewMock(),
+			Historian:     &state.FakeHistorian{},
+			Tracer:        tracing.InitializeTracerForTest(),
+			Log:           log.New("ngalert.state.manager"),
+		}
+		st := state.NewManager(cfg, state.NewNoopPersister())
+
+		parent := baseRuleWith(m.WithUID("parent-uid"))
+		dependent := baseRuleWith(m.WithUID("dependent-uid"), m.WithDependencies(models.AlertRuleDependency{
+			RuleUID: parent.UID,
+			Equal:   []string{"cluster"},
+		}))
+		clusterA := data.Labels{"cluster": "a"}
+		clusterB := data.Labels{"cluster": "b"}
+
+		var sent state.StateTransitions
+		sender := func(_ context.Context, states state.StateTransitions) {
+			sent = states
+		}
+		stateByCluster := func(cluster string) *state.State {
+			for _, s := range st.GetStatesForRuleUID(dependent.OrgID, dependent.UID) {
+				if s.Labels["cluster"] == cluster {
+					return s
+				}
+			}
+			return nil
+		}
+
+		_ = st.ProcessEvalResults(context.Background(), t1, parent, eval.Results{
+			newResult(eval.WithState(eval.Alerting), eval.WithLabels(clusterA), eval.WithEvaluatedAt(t1)),
+		}, systemLabels, state.NoopSender)
+		_ = st.ProcessEvalResults(context.Background(), t1, dependent, eval.Results{
+			newResult(eval.WithState(eval.Alerting), eval.WithLabels(clusterA), eval.WithEvaluatedAt(t1)),
+			newResult(eval.WithState(eval.Alerting), eval.WithLabels(clusterB), eval.WithEvaluatedAt(t1)),
+		}, systemLabels, sender)
+
+		inhibited := stateByCluster("a")
+		require.Equal(t, eval.Alerting, inhibited.State)
+		require.Equal(t, models.StateReasonInhibited, inhibited.StateReason)
+		require.True(t, inhibited.IsInhibited())
+		require.Nil(t, inhibited.LastSentAt)
+		notInhibited := stateByCluster("b")
+		require.Equal(t, eval.Alerting, notInhibited.State)
+		require.Empty(t, notInhibited.StateReason)
+		require.Len(t, sent, 1)
+		require.Equal(t, "b", sent[0].Labels["cluster"])
+
+		// the parent rule resolves, so the dependent instance is no longer inhibited and is sent to the Alertmanager.
+		_ = st.ProcessEvalResults(context.Background(), t2, parent, eval.Results{
+			newResult(eval.WithState(eval.Normal), eval.WithLabels(clusterA), eval.WithEvaluatedAt(t2)),
+		}, systemLabels, state.NoopSender)
+		_ = st.ProcessEvalResults(context.Background(), t2, dependent, eval.Results{
+			newResult(eval.WithState(eval.Alerting), eval.WithLabels(clusterA), eval.WithEvaluatedAt(t2)),
+			newResult(eval.WithState(eval.Alerting), eval.WithLabels(clusterB), eval.WithEvaluatedAt(t2)),
+		}, systemLabels, sender)
+
+		released := stateByCluster("a")
+		require.Equal(t, eval.Alerting, released.State)
+		require.Empty(t, released.StateReason)
+		require.False(t, released.IsInhibited())
+		require.Equal(t, t1, released.StartsAt)
+		require.Len(t, sent, 1)
+		require.Equal(t, "a", sent[0].Labels["cluster"])
+	})
 }
 
 func printAllAnnotations(annos map[int64]annotations.Item) string {
@@ -2261,6 +2435,130 @@ func TestResetStateByRuleUID(t *testing.T) {
 	}
 }
 
+func TestReloadRuleStates(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+
+	labels1 := models.InstanceLabels{"test1": "testValue1"}
+	_, hash1, _ := labels1.StringAndHash()
+	labels2 := models.InstanceLabels{"test2": "testValue2"}
+	_, hash2, _ := labels2.StringAndHash()
+	instances := []models.AlertInstance{
+		{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash1},
+			CurrentState:     models.InstanceStateNormal,
+			Labels:           labels1,
+		},
+		{
+			AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash2},
+			CurrentState:     models.InstanceStateFiring,
+			Labels:           labels2,
+		},
+	}
+	for _, instance := range instances {
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, instance))
+	}
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore: dbstore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	t.Run("should load states of the rule from the instance store", func(t *testing.T) {
+		st.ReloadRuleStates(ctx, rule)
+		states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 2)
+		for _, s := range states {
+			require.Equal(t, rule.Annotations, s.Annotations)
+		}
+	})
+
+	t.Run("should replace states of the rule with the ones in the instance store", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteAlertInstances(ctx, instances[0].AlertInstanceKey))
+		st.ReloadRuleStates(ctx, rule)
+		states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+	})
+
+	t.Run("should forget states of the rule but keep them in the instance store", func(t *testing.T) {
+		st.ForgetRuleStates(rule.GetKey())
+		require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+		alertInstances, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+		require.NoError(t, err)
+		require.Len(t, alertInstances, 1)
+	})
+}
+
+func TestRuleHandover(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+
+	// both instances of the cluster persist the states in the same database, but each sends the alerts to its own Alertmanager
+	newManager := func() *state.Manager {
+		cfg := state.ManagerCfg{
+			Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore:           dbstore,
+			Images:                  &state.NoopImageService{},
+			Clock:                   clock.NewMock(),
+			Historian:               &state.FakeHistorian{},
+			Tracer:                  tracing.InitializeTracerForTest(),
+			Log:                     log.New("ngalert.state.manager"),
+			MaxStateSaveConcurrency: 1,
+		}
+		return state.NewManager(cfg, state.NewSyncStatePersisiter(log.New("ngalert.state.manager.persist"), cfg))
+	}
+	sender := func(sent *state.StateTransitions) state.Sender {
+		return func(_ context.Context, states state.StateTransitions) {
+			*sent = append(*sent, states...)
+		}
+	}
+	firing := func(at time.Time) eval.Results {
+		return eval.Results{{Instance: data.Labels{"test": "handover"}, State: eval.Alerting, EvaluatedAt: at}}
+	}
+
+	oldOwner, newOwner := newManager(), newManager()
+	start := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	var sentByOldOwner state.StateTransitions
+	_ = oldOwner.ProcessEvalResults(ctx, start, rule, firing(start), nil, sender(&sentByOldOwner))
+	require.Len(t, sentByOldOwner, 1)
+
+	// the rule is handed over before the old owner resends the alert
+	oldOwner.ForgetRuleStates(rule.GetKey())
+	newOwner.ReloadRuleStates(ctx, rule)
+	handoverEval := start.Add(state.ResendDelay / 2)
+
+	var sentByNewOwner state.StateTransitions
+	_ = newOwner.ProcessEvalResults(ctx, handoverEval, rule, firing(handoverEval), nil, sender(&sentByNewOwner))
+
+	t.Run("new owner sends the firing alert on its first evaluation", func(t *testing.T) {
+		require.Len(t, sentByNewOwner, 1)
+		require.Equal(t, eval.Alerting, sentByNewOwner[0].State.State)
+		require.Truef(t, start.Equal(sentByNewOwner[0].StartsAt), "alert should keep the time it started firing, got %s", sentByNewOwner[0].StartsAt)
+	})
+
+	t.Run("alert of the old owner is not resolved but expires after the new owner sent it", func(t *testing.T) {
+		// the old owner does not send a resolved alert, its Alertmanager resolves the alert when it expires.
+		// Alertmanagers of the cluster do not share alerts, so the expired alert can still be notified as resolved.
+		require.Empty(t, oldOwner.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		require.True(t, sentByOldOwner[0].EndsAt.After(handoverEval))
+	})
+}
+
 func setCacheID(s *state.State) *state.State {
 	if s.CacheID != 0 {
 		return s
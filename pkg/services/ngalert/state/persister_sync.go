@@ -84,8 +84,9 @@ func (a *SyncStatePersister) saveAlertStates(ctx context.Context, states ...Stat
 			return nil
 		}
 
-		// Do not save normal state to database and remove transition to Normal state but keep mapped states
-		if a.doNotSaveNormalState && IsNormalStateWithNoReason(s.State) && !s.Changed() {
+		// Do not save normal state to database and remove transition to Normal state but keep mapped states,
+		// unless the state has threshold history that would be lost.
+		if a.doNotSaveNormalState && IsNormalStateWithNoReason(s.State) && !s.Changed() && len(s.ThresholdHistory) == 0 {
 			return nil
 		}
 
@@ -104,7 +105,9 @@ func (a *SyncStatePersister) saveAlertStates(ctx context.Context, states ...Stat
 			CurrentStateEnd:   s.EndsAt,
 			ResolvedAt:        s.ResolvedAt,
 			LastSentAt:        s.LastSentAt,
+			KeepFiringSince:   s.KeepFiringSince,
 			ResultFingerprint: s.ResultFingerprint.String(),
+			ThresholdHistory:  s.ThresholdHistory,
 		}
 
 		err = a.store.SaveAlertInstance(ctx, instance)
@@ -68,7 +68,14 @@ type State struct {
 	// ResolvedAt is set when the state is first resolved. That is to say, when the state first transitions
 	// from Alerting, NoData, or Error to Normal. It is reset to zero when the state transitions from Normal
 	// to any other state.
-	ResolvedAt           *time.Time
+	ResolvedAt *time.Time
+	// KeepFiringSince is set when the condition of an Alerting state is first no longer met, and the state
+	// is kept Alerting because the rule has KeepFiringFor. It is reset to nil when the condition is met again
+	// or the state is no longer Alerting.
+	KeepFiringSince *time.Time
+	// ThresholdHistory contains the previous evaluations of the condition of the rule, oldest first, if it is a
+	// threshold expression with several levels or a breach window. It is persisted with the alert instance.
+	ThresholdHistory     []expr.ThresholdEvaluation
 	LastSentAt           *time.Time
 	LastEvaluationString string
 	LastEvaluationTime   time.Time
@@ -136,6 +143,16 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.Error = nil
 }
 
+// IsInhibited returns true if the state is Alerting and inhibited by a rule it depends on.
+func (a *State) IsInhibited() bool {
+	return a.State == eval.Alerting && strings.Contains(a.StateReason, models.StateReasonInhibited)
+}
+
+// keepFiringExpired returns true if the state has been kept Alerting for at least keepFiringFor.
+func (a *State) keepFiringExpired(keepFiringFor time.Duration, evaluatedAt time.Time) bool {
+	return a.KeepFiringSince != nil && evaluatedAt.Sub(*a.KeepFiringSince) >= keepFiringFor
+}
+
 // Maintain updates the end time using the most recent evaluation.
 func (a *State) Maintain(interval int64, evaluatedAt time.Time) {
 	a.EndsAt = nextEndsTime(interval, evaluatedAt)
@@ -263,9 +280,24 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]float6
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger, reason string) {
 	if state.State == eval.Normal {
 		logger.Debug("Keeping state", "state", state.State)
+	} else if state.State == eval.Alerting && rule.KeepFiringFor > 0 && !state.keepFiringExpired(rule.KeepFiringFor, result.EvaluatedAt) {
+		if state.KeepFiringSince == nil {
+			state.KeepFiringSince = &result.EvaluatedAt
+		}
+		prevEndsAt := state.EndsAt
+		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		logger.Debug("Keeping state because of keep_firing_for",
+			"state",
+			state.State,
+			"keep_firing_since",
+			state.KeepFiringSince,
+			"previous_ends_at",
+			prevEndsAt,
+			"next_ends_at",
+			state.EndsAt)
 	} else {
 		nextEndsAt := result.EvaluatedAt
 		logger.Debug("Changing state",
@@ -341,6 +373,14 @@ func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, lo
 func resultError(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	handlerStr := "resultError"
 
+	if errors.Is(result.Error, eval.ErrLimitExceeded) {
+		// The rule exceeded the evaluation limits, so the result of the evaluation is unknown.
+		// The state is Error regardless of the execution error state to make the rule visible.
+		logger.Debug("Evaluation limit exceeded", "handler", handlerStr)
+		resultLimitExceeded(state, rule, result)
+		return
+	}
+
 	switch rule.ExecErrState {
 	case models.AlertingErrState:
 		logger.Debug("Execution error state is Alerting", "handler", "resultAlerting", "previous_handler", handlerStr)
@@ -391,6 +431,17 @@ func resultError(state *State, rule *models.AlertRule, result eval.Result, logge
 	}
 }
 
+func resultLimitExceeded(state *State, rule *models.AlertRule, result eval.Result) {
+	if state.State == eval.Error {
+		state.Error = result.Error
+		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+	} else {
+		state.SetError(result.Error, result.EvaluatedAt, nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt))
+	}
+	state.StateReason = models.StateReasonLimitExceeded
+	state.AddErrorInformation(result.Error, rule, false)
+}
+
 func resultNoData(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	handlerStr := "resultNoData"
 
@@ -472,6 +523,11 @@ func (a *State) NeedsSending(resendDelay time.Duration, resolvedRetention time.D
 		return false
 	}
 
+	// We do not send notifications for states inhibited by a rule they depend on.
+	if a.IsInhibited() {
+		return false
+	}
+
 	// We should send a notification if the state has been resolved since the last notification.
 	if a.ResolvedAt != nil && (a.LastSentAt == nil || a.ResolvedAt.After(*a.LastSentAt)) {
 		return true
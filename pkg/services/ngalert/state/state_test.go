@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"math/rand"
 	"testing"
@@ -18,6 +19,7 @@ import (
 	"github.com/grafana/alerting/models"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/screenshot"
@@ -251,6 +253,34 @@ func TestSetError(t *testing.T) {
 	}
 }
 
+func TestResultLimitExceeded(t *testing.T) {
+	mock := clock.NewMock()
+	now := mock.Now()
+	limitErr := fmt.Errorf("%w: evaluation returned 100 series (limit: 10)", eval.ErrLimitExceeded)
+	result := eval.Result{State: eval.Error, Error: limitErr, EvaluatedAt: now}
+
+	for _, execErrState := range []ngmodels.ExecutionErrorState{ngmodels.AlertingErrState, ngmodels.ErrorErrState, ngmodels.OkErrState, ngmodels.KeepLastErrState} {
+		t.Run(fmt.Sprintf("state is Error when execution error state is %s", execErrState), func(t *testing.T) {
+			rule := &ngmodels.AlertRule{IntervalSeconds: 10, ExecErrState: execErrState}
+			s := &State{State: eval.Alerting, StartsAt: now.Add(-time.Minute), Annotations: map[string]string{}}
+			resultError(s, rule, result, &logtest.Fake{})
+			assert.Equal(t, eval.Error, s.State)
+			assert.Equal(t, ngmodels.StateReasonLimitExceeded, s.StateReason)
+			assert.Equal(t, now, s.StartsAt)
+			assert.Equal(t, limitErr.Error(), s.Annotations["Error"])
+		})
+	}
+
+	t.Run("start of the error state is kept", func(t *testing.T) {
+		rule := &ngmodels.AlertRule{IntervalSeconds: 10, ExecErrState: ngmodels.ErrorErrState}
+		s := &State{State: eval.Error, StartsAt: now.Add(-time.Minute), Annotations: map[string]string{}}
+		resultError(s, rule, result, &logtest.Fake{})
+		assert.Equal(t, eval.Error, s.State)
+		assert.Equal(t, ngmodels.StateReasonLimitExceeded, s.StateReason)
+		assert.Equal(t, now.Add(-time.Minute), s.StartsAt)
+	})
+}
+
 func TestMaintain(t *testing.T) {
 	mock := clock.NewMock()
 	now := mock.Now()
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// thresholdHistory describes the history that the condition of a rule needs if it is a threshold expression with
+// several levels or a breach window. The history of each dimension is kept in its state, so that it is persisted with
+// the alert instance and survives restarts, updates of the rule and its handover to another replica.
+type thresholdHistory struct {
+	// referenceVar is the variable whose value is compared to the thresholds.
+	referenceVar string
+	// size is the number of evaluations to keep for each dimension.
+	size int
+}
+
+// newThresholdHistory returns the history of the condition of the rule, or false if the condition is not a threshold
+// expression with several levels or a breach window.
+func newThresholdHistory(rule *ngModels.AlertRule) (thresholdHistory, bool) {
+	for i := range rule.Data {
+		q := &rule.Data[i]
+		if q.RefID != rule.Condition {
+			continue
+		}
+		referenceVar, size, ok, err := q.StatefulThresholdHistory()
+		if err != nil || !ok {
+			return thresholdHistory{}, false
+		}
+		return thresholdHistory{referenceVar: referenceVar, size: size}, true
+	}
+	return thresholdHistory{}, false
+}
+
+// next returns the history of a dimension after the result of the condition is added to its previous history. The
+// oldest evaluations are dropped to keep the size of the history. The history is nil if the result has no level.
+func (h thresholdHistory) next(condition string, result eval.Result, previous []expr.ThresholdEvaluation) []expr.ThresholdEvaluation {
+	level, ok := result.Values[condition]
+	if !ok || level.Value == nil {
+		return nil
+	}
+	e := expr.ThresholdEvaluation{Level: *level.Value}
+	if v, ok := result.Values[h.referenceVar]; ok {
+		e.Value = v.Value
+	}
+	if size := max(h.size, 1); len(previous) >= size {
+		previous = previous[len(previous)-size+1:]
+	}
+	return append(append(make([]expr.ThresholdEvaluation, 0, len(previous)+1), previous...), e)
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestThresholdHistory(t *testing.T) {
+	newRule := func(model string) *ngmodels.AlertRule {
+		rule := ngmodels.RuleGen.GenerateRef()
+		rule.Condition = "B"
+		rule.Data = []ngmodels.AlertQuery{
+			{RefID: "A", Model: json.RawMessage(`{"expr": "up"}`)},
+			{RefID: "B", Model: json.RawMessage(model)},
+		}
+		return rule
+	}
+	result := func(label string, level, value float64) eval.Result {
+		return eval.Result{
+			Instance: data.Labels{"label": label},
+			State:    eval.Normal,
+			Values: map[string]eval.NumberValueCapture{
+				"A": {Var: "A", Value: util.Pointer(value)},
+				"B": {Var: "B", Value: util.Pointer(level)},
+			},
+		}
+	}
+	newManager := func() *Manager {
+		return NewManager(ManagerCfg{
+			Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			Tracer:        tracing.InitializeTracerForTest(),
+			Log:           log.New("ngalert.state.manager"),
+			InstanceStore: &FakeInstanceStore{},
+			Images:        &NotAvailableImageService{},
+			Clock:         clock.NewMock(),
+			Historian:     &FakeHistorian{},
+		}, NewNoopPersister())
+	}
+	history := func(st *Manager, rule *ngmodels.AlertRule) map[string][]expr.ThresholdEvaluation {
+		result := map[string][]expr.ThresholdEvaluation{}
+		for _, s := range st.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+			if len(s.ThresholdHistory) > 0 {
+				result[s.Labels["label"]] = s.ThresholdHistory
+			}
+		}
+		return result
+	}
+	process := func(st *Manager, rule *ngmodels.AlertRule, evaluatedAt time.Time, results ...eval.Result) {
+		for i := range results {
+			results[i].EvaluatedAt = evaluatedAt
+		}
+		st.ProcessEvalResults(context.Background(), evaluatedAt, rule, results, nil, nil)
+	}
+
+	windowRule := newRule(`{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [10]}}], "window": {"breaches": 2, "evaluations": 3}}`)
+	t1 := time.Now()
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	t.Run("should keep the most recent evaluations of each dimension in its state", func(t *testing.T) {
+		st := newManager()
+		process(st, windowRule, t1, result("a", 0, 1), result("b", 0, 2))
+		process(st, windowRule, t2, result("a", 1, 11), result("b", 0, 3))
+		process(st, windowRule, t3, result("a", 1, 12), result("b", 0, 4))
+		process(st, windowRule, t3.Add(time.Minute), result("a", 1, 13))
+
+		h := history(st, windowRule)
+		require.Equal(t, []expr.ThresholdEvaluation{
+			{Level: 1, Value: util.Pointer(12.0)},
+			{Level: 1, Value: util.Pointer(13.0)},
+		}, h["a"])
+	})
+
+	t.Run("should not record results with errors", func(t *testing.T) {
+		st := newManager()
+		process(st, windowRule, t1, result("a", 0, 1))
+		process(st, windowRule, t2, eval.Result{State: eval.Error, Error: errors.New("boom")})
+
+		require.Equal(t, map[string][]expr.ThresholdEvaluation{
+			"a": {{Level: 0, Value: util.Pointer(1.0)}},
+		}, history(st, windowRule))
+	})
+
+	t.Run("should clear the history if the condition is not stateful", func(t *testing.T) {
+		st := newManager()
+		process(st, windowRule, t1, result("a", 0, 1))
+		rule := ngmodels.CopyRule(windowRule, ngmodels.RuleMuts.WithQuery(newRule(`{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [10]}}]}`).Data...))
+		rule.Condition = "B"
+		process(st, rule, t2, result("a", 0, 1))
+
+		require.Empty(t, history(st, windowRule))
+	})
+
+	t.Run("should restore the history from the alert instance", func(t *testing.T) {
+		st := newManager()
+		h := ngmodels.InstanceThresholdHistory{{Level: 1, Value: util.Pointer(11.0)}}
+		s := st.stateFromInstance(&ngmodels.AlertInstance{
+			AlertInstanceKey: ngmodels.AlertInstanceKey{RuleOrgID: windowRule.OrgID, RuleUID: windowRule.UID},
+			Labels:           ngmodels.InstanceLabels{"label": "a"},
+			CurrentState:     ngmodels.InstanceStateNormal,
+			ThresholdHistory: h,
+		}, windowRule)
+
+		require.Equal(t, []expr.ThresholdEvaluation(h), s.ThresholdHistory)
+	})
+}
@@ -32,6 +32,8 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		RuleGroup:       ar.RuleGroup,
 		RuleGroupIndex:  ar.RuleGroupIndex,
 		For:             ar.For,
+		KeepFiringFor:   ar.KeepFiringFor,
+		QueryOffset:     ar.QueryOffset,
 		IsPaused:        ar.IsPaused,
 	}
 
@@ -88,6 +90,20 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		}
 	}
 
+	if ar.Dependencies != "" {
+		err = json.Unmarshal([]byte(ar.Dependencies), &result.Dependencies)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse dependencies: %w", err)
+		}
+	}
+
+	if ar.EvaluationLimits != "" {
+		err = json.Unmarshal([]byte(ar.EvaluationLimits), &result.EvaluationLimits)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse evaluation limits: %w", err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -117,6 +133,8 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 		NoDataState:     ar.NoDataState.String(),
 		ExecErrState:    ar.ExecErrState.String(),
 		For:             ar.For,
+		KeepFiringFor:   ar.KeepFiringFor,
+		QueryOffset:     ar.QueryOffset,
 		IsPaused:        ar.IsPaused,
 	}
 
@@ -165,6 +183,22 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 	}
 	result.Metadata = string(metadata)
 
+	if len(ar.Dependencies) > 0 {
+		dependenciesData, err := json.Marshal(ar.Dependencies)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal dependencies: %w", err)
+		}
+		result.Dependencies = string(dependenciesData)
+	}
+
+	if !ar.EvaluationLimits.IsZero() {
+		limitsData, err := json.Marshal(ar.EvaluationLimits)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal evaluation limits: %w", err)
+		}
+		result.EvaluationLimits = string(limitsData)
+	}
+
 	return result, nil
 }
 
@@ -187,10 +221,14 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		NoDataState:          rule.NoDataState,
 		ExecErrState:         rule.ExecErrState,
 		For:                  rule.For,
+		KeepFiringFor:        rule.KeepFiringFor,
+		QueryOffset:          rule.QueryOffset,
 		Annotations:          rule.Annotations,
 		Labels:               rule.Labels,
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: rule.NotificationSettings,
 		Metadata:             rule.Metadata,
+		Dependencies:         rule.Dependencies,
+		EvaluationLimits:     rule.EvaluationLimits,
 	}
 }
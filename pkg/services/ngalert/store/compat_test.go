@@ -2,6 +2,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -23,6 +24,27 @@ func TestAlertRuleToModelsAlertRule(t *testing.T) {
 		}
 	})
 
+	t.Run("should keep dependencies", func(t *testing.T) {
+		rule := g.With(g.WithDependencies(
+			ngmodels.AlertRuleDependency{RuleUID: util.GenerateShortUID(), Equal: []string{"cluster", "namespace"}},
+			ngmodels.AlertRuleDependency{RuleUID: util.GenerateShortUID()},
+		)).Generate()
+		r, err := alertRuleFromModelsAlertRule(rule)
+		require.NoError(t, err)
+		clone, err := alertRuleToModelsAlertRule(r, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, rule.Dependencies, clone.Dependencies)
+	})
+
+	t.Run("should keep evaluation limits", func(t *testing.T) {
+		rule := g.With(g.WithEvaluationLimits(ngmodels.AlertRuleEvaluationLimits{MaxDuration: time.Minute, MaxSeries: 10})).Generate()
+		r, err := alertRuleFromModelsAlertRule(rule)
+		require.NoError(t, err)
+		clone, err := alertRuleToModelsAlertRule(r, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, rule.EvaluationLimits, clone.EvaluationLimits)
+	})
+
 	t.Run("should use NoData if NoDataState is not known", func(t *testing.T) {
 		rule, err := alertRuleFromModelsAlertRule(g.Generate())
 		require.NoError(t, err)
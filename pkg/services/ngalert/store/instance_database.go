@@ -38,7 +38,7 @@ func (st DBstore) ListAlertInstances(ctx context.Context, cmd *models.ListAlertI
 		}
 
 		if st.FeatureToggles.IsEnabled(ctx, featuremgmt.FlagAlertingNoNormalState) {
-			s.WriteString(fmt.Sprintf(" AND NOT (current_state = '%s' AND current_reason = '')", models.InstanceStateNormal))
+			s.WriteString(fmt.Sprintf(" AND NOT (current_state = '%s' AND current_reason = '' AND threshold_history IS NULL)", models.InstanceStateNormal))
 		}
 		if err := sess.SQL(s.String(), params...).Find(&alertInstances); err != nil {
 			return err
@@ -61,6 +61,10 @@ func (st DBstore) SaveAlertInstance(ctx context.Context, alertInstance models.Al
 		if err != nil {
 			return err
 		}
+		thresholdHistory, err := nullableThresholdHistory(alertInstance.ThresholdHistory)
+		if err != nil {
+			return err
+		}
 		params := append(make([]any, 0),
 			alertInstance.RuleOrgID,
 			alertInstance.RuleUID,
@@ -73,13 +77,15 @@ func (st DBstore) SaveAlertInstance(ctx context.Context, alertInstance models.Al
 			alertInstance.LastEvalTime.Unix(),
 			nullableTimeToUnix(alertInstance.ResolvedAt),
 			nullableTimeToUnix(alertInstance.LastSentAt),
+			nullableTimeToUnix(alertInstance.KeepFiringSince),
 			alertInstance.ResultFingerprint,
+			thresholdHistory,
 		)
 
 		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
 			"alert_instance",
 			[]string{"rule_org_id", "rule_uid", "labels_hash"},
-			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "resolved_at", "last_sent_at", "result_fingerprint"})
+			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "resolved_at", "last_sent_at", "keep_firing_since", "result_fingerprint", "threshold_history"})
 		_, err = sess.SQL(upsertSQL, params...).Query()
 		if err != nil {
 			return err
@@ -243,9 +249,14 @@ func (st DBstore) FullSync(ctx context.Context, instances []models.AlertInstance
 				st.Logger.Warn("Failed to generate alert instance labels key, skipping", "err", err, "rule_uid", alertInstance.RuleUID)
 				continue
 			}
+			thresholdHistory, err := nullableThresholdHistory(alertInstance.ThresholdHistory)
+			if err != nil {
+				st.Logger.Warn("Failed to serialize alert instance threshold history, skipping", "err", err, "rule_uid", alertInstance.RuleUID)
+				continue
+			}
 
 			_, err = sess.Exec(
-				"INSERT INTO alert_instance (rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, resolved_at, last_sent_at) VALUES (?,?,?,?,?,?,?,?,?,?,?)",
+				"INSERT INTO alert_instance (rule_org_id, rule_uid, labels, labels_hash, current_state, current_reason, current_state_since, current_state_end, last_eval_time, resolved_at, last_sent_at, keep_firing_since, threshold_history) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)",
 				alertInstance.RuleOrgID,
 				alertInstance.RuleUID,
 				labelTupleJSON,
@@ -257,6 +268,8 @@ func (st DBstore) FullSync(ctx context.Context, instances []models.AlertInstance
 				alertInstance.LastEvalTime.Unix(),
 				nullableTimeToUnix(alertInstance.ResolvedAt),
 				nullableTimeToUnix(alertInstance.LastSentAt),
+				nullableTimeToUnix(alertInstance.KeepFiringSince),
+				thresholdHistory,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to insert into alert_instance table: %w", err)
@@ -277,3 +290,13 @@ func nullableTimeToUnix(t *time.Time) *int64 {
 	unix := t.Unix()
 	return &unix
 }
+
+// nullableThresholdHistory serializes the threshold history of an alert instance as JSON, or returns nil if it is empty.
+func nullableThresholdHistory(h models.InstanceThresholdHistory) (*string, error) {
+	b, err := h.ToDB()
+	if err != nil || b == nil {
+		return nil, err
+	}
+	v := string(b)
+	return &v, nil
+}
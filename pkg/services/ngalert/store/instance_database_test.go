@@ -197,6 +197,47 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Len(t, alerts, 4)
 	})
 
+	t.Run("can save and read the threshold history of an alert instance", func(t *testing.T) {
+		labels := models.InstanceLabels{"test": util.GenerateShortUID()}
+		_, hash, _ := labels.StringAndHash()
+		instance := models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  alertRule1.OrgID,
+				RuleUID:    alertRule1.UID,
+				LabelsHash: hash,
+			},
+			CurrentState: models.InstanceStateNormal,
+			Labels:       labels,
+			ThresholdHistory: models.InstanceThresholdHistory{
+				{Level: 0, Value: util.Pointer(5.0)},
+				{Level: 1, Value: util.Pointer(11.0)},
+			},
+		}
+		err := dbstore.SaveAlertInstance(ctx, instance)
+		require.NoError(t, err)
+
+		f := dbstore.FeatureToggles
+		dbstore.FeatureToggles = featuremgmt.WithFeatures(featuremgmt.FlagAlertingNoNormalState)
+		t.Cleanup(func() {
+			dbstore.FeatureToggles = f
+		})
+
+		alerts, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{
+			RuleOrgID: instance.RuleOrgID,
+			RuleUID:   instance.RuleUID,
+		})
+		require.NoError(t, err)
+
+		var found *models.AlertInstance
+		for _, alert := range alerts {
+			if alert.LabelsHash == hash {
+				found = alert
+			}
+		}
+		require.NotNil(t, found, "Normal states with threshold history are expected to be listed")
+		require.Equal(t, instance.ThresholdHistory, found.ThresholdHistory)
+	})
+
 	t.Run("should ignore Normal state with no reason if feature flag is enabled", func(t *testing.T) {
 		labels := models.InstanceLabels{"test": util.GenerateShortUID()}
 		instance1 := models.AlertInstance{
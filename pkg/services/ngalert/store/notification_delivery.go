@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	notificationDeliveryTable = "alert_notification_delivery"
+	// defaultNotificationDeliveriesLimit is the number of deliveries returned when the query does not set a limit.
+	defaultNotificationDeliveriesLimit = 100
+)
+
+// notificationDelivery is a notification attempt stored in the alert_notification_delivery table.
+type notificationDelivery struct {
+	ID               int64  `xorm:"pk autoincr 'id'"`
+	OrgID            int64  `xorm:"org_id"`
+	Receiver         string `xorm:"receiver"`
+	Integration      string `xorm:"integration"`
+	IntegrationIndex int    `xorm:"integration_index"`
+	GroupKey         string `xorm:"group_key"`
+	GroupLabels      string `xorm:"group_labels"`
+	Alerts           string `xorm:"alerts"`
+	Status           string `xorm:"status"`
+	HTTPStatus       int    `xorm:"http_status"`
+	Error            string `xorm:"error"`
+	LatencyMs        int64  `xorm:"latency_ms"`
+	PayloadHash      string `xorm:"payload_hash"`
+	ReplayOf         int64  `xorm:"replay_of"`
+	Epoch            int64  `xorm:"epoch"`
+}
+
+func (notificationDelivery) TableName() string {
+	return notificationDeliveryTable
+}
+
+// SaveNotificationDelivery saves the notification delivery and sets its ID.
+func (st DBstore) SaveNotificationDelivery(ctx context.Context, d *models.NotificationDelivery) error {
+	row, err := notificationDeliveryToRow(d)
+	if err != nil {
+		return err
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(row); err != nil {
+			return fmt.Errorf("failed to save notification delivery: %w", err)
+		}
+		d.ID = row.ID
+		return nil
+	})
+}
+
+// GetNotificationDelivery returns the notification delivery with the ID. It returns ErrNotificationDeliveryNotFound if the delivery does not exist.
+func (st DBstore) GetNotificationDelivery(ctx context.Context, orgID int64, id int64) (*models.NotificationDelivery, error) {
+	var row notificationDelivery
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND id = ?", orgID, id).Get(&row)
+		if err != nil {
+			return fmt.Errorf("failed to get notification delivery: %w", err)
+		}
+		if !exists {
+			return models.ErrNotificationDeliveryNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notificationDeliveryFromRow(row)
+}
+
+// ListNotificationDeliveries returns the most recent notification deliveries that match the query, most recent first.
+func (st DBstore) ListNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]*models.NotificationDelivery, error) {
+	var rows []notificationDelivery
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(notificationDeliveryTable).Where("org_id = ?", query.OrgID)
+		if query.Receivers != nil {
+			if len(query.Receivers) == 0 {
+				return nil
+			}
+			q = q.In("receiver", query.Receivers)
+		}
+		if query.Integration != "" {
+			q = q.Where("integration = ?", query.Integration)
+		}
+		if query.Status != "" {
+			q = q.Where("status = ?", string(query.Status))
+		}
+		if !query.From.IsZero() {
+			q = q.Where("epoch >= ?", query.From.UnixNano())
+		}
+		if !query.To.IsZero() {
+			q = q.Where("epoch <= ?", query.To.UnixNano())
+		}
+		limit := query.Limit
+		if limit <= 0 {
+			limit = defaultNotificationDeliveriesLimit
+		}
+		if err := q.Desc("epoch", "id").Limit(limit).Find(&rows); err != nil {
+			return fmt.Errorf("failed to list notification deliveries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.NotificationDelivery, 0, len(rows))
+	for _, row := range rows {
+		d, err := notificationDeliveryFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// DeleteNotificationDeliveriesBefore deletes the notification deliveries of the organization that are older than the time.
+// It returns the number of deleted deliveries.
+func (st DBstore) DeleteNotificationDeliveriesBefore(ctx context.Context, orgID int64, before time.Time) (int64, error) {
+	var n int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("org_id = ? AND epoch < ?", orgID, before.UnixNano()).Delete(&notificationDelivery{})
+		if err != nil {
+			return fmt.Errorf("failed to delete notification deliveries: %w", err)
+		}
+		n = rows
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return n, nil
+}
+
+func notificationDeliveryToRow(d *models.NotificationDelivery) (*notificationDelivery, error) {
+	groupLabels, err := json.Marshal(d.GroupLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal group labels: %w", err)
+	}
+	alerts, err := json.Marshal(d.Alerts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alerts: %w", err)
+	}
+	return &notificationDelivery{
+		OrgID:            d.OrgID,
+		Receiver:         d.Receiver,
+		Integration:      d.Integration,
+		IntegrationIndex: d.IntegrationIndex,
+		GroupKey:         d.GroupKey,
+		GroupLabels:      string(groupLabels),
+		Alerts:           string(alerts),
+		Status:           string(d.Status),
+		HTTPStatus:       d.HTTPStatus,
+		Error:            d.Error,
+		LatencyMs:        d.Latency.Milliseconds(),
+		PayloadHash:      d.PayloadHash,
+		ReplayOf:         d.ReplayOf,
+		Epoch:            d.Timestamp.UnixNano(),
+	}, nil
+}
+
+func notificationDeliveryFromRow(row notificationDelivery) (*models.NotificationDelivery, error) {
+	var groupLabels model.LabelSet
+	if err := json.Unmarshal([]byte(row.GroupLabels), &groupLabels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal group labels of notification delivery %d: %w", row.ID, err)
+	}
+	var alerts []model.Alert
+	if err := json.Unmarshal([]byte(row.Alerts), &alerts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alerts of notification delivery %d: %w", row.ID, err)
+	}
+	return &models.NotificationDelivery{
+		ID:               row.ID,
+		OrgID:            row.OrgID,
+		Receiver:         row.Receiver,
+		Integration:      row.Integration,
+		IntegrationIndex: row.IntegrationIndex,
+		GroupKey:         row.GroupKey,
+		GroupLabels:      groupLabels,
+		Alerts:           alerts,
+		Status:           models.NotificationDeliveryStatus(row.Status),
+		HTTPStatus:       row.HTTPStatus,
+		Error:            row.Error,
+		Latency:          time.Duration(row.LatencyMs) * time.Millisecond,
+		PayloadHash:      row.PayloadHash,
+		ReplayOf:         row.ReplayOf,
+		Timestamp:        time.Unix(0, row.Epoch).UTC(),
+	}, nil
+}
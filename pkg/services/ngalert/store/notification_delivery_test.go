@@ -0,0 +1,123 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationDeliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	newDelivery := func(orgID int64, receiver string, status models.NotificationDeliveryStatus, ts time.Time) *models.NotificationDelivery {
+		return &models.NotificationDelivery{
+			OrgID:            orgID,
+			Receiver:         receiver,
+			Integration:      "webhook",
+			IntegrationIndex: 0,
+			GroupKey:         `{}:{alertname="test"}`,
+			GroupLabels:      model.LabelSet{"alertname": "test"},
+			Alerts: []model.Alert{{
+				Labels:   model.LabelSet{"alertname": "test", "instance": "a"},
+				StartsAt: now.Add(-time.Hour),
+			}},
+			Status:      status,
+			HTTPStatus:  200,
+			Latency:     150 * time.Millisecond,
+			PayloadHash: "hash",
+			Timestamp:   ts,
+		}
+	}
+
+	old := newDelivery(1, "team-a", models.NotificationDeliveryStatusSuccess, now.Add(-2*time.Hour))
+	failed := newDelivery(1, "team-a", models.NotificationDeliveryStatusFailure, now.Add(-time.Hour))
+	failed.HTTPStatus = 500
+	failed.Error = "unexpected status code 500"
+	other := newDelivery(1, "team-b", models.NotificationDeliveryStatusSuccess, now)
+	otherOrg := newDelivery(2, "team-a", models.NotificationDeliveryStatusSuccess, now)
+	for _, d := range []*models.NotificationDelivery{old, failed, other, otherOrg} {
+		require.NoError(t, dbstore.SaveNotificationDelivery(ctx, d))
+		require.NotZero(t, d.ID)
+	}
+
+	t.Run("should get a delivery of the organization", func(t *testing.T) {
+		d, err := dbstore.GetNotificationDelivery(ctx, 1, failed.ID)
+		require.NoError(t, err)
+		require.Equal(t, failed, d)
+
+		_, err = dbstore.GetNotificationDelivery(ctx, 2, failed.ID)
+		require.ErrorIs(t, err, models.ErrNotificationDeliveryNotFound)
+	})
+
+	t.Run("should list the deliveries that match the query, most recent first", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			query    models.ListNotificationDeliveriesQuery
+			expected []*models.NotificationDelivery
+		}{
+			{
+				name:     "all deliveries of the organization",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1},
+				expected: []*models.NotificationDelivery{other, failed, old},
+			},
+			{
+				name:     "deliveries of receivers",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, Receivers: []string{"team-a"}},
+				expected: []*models.NotificationDelivery{failed, old},
+			},
+			{
+				name:     "no receivers",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, Receivers: []string{}},
+				expected: []*models.NotificationDelivery{},
+			},
+			{
+				name:     "failed deliveries",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, Status: models.NotificationDeliveryStatusFailure},
+				expected: []*models.NotificationDelivery{failed},
+			},
+			{
+				name:     "deliveries in a time range",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, From: now.Add(-90 * time.Minute), To: now.Add(-time.Minute)},
+				expected: []*models.NotificationDelivery{failed},
+			},
+			{
+				name:     "limit",
+				query:    models.ListNotificationDeliveriesQuery{OrgID: 1, Limit: 2},
+				expected: []*models.NotificationDelivery{other, failed},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				deliveries, err := dbstore.ListNotificationDeliveries(ctx, tc.query)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, deliveries)
+			})
+		}
+	})
+
+	t.Run("should delete the deliveries of the organization before the time", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationDeliveriesBefore(ctx, 1, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+		require.EqualValues(t, 2, deleted)
+
+		deliveries, err := dbstore.ListNotificationDeliveries(ctx, models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*models.NotificationDelivery{other}, deliveries)
+
+		deliveries, err = dbstore.ListNotificationDeliveries(ctx, models.ListNotificationDeliveriesQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Equal(t, []*models.NotificationDelivery{otherOrg}, deliveries)
+	})
+}
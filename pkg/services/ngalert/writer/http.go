@@ -0,0 +1,77 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+)
+
+// maxErrorBodySize is the maximum number of bytes of a response body that are included in a write error.
+const maxErrorBodySize = 1024
+
+func newHTTPWriterClient(httpClientProvider HttpClientProvider, rawURL string, customHeaders map[string]string, timeout time.Duration) (*http.Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %q is not absolute", rawURL)
+	}
+	if timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be greater than 0")
+	}
+
+	headers := make(http.Header)
+	for k, v := range customHeaders {
+		headers.Add(k, v)
+	}
+	cl, err := httpClientProvider.New(httpclient.Options{
+		Header: headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cl.Timeout = timeout
+	return cl, nil
+}
+
+// postWrite sends the body to the URL and returns the status code of the response.
+// Responses with a 4xx status code result in ErrRejectedWrite, and all other failures in ErrUnexpectedWriteFailure.
+func postWrite(ctx context.Context, client *http.Client, url string, contentType string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "grafana-recording-rule")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return res.StatusCode, nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	err = fmt.Errorf("server returned HTTP status %s: %s", res.Status, bytes.TrimSpace(msg))
+	if res.StatusCode/100 == 4 {
+		return res.StatusCode, errors.Join(ErrRejectedWrite, err)
+	}
+	return res.StatusCode, errors.Join(ErrUnexpectedWriteFailure, err)
+}
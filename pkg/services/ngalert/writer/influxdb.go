@@ -0,0 +1,124 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	influx "github.com/influxdata/line-protocol"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	influxDBBackendType = "influxdb"
+	// influxDBValueField is the name of the field that holds the value of a sample.
+	influxDBValueField = "value"
+)
+
+// InfluxDBWriter sends the results of recording rules in the line protocol to an InfluxDB write endpoint.
+// The metric name becomes the measurement and the labels become tags. Samples that are NaN or infinite
+// cannot be represented in the line protocol and are skipped.
+type InfluxDBWriter struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+	clock   clock.Clock
+	logger  log.Logger
+	metrics *metrics.RemoteWriter
+}
+
+func NewInfluxDBWriter(
+	settings setting.RecordingRuleInfluxDBSettings,
+	httpClientProvider HttpClientProvider,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*InfluxDBWriter, error) {
+	client, err := newHTTPWriterClient(httpClientProvider, settings.URL, nil, settings.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps are always encoded in milliseconds, which the endpoint must be told about.
+	u, err := url.Parse(settings.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("precision", "ms")
+	u.RawQuery = q.Encode()
+
+	headers := map[string]string{}
+	if settings.Token != "" {
+		headers["Authorization"] = "Token " + settings.Token
+	}
+
+	return &InfluxDBWriter{
+		client:  client,
+		url:     u.String(),
+		headers: headers,
+		clock:   clock,
+		logger:  l,
+		metrics: metrics,
+	}, nil
+}
+
+// Write writes the given frames to the InfluxDB endpoint.
+func (w *InfluxDBWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), influxDBBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	body, err := encodeLineProtocol(points)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+	if len(body) == 0 {
+		l.Debug("No samples to write", "name", name)
+		return nil
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	statusCode, err := postWrite(ctx, w.client, w.url, "text/plain; charset=utf-8", w.headers, body)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+	w.metrics.WritesTotal.WithLabelValues(append(lvs, fmt.Sprint(statusCode))...).Inc()
+
+	return err
+}
+
+// encodeLineProtocol encodes points in the line protocol with millisecond precision, one line per point.
+// Points with values that are NaN or infinite are skipped.
+func encodeLineProtocol(points []Point) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := influx.NewEncoder(&buf)
+	enc.SetPrecision(time.Millisecond)
+	enc.FailOnFieldErr(true)
+	for _, p := range points {
+		if math.IsNaN(p.Metric.V) || math.IsInf(p.Metric.V, 0) {
+			continue
+		}
+		m, err := influx.New(p.Name, p.Labels, map[string]any{influxDBValueField: p.Metric.V}, p.Metric.T)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := enc.Encode(m); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
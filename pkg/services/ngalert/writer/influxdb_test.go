@@ -0,0 +1,100 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestEncodeLineProtocol(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+
+	t.Run("encodes labels as tags and skips values that are not finite", func(t *testing.T) {
+		points := []Point{
+			{Name: "test", Labels: map[string]string{"b": "2", "a": "with space"}, Metric: Metric{T: now, V: 1.5}},
+			{Name: "test", Labels: map[string]string{"a": "1"}, Metric: Metric{T: now, V: math.NaN()}},
+			{Name: "test", Labels: map[string]string{"a": "2"}, Metric: Metric{T: now, V: math.Inf(1)}},
+		}
+
+		body, err := encodeLineProtocol(points)
+		require.NoError(t, err)
+		require.Equal(t, "test,a=with\\ space,b=2 value=1.5 1700000000123\n", string(body))
+	})
+
+	t.Run("returns empty body if there are no points", func(t *testing.T) {
+		body, err := encodeLineProtocol(nil)
+		require.NoError(t, err)
+		require.Empty(t, body)
+	})
+}
+
+func TestInfluxDBWriter_Write(t *testing.T) {
+	var lastRequest *http.Request
+	var lastBody []byte
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		lastRequest, lastBody = r, body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	settings := setting.RecordingRuleInfluxDBSettings{
+		URL:     srv.URL + "/api/v2/write?org=test&bucket=metrics",
+		Token:   "secret",
+		Timeout: time.Second,
+	}
+	writer, err := NewInfluxDBWriter(settings, testHTTPClientProvider{}, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.Now()
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, []map[string]string{{"foo": "1"}, {"foo": "2"}})
+
+	t.Run("writes line protocol with millisecond precision", func(t *testing.T) {
+		status = http.StatusNoContent
+		require.NoError(t, writer.Write(context.Background(), "test", now, frames, 1, map[string]string{"extra": "label"}))
+
+		require.Equal(t, "/api/v2/write", lastRequest.URL.Path)
+		require.Equal(t, "test", lastRequest.URL.Query().Get("org"))
+		require.Equal(t, "metrics", lastRequest.URL.Query().Get("bucket"))
+		require.Equal(t, "ms", lastRequest.URL.Query().Get("precision"))
+		require.Equal(t, "Token secret", lastRequest.Header.Get("Authorization"))
+
+		expected, err := encodeLineProtocol(mustPointsFromFrames(t, "test", now, frames, map[string]string{"extra": "label"}))
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(lastBody))
+	})
+
+	t.Run("rejected write when server returns 4xx", func(t *testing.T) {
+		status = http.StatusBadRequest
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrRejectedWrite)
+	})
+
+	t.Run("unexpected failure when server returns 5xx", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrUnexpectedWriteFailure)
+	})
+}
+
+func mustPointsFromFrames(t *testing.T, name string, now time.Time, frames data.Frames, extraLabels map[string]string) []Point {
+	t.Helper()
+	points, err := PointsFromFrames(name, now, frames, extraLabels)
+	require.NoError(t, err)
+	return points
+}
@@ -0,0 +1,97 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	otlpBackendType = "otlp"
+	otlpScopeName   = "grafana-recording-rule"
+)
+
+// OTLPWriter sends the results of recording rules as gauges to an OTLP/HTTP metrics endpoint.
+type OTLPWriter struct {
+	client  *http.Client
+	url     string
+	clock   clock.Clock
+	logger  log.Logger
+	metrics *metrics.RemoteWriter
+}
+
+func NewOTLPWriter(
+	settings setting.RecordingRuleOTLPSettings,
+	httpClientProvider HttpClientProvider,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*OTLPWriter, error) {
+	client, err := newHTTPWriterClient(httpClientProvider, settings.URL, settings.CustomHeaders, settings.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTLPWriter{
+		client:  client,
+		url:     settings.URL,
+		clock:   clock,
+		logger:  l,
+		metrics: metrics,
+	}, nil
+}
+
+// Write writes the given frames to the OTLP endpoint.
+func (w *OTLPWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), otlpBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	body, err := pmetricotlp.NewExportRequestFromMetrics(otlpMetricsFromPoints(name, points)).MarshalProto()
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	statusCode, err := postWrite(ctx, w.client, w.url, "application/x-protobuf", nil, body)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+	w.metrics.WritesTotal.WithLabelValues(append(lvs, fmt.Sprint(statusCode))...).Inc()
+
+	return err
+}
+
+func otlpMetricsFromPoints(name string, points []Point) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(otlpScopeName)
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(name)
+	dps := m.SetEmptyGauge().DataPoints()
+	for _, p := range points {
+		dp := dps.AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(p.Metric.T))
+		dp.SetDoubleValue(p.Metric.V)
+		for k, v := range p.Labels {
+			dp.Attributes().PutStr(k, v)
+		}
+	}
+	return md
+}
@@ -0,0 +1,146 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewOTLPWriter(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		settings setting.RecordingRuleOTLPSettings
+		err      bool
+	}{
+		{
+			name:     "relative url",
+			settings: setting.RecordingRuleOTLPSettings{URL: "/v1/metrics", Timeout: time.Second},
+			err:      true,
+		},
+		{
+			name:     "timeout is 0",
+			settings: setting.RecordingRuleOTLPSettings{URL: "http://localhost:4318/v1/metrics"},
+			err:      true,
+		},
+		{
+			name:     "valid settings",
+			settings: setting.RecordingRuleOTLPSettings{URL: "http://localhost:4318/v1/metrics", Timeout: time.Second},
+			err:      false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewOTLPWriter(tc.settings, testHTTPClientProvider{}, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+			if tc.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOTLPWriter_Write(t *testing.T) {
+	var lastRequest *http.Request
+	var lastBody []byte
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		lastRequest, lastBody = r, body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	settings := setting.RecordingRuleOTLPSettings{
+		URL:           srv.URL + "/v1/metrics",
+		CustomHeaders: map[string]string{"X-Scope-OrgID": "tenant"},
+		Timeout:       time.Second,
+	}
+	writer, err := NewOTLPWriter(settings, testHTTPClientProvider{}, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.Now()
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+
+	t.Run("writes expected points", func(t *testing.T) {
+		status = http.StatusOK
+		require.NoError(t, writer.Write(context.Background(), "test", now, frames, 1, map[string]string{"extra": "label"}))
+
+		require.Equal(t, "/v1/metrics", lastRequest.URL.Path)
+		require.Equal(t, "application/x-protobuf", lastRequest.Header.Get("Content-Type"))
+		require.Equal(t, "tenant", lastRequest.Header.Get("X-Scope-OrgID"))
+
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(lastBody))
+		ms := req.Metrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		require.Equal(t, 1, ms.Len())
+		require.Equal(t, "test", ms.At(0).Name())
+		require.Equal(t, pmetric.MetricTypeGauge, ms.At(0).Type())
+
+		dps := ms.At(0).Gauge().DataPoints()
+		require.Equal(t, len(series), dps.Len())
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			attrs := dp.Attributes().AsRaw()
+			require.Equal(t, "label", attrs["extra"])
+			foo := attrs["foo"].(string)
+			require.Equal(t, extractValue(t, frames, map[string]string{"foo": foo}, data.FrameTypeNumericWide), dp.DoubleValue())
+			require.Equal(t, now.UnixNano(), dp.Timestamp().AsTime().UnixNano())
+		}
+	})
+
+	t.Run("rejected write when server returns 4xx", func(t *testing.T) {
+		status = http.StatusBadRequest
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrRejectedWrite)
+	})
+
+	t.Run("unexpected failure when server returns 5xx", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrUnexpectedWriteFailure)
+	})
+
+	t.Run("error when frames are empty", func(t *testing.T) {
+		err := writer.Write(context.Background(), "test", now, data.Frames{data.NewFrame("test")}, 1, nil)
+		require.ErrorIs(t, err, ErrBadFrame)
+	})
+}
+
+type testHTTPClientProvider struct{}
+
+func (testHTTPClientProvider) New(options ...httpclient.Options) (*http.Client, error) {
+	var headers http.Header
+	if len(options) > 0 {
+		headers = options[0].Header
+	}
+	return &http.Client{Transport: headerTransport{headers: headers}}, nil
+}
+
+// headerTransport adds the headers configured in the client options, like the client created by the real provider does.
+type headerTransport struct {
+	headers http.Header
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for k, v := range t.headers {
+		req.Header[k] = v
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
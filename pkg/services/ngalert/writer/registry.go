@@ -0,0 +1,50 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ErrWriterNotConfigured is returned when a recording rule selects a writer that is not enabled.
+var ErrWriterNotConfigured = errors.New("recording rule writer is not configured")
+
+// Writer writes the results of a recording rule to a single backend.
+type Writer interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+}
+
+// Registry holds the writers of recording rules by type.
+// Every rule is written by the writer of the type it selects, or by the default writer if it does not select any.
+type Registry struct {
+	defaultType string
+	writers     map[string]Writer
+}
+
+// NewRegistry creates a Registry with the default writer registered under the given type.
+func NewRegistry(defaultType string, defaultWriter Writer) *Registry {
+	return &Registry{
+		defaultType: defaultType,
+		writers:     map[string]Writer{defaultType: defaultWriter},
+	}
+}
+
+// Register adds the writer of the given type, replacing the writer previously registered for it.
+func (r *Registry) Register(writerType string, w Writer) {
+	r.writers[writerType] = w
+}
+
+// Write writes the given frames with the writer of the given type. If the type is empty, the default writer is used.
+func (r *Registry) Write(ctx context.Context, writerType string, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	if writerType == "" {
+		writerType = r.defaultType
+	}
+	w, ok := r.writers[writerType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrWriterNotConfigured, writerType)
+	}
+	return w.Write(ctx, name, t, frames, orgID, extraLabels)
+}
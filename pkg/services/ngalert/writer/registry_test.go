@@ -0,0 +1,44 @@
+package writer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	var written []string
+	writerOf := func(writerType string) Writer {
+		return FakeWriter{WriteFunc: func(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+			written = append(written, writerType)
+			return nil
+		}}
+	}
+
+	registry := NewRegistry(ngmodels.RecordingWriterPrometheus, writerOf(ngmodels.RecordingWriterPrometheus))
+	registry.Register(ngmodels.RecordingWriterSQL, writerOf(ngmodels.RecordingWriterSQL))
+
+	t.Run("uses default writer if type is empty", func(t *testing.T) {
+		written = nil
+		require.NoError(t, registry.Write(context.Background(), "", "test", time.Now(), nil, 1, nil))
+		require.Equal(t, []string{ngmodels.RecordingWriterPrometheus}, written)
+	})
+
+	t.Run("uses writer of the given type", func(t *testing.T) {
+		written = nil
+		require.NoError(t, registry.Write(context.Background(), ngmodels.RecordingWriterSQL, "test", time.Now(), nil, 1, nil))
+		require.Equal(t, []string{ngmodels.RecordingWriterSQL}, written)
+	})
+
+	t.Run("fails if writer of the given type is not registered", func(t *testing.T) {
+		written = nil
+		err := registry.Write(context.Background(), ngmodels.RecordingWriterOTLP, "test", time.Now(), nil, 1, nil)
+		require.ErrorIs(t, err, ErrWriterNotConfigured)
+		require.Empty(t, written)
+	})
+}
@@ -0,0 +1,110 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/retention"
+	"github.com/grafana/grafana/pkg/services/ngalert/samplestore"
+)
+
+const sqlBackendType = "sql"
+
+// SQLWriter stores the results of recording rules in the Grafana database,
+// where they can be queried with the built-in Grafana datasource through the samplestore package.
+type SQLWriter struct {
+	db        db.DB
+	retention time.Duration
+	clock     clock.Clock
+	logger    log.Logger
+	metrics   *metrics.RemoteWriter
+
+	cleanup retention.Throttle
+}
+
+// NewSQLWriter creates a new SQLWriter. Samples older than retention are periodically deleted. If retention is 0, samples are kept forever.
+func NewSQLWriter(store db.DB, retention time.Duration, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) *SQLWriter {
+	return &SQLWriter{
+		db:        store,
+		retention: retention,
+		clock:     clock,
+		logger:    l,
+		metrics:   metrics,
+	}
+}
+
+// Write stores the given frames in the database.
+func (w *SQLWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), sqlBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	samples := make([]samplestore.Sample, 0, len(points))
+	for _, p := range points {
+		labels, err := json.Marshal(p.Labels)
+		if err != nil {
+			return errors.Join(ErrBadFrame, err)
+		}
+		samples = append(samples, samplestore.Sample{
+			OrgID:      orgID,
+			Metric:     p.Name,
+			Labels:     string(labels),
+			LabelsHash: data.Labels(p.Labels).Fingerprint().String(),
+			Epoch:      p.Metric.T.UnixMilli(),
+			Value:      p.Metric.V,
+		})
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	err = w.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if len(samples) == 0 {
+			return nil
+		}
+		_, err := sess.Insert(&samples)
+		return err
+	})
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	w.metrics.WritesTotal.WithLabelValues(append(lvs, status)...).Inc()
+	if err != nil {
+		return errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+
+	if w.shouldDeleteExpired() {
+		deleted, err := w.deleteExpired(ctx)
+		if err != nil {
+			l.Error("Failed to delete expired recording rule samples", "error", err)
+			return nil
+		}
+		l.Debug("Deleted expired recording rule samples", "deleted", deleted)
+	}
+	return nil
+}
+
+// shouldDeleteExpired returns true if retention is set and the last cleanup happened more than retention.Interval ago.
+func (w *SQLWriter) shouldDeleteExpired() bool {
+	return w.retention > 0 && w.cleanup.Due(w.clock.Now())
+}
+
+// deleteExpired deletes samples that are older than the retention period.
+func (w *SQLWriter) deleteExpired(ctx context.Context) (int64, error) {
+	return retention.DeleteBefore(ctx, w.db, samplestore.TableName, w.clock.Now().Add(-w.retention).UnixMilli())
+}
@@ -0,0 +1,98 @@
+package writer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/samplestore"
+)
+
+func TestIntegrationSQLWriter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := db.InitTestDB(t)
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	start := clk.Now()
+
+	retention := time.Hour
+	writer := NewSQLWriter(store, retention, clk, log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+	ctx := context.Background()
+
+	require.NoError(t, writer.Write(ctx, "test", start, frames, 1, map[string]string{"extra": "label"}))
+	require.NoError(t, writer.Write(ctx, "test", start.Add(time.Minute), frames, 1, map[string]string{"extra": "label"}))
+	require.NoError(t, writer.Write(ctx, "other", start, frames, 1, nil))
+	require.NoError(t, writer.Write(ctx, "test", start, frames, 2, nil))
+
+	query := func(t *testing.T, q samplestore.Query) data.Frames {
+		t.Helper()
+		if q.OrgID == 0 {
+			q.OrgID = 1
+		}
+		q.From = start.Add(-time.Hour)
+		q.To = start.Add(time.Hour)
+		result, err := samplestore.Find(ctx, store, q)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("returns a frame per series sorted by time", func(t *testing.T) {
+		result := query(t, samplestore.Query{Metric: "test"})
+		require.Len(t, result, len(series))
+		for i, frame := range result {
+			require.Equal(t, "test", frame.Name)
+			require.Equal(t, data.Labels{"foo": series[i]["foo"], "extra": "label"}, frame.Fields[1].Labels)
+			require.Equal(t, 2, frame.Rows())
+			require.Equal(t, start.UnixMilli(), frame.Fields[0].At(0).(time.Time).UnixMilli())
+			require.Equal(t, start.Add(time.Minute).UnixMilli(), frame.Fields[0].At(1).(time.Time).UnixMilli())
+			require.Equal(t, extractValue(t, frames, series[i], data.FrameTypeNumericWide), frame.Fields[1].At(0).(float64))
+		}
+	})
+
+	t.Run("filters by labels", func(t *testing.T) {
+		result := query(t, samplestore.Query{Metric: "test", Labels: map[string]string{"foo": "2"}})
+		require.Len(t, result, 1)
+		require.Equal(t, "2", result[0].Fields[1].Labels["foo"])
+
+		result = query(t, samplestore.Query{Metric: "test", Labels: map[string]string{"foo": "3"}})
+		require.Empty(t, result)
+	})
+
+	t.Run("filters by org", func(t *testing.T) {
+		result := query(t, samplestore.Query{OrgID: 2, Metric: "test"})
+		require.Len(t, result, len(series))
+		for _, frame := range result {
+			require.Equal(t, 1, frame.Rows())
+		}
+	})
+
+	t.Run("fails if metric is empty", func(t *testing.T) {
+		_, err := samplestore.Find(ctx, store, samplestore.Query{OrgID: 1})
+		require.Error(t, err)
+	})
+
+	t.Run("deletes samples older than retention", func(t *testing.T) {
+		clk.Add(retention + 90*time.Second)
+		require.NoError(t, writer.Write(ctx, "test", clk.Now(), frames, 1, map[string]string{"extra": "label"}))
+
+		result, err := samplestore.Find(ctx, store, samplestore.Query{OrgID: 1, Metric: "test", From: start.Add(-time.Hour), To: clk.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, result, len(series))
+		for _, frame := range result {
+			require.Equal(t, 1, frame.Rows())
+		}
+	})
+}
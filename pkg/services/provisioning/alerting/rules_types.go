@@ -72,11 +72,15 @@ type AlertRuleV1 struct {
 	NoDataState          values.StringValue      `json:"noDataState" yaml:"noDataState"`
 	ExecErrState         values.StringValue      `json:"execErrState" yaml:"execErrState"`
 	For                  values.StringValue      `json:"for" yaml:"for"`
+	KeepFiringFor        values.StringValue      `json:"keepFiringFor" yaml:"keepFiringFor"`
+	QueryOffset          values.StringValue      `json:"queryOffset" yaml:"queryOffset"`
 	Annotations          values.StringMapValue   `json:"annotations" yaml:"annotations"`
 	Labels               values.StringMapValue   `json:"labels" yaml:"labels"`
 	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
 	Record               *RecordV1               `json:"record" yaml:"record"`
+	Dependencies         []AlertRuleDependencyV1 `json:"dependencies" yaml:"dependencies"`
+	EvaluationLimits     *EvaluationLimitsV1     `json:"evaluation_limits" yaml:"evaluation_limits"`
 }
 
 func withFallback(value, fallback string) *string {
@@ -107,6 +111,24 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 	}
 	alertRule.For = time.Duration(duration)
+	keepFiringFor := model.Duration(0)
+	if rule.KeepFiringFor.Value() != "" {
+		var err error
+		keepFiringFor, err = model.ParseDuration(rule.KeepFiringFor.Value())
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse 'keepFiringFor' field: %w", alertRule.Title, err)
+		}
+	}
+	alertRule.KeepFiringFor = time.Duration(keepFiringFor)
+	queryOffset := model.Duration(0)
+	if rule.QueryOffset.Value() != "" {
+		var err error
+		queryOffset, err = model.ParseDuration(rule.QueryOffset.Value())
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse 'queryOffset' field: %w", alertRule.Title, err)
+		}
+	}
+	alertRule.QueryOffset = time.Duration(queryOffset)
 
 	dasboardUID := rule.DasboardUID.Value()
 	dashboardUID := rule.DashboardUID.Value()
@@ -162,6 +184,16 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.Record = &record
 	}
+	for _, dependencyV1 := range rule.Dependencies {
+		alertRule.Dependencies = append(alertRule.Dependencies, dependencyV1.mapToModel())
+	}
+	if rule.EvaluationLimits != nil {
+		limits, err := rule.EvaluationLimits.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.EvaluationLimits = limits
+	}
 	return alertRule, nil
 }
 
@@ -273,11 +305,57 @@ func (nsV1 *NotificationSettingsV1) mapToModel() (models.NotificationSettings, e
 type RecordV1 struct {
 	Metric values.StringValue `json:"metric" yaml:"metric"`
 	From   values.StringValue `json:"from" yaml:"from"`
+	Writer values.StringValue `json:"writer" yaml:"writer"`
 }
 
 func (record *RecordV1) mapToModel() (models.Record, error) {
 	return models.Record{
 		Metric: record.Metric.Value(),
 		From:   record.From.Value(),
+		Writer: record.Writer.Value(),
+	}, nil
+}
+
+type AlertRuleDependencyV1 struct {
+	RuleUID values.StringValue   `json:"rule_uid" yaml:"rule_uid"`
+	Equal   []values.StringValue `json:"equal" yaml:"equal"`
+}
+
+func (dependencyV1 *AlertRuleDependencyV1) mapToModel() models.AlertRuleDependency {
+	var equal []string
+	if len(dependencyV1.Equal) > 0 {
+		equal = make([]string, 0, len(dependencyV1.Equal))
+		for _, value := range dependencyV1.Equal {
+			if value.Value() == "" {
+				continue
+			}
+			equal = append(equal, value.Value())
+		}
+	}
+	return models.AlertRuleDependency{
+		RuleUID: dependencyV1.RuleUID.Value(),
+		Equal:   equal,
+	}
+}
+
+type EvaluationLimitsV1 struct {
+	MaxDuration values.StringValue `json:"max_duration" yaml:"max_duration"`
+	MaxSeries   values.IntValue    `json:"max_series" yaml:"max_series"`
+	MaxQueries  values.IntValue    `json:"max_queries" yaml:"max_queries"`
+}
+
+func (limitsV1 *EvaluationLimitsV1) mapToModel() (models.AlertRuleEvaluationLimits, error) {
+	maxDuration := model.Duration(0)
+	if limitsV1.MaxDuration.Value() != "" {
+		var err error
+		maxDuration, err = model.ParseDuration(limitsV1.MaxDuration.Value())
+		if err != nil {
+			return models.AlertRuleEvaluationLimits{}, fmt.Errorf("failed to parse 'max_duration' field of evaluation limits: %w", err)
+		}
+	}
+	return models.AlertRuleEvaluationLimits{
+		MaxDuration: time.Duration(maxDuration),
+		MaxSeries:   limitsV1.MaxSeries.Value(),
+		MaxQueries:  limitsV1.MaxQueries.Value(),
 	}, nil
 }
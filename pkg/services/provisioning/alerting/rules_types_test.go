@@ -128,6 +128,50 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule without a keepFiringFor duration should default to 0s", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with a keepFiringFor duration should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("15m"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 15*time.Minute, ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keepFiringFor duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("10x"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with a queryOffset duration should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		queryOffset := values.StringValue{}
+		err := yaml.Unmarshal([]byte("1m"), &queryOffset)
+		rule.QueryOffset = queryOffset
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, ruleMapped.QueryOffset)
+	})
+	t.Run("a rule with an invalid queryOffset duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		queryOffset := values.StringValue{}
+		err := yaml.Unmarshal([]byte("10x"), &queryOffset)
+		rule.QueryOffset = queryOffset
+		require.NoError(t, err)
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
@@ -200,6 +244,35 @@ func TestRules(t *testing.T) {
 		require.Len(t, ruleMapped.NotificationSettings, 1)
 		require.Equal(t, models.NotificationSettings{Receiver: "test-receiver"}, ruleMapped.NotificationSettings[0])
 	})
+	t.Run("a rule with dependencies should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Dependencies = []AlertRuleDependencyV1{
+			{RuleUID: stringToStringValue("parent-1"), Equal: []values.StringValue{stringToStringValue("cluster"), stringToStringValue("")}},
+			{RuleUID: stringToStringValue("parent-2")},
+		}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []models.AlertRuleDependency{
+			{RuleUID: "parent-1", Equal: []string{"cluster"}},
+			{RuleUID: "parent-2"},
+		}, ruleMapped.Dependencies)
+	})
+	t.Run("a rule with evaluation limits should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		var limits EvaluationLimitsV1
+		err := yaml.Unmarshal([]byte("max_duration: 30s\nmax_series: 100"), &limits)
+		require.NoError(t, err)
+		rule.EvaluationLimits = &limits
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, models.AlertRuleEvaluationLimits{MaxDuration: 30 * time.Second, MaxSeries: 100}, ruleMapped.EvaluationLimits)
+	})
+	t.Run("a rule with invalid max duration of evaluation limits should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.EvaluationLimits = &EvaluationLimitsV1{MaxDuration: stringToStringValue("10x")}
+		_, err := rule.mapToModel(1)
+		require.ErrorContains(t, err, "max_duration")
+	})
 }
 
 func TestNotificationsSettingsV1MapToModel(t *testing.T) {
@@ -19,6 +19,7 @@ type parsedQuery struct {
 
 type parsedRequest struct {
 	hasExpression bool
+	explain       bool
 	parsedQueries map[string][]parsedQuery
 	dsTypes       map[string]bool
 }
@@ -133,6 +133,22 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 
 	ualert.AddRuleMetadata(mg)
 
+	ualert.AddKeepFiringForColumns(mg)
+
+	ualert.AddRuleDependenciesColumns(mg)
+
+	ualert.AddStateHistoryTables(mg)
+
+	ualert.AddRecordingRuleSamplesTable(mg)
+
+	ualert.AddQueryOffsetColumns(mg)
+
+	ualert.AddNotificationDeliveryTable(mg)
+
+	ualert.AddThresholdHistoryColumn(mg)
+
+	ualert.AddRuleEvaluationLimitsColumns(mg)
+
 	accesscontrol.AddOrphanedMigrations(mg)
 
 	accesscontrol.AddActionSetPermissionsMigrator(mg)
@@ -0,0 +1,18 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleEvaluationLimitsColumns adds evaluation_limits column to alert_rule and alert_rule_version tables.
+func AddRuleEvaluationLimitsColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add evaluation_limits column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "evaluation_limits",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add evaluation_limits column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "evaluation_limits",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
@@ -0,0 +1,36 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddNotificationDeliveryTable creates the table of the notification delivery log.
+// alert_notification_delivery stores one row per attempt of an integration of a contact point to send a notification.
+func AddNotificationDeliveryTable(mg *migrator.Migrator) {
+	delivery := migrator.Table{
+		Name: "alert_notification_delivery",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "group_labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "alerts", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "http_status", Type: migrator.DB_Int, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: false},
+			{Name: "latency_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "payload_hash", Type: migrator.DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "replay_of", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "receiver", "epoch"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_delivery table", migrator.NewAddTableMigration(delivery))
+	mg.AddMigration("add index in alert_notification_delivery table on org_id, epoch columns", migrator.NewAddIndexMigration(delivery, delivery.Indices[0]))
+	mg.AddMigration("add index in alert_notification_delivery table on org_id, receiver, epoch columns", migrator.NewAddIndexMigration(delivery, delivery.Indices[1]))
+}
@@ -0,0 +1,28 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRecordingRuleSamplesTable creates the table used by the SQL writer of recording rules.
+// Every row is a single sample of a series, identified by the metric name and the hash of its labels.
+func AddRecordingRuleSamplesTable(mg *migrator.Migrator) {
+	samples := migrator.Table{
+		Name: "recording_rule_sample",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "metric", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "labels_hash", Type: migrator.DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "value", Type: migrator.DB_Double, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "metric", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"epoch"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create recording_rule_sample table", migrator.NewAddTableMigration(samples))
+	mg.AddMigration("add index in recording_rule_sample table on org_id, metric, epoch columns", migrator.NewAddIndexMigration(samples, samples.Indices[0]))
+	mg.AddMigration("add index in recording_rule_sample table on epoch column", migrator.NewAddIndexMigration(samples, samples.Indices[1]))
+}
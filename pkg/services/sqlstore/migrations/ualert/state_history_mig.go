@@ -0,0 +1,56 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddStateHistoryTables creates the tables used by the SQL state history backend.
+// alert_state_history stores one row per recorded state transition, and alert_state_history_label
+// stores a hash of every instance label of a transition, which makes it possible to filter by labels with an index.
+func AddStateHistoryTables(mg *migrator.Migrator) {
+	stateHistory := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+			{Name: "panel_id", Type: migrator.DB_BigInt, Nullable: true},
+			{Name: "labels_fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "line", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "epoch", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "rule_uid", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "labels_fingerprint", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "state", "epoch"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "dashboard_uid", "panel_id", "epoch"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(stateHistory))
+	mg.AddMigration("add index in alert_state_history table on org_id, epoch columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[0]))
+	mg.AddMigration("add index in alert_state_history table on org_id, rule_uid, epoch columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[1]))
+	mg.AddMigration("add index in alert_state_history table on org_id, labels_fingerprint, epoch columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[2]))
+	mg.AddMigration("add index in alert_state_history table on org_id, state, epoch columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[3]))
+	mg.AddMigration("add index in alert_state_history table on org_id, dashboard_uid, panel_id, epoch columns", migrator.NewAddIndexMigration(stateHistory, stateHistory.Indices[4]))
+
+	stateHistoryLabel := migrator.Table{
+		Name: "alert_state_history_label",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "history_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "label_hash", Type: migrator.DB_NVarchar, Length: 16, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"label_hash", "history_id"}, Type: migrator.IndexType},
+			{Cols: []string{"history_id"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history_label table", migrator.NewAddTableMigration(stateHistoryLabel))
+	mg.AddMigration("add index in alert_state_history_label table on label_hash, history_id columns", migrator.NewAddIndexMigration(stateHistoryLabel, stateHistoryLabel.Indices[0]))
+	mg.AddMigration("add index in alert_state_history_label table on history_id column", migrator.NewAddIndexMigration(stateHistoryLabel, stateHistoryLabel.Indices[1]))
+}
@@ -0,0 +1,12 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddThresholdHistoryColumn adds threshold_history to alert_instance.
+func AddThresholdHistoryColumn(mg *migrator.Migrator) {
+	mg.AddMigration("add threshold_history column to alert_instance table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_instance"}, &migrator.Column{
+		Name:     "threshold_history",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
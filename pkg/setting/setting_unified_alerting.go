@@ -65,6 +65,9 @@ const (
 	lokiDefaultMaxQueryLength      = 721 * time.Hour // 30d1h, matches the default value in Loki
 	defaultRecordingRequestTimeout = 10 * time.Second
 	lokiDefaultMaxQuerySize        = 65536 // 64kb
+	sqlHistoryDefaultRetention     = 720 * time.Hour
+	defaultRecordingWriter         = "prometheus"
+	recordingSQLDefaultRetention   = 168 * time.Hour
 )
 
 type UnifiedAlertingSettings struct {
@@ -90,11 +93,13 @@ type UnifiedAlertingSettings struct {
 	HARedisMaxConns                 int
 	HARedisTLSEnabled               bool
 	HARedisTLSConfig                dstls.ClientConfig
+	HASchedulerSharding             bool
 	MaxAttempts                     int64
 	MinInterval                     time.Duration
 	EvaluationTimeout               time.Duration
 	EvaluationResultLimit           int
 	DisableJitter                   bool
+	QueryResultCache                bool
 	ExecuteAlerts                   bool
 	DefaultConfiguration            string
 	Enabled                         *bool // determines whether unified alerting is enabled. If it is nil then user did not define it and therefore its value will be determined during migration. Services should not use it directly.
@@ -110,6 +115,7 @@ type UnifiedAlertingSettings struct {
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	RecordingRules                RecordingRuleSettings
+	EvaluationLimits              UnifiedAlertingEvaluationLimitsSettings
 
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency   int
@@ -119,6 +125,9 @@ type UnifiedAlertingSettings struct {
 	// Retention period for Alertmanager notification log entries.
 	NotificationLogRetention time.Duration
 
+	// Retention period for the log of notification deliveries of contact points. The log is disabled if it is 0.
+	NotificationDeliveryLogRetention time.Duration
+
 	// Duration for which a resolved alert state transition will continue to be sent to the Alertmanager.
 	ResolvedAlertRetention time.Duration
 
@@ -135,6 +144,58 @@ type RecordingRuleSettings struct {
 	BasicAuthPassword string
 	CustomHeaders     map[string]string
 	Timeout           time.Duration
+	DefaultWriter     string
+	SQL               RecordingRuleSQLSettings
+	OTLP              RecordingRuleOTLPSettings
+	InfluxDB          RecordingRuleInfluxDBSettings
+}
+
+// RecordingRuleSQLSettings configures the writer that stores the results of recording rules in the Grafana database.
+type RecordingRuleSQLSettings struct {
+	Enabled   bool
+	Retention time.Duration
+}
+
+// RecordingRuleOTLPSettings configures the writer that sends the results of recording rules to an OTLP/HTTP metrics endpoint.
+type RecordingRuleOTLPSettings struct {
+	Enabled       bool
+	URL           string
+	CustomHeaders map[string]string
+	Timeout       time.Duration
+}
+
+// RecordingRuleInfluxDBSettings configures the writer that sends the results of recording rules to an InfluxDB write endpoint.
+type RecordingRuleInfluxDBSettings struct {
+	Enabled bool
+	URL     string
+	Token   string
+	Timeout time.Duration
+}
+
+// EvaluationLimits are the limits that the evaluation of an alert rule must not exceed. Zero means no limit.
+type EvaluationLimits struct {
+	// MaxDuration is the maximum time the evaluation of the rule can take.
+	MaxDuration time.Duration
+	// MaxSeries is the maximum number of series the evaluation of the rule can return.
+	MaxSeries int
+	// MaxQueries is the maximum number of data source queries the rule can have.
+	MaxQueries int
+}
+
+// UnifiedAlertingEvaluationLimitsSettings configures the limits of the evaluation of alert rules.
+type UnifiedAlertingEvaluationLimitsSettings struct {
+	// Default contains the limits of the alert rules of all organizations.
+	Default EvaluationLimits
+	// Orgs contains the limits of the alert rules of specific organizations.
+	Orgs map[int64]EvaluationLimits
+}
+
+// For returns the limits of the alert rules of the organization.
+func (s UnifiedAlertingEvaluationLimitsSettings) For(orgID int64) EvaluationLimits {
+	if limits, ok := s.Orgs[orgID]; ok {
+		return limits
+	}
+	return s.Default
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -171,6 +232,7 @@ type UnifiedAlertingStateHistorySettings struct {
 	LokiBasicAuthUsername string
 	LokiMaxQueryLength    time.Duration
 	LokiMaxQuerySize      int
+	SQLRetention          time.Duration
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
@@ -274,6 +336,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 			uaCfg.HAPeers = append(uaCfg.HAPeers, peer)
 		}
 	}
+	uaCfg.HASchedulerSharding = ua.Key("ha_scheduler_sharding").MustBool(false)
 	uaCfg.HARedisTLSEnabled = ua.Key("ha_redis_tls_enabled").MustBool(false)
 	uaCfg.HARedisTLSConfig.CertPath = ua.Key("ha_redis_tls_cert_path").MustString("")
 	uaCfg.HARedisTLSConfig.KeyPath = ua.Key("ha_redis_tls_key_path").MustString("")
@@ -317,6 +380,8 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	// We can consider removing the knob entirely in a release after 10.4.
 	uaCfg.DisableJitter = ua.Key("disable_jitter").MustBool(false)
 
+	uaCfg.QueryResultCache = ua.Key("query_result_cache").MustBool(false)
+
 	// The base interval of the scheduler for evaluating alerts.
 	// 1. It is used by the internal scheduler's timer to tick at this interval.
 	// 2. to spread evaluations of rules that need to be evaluated at the current tick T. In other words, the evaluation of rules at the tick T will be evenly spread in the interval from T to T+scheduler_tick_interval.
@@ -419,6 +484,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		LokiBasicAuthPassword: stateHistory.Key("loki_basic_auth_password").MustString(""),
 		LokiMaxQueryLength:    stateHistory.Key("loki_max_query_length").MustDuration(lokiDefaultMaxQueryLength),
 		LokiMaxQuerySize:      stateHistory.Key("loki_max_query_size").MustInt(lokiDefaultMaxQuerySize),
+		SQLRetention:          stateHistory.Key("sql_retention").MustDuration(sqlHistoryDefaultRetention),
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
@@ -432,6 +498,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		BasicAuthUsername: rr.Key("basic_auth_username").MustString(""),
 		BasicAuthPassword: rr.Key("basic_auth_password").MustString(""),
 		Timeout:           rr.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+		DefaultWriter:     rr.Key("default_writer").MustString(defaultRecordingWriter),
 	}
 
 	rrHeaders := iniFile.Section("recording_rules.custom_headers")
@@ -441,8 +508,39 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		uaCfgRecordingRules.CustomHeaders[key.Name()] = key.Value()
 	}
 
+	rrSQL := iniFile.Section("recording_rules.sql")
+	uaCfgRecordingRules.SQL = RecordingRuleSQLSettings{
+		Enabled:   rrSQL.Key("enabled").MustBool(false),
+		Retention: rrSQL.Key("retention").MustDuration(recordingSQLDefaultRetention),
+	}
+
+	rrOTLP := iniFile.Section("recording_rules.otlp")
+	uaCfgRecordingRules.OTLP = RecordingRuleOTLPSettings{
+		Enabled: rrOTLP.Key("enabled").MustBool(false),
+		URL:     rrOTLP.Key("url").MustString(""),
+		Timeout: rrOTLP.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+	}
+	rrOTLPHeadersKeys := iniFile.Section("recording_rules.otlp.custom_headers").Keys()
+	uaCfgRecordingRules.OTLP.CustomHeaders = make(map[string]string, len(rrOTLPHeadersKeys))
+	for _, key := range rrOTLPHeadersKeys {
+		uaCfgRecordingRules.OTLP.CustomHeaders[key.Name()] = key.Value()
+	}
+
+	rrInfluxDB := iniFile.Section("recording_rules.influxdb")
+	uaCfgRecordingRules.InfluxDB = RecordingRuleInfluxDBSettings{
+		Enabled: rrInfluxDB.Key("enabled").MustBool(false),
+		URL:     rrInfluxDB.Key("url").MustString(""),
+		Token:   rrInfluxDB.Key("token").MustString(""),
+		Timeout: rrInfluxDB.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+	}
+
 	uaCfg.RecordingRules = uaCfgRecordingRules
 
+	uaCfg.EvaluationLimits, err = readEvaluationLimitsSettings(iniFile)
+	if err != nil {
+		return err
+	}
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
 	uaCfg.StatePeriodicSaveInterval, err = gtime.ParseDuration(valueAsString(ua, "state_periodic_save_interval", (time.Minute * 5).String()))
@@ -455,6 +553,11 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		return err
 	}
 
+	uaCfg.NotificationDeliveryLogRetention, err = gtime.ParseDuration(valueAsString(ua, "notification_delivery_log_retention", (7 * 24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+
 	uaCfg.ResolvedAlertRetention, err = gtime.ParseDuration(valueAsString(ua, "resolved_alert_retention", (15 * time.Minute).String()))
 	if err != nil {
 		return err
@@ -469,6 +572,49 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	return nil
 }
 
+const evaluationLimitsOrgSectionPrefix = "unified_alerting.evaluation_limits.org."
+
+// readEvaluationLimitsSettings reads the default limits from the section [unified_alerting.evaluation_limits],
+// and the limits of specific organizations from the sections [unified_alerting.evaluation_limits.org.<org ID>].
+// The limits of an organization that are not set use the default value.
+func readEvaluationLimitsSettings(iniFile *ini.File) (UnifiedAlertingEvaluationLimitsSettings, error) {
+	settings := UnifiedAlertingEvaluationLimitsSettings{
+		Orgs: make(map[int64]EvaluationLimits),
+	}
+	var err error
+	settings.Default, err = readEvaluationLimits(iniFile.Section("unified_alerting.evaluation_limits"), EvaluationLimits{})
+	if err != nil {
+		return settings, err
+	}
+	for _, section := range iniFile.Sections() {
+		org, ok := strings.CutPrefix(section.Name(), evaluationLimitsOrgSectionPrefix)
+		if !ok {
+			continue
+		}
+		orgID, err := strconv.ParseInt(org, 10, 64)
+		if err != nil {
+			return settings, fmt.Errorf("section [%s] is invalid, the suffix must be the ID of an organization", section.Name())
+		}
+		settings.Orgs[orgID], err = readEvaluationLimits(section, settings.Default)
+		if err != nil {
+			return settings, err
+		}
+	}
+	return settings, nil
+}
+
+func readEvaluationLimits(section *ini.Section, defaults EvaluationLimits) (EvaluationLimits, error) {
+	limits := EvaluationLimits{
+		MaxDuration: section.Key("max_duration").MustDuration(defaults.MaxDuration),
+		MaxSeries:   section.Key("max_series").MustInt(defaults.MaxSeries),
+		MaxQueries:  section.Key("max_queries").MustInt(defaults.MaxQueries),
+	}
+	if limits.MaxDuration < 0 || limits.MaxSeries < 0 || limits.MaxQueries < 0 {
+		return limits, fmt.Errorf("section [%s] is invalid, limits cannot be negative", section.Name())
+	}
+	return limits, nil
+}
+
 func GetAlertmanagerDefaultConfiguration() string {
 	return alertmanagerDefaultConfiguration
 }
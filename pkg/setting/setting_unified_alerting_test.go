@@ -346,3 +346,48 @@ func TestHARedisTLSSettings(t *testing.T) {
 	require.Equal(t, cipherSuites, cfg.UnifiedAlerting.HARedisTLSConfig.CipherSuites)
 	require.Equal(t, minVersion, cfg.UnifiedAlerting.HARedisTLSConfig.MinVersion)
 }
+
+func TestEvaluationLimitsSettings(t *testing.T) {
+	t.Run("limits are disabled by default", func(t *testing.T) {
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(ini.Empty()))
+		require.Equal(t, EvaluationLimits{}, cfg.UnifiedAlerting.EvaluationLimits.For(1))
+	})
+
+	t.Run("organizations override the default limits", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[unified_alerting.evaluation_limits]
+max_duration = 30s
+max_series = 1000
+max_queries = 5
+
+[unified_alerting.evaluation_limits.org.2]
+max_series = 10000
+`))
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		limits := cfg.UnifiedAlerting.EvaluationLimits
+		require.Equal(t, EvaluationLimits{MaxDuration: 30 * time.Second, MaxSeries: 1000, MaxQueries: 5}, limits.For(1))
+		require.Equal(t, EvaluationLimits{MaxDuration: 30 * time.Second, MaxSeries: 10000, MaxQueries: 5}, limits.For(2))
+	})
+
+	t.Run("fails if organization ID is invalid", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[unified_alerting.evaluation_limits.org.main]
+max_series = 10000
+`))
+		require.NoError(t, err)
+		require.Error(t, NewCfg().ReadUnifiedAlertingSettings(f))
+	})
+
+	t.Run("fails if limit is negative", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[unified_alerting.evaluation_limits]
+max_queries = -1
+`))
+		require.NoError(t, err)
+		require.Error(t, NewCfg().ReadUnifiedAlertingSettings(f))
+	})
+}
@@ -1,6 +1,7 @@
 package setting
 
 import (
+	"path/filepath"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/apiserver/rest"
@@ -37,6 +38,9 @@ func (cfg *Cfg) setUnifiedStorageConfig() {
 	cfg.UnifiedStorage = storageConfig
 }
 
+// setIndexPath reads the directory of the search index of unified storage, which defaults to a directory under the data path
+// so that the index is kept across restarts.
 func (cfg *Cfg) setIndexPath() {
-	cfg.IndexPath = cfg.Raw.Section("unified_storage").Key("index_path").String()
+	section := cfg.Raw.Section("unified_storage")
+	cfg.IndexPath = valueAsString(section, "index_path", filepath.Join(cfg.DataPath, "unified-search-index"))
 }
@@ -1,6 +1,7 @@
 package setting
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +33,21 @@ func TestCfg_setUnifiedStorageConfig(t *testing.T) {
 		})
 	})
 }
+
+func TestCfg_setIndexPath(t *testing.T) {
+	cfg := NewCfg()
+	err := cfg.Load(CommandLineArgs{HomePath: "../../", Config: "../../conf/defaults.ini"})
+	assert.NoError(t, err)
+
+	t.Run("defaults to a directory under the data path", func(t *testing.T) {
+		assert.Equal(t, filepath.Join(cfg.DataPath, "unified-search-index"), cfg.IndexPath)
+	})
+
+	t.Run("read index_path", func(t *testing.T) {
+		_, err := cfg.Raw.Section("unified_storage").NewKey("index_path", "/var/lib/grafana-index")
+		assert.NoError(t, err)
+
+		cfg.setIndexPath()
+		assert.Equal(t, "/var/lib/grafana-index", cfg.IndexPath)
+	})
+}
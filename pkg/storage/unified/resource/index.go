@@ -2,16 +2,41 @@ package resource
 
 import (
 	"context"
-	golog "log"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
-	"github.com/google/uuid"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
 	"github.com/grafana/grafana/pkg/infra/log"
 )
 
+const (
+	// indexFormatVersion must be incremented when the way resources are indexed changes,
+	// so that the shards persisted by previous versions are rebuilt.
+	indexFormatVersion = 1
+
+	// shardDirSuffix is the suffix of the directories holding the shards of the index.
+	shardDirSuffix = ".bleve"
+
+	// Keys of the metadata stored in the shards
+	internalSchemaKey = "schema"
+	internalRVKey     = "rv"
+)
+
+var errIndexClosed = errors.New("index is closed")
+
 type Shard struct {
 	index bleve.Index
 	path  string
@@ -20,21 +45,25 @@ type Shard struct {
 
 type Index struct {
 	shards map[string]Shard
+	// The resource version up to which each resource type is indexed, by group/resource.
+	// Every shard persists a copy, so that the index can be resumed from the backend on restart.
+	rvs    map[string]int64
+	schema string
+	closed bool
+	mu     sync.RWMutex
 	opts   Opts
 	s      *server
 	log    log.Logger
 	path   string
 }
 
+// NewIndex creates an index whose shards are persisted in the directory at path.
 func NewIndex(s *server, opts Opts, path string) *Index {
-	if path == "" {
-		path = os.TempDir()
-	}
-
 	idx := &Index{
 		s:      s,
 		opts:   opts,
 		shards: make(map[string]Shard),
+		rvs:    make(map[string]int64),
 		log:    log.New("unifiedstorage.search.index"),
 		path:   path,
 	}
@@ -42,7 +71,7 @@ func NewIndex(s *server, opts Opts, path string) *Index {
 	return idx
 }
 
-func (i *Index) IndexBatch(list *ListResponse, kind string) error {
+func (i *Index) indexBatch(list *ListResponse, kind string) error {
 	for _, obj := range list.Items {
 		res, err := NewIndexedResource(obj.Value)
 		if err != nil {
@@ -55,13 +84,7 @@ func (i *Index) IndexBatch(list *ListResponse, kind string) error {
 		}
 		i.log.Debug("indexing resource in batch", "batch_count", len(list.Items), "kind", kind, "tenant", res.Namespace)
 
-		// Transform the raw resource into a more generic indexable resource
-		indexableResource, err := NewIndexedResource(obj.Value)
-		if err != nil {
-			return err
-		}
-
-		err = shard.batch.Index(res.Uid, indexableResource)
+		err = shard.batch.Index(res.Uid, res)
 		if err != nil {
 			return err
 		}
@@ -78,24 +101,181 @@ func (i *Index) IndexBatch(list *ListResponse, kind string) error {
 	return nil
 }
 
+// Init opens the shards persisted by a previous run and catches up with the changes made since then.
+// The index is rebuilt from scratch when the shards were persisted with a different schema, when they are
+// corrupted or when the storage backend cannot replay its events.
 func (i *Index) Init(ctx context.Context) error {
 	start := time.Now().Unix()
 
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	schema, err := indexSchema()
+	if err != nil {
+		return err
+	}
+	i.schema = schema
+	i.closed = false
+
 	resourceTypes := fetchResourceTypes()
+	if err := i.resume(ctx, resourceTypes); err != nil {
+		i.log.Info("rebuilding index", "reason", err)
+		if err := i.rebuild(ctx, resourceTypes); err != nil {
+			return err
+		}
+	}
+
+	end := time.Now().Unix()
+	i.log.Debug("Initial indexing finished", "seconds", float64(end-start))
+	if IndexServerMetrics != nil {
+		IndexServerMetrics.IndexCreationTime.WithLabelValues().Observe(float64(end - start))
+	}
+
+	return nil
+}
+
+// resume opens the persisted shards and replays the events written since they were last updated.
+func (i *Index) resume(ctx context.Context, resourceTypes []*ListOptions) error {
+	backend, ok := i.s.backend.(ReplayableBackend)
+	if !ok {
+		return errors.New("the storage backend cannot replay write events")
+	}
+	if err := i.openShards(resourceTypes); err != nil {
+		return err
+	}
+	if len(i.shards) == 0 {
+		return errors.New("no persisted shards")
+	}
+
+	for _, rt := range resourceTypes {
+		key := resourceTypeKey(rt.Key)
+		since := i.rvs[key]
+		rv, err := backend.ReplayWriteEvents(ctx, rt.Key, since, func(event *WrittenEvent) error {
+			return i.applyWrittenEvent(event)
+		})
+		if err != nil {
+			return fmt.Errorf("replay %s events: %w", rt.Key.Resource, err)
+		}
+		i.log.Info("resumed index", "kind", rt.Key.Resource, "since", since, "resourceVersion", rv)
+		i.rvs[key] = max(i.rvs[key], rv)
+	}
+
+	return i.persistRVs()
+}
+
+// openShards opens the shards persisted in the index directory. The index resumes every resource type from
+// the lowest resource version persisted by the shards, as the shards that were not updated lag behind.
+func (i *Index) openShards(resourceTypes []*ListOptions) error {
+	entries, err := os.ReadDir(i.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), shardDirSuffix)
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		tenant, err := url.PathUnescape(name)
+		if err != nil {
+			return fmt.Errorf("invalid shard directory %q: %w", entry.Name(), err)
+		}
+		path := filepath.Join(i.path, entry.Name())
+		index, err := bleve.Open(path)
+		if err != nil {
+			return fmt.Errorf("open shard of tenant %q: %w", tenant, err)
+		}
+		i.shards[tenant] = Shard{
+			index: index,
+			path:  path,
+			batch: index.NewBatch(),
+		}
+
+		rvs, err := i.readShardMetadata(index)
+		if err != nil {
+			return fmt.Errorf("shard of tenant %q: %w", tenant, err)
+		}
+		for _, rt := range resourceTypes {
+			key := resourceTypeKey(rt.Key)
+			rv, ok := rvs[key]
+			if !ok {
+				return fmt.Errorf("shard of tenant %q: missing resource version of %s", tenant, key)
+			}
+			if current, ok := i.rvs[key]; !ok || rv < current {
+				i.rvs[key] = rv
+			}
+		}
+	}
+
+	return nil
+}
+
+// readShardMetadata checks that the shard was persisted with the current schema and returns its resource versions.
+func (i *Index) readShardMetadata(index bleve.Index) (map[string]int64, error) {
+	schema, err := index.GetInternal([]byte(internalSchemaKey))
+	if err != nil {
+		return nil, err
+	}
+	if string(schema) != i.schema {
+		return nil, errors.New("schema has changed")
+	}
+	if _, err := index.DocCount(); err != nil {
+		return nil, err
+	}
+
+	raw, err := index.GetInternal([]byte(internalRVKey))
+	if err != nil {
+		return nil, err
+	}
+	rvs := map[string]int64{}
+	if err := json.Unmarshal(raw, &rvs); err != nil {
+		return nil, fmt.Errorf("invalid resource versions: %w", err)
+	}
+	return rvs, nil
+}
+
+// rebuild removes the persisted shards and indexes every resource from scratch.
+func (i *Index) rebuild(ctx context.Context, resourceTypes []*ListOptions) error {
+	if err := i.closeShards(); err != nil {
+		i.log.Warn("error closing shards", "error", err)
+	}
+	i.shards = make(map[string]Shard)
+	i.rvs = make(map[string]int64)
+
+	entries, err := os.ReadDir(i.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), shardDirSuffix) {
+			if err := os.RemoveAll(filepath.Join(i.path, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
 	for _, rt := range resourceTypes {
 		i.log.Info("indexing resource", "kind", rt.Key.Resource)
 		r := &ListRequest{Options: rt, Limit: 100}
 
 		// Paginate through the list of resources and index each page
+		var rv int64
 		for {
 			i.log.Debug("fetching resource list", "kind", rt.Key.Resource)
 			list, err := i.s.List(ctx, r)
 			if err != nil {
 				return err
 			}
+			if list.Error != nil {
+				return fmt.Errorf("list %s: %s", rt.Key.Resource, list.Error.Message)
+			}
+			rv = list.ResourceVersion
 
 			// Index current page
-			err = i.IndexBatch(list, rt.Key.Resource)
+			err = i.indexBatch(list, rt.Key.Resource)
 			if err != nil {
 				return err
 			}
@@ -106,30 +286,61 @@ func (i *Index) Init(ctx context.Context) error {
 
 			r.NextPageToken = list.NextPageToken
 		}
+		i.rvs[resourceTypeKey(rt.Key)] = rv
 	}
 
-	end := time.Now().Unix()
-	i.log.Debug("Initial indexing finished", "seconds", float64(end-start))
-	if IndexServerMetrics != nil {
-		IndexServerMetrics.IndexCreationTime.WithLabelValues().Observe(float64(end - start))
-	}
+	return i.persistRVs()
+}
 
+// persistRVs stores the resource versions of the index in every shard.
+func (i *Index) persistRVs() error {
+	raw, err := json.Marshal(i.rvs)
+	if err != nil {
+		return err
+	}
+	for tenant, shard := range i.shards {
+		if err := shard.index.SetInternal([]byte(internalRVKey), raw); err != nil {
+			return fmt.Errorf("store resource versions of tenant %q: %w", tenant, err)
+		}
+	}
 	return nil
 }
 
-func (i *Index) Index(ctx context.Context, data *Data) error {
-	// Transform the raw resource into a more generic indexable resource
-	res, err := NewIndexedResource(data.Value.Value)
+// applyWrittenEvent applies an event replayed by the storage backend.
+func (i *Index) applyWrittenEvent(event *WrittenEvent) error {
+	if event.Type == WatchEvent_DELETED {
+		// The value of deleted resources is a marker that keeps their metadata
+		marker := &DeletedMarker{}
+		if err := json.Unmarshal(event.Value, marker); err != nil {
+			return err
+		}
+		key := &ResourceKey{
+			Group:     event.Key.Group,
+			Resource:  event.Key.Resource,
+			Namespace: marker.Namespace,
+			Name:      marker.Name,
+		}
+		return i.delete(string(marker.UID), key, event.ResourceVersion)
+	}
+
+	res, err := NewIndexedResource(event.Value)
 	if err != nil {
 		return err
 	}
-	tenant := res.Namespace
-	i.log.Debug("indexing resource for tenant", "res", string(data.Value.Value), "tenant", tenant)
-	shard, err := i.getShard(tenant)
+	return i.index(res, event.Key, event.ResourceVersion)
+}
+
+func (i *Index) Index(ctx context.Context, data *Data) error {
+	// Transform the raw resource into a more generic indexable resource
+	res, err := NewIndexedResource(data.Value.Value)
 	if err != nil {
 		return err
 	}
-	err = shard.index.Index(res.Uid, res)
+	i.log.Debug("indexing resource for tenant", "res", string(data.Value.Value), "tenant", res.Namespace)
+
+	i.mu.Lock()
+	err = i.index(res, data.Key, data.Value.ResourceVersion)
+	i.mu.Unlock()
 	if err != nil {
 		return err
 	}
@@ -143,26 +354,102 @@ func (i *Index) Index(ctx context.Context, data *Data) error {
 	return nil
 }
 
-func (i *Index) Delete(ctx context.Context, uid string, key *ResourceKey) error {
-	shard, err := i.getShard(key.Namespace)
+func (i *Index) index(res *IndexedResource, key *ResourceKey, rv int64) error {
+	return i.write(res.Namespace, key, rv, func(batch *bleve.Batch) error {
+		return batch.Index(res.Uid, res)
+	})
+}
+
+// Delete removes a resource from the index. The resource version is the one of the delete event.
+func (i *Index) Delete(ctx context.Context, uid string, key *ResourceKey, rv int64) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.delete(uid, key, rv)
+}
+
+func (i *Index) delete(uid string, key *ResourceKey, rv int64) error {
+	return i.write(key.Namespace, key, rv, func(batch *bleve.Batch) error {
+		batch.Delete(uid)
+		return nil
+	})
+}
+
+// write applies a change to the shard of the tenant in a single batch, together with the resource version up to
+// which the resource type is indexed, so that the change and the resource version are persisted atomically.
+func (i *Index) write(tenant string, key *ResourceKey, rv int64, change func(*bleve.Batch) error) error {
+	shard, err := i.getShard(tenant)
 	if err != nil {
 		return err
 	}
-	err = shard.index.Delete(uid)
+
+	batch := shard.index.NewBatch()
+	if err := change(batch); err != nil {
+		return err
+	}
+	rvs := maps.Clone(i.rvs)
+	typeKey := resourceTypeKey(key)
+	rvs[typeKey] = max(rvs[typeKey], rv)
+	raw, err := json.Marshal(rvs)
 	if err != nil {
 		return err
 	}
+	batch.SetInternal([]byte(internalRVKey), raw)
+	if err := shard.index.Batch(batch); err != nil {
+		return err
+	}
+	i.rvs = rvs
 	return nil
 }
 
-func (i *Index) Search(ctx context.Context, tenant string, query string, limit int, offset int) ([]IndexedResource, error) {
+// resourceVersion returns the resource version up to which the resource type is indexed.
+func (i *Index) resourceVersion(key *ResourceKey) int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.rvs[resourceTypeKey(key)]
+}
+
+// Close closes the shards of the index. They are resumed the next time an index is initialized with the same path.
+func (i *Index) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	err := i.closeShards()
+	i.shards = make(map[string]Shard)
+	i.closed = true
+	return err
+}
+
+func (i *Index) closeShards() error {
+	var errs []error
+	for tenant, shard := range i.shards {
+		if err := shard.index.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close shard of tenant %q: %w", tenant, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// IndexResults holds the resources matching a search, along with the total hit count and the facets.
+type IndexResults struct {
+	Values    []IndexedResource
+	TotalHits int64
+	Facets    map[string]*SearchFacetResult
+}
+
+func (i *Index) Search(ctx context.Context, request *SearchRequest) (*IndexResults, error) {
+	tenant := request.Tenant
 	if tenant == "" {
 		tenant = "default"
 	}
-	shard, err := i.getShard(tenant)
+	// the index is read locked for the whole search, so that the shard is not closed while it is searched
+	shard, err := i.readShard(tenant)
 	if err != nil {
 		return nil, err
 	}
+	defer i.mu.RUnlock()
+
 	docCount, err := shard.index.DocCount()
 	if err != nil {
 		return nil, err
@@ -173,17 +460,53 @@ func (i *Index) Search(ctx context.Context, tenant string, query string, limit i
 	i.log.Debug("indexed fields", "fields", fields)
 
 	// use 10 as a default limit for now
+	limit := int(request.Limit)
 	if limit <= 0 {
 		limit = 10
 	}
 
-	req := bleve.NewSearchRequest(bleve.NewQueryStringQuery(query))
-	req.From = offset
+	req := bleve.NewSearchRequest(searchQuery(request))
+	req.From = int(request.Offset)
 	req.Size = limit
 
 	req.Fields = []string{"*"} // return all indexed fields in search results
 
-	i.log.Info("searching index", "query", query, "tenant", tenant)
+	if len(request.Sort) > 0 {
+		order := make([]string, 0, len(request.Sort))
+		for _, sortBy := range request.Sort {
+			if sortBy.Field == "" || strings.HasPrefix(sortBy.Field, "-") {
+				return nil, fmt.Errorf("invalid sort field %q", sortBy.Field)
+			}
+			if sortBy.Desc {
+				order = append(order, "-"+sortBy.Field)
+			} else {
+				order = append(order, sortBy.Field)
+			}
+		}
+		req.SortBy(order)
+	}
+
+	for _, facet := range request.Facets {
+		if facet.Field == "" {
+			return nil, errors.New("missing facet field")
+		}
+		name := facet.Name
+		if name == "" {
+			name = facet.Field
+		}
+		// use 10 terms as a default limit
+		size := int(facet.Limit)
+		if size <= 0 {
+			size = 10
+		}
+		req.AddFacet(name, bleve.NewFacetRequest(facet.Field, size))
+	}
+
+	if request.Highlight {
+		req.Highlight = bleve.NewHighlight()
+	}
+
+	i.log.Info("searching index", "query", request.Query, "tenant", tenant)
 	res, err := shard.index.Search(req)
 	if err != nil {
 		return nil, err
@@ -192,38 +515,136 @@ func (i *Index) Search(ctx context.Context, tenant string, query string, limit i
 
 	i.log.Info("got search results", "hits", hits)
 
-	results := make([]IndexedResource, len(hits))
+	results := &IndexResults{
+		Values:    make([]IndexedResource, len(hits)),
+		TotalHits: int64(res.Total),
+		Facets:    make(map[string]*SearchFacetResult, len(res.Facets)),
+	}
 	for resKey, hit := range hits {
 		ir := IndexedResource{}.FromSearchHit(hit)
-		results[resKey] = ir
+		results.Values[resKey] = ir
+	}
+	for name, facet := range res.Facets {
+		result := &SearchFacetResult{
+			Field:   facet.Field,
+			Total:   int64(facet.Total),
+			Missing: int64(facet.Missing),
+			Other:   int64(facet.Other),
+		}
+		if facet.Terms != nil {
+			for _, term := range facet.Terms.Terms() {
+				result.Terms = append(result.Terms, &SearchFacetTerm{Term: term.Term, Count: int64(term.Count)})
+			}
+		}
+		results.Facets[name] = result
 	}
 
 	return results, nil
 }
 
+// searchQuery combines the query string of the request with its filters.
+// An empty query string matches every resource, so that filters can be used on their own.
+func searchQuery(request *SearchRequest) query.Query {
+	var q query.Query = bleve.NewMatchAllQuery()
+	if request.Query != "" {
+		q = bleve.NewQueryStringQuery(request.Query)
+	}
+
+	filters := request.Filters
+	if filters == nil {
+		return q
+	}
+	conjuncts := []query.Query{q}
+	if len(filters.Folders) > 0 {
+		conjuncts = append(conjuncts, anyTermQuery("FolderId", filters.Folders))
+	}
+	for _, tag := range filters.Tags {
+		conjuncts = append(conjuncts, termQuery("Tags", tag))
+	}
+	for key, value := range filters.Labels {
+		conjuncts = append(conjuncts, termQuery("Labels."+key, value))
+	}
+	if len(filters.CreatedBy) > 0 {
+		conjuncts = append(conjuncts, anyTermQuery("CreatedBy", filters.CreatedBy))
+	}
+	if len(conjuncts) == 1 {
+		return q
+	}
+	return bleve.NewConjunctionQuery(conjuncts...)
+}
+
+func termQuery(field string, term string) query.Query {
+	q := bleve.NewTermQuery(term)
+	q.SetField(field)
+	return q
+}
+
+func anyTermQuery(field string, terms []string) query.Query {
+	disjuncts := make([]query.Query, 0, len(terms))
+	for _, term := range terms {
+		disjuncts = append(disjuncts, termQuery(field, term))
+	}
+	return bleve.NewDisjunctionQuery(disjuncts...)
+}
+
 type Opts struct {
 	Workers    int // This controls how many goroutines are used to index objects
 	BatchSize  int // This is the batch size for how many objects to add to the index at once
 	Concurrent bool
 }
 
-func createFileIndex(path string) (bleve.Index, string, error) {
-	indexPath := filepath.Join(path, uuid.New().String())
-	index, err := bleve.New(indexPath, createIndexMappings())
+// indexSchema identifies how resources are indexed. The persisted shards are rebuilt when it changes.
+func indexSchema() (string, error) {
+	resourceTypes := []string{}
+	for _, rt := range fetchResourceTypes() {
+		resourceTypes = append(resourceTypes, resourceTypeKey(rt.Key))
+	}
+	raw, err := json.Marshal(struct {
+		Version       int
+		Mapping       *mapping.IndexMappingImpl
+		ResourceTypes []string
+	}{
+		Version:       indexFormatVersion,
+		Mapping:       createIndexMappings(),
+		ResourceTypes: resourceTypes,
+	})
 	if err != nil {
-		golog.Fatalf("Failed to create index: %v", err)
+		return "", err
 	}
-	return index, indexPath, err
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func resourceTypeKey(key *ResourceKey) string {
+	return key.Group + "/" + key.Resource
 }
 
+// getShard returns the shard of the tenant, creating it if needed. It must be called with the index locked.
 func (i *Index) getShard(tenant string) (Shard, error) {
+	if i.closed {
+		return Shard{}, errIndexClosed
+	}
 	shard, ok := i.shards[tenant]
 	if ok {
 		return shard, nil
 	}
-	index, path, err := createFileIndex(i.path)
+
+	path := filepath.Join(i.path, url.PathEscape(tenant)+shardDirSuffix)
+	index, err := bleve.New(path, createIndexMappings())
 	if err != nil {
-		return Shard{}, err
+		return Shard{}, fmt.Errorf("create shard of tenant %q: %w", tenant, err)
+	}
+	// The shard holds no resource of the tenant yet, so it is as up to date as the rest of the index
+	raw, err := json.Marshal(i.rvs)
+	if err == nil {
+		err = index.SetInternal([]byte(internalSchemaKey), []byte(i.schema))
+	}
+	if err == nil {
+		err = index.SetInternal([]byte(internalRVKey), raw)
+	}
+	if err != nil {
+		_ = index.Close()
+		return Shard{}, fmt.Errorf("store metadata of tenant %q: %w", tenant, err)
 	}
 
 	shard = Shard{
@@ -231,11 +652,33 @@ func (i *Index) getShard(tenant string) (Shard, error) {
 		path:  path,
 		batch: index.NewBatch(),
 	}
-	// TODO: do we need to lock this?
 	i.shards[tenant] = shard
 	return shard, nil
 }
 
+// readShard returns the shard of the tenant with the index read locked, creating the shard if needed.
+// Unless an error is returned, the caller must release the read lock.
+func (i *Index) readShard(tenant string) (Shard, error) {
+	for {
+		i.mu.RLock()
+		if i.closed {
+			i.mu.RUnlock()
+			return Shard{}, errIndexClosed
+		}
+		if shard, ok := i.shards[tenant]; ok {
+			return shard, nil
+		}
+		i.mu.RUnlock()
+
+		i.mu.Lock()
+		_, err := i.getShard(tenant)
+		i.mu.Unlock()
+		if err != nil {
+			return Shard{}, err
+		}
+	}
+}
+
 // TODO - fetch from api
 func fetchResourceTypes() []*ListOptions {
 	items := []*ListOptions{}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
 	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/blevesearch/bleve/v2/search"
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
@@ -22,7 +23,12 @@ type IndexedResource struct {
 	UpdatedAt string
 	UpdatedBy string
 	FolderId  string
+	Tags      []string
+	Labels    map[string]string
 	Spec      any
+
+	// Fragments of the fields that match the query, by field name. Only set in search results.
+	Highlights map[string][]string `json:",omitempty"`
 }
 
 func (ir IndexedResource) FromSearchHit(hit *search.DocumentMatch) IndexedResource {
@@ -36,20 +42,49 @@ func (ir IndexedResource) FromSearchHit(hit *search.DocumentMatch) IndexedResour
 	ir.UpdatedAt = hit.Fields["UpdatedAt"].(string)
 	ir.UpdatedBy = hit.Fields["UpdatedBy"].(string)
 	ir.Title = hit.Fields["Title"].(string)
+	// these fields are not indexed when empty
+	ir.FolderId, _ = hit.Fields["FolderId"].(string)
+	ir.Tags = stringValues(hit.Fields["Tags"])
+	if len(hit.Fragments) > 0 {
+		ir.Highlights = hit.Fragments
+	}
 
-	// add indexed spec fields to search results
+	// add indexed spec fields and labels to search results
 	specResult := map[string]interface{}{}
 	for k, v := range hit.Fields {
 		if strings.HasPrefix(k, "Spec.") {
 			specKey := strings.TrimPrefix(k, "Spec.")
 			specResult[specKey] = v
 		}
+		if label, ok := strings.CutPrefix(k, "Labels."); ok {
+			if ir.Labels == nil {
+				ir.Labels = map[string]string{}
+			}
+			ir.Labels[label], _ = v.(string)
+		}
 		ir.Spec = specResult
 	}
 
 	return ir
 }
 
+// stringValues returns the values of a stored field, which is a single value when the field has only one.
+func stringValues(field any) []string {
+	switch v := field.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
 // NewIndexedResource creates a new IndexedResource from a raw resource.
 // rawResource is the raw json for the resource from unified storage.
 func NewIndexedResource(rawResource []byte) (*IndexedResource, error) {
@@ -84,15 +119,31 @@ func NewIndexedResource(rawResource []byte) (*IndexedResource, error) {
 		ir.UpdatedAt = ir.CreatedAt
 	}
 	ir.UpdatedBy = meta.GetUpdatedBy()
+	ir.FolderId = meta.GetFolder()
+	ir.Labels = meta.GetLabels()
 	spec, err := meta.GetSpec()
 	if err != nil {
 		return nil, err
 	}
 	ir.Spec = spec
+	ir.Tags = specTags(spec)
 
 	return ir, nil
 }
 
+// specTags returns the tags of resources whose spec has a list of tags, like dashboards.
+func specTags(spec any) []string {
+	m, ok := spec.(map[string]any)
+	if !ok {
+		return nil
+	}
+	tags, ok := m["tags"].([]any)
+	if !ok {
+		return nil
+	}
+	return stringValues(tags)
+}
+
 func createIndexMappings() *mapping.IndexMappingImpl {
 	// Create the index mapping
 	indexMapping := bleve.NewIndexMapping()
@@ -118,10 +169,12 @@ func createIndexMappingForKind(resourceKind string) *mapping.DocumentMapping {
 		"Name":      bleve.NewTextFieldMapping(),
 		"Title":     bleve.NewTextFieldMapping(),
 		"CreatedAt": bleve.NewDateTimeFieldMapping(),
-		"CreatedBy": bleve.NewTextFieldMapping(),
+		// keyword fields are indexed as a single term, so they can be filtered and faceted by exact value
+		"CreatedBy": bleve.NewKeywordFieldMapping(),
 		"UpdatedAt": bleve.NewDateTimeFieldMapping(),
 		"UpdatedBy": bleve.NewTextFieldMapping(),
-		"FolderId":  bleve.NewTextFieldMapping(),
+		"FolderId":  bleve.NewKeywordFieldMapping(),
+		"Tags":      bleve.NewKeywordFieldMapping(),
 	}
 
 	// Spec is different for all resources, so we need to generate the spec mapping based on the kind
@@ -133,6 +186,11 @@ func createIndexMappingForKind(resourceKind string) *mapping.DocumentMapping {
 	// map spec
 	objectMapping.AddSubDocumentMapping("Spec", specMapping)
 
+	// map every label as a keyword field, labels keys are not known in advance
+	labelsMapping := bleve.NewDocumentMapping()
+	labelsMapping.DefaultAnalyzer = keyword.Name
+	objectMapping.AddSubDocumentMapping("Labels", labelsMapping)
+
 	// map top level fields
 	for k, v := range baseFields {
 		objectMapping.AddFieldMappingsAt(k, v)
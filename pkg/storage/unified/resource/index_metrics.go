@@ -93,6 +93,8 @@ func getTotalDocCount(index *Index) float64 {
 	if index == nil {
 		return totalCount
 	}
+	index.mu.Lock()
+	defer index.mu.Unlock()
 	for _, shard := range index.shards {
 		docCount, err := shard.index.DocCount()
 		if err != nil {
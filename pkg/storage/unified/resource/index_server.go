@@ -15,18 +15,20 @@ type IndexServer struct {
 	ResourceServer
 	s     *server
 	index *Index
-	ws    *indexWatchServer
 	log   *slog.Logger
 	cfg   *setting.Cfg
 }
 
 func (is *IndexServer) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
-	results, err := is.index.Search(ctx, req.Tenant, req.Query, int(req.Limit), int(req.Offset))
+	results, err := is.index.Search(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	res := &SearchResponse{}
-	for _, r := range results {
+	res := &SearchResponse{
+		TotalHits: results.TotalHits,
+		Facets:    results.Facets,
+	}
+	for _, r := range results.Values {
 		resJsonBytes, err := json.Marshal(r)
 		if err != nil {
 			return nil, err
@@ -58,17 +60,28 @@ func (is *IndexServer) Load(ctx context.Context) error {
 func (is *IndexServer) Watch(ctx context.Context) error {
 	rtList := fetchResourceTypes()
 	for _, rt := range rtList {
-		wr := &WatchRequest{
-			Options: rt,
+		ws := &indexWatchServer{
+			is:      is,
+			key:     rt.Key,
+			context: ctx,
 		}
 
 		go func() {
 			for {
+				// Resume from the last indexed resource version, so that no event is missed between watches
+				wr := &WatchRequest{
+					Options: rt,
+					Since:   is.index.resourceVersion(rt.Key),
+				}
 				// blocking call
-				err := is.s.Watch(wr, is.ws)
+				err := is.s.Watch(wr, ws)
 				if err != nil {
 					is.log.Error("Error watching resource", "error", err)
 				}
+				if is.s.ctx.Err() != nil {
+					is.log.Debug("Resource server stopped. Ending watch")
+					return
+				}
 				is.log.Debug("Resource watch ended. Restarting watch")
 			}
 		}()
@@ -81,10 +94,6 @@ func (is *IndexServer) Watch(ctx context.Context) error {
 // TODO: a chicken and egg problem - index server needs the resource server but the resource server is created with the index server
 func (is *IndexServer) Init(ctx context.Context, rs *server) error {
 	is.s = rs
-	is.ws = &indexWatchServer{
-		is:      is,
-		context: ctx,
-	}
 	return nil
 }
 
@@ -112,6 +121,8 @@ type indexWatchServer struct {
 	grpc.ServerStream
 	context context.Context
 	is      *IndexServer
+	// The group and resource being watched
+	key *ResourceKey
 }
 
 func (f *indexWatchServer) Send(we *WatchEvent) error {
@@ -150,7 +161,7 @@ func (f *indexWatchServer) Index() *Index {
 }
 
 func (f *indexWatchServer) Add(we *WatchEvent) error {
-	data, err := getData(we.Resource)
+	data, err := getData(f.key, we.Resource)
 	if err != nil {
 		return err
 	}
@@ -166,11 +177,11 @@ func (f *indexWatchServer) Delete(we *WatchEvent) error {
 	if err != nil {
 		return err
 	}
-	data, err := getData(rs)
+	data, err := getData(f.key, rs)
 	if err != nil {
 		return err
 	}
-	err = f.Index().Delete(f.context, data.Uid, data.Key)
+	err = f.Index().Delete(f.context, data.Uid, data.Key, we.Resource.Version)
 	if err != nil {
 		return err
 	}
@@ -182,11 +193,11 @@ func (f *indexWatchServer) Update(we *WatchEvent) error {
 	if err != nil {
 		return err
 	}
-	data, err := getData(rs)
+	data, err := getData(f.key, rs)
 	if err != nil {
 		return err
 	}
-	err = f.Index().Delete(f.context, data.Uid, data.Key)
+	err = f.Index().Delete(f.context, data.Uid, data.Key, we.Resource.Version)
 	if err != nil {
 		return err
 	}
@@ -203,15 +214,15 @@ type Data struct {
 	Uid   string
 }
 
-func getData(wr *WatchEvent_Resource) (*Data, error) {
+func getData(watched *ResourceKey, wr *WatchEvent_Resource) (*Data, error) {
 	r, err := NewIndexedResource(wr.Value)
 	if err != nil {
 		return nil, err
 	}
 
 	key := &ResourceKey{
-		Group:     r.Group,
-		Resource:  r.Kind,
+		Group:     watched.Group,
+		Resource:  watched.Resource,
 		Namespace: r.Namespace,
 		Name:      r.Name,
 	}
@@ -0,0 +1,330 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/grafana/authlib/claims"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+)
+
+// replayableBackend records the events written to the backend so that they can be replayed.
+type replayableBackend struct {
+	StorageBackend
+
+	mu      sync.Mutex
+	events  []*WrittenEvent
+	listErr error
+}
+
+func (b *replayableBackend) WriteEvent(ctx context.Context, event WriteEvent) (int64, error) {
+	rv, err := b.StorageBackend.WriteEvent(ctx, event)
+	if err == nil {
+		b.mu.Lock()
+		b.events = append(b.events, &WrittenEvent{WriteEvent: event, ResourceVersion: rv})
+		b.mu.Unlock()
+	}
+	return rv, err
+}
+
+func (b *replayableBackend) ListIterator(ctx context.Context, req *ListRequest, cb func(ListIterator) error) (int64, error) {
+	if b.listErr != nil {
+		return 0, b.listErr
+	}
+	return b.StorageBackend.ListIterator(ctx, req, cb)
+}
+
+func (b *replayableBackend) ReplayWriteEvents(_ context.Context, key *ResourceKey, since int64, fn func(*WrittenEvent) error) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	last := since
+	for _, event := range b.events {
+		if event.ResourceVersion <= since || event.Key.Group != key.Group || event.Key.Resource != key.Resource {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return last, err
+		}
+		last = event.ResourceVersion
+	}
+	return last, nil
+}
+
+func TestIndexResume(t *testing.T) {
+	ctx := claims.WithClaims(context.Background(), &identity.StaticRequester{
+		Type:           claims.TypeUser,
+		Login:          "testuser",
+		UserID:         123,
+		UserUID:        "u123",
+		OrgRole:        identity.RoleAdmin,
+		IsGrafanaAdmin: true,
+	})
+
+	store, err := NewCDKBackend(ctx, CDKBackendOptions{
+		Bucket: memblob.OpenBucket(nil),
+	})
+	require.NoError(t, err)
+	backend := &replayableBackend{StorageBackend: store}
+	rs, err := NewResourceServer(ResourceServerOptions{
+		Backend: backend,
+	})
+	require.NoError(t, err)
+	srv := rs.(*server)
+
+	createPlaylist := func(t *testing.T, name string) int64 {
+		t.Helper()
+		created, err := srv.Create(ctx, &CreateRequest{
+			Key: &ResourceKey{
+				Group:     "playlist.grafana.app",
+				Resource:  "playlists",
+				Namespace: "default",
+				Name:      name,
+			},
+			Value: []byte(fmt.Sprintf(`{
+				"apiVersion": "playlist.grafana.app/v0alpha1",
+				"kind": "Playlist",
+				"metadata": {
+					"name": %[1]q,
+					"namespace": "default",
+					"uid": "uid-%[1]s"
+				},
+				"spec": {
+					"title": %[1]q,
+					"interval": "5m",
+					"items": []
+				}
+			}`, name)),
+		})
+		require.NoError(t, err)
+		require.Nil(t, created.Error)
+		return created.ResourceVersion
+	}
+	requireIndexed := func(t *testing.T, index *Index, uid string, indexed bool) {
+		t.Helper()
+		shard, err := index.getShard("default")
+		require.NoError(t, err)
+		doc, err := shard.index.Document(uid)
+		require.NoError(t, err)
+		require.Equal(t, indexed, doc != nil, uid)
+	}
+	playlists := &ResourceKey{Group: "playlist.grafana.app", Resource: "playlists"}
+
+	path := t.TempDir()
+	firstRV := createPlaylist(t, "first")
+
+	t.Run("should build the index when nothing is persisted", func(t *testing.T) {
+		index := NewIndex(srv, Opts{}, path)
+		require.NoError(t, index.Init(ctx))
+		requireIndexed(t, index, "uid-first", true)
+		require.GreaterOrEqual(t, index.resourceVersion(playlists), firstRV)
+		require.NoError(t, index.Close())
+		require.DirExists(t, filepath.Join(path, "default"+shardDirSuffix))
+	})
+
+	secondRV := createPlaylist(t, "second")
+	deleted, err := srv.Delete(ctx, &DeleteRequest{
+		Key: &ResourceKey{
+			Group:     "playlist.grafana.app",
+			Resource:  "playlists",
+			Namespace: "default",
+			Name:      "first",
+		},
+		ResourceVersion: firstRV,
+	})
+	require.NoError(t, err)
+	require.Nil(t, deleted.Error)
+	require.Greater(t, deleted.ResourceVersion, secondRV)
+
+	t.Run("should resume the persisted index from the events written since it was closed", func(t *testing.T) {
+		backend.listErr = errors.New("the index should not list resources when resuming")
+		defer func() { backend.listErr = nil }()
+
+		index := NewIndex(srv, Opts{}, path)
+		require.NoError(t, index.Init(ctx))
+		requireIndexed(t, index, "uid-first", false)
+		requireIndexed(t, index, "uid-second", true)
+		require.Equal(t, deleted.ResourceVersion, index.resourceVersion(playlists))
+		require.NoError(t, index.Close())
+	})
+
+	t.Run("should rebuild the index when the schema changed", func(t *testing.T) {
+		shard, err := bleve.Open(filepath.Join(path, "default"+shardDirSuffix))
+		require.NoError(t, err)
+		require.NoError(t, shard.SetInternal([]byte(internalSchemaKey), []byte("previous")))
+		require.NoError(t, shard.Delete("uid-second"))
+		require.NoError(t, shard.Close())
+
+		index := NewIndex(srv, Opts{}, path)
+		require.NoError(t, index.Init(ctx))
+		requireIndexed(t, index, "uid-second", true)
+		schema, err := indexSchema()
+		require.NoError(t, err)
+		persisted, err := index.shards["default"].index.GetInternal([]byte(internalSchemaKey))
+		require.NoError(t, err)
+		require.Equal(t, schema, string(persisted))
+		require.NoError(t, index.Close())
+	})
+}
+
+func TestIndexSearch(t *testing.T) {
+	ctx := context.Background()
+	index := NewIndex(nil, Opts{}, t.TempDir())
+	t.Cleanup(func() { _ = index.Close() })
+
+	playlists := []struct {
+		name      string
+		title     string
+		folder    string
+		createdBy string
+		labels    string
+		tags      string
+	}{
+		{name: "pla", title: "alpha", folder: "f1", createdBy: "user:1", labels: `{"env": "prod"}`, tags: `["ops", "db"]`},
+		{name: "plb", title: "beta", folder: "f1", createdBy: "user:2", labels: `{"env": "dev"}`, tags: `["ops"]`},
+		{name: "plc", title: "gamma alpha", folder: "f2", createdBy: "user:1", labels: `{}`, tags: `[]`},
+	}
+	for rv, p := range playlists {
+		err := index.Index(ctx, &Data{
+			Key: &ResourceKey{Group: "playlist.grafana.app", Resource: "playlists", Namespace: "default", Name: p.name},
+			Value: &ResourceWrapper{
+				ResourceVersion: int64(rv + 1),
+				Value: []byte(fmt.Sprintf(`{
+					"apiVersion": "playlist.grafana.app/v0alpha1",
+					"kind": "Playlist",
+					"metadata": {
+						"name": %[1]q,
+						"namespace": "default",
+						"uid": "uid-%[1]s",
+						"creationTimestamp": "2024-01-01T00:00:00Z",
+						"labels": %[5]s,
+						"annotations": {
+							"grafana.app/folder": %[3]q,
+							"grafana.app/createdBy": %[4]q
+						}
+					},
+					"spec": {
+						"title": %[2]q,
+						"interval": "5m",
+						"tags": %[6]s
+					}
+				}`, p.name, p.title, p.folder, p.createdBy, p.labels, p.tags)),
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	names := func(results *IndexResults) []string {
+		names := make([]string, 0, len(results.Values))
+		for _, v := range results.Values {
+			names = append(names, v.Name)
+		}
+		return names
+	}
+
+	t.Run("should filter the resources", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			query    string
+			filters  *SearchFilters
+			expected []string
+		}{
+			{name: "no query or filters", expected: []string{"pla", "plb", "plc"}},
+			{name: "folders", filters: &SearchFilters{Folders: []string{"f1"}}, expected: []string{"pla", "plb"}},
+			{name: "tags", filters: &SearchFilters{Tags: []string{"ops", "db"}}, expected: []string{"pla"}},
+			{name: "labels", filters: &SearchFilters{Labels: map[string]string{"env": "prod"}}, expected: []string{"pla"}},
+			{name: "created by", filters: &SearchFilters{CreatedBy: []string{"user:1"}}, expected: []string{"pla", "plc"}},
+			{name: "query and filters", query: "Title:alpha", filters: &SearchFilters{Folders: []string{"f2"}}, expected: []string{"plc"}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				results, err := index.Search(ctx, &SearchRequest{Tenant: "default", Query: tc.query, Filters: tc.filters})
+				require.NoError(t, err)
+				require.ElementsMatch(t, tc.expected, names(results))
+				require.Equal(t, int64(len(tc.expected)), results.TotalHits)
+			})
+		}
+	})
+
+	t.Run("should sort the resources and count all the hits", func(t *testing.T) {
+		results, err := index.Search(ctx, &SearchRequest{
+			Tenant: "default",
+			Limit:  2,
+			Sort:   []*SearchSort{{Field: "Name", Desc: true}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"plc", "plb"}, names(results))
+		require.Equal(t, int64(3), results.TotalHits)
+
+		_, err = index.Search(ctx, &SearchRequest{Tenant: "default", Sort: []*SearchSort{{Field: "-Name"}}})
+		require.Error(t, err)
+	})
+
+	t.Run("should count the facets of the matching resources", func(t *testing.T) {
+		results, err := index.Search(ctx, &SearchRequest{
+			Tenant: "default",
+			Facets: []*SearchFacetRequest{{Name: "tags", Field: "Tags"}, {Field: "FolderId", Limit: 1}},
+		})
+		require.NoError(t, err)
+
+		tags := results.Facets["tags"]
+		require.NotNil(t, tags)
+		require.Equal(t, int64(1), tags.Missing)
+		require.Equal(t, []*SearchFacetTerm{{Term: "ops", Count: 2}, {Term: "db", Count: 1}}, tags.Terms)
+
+		folders := results.Facets["FolderId"]
+		require.NotNil(t, folders)
+		require.Equal(t, []*SearchFacetTerm{{Term: "f1", Count: 2}}, folders.Terms)
+		require.Equal(t, int64(1), folders.Other)
+	})
+
+	t.Run("should return the resources with their labels, tags and highlighted matches", func(t *testing.T) {
+		results, err := index.Search(ctx, &SearchRequest{Tenant: "default", Query: "Title:beta", Highlight: true})
+		require.NoError(t, err)
+		require.Len(t, results.Values, 1)
+
+		res := results.Values[0]
+		require.Equal(t, "f1", res.FolderId)
+		require.Equal(t, map[string]string{"env": "dev"}, res.Labels)
+		require.Equal(t, []string{"ops"}, res.Tags)
+		require.Contains(t, res.Highlights, "Title")
+	})
+}
+
+func TestIndexSearchWhileClosing(t *testing.T) {
+	ctx := context.Background()
+	index := NewIndex(nil, Opts{}, t.TempDir())
+
+	t.Run("should create the shard of a tenant that has no resources", func(t *testing.T) {
+		results, err := index.Search(ctx, &SearchRequest{Tenant: "empty"})
+		require.NoError(t, err)
+		require.Empty(t, results.Values)
+		require.Contains(t, index.shards, "empty")
+	})
+
+	t.Run("should not close the shards while they are searched", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for n := 0; n < 10; n++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := index.Search(ctx, &SearchRequest{Tenant: fmt.Sprintf("tenant-%d", n%3)})
+				if err != nil && !errors.Is(err, errIndexClosed) {
+					t.Errorf("unexpected search error: %v", err)
+				}
+			}()
+		}
+		require.NoError(t, index.Close())
+		wg.Wait()
+
+		_, err := index.Search(ctx, &SearchRequest{Tenant: "empty"})
+		require.ErrorIs(t, err, errIndexClosed)
+	})
+}
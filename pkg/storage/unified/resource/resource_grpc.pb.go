@@ -751,3 +751,100 @@ var Diagnostics_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "resource.proto",
 }
+
+const (
+	WatchNotifier_NotifyWrite_FullMethodName = "/resource.WatchNotifier/NotifyWrite"
+)
+
+// WatchNotifierClient is the client API for WatchNotifier service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Storage servers sharing a database notify each other of the resources they write,
+// so that watchers see the changes without waiting for the next poll of the database
+type WatchNotifierClient interface {
+	// Notify that resources of a group and resource were written
+	NotifyWrite(ctx context.Context, in *NotifyWriteRequest, opts ...grpc.CallOption) (*NotifyWriteResponse, error)
+}
+
+type watchNotifierClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWatchNotifierClient(cc grpc.ClientConnInterface) WatchNotifierClient {
+	return &watchNotifierClient{cc}
+}
+
+func (c *watchNotifierClient) NotifyWrite(ctx context.Context, in *NotifyWriteRequest, opts ...grpc.CallOption) (*NotifyWriteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotifyWriteResponse)
+	err := c.cc.Invoke(ctx, WatchNotifier_NotifyWrite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WatchNotifierServer is the server API for WatchNotifier service.
+// All implementations should embed UnimplementedWatchNotifierServer
+// for forward compatibility
+//
+// Storage servers sharing a database notify each other of the resources they write,
+// so that watchers see the changes without waiting for the next poll of the database
+type WatchNotifierServer interface {
+	// Notify that resources of a group and resource were written
+	NotifyWrite(context.Context, *NotifyWriteRequest) (*NotifyWriteResponse, error)
+}
+
+// UnimplementedWatchNotifierServer should be embedded to have forward compatible implementations.
+type UnimplementedWatchNotifierServer struct {
+}
+
+func (UnimplementedWatchNotifierServer) NotifyWrite(context.Context, *NotifyWriteRequest) (*NotifyWriteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NotifyWrite not implemented")
+}
+
+// UnsafeWatchNotifierServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WatchNotifierServer will
+// result in compilation errors.
+type UnsafeWatchNotifierServer interface {
+	mustEmbedUnimplementedWatchNotifierServer()
+}
+
+func RegisterWatchNotifierServer(s grpc.ServiceRegistrar, srv WatchNotifierServer) {
+	s.RegisterService(&WatchNotifier_ServiceDesc, srv)
+}
+
+func _WatchNotifier_NotifyWrite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotifyWriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchNotifierServer).NotifyWrite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WatchNotifier_NotifyWrite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchNotifierServer).NotifyWrite(ctx, req.(*NotifyWriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WatchNotifier_ServiceDesc is the grpc.ServiceDesc for WatchNotifier service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WatchNotifier_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "resource.WatchNotifier",
+	HandlerType: (*WatchNotifierServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NotifyWrite",
+			Handler:    _WatchNotifier_NotifyWrite_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "resource.proto",
+}
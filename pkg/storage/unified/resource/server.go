@@ -77,6 +77,16 @@ type StorageBackend interface {
 	WatchWriteEvents(ctx context.Context) (<-chan *WrittenEvent, error)
 }
 
+// ReplayableBackend is implemented by the storage backends that can replay the events written in the past.
+// The search index uses it to catch up with the changes made while it was not running.
+type ReplayableBackend interface {
+	StorageBackend
+
+	// Call fn, in resource version order, with the events of the key group and resource that were written
+	// after the since resource version. Return the resource version of the last event, or since if there was none.
+	ReplayWriteEvents(ctx context.Context, key *ResourceKey, since int64, fn func(*WrittenEvent) error) (int64, error)
+}
+
 // This interface is not exposed to end users directly
 // Access to this interface is already gated by access control
 type BlobSupport interface {
@@ -259,6 +269,13 @@ func (s *server) Stop(ctx context.Context) error {
 	// Stops the streaming
 	s.cancel()
 
+	// Close the search index so that it can be resumed on restart
+	if is, ok := s.index.(*IndexServer); ok && is.index != nil {
+		if err := is.index.Close(); err != nil {
+			s.log.Warn("error closing the search index", "error", err)
+		}
+	}
+
 	// mark the value as done
 	if stopFailed {
 		return s.initErr
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
@@ -27,16 +29,25 @@ import (
 const tracePrefix = "sql.resource."
 const defaultPollingInterval = 100 * time.Millisecond
 
+// defaultNotifiedPollingInterval is the polling interval when the writes of the
+// other backends sharing the database are notified, so that polling only
+// catches the notifications that were lost.
+const defaultNotifiedPollingInterval = 5 * time.Second
+
 type Backend interface {
-	resource.StorageBackend
+	resource.ReplayableBackend
 	resource.DiagnosticsServer
 	resource.LifecycleHooks
+	resource.WatchNotifierServer
 }
 
 type BackendOptions struct {
 	DBProvider      db.DBProvider
 	Tracer          trace.Tracer
 	PollingInterval time.Duration
+	// WatchPeers are the gRPC addresses of the other storage servers sharing
+	// the database, which are notified of the writes when the database can't.
+	WatchPeers []string
 }
 
 func NewBackend(opts BackendOptions) (Backend, error) {
@@ -48,17 +59,15 @@ func NewBackend(opts BackendOptions) (Backend, error) {
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 
-	pollingInterval := opts.PollingInterval
-	if pollingInterval == 0 {
-		pollingInterval = defaultPollingInterval
-	}
 	return &backend{
 		done:            ctx.Done(),
 		cancel:          cancel,
 		log:             log.New("sql-resource-server"),
 		tracer:          opts.Tracer,
 		dbProvider:      opts.DBProvider,
-		pollingInterval: pollingInterval,
+		pollingInterval: opts.PollingInterval,
+		watchPeers:      opts.WatchPeers,
+		watchers:        newWriteBroadcaster(),
 	}, nil
 }
 
@@ -81,6 +90,9 @@ type backend struct {
 	// watch streaming
 	//stream chan *resource.WatchEvent
 	pollingInterval time.Duration
+	watchPeers      []string
+	watchers        *writeBroadcaster
+	notifiers       []writeNotifier
 }
 
 func (b *backend) Init(ctx context.Context) error {
@@ -103,7 +115,38 @@ func (b *backend) initLocked(ctx context.Context) error {
 		return fmt.Errorf("no dialect for driver %q", driverName)
 	}
 
-	return b.db.PingContext(ctx)
+	if err := b.db.PingContext(ctx); err != nil {
+		return err
+	}
+
+	return b.initNotifiers(driverName)
+}
+
+// initNotifiers sets up the notification of the writes to the other backends
+// sharing the database, with PostgreSQL LISTEN/NOTIFY if possible and with the
+// configured watch peers otherwise.
+func (b *backend) initNotifiers(driverName string) error {
+	if dsnProvider, ok := b.dbProvider.(db.DSNProvider); ok && driverName == db.DriverPostgres {
+		n := &pgNotifier{db: b.db, dsn: dsnProvider.DSN(), log: b.log}
+		if err := n.listen(b.done, b.watchers); err != nil {
+			return fmt.Errorf("listen to resource writes: %w", err)
+		}
+		b.notifiers = append(b.notifiers, n)
+	} else if len(b.watchPeers) > 0 {
+		n, err := newPeerNotifier(b.watchPeers)
+		if err != nil {
+			return err
+		}
+		b.notifiers = append(b.notifiers, n)
+	}
+
+	if b.pollingInterval == 0 {
+		b.pollingInterval = defaultPollingInterval
+		if len(b.notifiers) > 0 {
+			b.pollingInterval = defaultNotifiedPollingInterval
+		}
+	}
+	return nil
 }
 
 func (b *backend) IsHealthy(ctx context.Context, r *resource.HealthCheckRequest) (*resource.HealthCheckResponse, error) {
@@ -118,7 +161,43 @@ func (b *backend) IsHealthy(ctx context.Context, r *resource.HealthCheckRequest)
 
 func (b *backend) Stop(_ context.Context) error {
 	b.cancel()
-	return nil
+	var errs []error
+	for _, n := range b.notifiers {
+		if closer, ok := n.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyWrite is called by the other storage servers sharing the database when
+// they write resources, to notify the watchers of this one.
+func (b *backend) NotifyWrite(_ context.Context, req *resource.NotifyWriteRequest) (*resource.NotifyWriteResponse, error) {
+	b.watchers.broadcast(groupResource{group: req.Group, resource: req.Resource})
+	return &resource.NotifyWriteResponse{}, nil
+}
+
+// notifyWrite notifies the watchers of this and the other backends sharing the
+// database that resources of the group and resource of the key were written.
+// Failures are only logged, since the watchers still poll for the writes.
+func (b *backend) notifyWrite(ctx context.Context, key *resource.ResourceKey) {
+	gr := groupResource{group: key.Group, resource: key.Resource}
+	b.watchers.broadcast(gr)
+	if len(b.notifiers) == 0 {
+		return
+	}
+
+	// Don't delay the response to the write, nor abort when it's sent
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, n := range b.notifiers {
+			ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+			if err := n.notify(ctx, gr); err != nil {
+				b.log.Warn("notify resource write", "group", gr.group, "resource", gr.resource, "err", err)
+			}
+			cancel()
+		}
+	}()
 }
 
 func (b *backend) WriteEvent(ctx context.Context, event resource.WriteEvent) (int64, error) {
@@ -191,6 +270,10 @@ func (b *backend) create(ctx context.Context, event resource.WriteEvent) (int64,
 
 		return nil
 	})
+	if err == nil {
+		b.notifyWrite(ctx, event.Key)
+	}
+
 	return newVersion, err
 }
 
@@ -249,6 +332,9 @@ func (b *backend) update(ctx context.Context, event resource.WriteEvent) (int64,
 
 		return nil
 	})
+	if err == nil {
+		b.notifyWrite(ctx, event.Key)
+	}
 
 	return newVersion, err
 }
@@ -301,6 +387,9 @@ func (b *backend) delete(ctx context.Context, event resource.WriteEvent) (int64,
 
 		return nil
 	})
+	if err == nil {
+		b.notifyWrite(ctx, event.Key)
+	}
 
 	return newVersion, err
 }
@@ -510,62 +599,82 @@ func (b *backend) listAtRevision(ctx context.Context, req *resource.ListRequest,
 }
 
 func (b *backend) WatchWriteEvents(ctx context.Context) (<-chan *resource.WrittenEvent, error) {
+	// Subscribe to the write notifications first, so that no write is missed
+	sub := b.watchers.subscribe()
+
 	// Get the latest RV
 	since, err := b.listLatestRVs(ctx)
 	if err != nil {
+		b.watchers.unsubscribe(sub)
 		return nil, fmt.Errorf("get the latest resource version: %w", err)
 	}
 	// Start the poller
 	stream := make(chan *resource.WrittenEvent)
-	go b.poller(ctx, since, stream)
+	go b.poller(ctx, since, sub, stream)
 	return stream, nil
 }
 
-func (b *backend) poller(ctx context.Context, since groupResourceRV, stream chan<- *resource.WrittenEvent) {
+// poller polls the resources which were notified to be written, and all of
+// them on every polling interval in case notifications were lost.
+func (b *backend) poller(ctx context.Context, since groupResourceRV, sub *writeSubscription, stream chan<- *resource.WrittenEvent) {
 	t := time.NewTicker(b.pollingInterval)
 	defer close(stream)
 	defer t.Stop()
+	defer b.watchers.unsubscribe(sub)
 
 	for {
 		select {
 		case <-b.done:
 			return
-		case <-t.C:
-			// List the latest RVs
-			grv, err := b.listLatestRVs(ctx)
-			if err != nil {
-				b.log.Error("get the latest resource version", "err", err)
-				t.Reset(b.pollingInterval)
+		case <-sub.ready:
+			written := sub.take()
+			if slices.Contains(written, groupResource{}) {
+				b.pollAll(ctx, since, stream)
 				continue
 			}
-			for group, items := range grv {
-				for resource := range items {
-					// If we haven't seen this resource before, we start from 0
-					if _, ok := since[group]; !ok {
-						since[group] = make(map[string]int64)
-					}
-					if _, ok := since[group][resource]; !ok {
-						since[group][resource] = 0
-					}
-
-					// Poll for new events
-					next, err := b.poll(ctx, group, resource, since[group][resource], stream)
-					if err != nil {
-						b.log.Error("polling for resource", "err", err)
-						t.Reset(b.pollingInterval)
-						continue
-					}
-					if next > since[group][resource] {
-						since[group][resource] = next
-					}
-				}
+			for _, gr := range written {
+				b.pollGroupResource(ctx, since, gr.group, gr.resource, stream)
 			}
-
+		case <-t.C:
+			b.pollAll(ctx, since, stream)
 			t.Reset(b.pollingInterval)
 		}
 	}
 }
 
+// pollAll polls every resource for the events written since the last poll.
+func (b *backend) pollAll(ctx context.Context, since groupResourceRV, stream chan<- *resource.WrittenEvent) {
+	// List the latest RVs
+	grv, err := b.listLatestRVs(ctx)
+	if err != nil {
+		b.log.Error("get the latest resource version", "err", err)
+		return
+	}
+	for group, items := range grv {
+		for resource := range items {
+			b.pollGroupResource(ctx, since, group, resource, stream)
+		}
+	}
+}
+
+// pollGroupResource polls a resource for the events written since the last poll.
+func (b *backend) pollGroupResource(ctx context.Context, since groupResourceRV, grp, res string, stream chan<- *resource.WrittenEvent) {
+	// If we haven't seen this resource before, we start from 0
+	if _, ok := since[grp]; !ok {
+		since[grp] = make(map[string]int64)
+	}
+
+	// Poll for new events
+	next, err := b.poll(ctx, grp, res, since[grp][res], stream)
+	if err != nil {
+		b.log.Error("polling for resource", "err", err)
+		return
+	}
+	if next > since[grp][res] {
+		since[grp][res] = next
+	}
+}
+
 // listLatestRVs returns the latest resource version for each (Group, Resource) pair.
 func (b *backend) listLatestRVs(ctx context.Context) (groupResourceRV, error) {
 	var grvs []*groupResourceVersion
@@ -613,6 +722,29 @@ func fetchLatestRV(ctx context.Context, x db.ContextExecer, d sqltemplate.Dialec
 func (b *backend) poll(ctx context.Context, grp string, res string, since int64, stream chan<- *resource.WrittenEvent) (int64, error) {
 	ctx, span := b.tracer.Start(ctx, tracePrefix+"poll")
 	defer span.End()
+
+	return b.readHistory(ctx, grp, res, since, func(event *resource.WrittenEvent) error {
+		stream <- event
+		return nil
+	})
+}
+
+// ReplayWriteEvents calls fn, in resource version order, with the events of the resource type that were
+// written after the resource version. It returns the resource version of the last event.
+func (b *backend) ReplayWriteEvents(ctx context.Context, key *resource.ResourceKey, since int64, fn func(*resource.WrittenEvent) error) (int64, error) {
+	ctx, span := b.tracer.Start(ctx, tracePrefix+"ReplayWriteEvents")
+	defer span.End()
+
+	last, err := b.readHistory(ctx, key.Group, key.Resource, since, fn)
+	if err != nil {
+		return 0, err
+	}
+	return max(last, since), nil
+}
+
+// readHistory calls fn, in resource version order, with the events of the resource type that were written after since.
+// It returns the resource version of the last event, or 0 if there was none.
+func (b *backend) readHistory(ctx context.Context, grp string, res string, since int64, fn func(*resource.WrittenEvent) error) (int64, error) {
 	var records []*historyPollResponse
 	err := b.db.WithTx(ctx, ReadCommittedRO, func(ctx context.Context, tx db.Tx) error {
 		var err error
@@ -634,12 +766,11 @@ func (b *backend) poll(ctx context.Context, grp string, res string, since int64,
 		if rec.Key.Group == "" || rec.Key.Resource == "" || rec.Key.Name == "" {
 			return nextRV, fmt.Errorf("missing key in response")
 		}
-		nextRV = rec.ResourceVersion
-		prevRV := rec.PreviousRV
-		if prevRV == nil {
-			*prevRV = int64(0)
+		var prevRV int64
+		if rec.PreviousRV != nil {
+			prevRV = *rec.PreviousRV
 		}
-		stream <- &resource.WrittenEvent{
+		err := fn(&resource.WrittenEvent{
 			WriteEvent: resource.WriteEvent{
 				Value: rec.Value,
 				Key: &resource.ResourceKey{
@@ -649,11 +780,15 @@ func (b *backend) poll(ctx context.Context, grp string, res string, since int64,
 					Name:      rec.Key.Name,
 				},
 				Type:       resource.WatchEvent_Type(rec.Action),
-				PreviousRV: *prevRV,
+				PreviousRV: prevRV,
 			},
 			ResourceVersion: rec.ResourceVersion,
 			// Timestamp:  , // TODO: add timestamp
+		})
+		if err != nil {
+			return nextRV, err
 		}
+		nextRV = rec.ResourceVersion
 	}
 
 	return nextRV, nil
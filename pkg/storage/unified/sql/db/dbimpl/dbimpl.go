@@ -42,21 +42,28 @@ func ProvideResourceDB(grafanaDB infraDB.DB, cfg *setting.Cfg, tracer trace.Trac
 	if err != nil {
 		return nil, fmt.Errorf("provide Resource DB: %w", err)
 	}
-	var once sync.Once
-	var resourceDB db.DB
-
-	return dbProviderFunc(func(ctx context.Context) (db.DB, error) {
-		once.Do(func() {
-			resourceDB, err = p.init(ctx)
-		})
-		return resourceDB, err
-	}), nil
+	return &dbProvider{p: p}, nil
 }
 
-type dbProviderFunc func(context.Context) (db.DB, error)
+// dbProvider initializes the Resource DB once, and tells its data source name.
+type dbProvider struct {
+	p          *resourceDBProvider
+	once       sync.Once
+	resourceDB db.DB
+	err        error
+}
+
+var _ db.DSNProvider = (*dbProvider)(nil)
+
+func (d *dbProvider) Init(ctx context.Context) (db.DB, error) {
+	d.once.Do(func() {
+		d.resourceDB, d.err = d.p.init(ctx)
+	})
+	return d.resourceDB, d.err
+}
 
-func (f dbProviderFunc) Init(ctx context.Context) (db.DB, error) {
-	return f(ctx)
+func (d *dbProvider) DSN() string {
+	return d.p.engine.DataSourceName()
 }
 
 type resourceDBProvider struct {
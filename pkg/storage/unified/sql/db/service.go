@@ -26,6 +26,13 @@ type DBProvider interface {
 	Init(context.Context) (DB, error)
 }
 
+// DSNProvider is implemented by the DBProviders that know the data source name
+// of the SQL Database, so that dedicated connections can be opened to it, e.g.
+// to listen to notifications.
+type DSNProvider interface {
+	DSN() string
+}
+
 // DB is a thin abstraction on *sql.DB to allow mocking to provide better unit
 // testing. We purposefully hide database operation methods that would use
 // context.Background().
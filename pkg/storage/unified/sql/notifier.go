@@ -0,0 +1,225 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fullstorydev/grpchan"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+	grpcUtils "github.com/grafana/grafana/pkg/storage/unified/resource/grpc"
+	"github.com/grafana/grafana/pkg/storage/unified/sql/db"
+)
+
+const (
+	// pgNotifyChannel is the PostgreSQL channel the backends notify their writes on.
+	pgNotifyChannel = "grafana_resource_write"
+	// pgListenerPingInterval is how often an idle listener checks that its connection is alive.
+	pgListenerPingInterval = 90 * time.Second
+	// notifyTimeout bounds the time spent notifying other backends of a write.
+	notifyTimeout = time.Second
+)
+
+// groupResource identifies the resources a write notification is about. The
+// zero value means that resources of any group and resource may have been
+// written, e.g. when notifications could have been missed.
+type groupResource struct {
+	group    string
+	resource string
+}
+
+func (gr groupResource) String() string {
+	return gr.group + "/" + gr.resource
+}
+
+func parseGroupResource(s string) groupResource {
+	group, res, _ := strings.Cut(s, "/")
+	return groupResource{group: group, resource: res}
+}
+
+// writeSubscription collects the write notifications of a watcher. Notifications
+// are coalesced while the watcher is busy, so that broadcasting never blocks
+// and no notification is lost.
+type writeSubscription struct {
+	mu      sync.Mutex
+	pending map[groupResource]struct{}
+	ready   chan struct{}
+}
+
+func (s *writeSubscription) add(gr groupResource) {
+	s.mu.Lock()
+	s.pending[gr] = struct{}{}
+	s.mu.Unlock()
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the notifications received since the previous call.
+func (s *writeSubscription) take() []groupResource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	grs := make([]groupResource, 0, len(s.pending))
+	for gr := range s.pending {
+		grs = append(grs, gr)
+	}
+	clear(s.pending)
+	return grs
+}
+
+// writeBroadcaster notifies the watchers of this process of the writes.
+type writeBroadcaster struct {
+	mu   sync.Mutex
+	subs map[*writeSubscription]struct{}
+}
+
+func newWriteBroadcaster() *writeBroadcaster {
+	return &writeBroadcaster{subs: map[*writeSubscription]struct{}{}}
+}
+
+func (w *writeBroadcaster) subscribe() *writeSubscription {
+	sub := &writeSubscription{
+		pending: map[groupResource]struct{}{},
+		ready:   make(chan struct{}, 1),
+	}
+	w.mu.Lock()
+	w.subs[sub] = struct{}{}
+	w.mu.Unlock()
+	return sub
+}
+
+func (w *writeBroadcaster) unsubscribe(sub *writeSubscription) {
+	w.mu.Lock()
+	delete(w.subs, sub)
+	w.mu.Unlock()
+}
+
+func (w *writeBroadcaster) broadcast(gr groupResource) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subs {
+		sub.add(gr)
+	}
+}
+
+// writeNotifier notifies the backends of the other processes sharing the
+// database of the writes of this one.
+type writeNotifier interface {
+	notify(ctx context.Context, gr groupResource) error
+}
+
+// pgNotifier notifies the writes with PostgreSQL NOTIFY, and broadcasts the
+// notifications it listens to.
+type pgNotifier struct {
+	db  db.DB
+	dsn string
+	log log.Logger
+}
+
+func (n *pgNotifier) notify(ctx context.Context, gr groupResource) error {
+	_, err := n.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", pgNotifyChannel, gr.String())
+	return err
+}
+
+// listen broadcasts the notifications of all the backends until done is
+// closed. As notifications may be missed while the connection is lost, all
+// watchers poll every resource when it is re-established.
+func (n *pgNotifier) listen(done <-chan struct{}, watchers *writeBroadcaster) error {
+	listener := pq.NewListener(n.dsn, 10*time.Millisecond, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			n.log.Warn("resource write listener", "event", ev, "err", err)
+		}
+	})
+	if err := listener.Listen(pgNotifyChannel); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("listen to %s: %w", pgNotifyChannel, err)
+	}
+
+	go func() {
+		defer func() { _ = listener.Close() }()
+		t := time.NewTicker(pgListenerPingInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case notification := <-listener.Notify:
+				// A nil notification is sent after reconnecting
+				if notification == nil {
+					watchers.broadcast(groupResource{})
+					continue
+				}
+				watchers.broadcast(parseGroupResource(notification.Extra))
+			case <-t.C:
+				go func() {
+					if err := listener.Ping(); err != nil {
+						n.log.Warn("ping resource write listener", "err", err)
+					}
+				}()
+			}
+		}
+	}()
+	return nil
+}
+
+// peerNotifier notifies the writes to the WatchNotifier gRPC service of the
+// other storage servers, for databases without notifications.
+type peerNotifier struct {
+	conns   []*grpc.ClientConn
+	clients []resource.WatchNotifierClient
+}
+
+func newPeerNotifier(addresses []string) (*peerNotifier, error) {
+	n := &peerNotifier{}
+	for _, address := range addresses {
+		conn, err := grpc.NewClient(address,
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			_ = n.Close()
+			return nil, fmt.Errorf("connect to watch peer %q: %w", address, err)
+		}
+		cc := grpchan.InterceptClientConn(conn, grpcUtils.UnaryClientInterceptor, grpcUtils.StreamClientInterceptor)
+		n.conns = append(n.conns, conn)
+		n.clients = append(n.clients, resource.NewWatchNotifierClient(cc))
+	}
+	return n, nil
+}
+
+func (n *peerNotifier) notify(ctx context.Context, gr groupResource) error {
+	req := &resource.NotifyWriteRequest{Group: gr.group, Resource: gr.resource}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(n.clients))
+	for i, client := range n.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = client.NotifyWrite(ctx, req)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (n *peerNotifier) Close() error {
+	var errs []error
+	for _, conn := range n.conns {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,90 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/storage/unified/resource"
+)
+
+func TestWriteBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	playlists := groupResource{group: "playlist.grafana.app", resource: "playlists"}
+	dashboards := groupResource{group: "dashboard.grafana.app", resource: "dashboards"}
+
+	t.Run("coalesces the notifications of busy watchers", func(t *testing.T) {
+		t.Parallel()
+
+		w := newWriteBroadcaster()
+		sub := w.subscribe()
+		w.broadcast(playlists)
+		w.broadcast(dashboards)
+		w.broadcast(playlists)
+
+		require.Len(t, sub.ready, 1)
+		<-sub.ready
+		require.ElementsMatch(t, []groupResource{playlists, dashboards}, sub.take())
+		require.Empty(t, sub.take())
+	})
+
+	t.Run("stops notifying unsubscribed watchers", func(t *testing.T) {
+		t.Parallel()
+
+		w := newWriteBroadcaster()
+		sub := w.subscribe()
+		other := w.subscribe()
+		w.unsubscribe(other)
+		w.broadcast(playlists)
+
+		require.Equal(t, []groupResource{playlists}, sub.take())
+		require.Empty(t, other.take())
+		require.Empty(t, other.ready)
+	})
+
+	t.Run("parses the notified group and resource", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, playlists, parseGroupResource(playlists.String()))
+		require.Equal(t, groupResource{}, parseGroupResource(""))
+	})
+}
+
+func TestBackend_NotifyWrite(t *testing.T) {
+	t.Parallel()
+
+	b, ctx := setupBackendTest(t)
+	sub := b.watchers.subscribe()
+
+	t.Run("notifies the watchers of the writes of other servers", func(t *testing.T) {
+		_, err := b.NotifyWrite(ctx, &resource.NotifyWriteRequest{Group: "gr", Resource: "rs"})
+		require.NoError(t, err)
+		<-sub.ready
+		require.Equal(t, []groupResource{{group: "gr", resource: "rs"}}, sub.take())
+	})
+
+	t.Run("notifies the watchers of the committed writes", func(t *testing.T) {
+		b.SQLMock.ExpectBegin()
+		b.ExecWithResult("delete resource", 0, 1)
+		b.ExecWithResult("insert resource_history", 0, 1)
+		expectSuccessfulResourceVersionAtomicInc(t, b)
+		b.ExecWithResult("update resource_history", 0, 1)
+		b.SQLMock.ExpectCommit()
+
+		_, err := b.delete(ctx, resource.WriteEvent{Type: resource.WatchEvent_DELETED, Key: resKey})
+		require.NoError(t, err)
+		<-sub.ready
+		require.Equal(t, []groupResource{{group: resKey.Group, resource: resKey.Resource}}, sub.take())
+	})
+
+	t.Run("does not notify the watchers of failed writes", func(t *testing.T) {
+		b.SQLMock.ExpectBegin()
+		b.ExecWithErr("delete resource", errTest)
+		b.SQLMock.ExpectRollback()
+
+		_, err := b.delete(ctx, resource.WriteEvent{Type: resource.WatchEvent_DELETED, Key: resKey})
+		require.Error(t, err)
+		require.Empty(t, sub.ready)
+	})
+}
@@ -18,7 +18,14 @@ import (
 
 // Creates a new ResourceServer
 func NewResourceServer(ctx context.Context, db infraDB.DB, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer, reg prometheus.Registerer) (resource.ResourceServer, error) {
+	server, _, err := newResourceServer(ctx, db, cfg, features, tracer, reg)
+	return server, err
+}
+
+// newResourceServer creates a new ResourceServer, and returns the SQL backend it uses too.
+func newResourceServer(ctx context.Context, db infraDB.DB, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer, reg prometheus.Registerer) (resource.ResourceServer, Backend, error) {
 	apiserverCfg := cfg.SectionWithEnvOverrides("grafana-apiserver")
+	resourceAPICfg := cfg.SectionWithEnvOverrides("resource_api")
 	opts := resource.ResourceServerOptions{
 		Tracer: tracer,
 		Blob: resource.BlobConfig{
@@ -32,18 +39,23 @@ func NewResourceServer(ctx context.Context, db infraDB.DB, cfg *setting.Cfg, fea
 		dir := strings.Replace(opts.Blob.URL, "./data", cfg.DataPath, 1)
 		err := os.MkdirAll(dir, 0700)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		opts.Blob.URL = "file:///" + dir
 	}
 
 	eDB, err := dbimpl.ProvideResourceDB(db, cfg, tracer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	store, err := NewBackend(BackendOptions{DBProvider: eDB, Tracer: tracer})
+	store, err := NewBackend(BackendOptions{
+		DBProvider:      eDB,
+		Tracer:          tracer,
+		PollingInterval: resourceAPICfg.Key("watch_poll_interval").MustDuration(0),
+		WatchPeers:      resourceAPICfg.Key("watch_peers").Strings(","),
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	opts.Backend = store
 	opts.Diagnostics = store
@@ -53,15 +65,15 @@ func NewResourceServer(ctx context.Context, db infraDB.DB, cfg *setting.Cfg, fea
 		opts.Index = resource.NewResourceIndexServer(cfg)
 		server, err := resource.NewResourceServer(opts)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		// initialze the search index
 		indexer, ok := server.(resource.ResourceIndexer)
 		if !ok {
-			return nil, errors.New("index server does not implement ResourceIndexer")
+			return nil, nil, errors.New("index server does not implement ResourceIndexer")
 		}
 		_, err = indexer.Index(ctx)
-		return server, err
+		return server, store, err
 	}
 
 	if features.IsEnabledGlobally(featuremgmt.FlagKubernetesFolders) {
@@ -75,5 +87,6 @@ func NewResourceServer(ctx context.Context, db infraDB.DB, cfg *setting.Cfg, fea
 		}
 	}
 
-	return resource.NewResourceServer(opts)
+	server, err := resource.NewResourceServer(opts)
+	return server, store, err
 }
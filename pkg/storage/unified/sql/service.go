@@ -87,7 +87,7 @@ func ProvideUnifiedStorageGrpcService(
 }
 
 func (s *service) start(ctx context.Context) error {
-	server, err := NewResourceServer(ctx, s.db, s.cfg, s.features, s.tracing, s.reg)
+	server, store, err := newResourceServer(ctx, s.db, s.cfg, s.features, s.tracing, s.reg)
 	if err != nil {
 		return err
 	}
@@ -106,6 +106,7 @@ func (s *service) start(ctx context.Context) error {
 	resource.RegisterResourceIndexServer(srv, server)
 	resource.RegisterBlobStoreServer(srv, server)
 	resource.RegisterDiagnosticsServer(srv, server)
+	resource.RegisterWatchNotifierServer(srv, store)
 	grpc_health_v1.RegisterHealthServer(srv, healthService)
 
 	// register reflection service
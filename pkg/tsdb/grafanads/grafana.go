@@ -13,9 +13,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/samplestore"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/unifiedSearch"
@@ -53,15 +55,16 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
-	return newService(search, searchNext, store, features)
+func ProvideService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, sqlStore db.DB) *Service {
+	return newService(search, searchNext, store, features, sqlStore)
 }
 
-func newService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
+func newService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, sqlStore db.DB) *Service {
 	s := &Service{
 		search:     search,
 		searchNext: searchNext,
 		store:      store,
+		sqlStore:   sqlStore,
 		log:        log.New("grafanads"),
 		features:   features,
 	}
@@ -74,6 +77,7 @@ type Service struct {
 	search     searchV2.SearchService
 	searchNext unifiedSearch.SearchService
 	store      store.StorageService
+	sqlStore   db.DB
 	log        log.Logger
 	features   featuremgmt.FeatureToggles
 }
@@ -103,6 +107,8 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch, queryTypeSearchNext:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeRecordedMetrics:
+			response.Responses[q.RefID] = s.doRecordedMetricsQuery(ctx, req, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -169,6 +175,27 @@ func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) back
 	return response
 }
 
+func (s *Service) doRecordedMetricsQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &recordedMetricsQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	frames, err := samplestore.Find(ctx, s.sqlStore, samplestore.Query{
+		OrgID:  req.PluginContext.OrgID,
+		Metric: q.Metric,
+		Labels: q.Labels,
+		From:   query.TimeRange.From,
+		To:     query.TimeRange.To,
+	})
+	response.Error = err
+	response.Frames = frames
+	return response
+}
+
 func (s *Service) doRandomWalk(query backend.DataQuery) backend.DataResponse {
 	response := backend.DataResponse{}
 
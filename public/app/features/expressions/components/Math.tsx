@@ -88,6 +88,26 @@ export const Math = ({ labelWidth, onChange, query, onRunQuery }: Props) => {
                       name="floor"
                       description="rounds the number down to the nearest integer value. It's able to operate on series or escalar values."
                     />
+                    <DocumentedFunction
+                      name="clamp"
+                      description="limits the value to a lower and an upper bound, for example clamp($A, 0, 100). It's able to operate on series or scalar values."
+                    />
+                    <DocumentedFunction
+                      name="shift"
+                      description="moves each point of a series forward in time by a duration, for example shift($A, 1w)."
+                    />
+                    <DocumentedFunction
+                      name="moving_avg"
+                      description="returns the mean of the values of a series within the preceding duration, for example moving_avg($A, 5m)."
+                    />
+                    <DocumentedFunction
+                      name="delta, rate, and cumsum"
+                      description="return the difference to the previous point, the per-second rate of increase since the previous point, and the running total of a series."
+                    />
+                    <DocumentedFunction
+                      name="holt_winters"
+                      description="smooths a series with a smoothing and a trend factor between 0 and 1, for example holt_winters($A, 0.5, 0.5)."
+                    />
                   </div>
                 </div>
               }
@@ -89,6 +89,13 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
         description: 'Search for grafana resources',
       });
     }
+    if (config.featureToggles.grafanaManagedRecordingRules) {
+      this.queryTypes.push({
+        label: 'Recorded metrics',
+        value: GrafanaQueryType.RecordedMetrics,
+        description: 'Query metrics stored in the Grafana database by recording rules',
+      });
+    }
     if (config.featureToggles.editPanelCSVDragAndDrop) {
       this.queryTypes.push({
         label: 'Spreadsheet or snapshot',
@@ -318,6 +325,33 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
     onRunQuery();
   };
 
+  onMetricChange = (e: React.FormEvent<HTMLInputElement>) => {
+    const { onChange, query, onRunQuery } = this.props;
+    onChange({ ...query, metric: e.currentTarget.value });
+    onRunQuery();
+  };
+
+  onLabelsChange = (e: React.FormEvent<HTMLInputElement>) => {
+    const { onChange, query, onRunQuery } = this.props;
+    onChange({ ...query, labels: parseLabels(e.currentTarget.value) });
+    onRunQuery();
+  };
+
+  renderRecordedMetricsQuery() {
+    const { metric, labels } = this.props.query;
+
+    return (
+      <InlineFieldRow>
+        <InlineField label="Metric" grow={true} labelWidth={labelWidth}>
+          <Input defaultValue={metric ?? ''} placeholder="Metric name" onBlur={this.onMetricChange} />
+        </InlineField>
+        <InlineField label="Labels" grow={true} tooltip="Comma separated label=value pairs that series must have">
+          <Input defaultValue={formatLabels(labels)} placeholder="label=value, ..." onBlur={this.onLabelsChange} />
+        </InlineField>
+      </InlineFieldRow>
+    );
+  }
+
   renderListPublicFiles() {
     let { path } = this.props.query;
     let { folders } = this.state;
@@ -489,6 +523,7 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
         {queryType === GrafanaQueryType.LiveMeasurements && this.renderMeasurementsQuery()}
         {queryType === GrafanaQueryType.List && this.renderListPublicFiles()}
         {queryType === GrafanaQueryType.Snapshot && this.renderSnapshotQuery()}
+        {queryType === GrafanaQueryType.RecordedMetrics && this.renderRecordedMetricsQuery()}
         {queryType === GrafanaQueryType.Search && (
           <SearchEditor value={query.search ?? {}} onChange={this.onSearchChange} />
         )}
@@ -502,6 +537,24 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
 
 export const QueryEditor = withTheme2(UnthemedQueryEditor);
 
+function parseLabels(value: string): Record<string, string> | undefined {
+  const labels: Record<string, string> = {};
+  for (const pair of value.split(',')) {
+    const idx = pair.indexOf('=');
+    if (idx < 1) {
+      continue;
+    }
+    labels[pair.slice(0, idx).trim()] = pair.slice(idx + 1).trim();
+  }
+  return Object.keys(labels).length ? labels : undefined;
+}
+
+function formatLabels(labels?: Record<string, string>): string {
+  return Object.entries(labels ?? {})
+    .map(([k, v]) => `${k}=${v}`)
+    .join(', ');
+}
+
 function getStyles(theme: GrafanaTheme2) {
   return {
     file: css`
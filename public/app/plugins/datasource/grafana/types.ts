@@ -20,6 +20,7 @@ export enum GrafanaQueryType {
   Read = 'read',
   Search = 'search',
   SearchNext = 'searchNext',
+  RecordedMetrics = 'recordedMetrics',
 }
 
 export interface GrafanaQuery extends DataQuery {
@@ -33,6 +34,8 @@ export interface GrafanaQuery extends DataQuery {
   snapshot?: DataFrameJSON[];
   timeRegion?: TimeRegionConfig;
   file?: GrafanaQueryFile;
+  metric?: string; // for recordedMetrics
+  labels?: Record<string, string>; // for recordedMetrics
 }
 
 export interface GrafanaQueryFile {
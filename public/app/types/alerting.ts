@@ -57,7 +57,11 @@ export type GrafanaNotifierType =
   | 'LINE'
   | 'kafka'
   | 'wecom'
-  | 'mqtt';
+  | 'mqtt'
+  | 'zulip'
+  | 'matrix'
+  | 'rocketchat'
+  | 'jira';
 
 export type CloudNotifierType =
   | 'oncall' // Only FE implementation for now